@@ -0,0 +1,75 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/errs"
+)
+
+// Exit codes let scripts tell failures apart. Any failure not listed, such as
+// an invalid flag, exits with exitFailure.
+const (
+	exitFailure = 1
+	// exitUnsupported is returned when an input has no converter
+	exitUnsupported = 2
+	// exitNotFound is returned when an input does not exist
+	exitNotFound = 3
+	// exitConversion is returned when a converter fails to read an input,
+	// including corrupt, encrypted and empty documents, and when
+	// --fail-on-empty finds no Markdown to write
+	exitConversion = 4
+	// exitPartial is returned when some inputs of a batch converted and others failed
+	exitPartial = 5
+)
+
+// conversionError marks a failure of a converter, as opposed to one of the
+// command line or the file system
+type conversionError struct {
+	err error
+}
+
+func (e conversionError) Error() string { return e.err.Error() }
+
+func (e conversionError) Unwrap() error { return e.err }
+
+// batchError reports the inputs of a batch that failed while others converted
+type batchError struct {
+	failures []error
+	total    int
+}
+
+func (e *batchError) Error() string {
+	return fmt.Sprintf("%d of %d inputs failed to convert:\n%v", len(e.failures), e.total, errors.Join(e.failures...))
+}
+
+func (e *batchError) Unwrap() []error { return e.failures }
+
+// batchResult combines the failures of converting total inputs. A batch in
+// which every input failed reports those failures themselves.
+func batchResult(failures []error, total int) error {
+	switch {
+	case len(failures) == 0:
+		return nil
+	case len(failures) < total:
+		return &batchError{failures: failures, total: total}
+	}
+	return errors.Join(failures...)
+}
+
+// exitCode returns the exit code for an error returned by the command
+func exitCode(err error) int {
+	var batch *batchError
+	var conversion conversionError
+	switch {
+	case errors.As(err, &batch):
+		return exitPartial
+	case errors.Is(err, errs.ErrUnsupportedType):
+		return exitUnsupported
+	case errors.Is(err, errs.ErrNotFound):
+		return exitNotFound
+	case errors.As(err, &conversion), errors.Is(err, errs.ErrCorrupt), errors.Is(err, errs.ErrEncrypted), errors.Is(err, errs.ErrNoPages), errors.Is(err, errs.ErrEmptyOutput):
+		return exitConversion
+	}
+	return exitFailure
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/errs"
+)
+
+func TestExitCode(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"notes.txt":   "Some notes.\n",
+		"empty.txt":   "",
+		"data.xyz":    "unknown",
+		"corrupt.pdf": "not a PDF",
+		"broken.pdf":  "%PDF-1.7\n",
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	in := func(name string) string { return filepath.Join(dir, name) }
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"success", []string{in("notes.txt")}, 0},
+		{"unsupported type", []string{in("data.xyz")}, exitUnsupported},
+		{"not found", []string{in("missing.pdf")}, exitNotFound},
+		{"corrupt", []string{in("corrupt.pdf")}, exitConversion},
+		{"empty output", []string{"--fail-on-empty", in("empty.txt")}, exitConversion},
+		{"partial batch", []string{in("notes.txt"), in("corrupt.pdf")}, exitPartial},
+		{"whole batch failed", []string{in("corrupt.pdf"), in("broken.pdf")}, exitConversion},
+		{"invalid flag", []string{"--chunk-unit", "words", in("notes.txt")}, exitFailure},
+		{"conflicting flags", []string{"--include-comments", "--strip-comments", in("notes.txt")}, exitFailure},
+	}
+	for _, tt := range tests {
+		out := t.TempDir()
+		args := append([]string{"doc2md", "--assets-dir", filepath.Join(out, "assets"), "-o", out}, tt.args...)
+		err := newApp().Run(args)
+		got := 0
+		if err != nil {
+			got = exitCode(err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: exit code = %d (%v), want %d", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestBatchResult(t *testing.T) {
+	failure := errors.New("failed")
+	tests := []struct {
+		name     string
+		failures []error
+		total    int
+		want     int
+	}{
+		{"none failed", nil, 2, 0},
+		{"some failed", []error{failure}, 2, exitPartial},
+		{"all failed", []error{failure, conversionError{failure}}, 2, exitConversion},
+	}
+	for _, tt := range tests {
+		err := batchResult(tt.failures, tt.total)
+		got := 0
+		if err != nil {
+			got = exitCode(err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: exit code = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+// onePagePDF is a PDF of a page drawn by content, with the Helvetica font F1
+// and an image XObject Im1 of one gray pixel among its resources
+func onePagePDF(content string) []byte {
+	return infoPDF(content, "")
+}
+
+// infoPDF is onePagePDF with a document information dictionary, left out
+// when info is empty
+func infoPDF(content, info string) []byte {
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Resources << /Font << /F1 6 0 R >> /XObject << /Im1 5 0 R >> >> /Contents 4 0 R >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content),
+		"<< /Type /XObject /Subtype /Image /Width 1 /Height 1 /ColorSpace /DeviceGray /BitsPerComponent 8 /Length 1 >>\nstream\n\x80\nendstream",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+	}
+	trailer := fmt.Sprintf("/Size %d /Root 1 0 R", len(objects)+1)
+	if info != "" {
+		objects = append(objects, info)
+		trailer = fmt.Sprintf("/Size %d /Root 1 0 R /Info %d 0 R", len(objects)+1, len(objects))
+	}
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.7\n")
+	offsets := make([]int, len(objects))
+	for i, body := range objects {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, body)
+	}
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&b, "trailer\n<< %s >>\nstartxref\n%d\n%%%%EOF\n", trailer, xref)
+	return b.Bytes()
+}
+
+func TestFailOnEmpty(t *testing.T) {
+	dir := t.TempDir()
+	inputs := map[string][]byte{
+		// A page with no text layer, only vector graphics
+		"drawing.pdf": onePagePDF("0 0 1 rg 100 100 200 200 re f"),
+		// A scanned page, whose image is written as its Markdown
+		"scan.pdf":   onePagePDF("q 612 0 0 792 0 0 cm /Im1 Do Q"),
+		"spaces.txt": []byte(" \n\t\n"),
+	}
+	for name, data := range inputs {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		input     string
+		failEmpty bool
+		wantErr   bool
+	}{
+		{"drawing.pdf", true, true},
+		{"drawing.pdf", false, false},
+		{"spaces.txt", true, true},
+		{"scan.pdf", true, false},
+	}
+	for _, tt := range tests {
+		out := filepath.Join(t.TempDir(), "out.md")
+		args := []string{"doc2md", "--assets-dir", filepath.Join(dir, "assets"), "-o", out}
+		if tt.failEmpty {
+			args = append(args, "--fail-on-empty")
+		}
+		err := newApp().Run(append(args, filepath.Join(dir, tt.input)))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s (--fail-on-empty %v): error = %v, wantErr %v", tt.input, tt.failEmpty, err, tt.wantErr)
+			continue
+		}
+		_, statErr := os.Stat(out)
+		if tt.wantErr {
+			if !errors.Is(err, errs.ErrEmptyOutput) || exitCode(err) != exitConversion {
+				t.Errorf("%s: error = %v (exit code %d), want %v (exit code %d)", tt.input, err, exitCode(err), errs.ErrEmptyOutput, exitConversion)
+			}
+			if statErr == nil {
+				t.Errorf("%s: wrote %s, want no output", tt.input, out)
+			}
+		} else if statErr != nil {
+			t.Errorf("%s: %v, want the output written", tt.input, statErr)
+		}
+	}
+}
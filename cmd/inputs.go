@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/converter"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/remote"
+)
+
+// inputFilter selects which local inputs are converted
+type inputFilter struct {
+	Recursive bool
+	// Since, when non-zero, skips files last modified before it
+	Since time.Time
+}
+
+// parseSince accepts a duration relative to now, such as "24h", or an RFC 3339 timestamp
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --since value %q: expected a duration such as 24h or an RFC 3339 timestamp", value)
+	}
+	return t, nil
+}
+
+// expandInputs resolves the command line arguments to the files to convert.
+// Directories are walked for supported documents when Recursive is set.
+func expandInputs(args []string, filter inputFilter) ([]string, error) {
+	var inputs []string
+	for _, arg := range args {
+		if remote.IsURL(arg) {
+			inputs = append(inputs, arg)
+			continue
+		}
+
+		info, err := os.Stat(arg)
+		if err != nil || !info.IsDir() {
+			// Missing files are reported when they are converted
+			if err != nil || filter.includes(info) {
+				inputs = append(inputs, arg)
+			}
+			continue
+		}
+		if !filter.Recursive {
+			return nil, fmt.Errorf("%s is a directory; use --recursive to convert its contents", arg)
+		}
+
+		err = filepath.WalkDir(arg, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || !converter.IsSupported(path) {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			if filter.includes(info) {
+				inputs = append(inputs, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to walk %s: %v", arg, err)
+		}
+	}
+	return inputs, nil
+}
+
+func (f inputFilter) includes(info os.FileInfo) bool {
+	return f.Since.IsZero() || info.ModTime().After(f.Since)
+}
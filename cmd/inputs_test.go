@@ -0,0 +1,98 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{"24h", now.Add(-24 * time.Hour), false},
+		{"90m", now.Add(-90 * time.Minute), false},
+		{"2024-05-01T08:00:00Z", time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC), false},
+		{"2024-05-01", time.Time{}, true},
+		{"yesterday", time.Time{}, true},
+	}
+	for _, tt := range tests {
+		got, err := parseSince(tt.value, now)
+		if (err != nil) != tt.wantErr || !got.Equal(tt.want) {
+			t.Errorf("parseSince(%q) = %v, %v, want %v", tt.value, got, err, tt.want)
+		}
+	}
+}
+
+func TestExpandInputsSince(t *testing.T) {
+	dir := t.TempDir()
+	old := time.Now().Add(-48 * time.Hour)
+	files := map[string]time.Time{
+		"old.txt":        old,
+		"new.txt":        time.Now(),
+		"sub/old.tex":    old,
+		"sub/new.tex":    time.Now(),
+		"sub/ignored.go": time.Now(),
+	}
+	for name, modTime := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("text"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	since := time.Now().Add(-24 * time.Hour)
+	tests := []struct {
+		name   string
+		args   []string
+		filter inputFilter
+		want   []string
+	}{
+		{"recursive", []string{dir}, inputFilter{Recursive: true, Since: since}, []string{"new.txt", "sub/new.tex"}},
+		{"files", []string{filepath.Join(dir, "old.txt"), filepath.Join(dir, "new.txt")}, inputFilter{Since: since}, []string{"new.txt"}},
+		{"no limit", []string{dir}, inputFilter{Recursive: true}, []string{"new.txt", "old.txt", "sub/new.tex", "sub/old.tex"}},
+		{"missing files kept", []string{filepath.Join(dir, "gone.txt")}, inputFilter{Since: since}, []string{"gone.txt"}},
+	}
+	for _, tt := range tests {
+		inputs, err := expandInputs(tt.args, tt.filter)
+		if err != nil {
+			t.Fatalf("%s: expandInputs() error = %v", tt.name, err)
+		}
+		var got []string
+		for _, input := range inputs {
+			rel, _ := filepath.Rel(dir, input)
+			got = append(got, filepath.ToSlash(rel))
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: inputs = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestExpandInputsDirectory(t *testing.T) {
+	if _, err := expandInputs([]string{t.TempDir()}, inputFilter{}); err == nil {
+		t.Error("expandInputs(directory) error = nil, want an error without --recursive")
+	}
+}
+
+func TestSinceInvalid(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(path, []byte("text"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := newApp().Run([]string{"doc2md", "--since", "yesterday", "--assets-dir", filepath.Join(dir, "assets"), "-o", dir, path}); err == nil {
+		t.Error("run --since yesterday: error = nil, want an error")
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Output directory structures accepted by --output-dir-structure
+const (
+	// structureMirror recreates the directories of the inputs under the output
+	structureMirror = "mirror"
+	// structureFlat writes every output directly into the output directory
+	structureFlat = "flat"
+)
+
+// outputLayout places the outputs of the inputs found under directory
+// arguments. Outputs that would share a path get a numbered suffix, so no
+// input overwrites another.
+type outputLayout struct {
+	structure string
+	// roots are the directory arguments inputs were found under
+	roots []string
+	// assigned maps each output path given out to the input it belongs to
+	assigned map[string]string
+}
+
+// newOutputLayout validates the structure and records the directory arguments
+func newOutputLayout(structure string, args []string) (*outputLayout, error) {
+	if structure != structureMirror && structure != structureFlat {
+		return nil, fmt.Errorf("unsupported output dir structure: %s (expected mirror or flat)", structure)
+	}
+	l := &outputLayout{structure: structure, assigned: make(map[string]string)}
+	for _, arg := range args {
+		if info, err := os.Stat(arg); err == nil && info.IsDir() {
+			l.roots = append(l.roots, arg)
+		}
+	}
+	return l, nil
+}
+
+// place returns where the output of an input goes, given the path it would
+// have on its own. Inputs named on the command line keep that path, as do
+// outputs written to a file given with --output.
+func (l *outputLayout) place(inputPath, outputPath, outputOption string) string {
+	if l == nil {
+		return outputPath
+	}
+	if info, err := os.Stat(outputOption); outputOption != "" && (err != nil || !info.IsDir()) {
+		return outputPath
+	}
+	rel, ok := l.relativeDir(inputPath)
+	if !ok {
+		return outputPath
+	}
+	if l.structure == structureMirror && rel != "." {
+		outputPath = filepath.Join(filepath.Dir(outputPath), rel, filepath.Base(outputPath))
+	}
+
+	ext := filepath.Ext(outputPath)
+	base := strings.TrimSuffix(outputPath, ext)
+	for n := 2; ; n++ {
+		if owner, taken := l.assigned[outputPath]; !taken || owner == inputPath {
+			break
+		}
+		outputPath = base + "-" + strconv.Itoa(n) + ext
+	}
+	l.assigned[outputPath] = inputPath
+	return outputPath
+}
+
+// relativeDir returns the directory of an input relative to the directory
+// argument it was found under
+func (l *outputLayout) relativeDir(inputPath string) (string, bool) {
+	for _, root := range l.roots {
+		rel, err := filepath.Rel(root, inputPath)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return filepath.Dir(rel), true
+		}
+	}
+	return "", false
+}
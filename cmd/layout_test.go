@@ -0,0 +1,113 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// writeTree writes files of text under a directory by their slash-separated
+// relative paths
+func writeTree(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, text := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(text), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// listOutputs returns the slash-separated paths of the Markdown files under a directory
+func listOutputs(t *testing.T, dir string) []string {
+	t.Helper()
+	var outputs []string
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() && strings.HasSuffix(path, ".md") {
+			rel, _ := filepath.Rel(dir, path)
+			outputs = append(outputs, filepath.ToSlash(rel))
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(outputs)
+	return outputs
+}
+
+func TestOutputDirStructure(t *testing.T) {
+	input := t.TempDir()
+	writeTree(t, input, map[string]string{
+		"a.txt":          "Top a\n",
+		"sub/a.txt":      "Nested a\n",
+		"sub/deep/b.txt": "Deep b\n",
+	})
+
+	tests := []struct {
+		structure string
+		want      []string
+	}{
+		{structureMirror, []string{"a.md", "sub/a.md", "sub/deep/b.md"}},
+		{structureFlat, []string{"a-2.md", "a.md", "b.md"}},
+	}
+	for _, tt := range tests {
+		out := t.TempDir()
+		args := []string{"doc2md", "--recursive", "--output-dir-structure", tt.structure, "--assets-dir", filepath.Join(t.TempDir(), "assets"), "-o", out, input}
+		if err := newApp().Run(args); err != nil {
+			t.Fatalf("%s: run error = %v", tt.structure, err)
+		}
+		if got := listOutputs(t, out); strings.Join(got, " ") != strings.Join(tt.want, " ") {
+			t.Errorf("%s: outputs = %v, want %v", tt.structure, got, tt.want)
+		}
+		if tt.structure == structureFlat {
+			// Inputs are walked in lexical order, so the top-level one keeps its name
+			if data, _ := os.ReadFile(filepath.Join(out, "a.md")); !strings.Contains(string(data), "Top a") {
+				t.Errorf("flat a.md = %q, want the top-level input", data)
+			}
+		}
+	}
+}
+
+func TestOutputDirStructureInvalid(t *testing.T) {
+	input := t.TempDir()
+	writeTree(t, input, map[string]string{"a.txt": "a\n"})
+	args := []string{"doc2md", "--recursive", "--output-dir-structure", "tree", "--assets-dir", filepath.Join(t.TempDir(), "assets"), "-o", t.TempDir(), input}
+	if err := newApp().Run(args); err == nil {
+		t.Error("run --output-dir-structure tree: error = nil, want an error")
+	}
+}
+
+func TestOutputLayoutPlace(t *testing.T) {
+	root := t.TempDir()
+	out := t.TempDir()
+	writeTree(t, root, map[string]string{"x/r.txt": "r\n"})
+	l, err := newOutputLayout(structureFlat, []string{root, filepath.Join(root, "x", "r.txt")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mdPath := filepath.Join(out, "r.md")
+	tests := []struct {
+		name, input, output, option, want string
+	}{
+		{"first", filepath.Join(root, "x", "r.txt"), mdPath, out, mdPath},
+		{"same input again", filepath.Join(root, "x", "r.txt"), mdPath, out, mdPath},
+		{"collision", filepath.Join(root, "y", "r.txt"), mdPath, out, filepath.Join(out, "r-2.md")},
+		{"outside the roots", filepath.Join(t.TempDir(), "r.txt"), mdPath, out, mdPath},
+		{"output file", filepath.Join(root, "z", "r.txt"), filepath.Join(out, "single.md"), filepath.Join(out, "single.md"), filepath.Join(out, "single.md")},
+	}
+	for _, tt := range tests {
+		if got := l.place(tt.input, tt.output, tt.option); got != tt.want {
+			t.Errorf("%s: place() = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+	var none *outputLayout
+	if got := none.place("in.txt", "in.md", ""); got != "in.md" {
+		t.Errorf("nil layout: place() = %s, want in.md", got)
+	}
+}
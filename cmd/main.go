@@ -1,37 +1,386 @@
 package main
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/leandrowiemesfilho/markdown-converter/internal/converter"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/errs"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/ocr"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/pdf"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/remote"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/transform"
 	"github.com/leandrowiemesfilho/markdown-converter/internal/utils"
 	"github.com/urfave/cli/v2"
 )
 
+// outputOptions configures the passes applied to converted Markdown before it is written
+type outputOptions struct {
+	// Transforms are the post-processing passes selected by flags, in the order they run
+	Transforms transform.Pipeline
+	// Template is the file name pattern used when the output is a directory
+	Template string
+	// ChunkSize splits the output into files of at most this many ChunkUnit
+	ChunkSize int
+	ChunkUnit string
+	// FailOnEmpty fails an input whose Markdown is blank instead of writing it
+	FailOnEmpty bool
+	// Layout places the outputs of inputs found by --recursive, nil otherwise
+	Layout *outputLayout
+	// ReadMetadata reads the metadata of downloaded inputs for
+	// --include-metadata-table before their copy is removed, nil otherwise
+	ReadMetadata transform.MetadataReader
+}
+
 func main() {
-	app := &cli.App{
+	err := newApp().Run(os.Args)
+	if err != nil {
+		log.Print(err)
+		os.Exit(exitCode(err))
+	}
+}
+
+// newApp returns the command with its flags
+func newApp() *cli.App {
+	return &cli.App{
 		Name:  "doc2md",
-		Usage: "Convert documents (PDF, DOCX, XLSX, PPTX) to Markdown",
+		Usage: "Convert documents (PDF, DOCX, XLSX, PPTX, ODP, ODS, EML, MSG, LaTeX, DjVu, Jupyter, iWork, plain text) and images to Markdown",
+		// Replace rules are regular expressions and may contain commas
+		DisableSliceFlagSeparator: true,
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:    "output",
 				Aliases: []string{"o"},
 				Usage:   "Output file or directory",
 			},
+			&cli.StringFlag{
+				Name:  "output-template",
+				Usage: "File name pattern used when the output is a directory, with {name}, {ext}, {date} and {parent} placeholders (e.g. {date}-{name}.md)",
+			},
+			&cli.StringFlag{
+				Name:  "template",
+				Usage: "Markdown template wrapping each output, with {{ .Content }}, {{ .Title }}, {{ .Subtitle }}, {{ .Author }}, {{ .Date }}, {{ .Tags }} and {{ .Source }} placeholders",
+			},
 			&cli.StringFlag{
 				Name:    "assets-dir",
 				Aliases: []string{"a"},
 				Value:   "assets",
 				Usage:   "Directory for extracted assets",
 			},
+			&cli.StringFlag{
+				Name:  "assets-naming",
+				Value: utils.AssetNamingPositional,
+				Usage: "Asset file names: positional (document and place), hash (content digest) or sequential (asset1, asset2, ...)",
+			},
+			&cli.BoolFlag{
+				Name:    "recursive",
+				Aliases: []string{"r"},
+				Usage:   "Convert the supported documents found under directory inputs",
+			},
+			&cli.StringFlag{
+				Name:  "output-dir-structure",
+				Value: structureMirror,
+				Usage: "Layout of the outputs of --recursive: mirror the input directories under --output, or flat, numbering outputs whose names collide",
+			},
+			&cli.BoolFlag{
+				Name:    "watch",
+				Aliases: []string{"w"},
+				Usage:   "Keep running and reconvert inputs whenever they change",
+			},
+			&cli.StringFlag{
+				Name:  "since",
+				Usage: "Only convert inputs modified within a duration (e.g. 24h) or since an RFC 3339 timestamp",
+			},
 			&cli.BoolFlag{
 				Name:    "verbose",
 				Aliases: []string{"v"},
 				Usage:   "Enable verbose output",
 			},
+			&cli.IntFlag{
+				Name:    "jobs",
+				Aliases: []string{"j"},
+				Value:   1,
+				Usage:   "Number of PDF pages converted concurrently",
+			},
+			&cli.BoolFlag{
+				Name:  "auto-tags",
+				Usage: "Derive front matter tags from frequent terms when the document has no keywords",
+			},
+			&cli.BoolFlag{
+				Name:  "use-tags",
+				Usage: "Use the structure tree of tagged PDFs instead of layout heuristics",
+			},
+			&cli.StringFlag{
+				Name:  "image-format",
+				Value: "original",
+				Usage: "Format of extracted images: original, png, jpeg or none",
+			},
+			&cli.StringFlag{
+				Name:  "preset",
+				Usage: "Tune heuristics for speed or accuracy: fast, balanced or accurate",
+			},
+			&cli.StringFlag{
+				Name:  "dialect",
+				Value: "gfm",
+				Usage: "Markdown dialect to target: commonmark, gfm, pandoc or obsidian",
+			},
+			&cli.BoolFlag{
+				Name:  "preserve-colors",
+				Usage: "Keep highlight and text colors as inline HTML styles",
+			},
+			&cli.BoolFlag{
+				Name:  "keep-font-info",
+				Usage: "Wrap each PDF text run in an HTML span with data-font and data-size attributes, for debugging",
+			},
+			&cli.BoolFlag{
+				Name:  "underline-as-emphasis",
+				Usage: "Render underlined text as emphasis instead of <u> HTML",
+			},
+			&cli.BoolFlag{
+				Name:  "kv-pairs",
+				Usage: "Render two-column label/value lines of PDFs as **Label:** value pairs instead of tables",
+			},
+			&cli.BoolFlag{
+				Name:  "keep-page-numbers",
+				Usage: "Keep page numbers found in PDF headers and footers as HTML comments",
+			},
+			&cli.BoolFlag{
+				Name:  "drop-source-toc",
+				Usage: "Leave out table of contents entries of PDFs instead of rendering them as links",
+			},
+			&cli.BoolFlag{
+				Name:  "preserve-empty-cells",
+				Usage: "Align cells of borderless PDF tables to their columns, keeping empty cells in place",
+			},
+			&cli.BoolFlag{
+				Name:  "anchor-links",
+				Usage: "Turn internal PDF links into links to the heading they point at",
+			},
+			&cli.IntFlag{
+				Name:  "max-heading-level",
+				Usage: "Demote PDF headings deeper than this level to it (1-6)",
+			},
+			&cli.IntFlag{
+				Name:  "max-rows",
+				Usage: "Write at most this many rows of each spreadsheet sheet (XLSX and ODS), the header included",
+			},
+			&cli.IntFlag{
+				Name:  "max-cols",
+				Usage: "Write at most this many columns of each spreadsheet sheet (XLSX and ODS)",
+			},
+			&cli.BoolFlag{
+				Name:  "truncation-marker",
+				Usage: "Note the rows and columns left out by --max-rows and --max-cols below each table, as in \"… (N more rows)\"",
+			},
+			&cli.BoolFlag{
+				Name:  "strip-watermarks",
+				Usage: "Leave out PDF watermark text, such as a large diagonal or faded DRAFT",
+			},
+			&cli.BoolFlag{
+				Name:  "notes-appendix",
+				Usage: "Collect PDF comments and annotation notes into a Notes section at the end, linked back to where they are attached",
+			},
+			&cli.StringFlag{
+				Name:  "detect-lists-aggressiveness",
+				Value: "medium",
+				Usage: "How readily PDF lines are taken as list items: low (no dash or letter markers), medium or high (also markers set against their text)",
+			},
+			&cli.BoolFlag{
+				Name:  "dehyphenate",
+				Usage: "Rejoin words hyphenated at the end of PDF lines",
+			},
+			&cli.BoolFlag{
+				Name:  "detect-columns",
+				Usage: "Read PDF pages set in two columns column by column instead of across the gutter",
+			},
+			&cli.StringFlag{
+				Name:  "report",
+				Usage: "Write the classification, font size and position of each line of PDF pages to this JSON file, for debugging structure detection",
+			},
+			&cli.BoolFlag{
+				Name:  "strip-code-line-numbers",
+				Usage: "Render PDF code listings set in a monospaced font as fenced code, leaving out the line numbers printed in their gutter",
+			},
+			&cli.BoolFlag{
+				Name:  "preserve-tabs",
+				Usage: "Render PDF text aligned at tab stops outside bordered tables as fenced code with literal tabs",
+			},
+			&cli.BoolFlag{
+				Name:  "preserve-cell-spacing",
+				Usage: "Keep the leading, trailing and repeated spaces of PDF table cells as non-breaking spaces; cell text loses its inline formatting",
+			},
+			&cli.BoolFlag{
+				Name:  "gap-breaks",
+				Usage: "Mark large vertical gaps between blocks of PDF text with a --- thematic break",
+			},
+			&cli.Float64Flag{
+				Name:  "gap-threshold",
+				Value: 3,
+				Usage: "Gap that --gap-breaks marks, in multiples of the page's line spacing",
+			},
+			&cli.BoolFlag{
+				Name:  "score-headings",
+				Usage: "Detect PDF headings by a score weighing size, boldness, centering, capitals, isolation and numbering",
+			},
+			&cli.Float64Flag{
+				Name:  "heading-threshold",
+				Value: 0.5,
+				Usage: "Score at which --score-headings takes a line as a heading",
+			},
+			&cli.BoolFlag{
+				Name:  "ocr",
+				Usage: "Use the text tesseract recognizes in DOCX and PPTX pictures without a description as their alt text, and in DjVu pages without a text layer",
+			},
+			&cli.BoolFlag{
+				Name:  "show-revisions",
+				Usage: "Mark tracked DOCX insertions as ++text++ and deletions as ~~text~~, with comments shown inline",
+			},
+			&cli.BoolFlag{
+				Name:  "strip-comments",
+				Usage: "Leave out the comments shown with --show-revisions",
+			},
+			&cli.BoolFlag{
+				Name:  "include-comments",
+				Usage: "Render DOCX comment threads as footnotes holding each comment and reply in order with its author",
+			},
+			&cli.BoolFlag{
+				Name:  "flatten-tables",
+				Usage: "Render each table row as \"**Header:** value\" pairs instead of a pipe table",
+			},
+			&cli.BoolFlag{
+				Name:  "keep-latex-commands",
+				Usage: "Pass LaTeX commands without a Markdown equivalent through instead of stripping them",
+			},
+			&cli.BoolFlag{
+				Name:  "summary-only",
+				Usage: "Emit only the heading outline of each document",
+			},
+			&cli.StringFlag{
+				Name:  "bullet-marker",
+				Value: "-",
+				Usage: "Marker for bullet list items: -, * or +",
+			},
+			&cli.StringFlag{
+				Name:  "ordered-style",
+				Value: ".",
+				Usage: "Delimiter after ordered list numbers: . or )",
+			},
+			&cli.StringFlag{
+				Name:  "soft-break-mode",
+				Value: "space",
+				Usage: "How line breaks inside paragraphs of text and LaTeX files are rendered: space, break or newline",
+			},
+			&cli.BoolFlag{
+				Name:  "math",
+				Usage: "Render text in math fonts as inline LaTeX",
+			},
+			&cli.StringFlag{
+				Name:  "layers",
+				Usage: "PDF layers to show as a comma-separated list of names, 'all', or 'list' to print the layers of each input",
+			},
+			&cli.BoolFlag{
+				Name:  "no-page-breaks",
+				Usage: "Produce continuous output without page separators, joining paragraphs split across pages",
+			},
+			&cli.BoolFlag{
+				Name:  "merge-tables",
+				Usage: "Join PDF tables split across pages into one table, dropping the repeated header",
+			},
+			&cli.BoolFlag{
+				Name:  "convert-attachments",
+				Usage: "Also convert supported files embedded in PDFs and link the results",
+			},
+			&cli.BoolFlag{
+				Name:  "merge-portfolio",
+				Usage: "Convert the documents of a PDF portfolio into one section each",
+			},
+			&cli.BoolFlag{
+				Name:  "merge",
+				Usage: "Combine all inputs into one Markdown file with a section per input",
+			},
+			&cli.BoolFlag{
+				Name:  "trim-whitespace",
+				Usage: "Trim trailing whitespace, collapse repeated spaces and trim table cells",
+			},
+			&cli.BoolFlag{
+				Name:  "first-heading-as-title",
+				Usage: "Make the first heading the only H1 and the front matter title, moving the other headings below it",
+			},
+			&cli.BoolFlag{
+				Name:  "number-headings",
+				Usage: "Number headings by their place in the hierarchy (1., 1.1, 1.1.1), replacing the source numbering",
+			},
+			&cli.BoolFlag{
+				Name:  "title-case",
+				Usage: "Render headings in Title Case, keeping short stopwords and all-caps words as they are",
+			},
+			&cli.BoolFlag{
+				Name:  "sentence-case",
+				Usage: "Render headings in Sentence case, keeping all-caps words as they are",
+			},
+			&cli.IntFlag{
+				Name:  "flatten-headings-to-bold",
+				Usage: "Render headings deeper than this level as bold paragraphs (0 flattens every heading)",
+			},
+			&cli.BoolFlag{
+				Name:  "reference-links",
+				Usage: "Write links as numbered references collected at the end of the document",
+			},
+			&cli.BoolFlag{
+				Name:  "normalize-quotes",
+				Usage: "Replace curly quotes with straight quotes and em/en dashes with --/-",
+			},
+			&cli.BoolFlag{
+				Name:  "smart-quotes",
+				Usage: "Replace straight quotes with curly quotes and --/spaced - with em/en dashes",
+			},
+			&cli.BoolFlag{
+				Name:  "include-metadata-table",
+				Usage: "Add a table of the document's title, author, page count and creation date, a lighter alternative to front matter; not read for standard input",
+			},
+			&cli.StringFlag{
+				Name:  "metadata-table-position",
+				Value: transform.MetadataTop,
+				Usage: "Where --include-metadata-table puts the table: top or bottom",
+			},
+			&cli.BoolFlag{
+				Name:  "ascii",
+				Usage: "Transliterate the output to ASCII, such as café to cafe and curly quotes to straight quotes",
+			},
+			&cli.StringFlag{
+				Name:  "ascii-unmapped",
+				Value: transform.UnmappedReplace,
+				Usage: "Characters --ascii has no equivalent for: replace (with ?) or drop",
+			},
+			&cli.IntFlag{
+				Name:  "chunk-size",
+				Usage: "Split the output into name.partN.md files of at most N characters (or tokens), at block boundaries",
+			},
+			&cli.StringFlag{
+				Name:  "chunk-unit",
+				Value: transform.ChunkChars,
+				Usage: "Unit of --chunk-size: chars or tokens (estimated at 4 characters each)",
+			},
+			&cli.BoolFlag{
+				Name:  "fail-on-empty",
+				Usage: "Fail an input whose Markdown is empty or whitespace only instead of writing a blank file",
+			},
+			&cli.StringFlag{
+				Name:  "user-agent",
+				Value: remote.DefaultUserAgent,
+				Usage: "User-Agent sent when downloading http(s) inputs",
+			},
+			&cli.StringSliceFlag{
+				Name:  "replace",
+				Usage: "Regex substitution '/pattern/replacement/' applied to the output after every other pass, --template and --ascii included (repeatable, applied in order)",
+			},
 		},
 		Action: func(c *cli.Context) error {
 			if c.NArg() == 0 {
@@ -41,59 +390,466 @@ func main() {
 			outputOption := c.String("output")
 			assetsDir := c.String("assets-dir")
 			verbose := c.Bool("verbose")
+			opts, err := readOptions(c)
+			if err != nil {
+				return err
+			}
+			if verbose {
+				opts.Logf = log.Printf
+			}
+			var report *structureReport
+			if path := c.String("report"); path != "" {
+				report = &structureReport{path: path}
+				opts.Report = report.add
+			}
+			if layers := c.String("layers"); layers == "list" {
+				return listLayers(c.Args().Slice())
+			} else if layers != "" {
+				opts.Layers = strings.Split(layers, ",")
+			}
+			if err := opts.Validate(); err != nil {
+				return err
+			}
+			namer, err := utils.NewAssetNamer(c.String("assets-naming"))
+			if err != nil {
+				return err
+			}
+			opts.AssetNamer = namer
+			if opts.OCR && !ocr.Available() {
+				log.Printf("%s not found, pictures are converted without recognized text", ocr.Command)
+			}
+			if err := utils.ValidateOutputTemplate(c.String("output-template")); err != nil {
+				return err
+			}
+
+			out := outputOptions{
+				Template:    c.String("output-template"),
+				ChunkSize:   c.Int("chunk-size"),
+				ChunkUnit:   c.String("chunk-unit"),
+				FailOnEmpty: c.Bool("fail-on-empty"),
+			}
+			if c.Bool("include-metadata-table") {
+				out.ReadMetadata = converter.ExtractMetadata
+			}
+			if out.ChunkUnit != transform.ChunkChars && out.ChunkUnit != transform.ChunkTokens {
+				return fmt.Errorf("unsupported chunk unit: %s (expected chars or tokens)", out.ChunkUnit)
+			}
+			if c.Bool("include-comments") && c.Bool("strip-comments") {
+				return fmt.Errorf("--include-comments and --strip-comments cannot be combined")
+			}
+			if out.Transforms, err = readTransforms(c); err != nil {
+				return err
+			}
 
 			// Create assets directory
 			if err := utils.EnsureDir(assetsDir); err != nil {
 				return fmt.Errorf("failed to create assets directory: %v", err)
 			}
 
-			// Process each input file
-			for _, inputPath := range c.Args().Slice() {
-				if verbose {
-					log.Printf("Processing: %s", inputPath)
+			fetcher := &remote.Fetcher{UserAgent: c.String("user-agent")}
+
+			filter := inputFilter{Recursive: c.Bool("recursive")}
+			if filter.Recursive {
+				if out.Layout, err = newOutputLayout(c.String("output-dir-structure"), c.Args().Slice()); err != nil {
+					return err
+				}
+			}
+			if since := c.String("since"); since != "" {
+				t, err := parseSince(since, time.Now())
+				if err != nil {
+					return err
 				}
+				filter.Since = t
+			}
+			inputs, err := expandInputs(c.Args().Slice(), filter)
+			if err != nil {
+				return err
+			}
 
-				if err := convertFile(inputPath, outputOption, assetsDir, verbose); err != nil {
-					return fmt.Errorf("failed to convert %s: %v", inputPath, err)
+			convertInputs := func(changed, all []string) error {
+				if c.Bool("merge") {
+					return mergeFiles(all, outputOption, opts, out, fetcher, verbose)
 				}
 
-				if verbose {
-					log.Printf("Successfully converted: %s", inputPath)
+				// Process each input file, carrying on past failures
+				var failures []error
+				for _, inputPath := range changed {
+					if verbose {
+						log.Printf("Processing: %s", inputPath)
+					}
+
+					if err := convertFile(inputPath, outputOption, opts, out, fetcher, verbose); err != nil {
+						failures = append(failures, fmt.Errorf("failed to convert %s: %w", inputPath, err))
+						continue
+					}
+
+					if verbose {
+						log.Printf("Successfully converted: %s", inputPath)
+					}
+				}
+				return batchResult(failures, len(changed))
+			}
+			// The report covers every input converted so far
+			convert := func(changed, all []string) error {
+				err := convertInputs(changed, all)
+				if report != nil {
+					if reportErr := report.write(); reportErr != nil && err == nil {
+						err = reportErr
+					}
 				}
+				return err
 			}
 
-			return nil
+			err = convert(inputs, inputs)
+			if c.Bool("watch") {
+				// Keep watching so the next save can fix the input
+				if err != nil {
+					log.Printf("Conversion failed: %v", err)
+				}
+				return watchInputs(c.Args().Slice(), filter, convert)
+			}
+			return err
 		},
 	}
+}
+
+// readOptions reads the converter options from the flags, applying --preset
+// under the flags set explicitly
+func readOptions(c *cli.Context) (converter.Options, error) {
+	opts := converter.Options{
+		AssetsDir:            c.String("assets-dir"),
+		AutoTags:             c.Bool("auto-tags"),
+		UseTags:              c.Bool("use-tags"),
+		ImageFormat:          c.String("image-format"),
+		Dialect:              c.String("dialect"),
+		PreserveColors:       c.Bool("preserve-colors"),
+		KeepFontInfo:         c.Bool("keep-font-info"),
+		SummaryOnly:          c.Bool("summary-only"),
+		BulletMarker:         c.String("bullet-marker"),
+		OrderedStyle:         c.String("ordered-style"),
+		SoftBreakMode:        c.String("soft-break-mode"),
+		Math:                 c.Bool("math"),
+		NoPageBreaks:         c.Bool("no-page-breaks"),
+		MergeTables:          c.Bool("merge-tables"),
+		UnderlineEmphasis:    c.Bool("underline-as-emphasis"),
+		ConvertAttachments:   c.Bool("convert-attachments"),
+		MergePortfolio:       c.Bool("merge-portfolio"),
+		KVPairs:              c.Bool("kv-pairs"),
+		KeepPageNumbers:      c.Bool("keep-page-numbers"),
+		DropSourceTOC:        c.Bool("drop-source-toc"),
+		Jobs:                 c.Int("jobs"),
+		PreserveEmptyCells:   c.Bool("preserve-empty-cells"),
+		KeepLaTeXCommands:    c.Bool("keep-latex-commands"),
+		FlattenTables:        c.Bool("flatten-tables"),
+		AnchorLinks:          c.Bool("anchor-links"),
+		MaxHeadingLevel:      c.Int("max-heading-level"),
+		StripWatermarks:      c.Bool("strip-watermarks"),
+		GapBreaks:            c.Bool("gap-breaks"),
+		GapBreakThreshold:    c.Float64("gap-threshold"),
+		ScoreHeadings:        c.Bool("score-headings"),
+		HeadingThreshold:     c.Float64("heading-threshold"),
+		NotesAppendix:        c.Bool("notes-appendix"),
+		StripCodeLineNumbers: c.Bool("strip-code-line-numbers"),
+		PreserveTabs:         c.Bool("preserve-tabs"),
+		PreserveCellSpacing:  c.Bool("preserve-cell-spacing"),
+		ListDetection:        c.String("detect-lists-aggressiveness"),
+		Dehyphenate:          c.Bool("dehyphenate"),
+		DetectColumns:        c.Bool("detect-columns"),
+		MaxRows:              c.Int("max-rows"),
+		MaxCols:              c.Int("max-cols"),
+		TruncationMarker:     c.Bool("truncation-marker"),
+		OCR:                  c.Bool("ocr"),
+		ShowRevisions:        c.Bool("show-revisions"),
+		StripComments:        c.Bool("strip-comments"),
+		IncludeComments:      c.Bool("include-comments"),
+	}
+	if preset := c.String("preset"); preset != "" {
+		explicit := opts
+		if err := opts.ApplyPreset(preset); err != nil {
+			return converter.Options{}, err
+		}
+		// Explicit flags override the preset
+		for flag, restore := range map[string]func(){
+			"image-format":      func() { opts.ImageFormat = explicit.ImageFormat },
+			"use-tags":          func() { opts.UseTags = explicit.UseTags },
+			"ocr":               func() { opts.OCR = explicit.OCR },
+			"dehyphenate":       func() { opts.Dehyphenate = explicit.Dehyphenate },
+			"detect-columns":    func() { opts.DetectColumns = explicit.DetectColumns },
+			"score-headings":    func() { opts.ScoreHeadings = explicit.ScoreHeadings },
+			"heading-threshold": func() { opts.HeadingThreshold = explicit.HeadingThreshold },
+		} {
+			if c.IsSet(flag) {
+				restore()
+			}
+		}
+	}
+	return opts, nil
+}
 
-	err := app.Run(os.Args)
+// readTransforms composes the post-processing passes selected by the flags.
+// --replace runs last, so its rules match the Markdown as it is written.
+func readTransforms(c *cli.Context) (transform.Pipeline, error) {
+	var pipeline transform.Pipeline
+	if c.Bool("normalize-quotes") && c.Bool("smart-quotes") {
+		return transform.Pipeline{}, fmt.Errorf("--normalize-quotes and --smart-quotes cannot be combined")
+	}
+	unmapped, err := transform.ParseUnmapped(c.String("ascii-unmapped"))
 	if err != nil {
-		log.Fatal(err)
+		return transform.Pipeline{}, err
+	}
+	metadataPosition, err := transform.ParseMetadataPosition(c.String("metadata-table-position"))
+	if err != nil {
+		return transform.Pipeline{}, err
+	}
+	if c.Bool("title-case") && c.Bool("sentence-case") {
+		return transform.Pipeline{}, fmt.Errorf("--title-case and --sentence-case cannot be combined")
+	}
+	// Outlines are taken first, so the passes after them see only headings
+	if c.Bool("summary-only") {
+		pipeline.Register(transform.Text(transform.Outline))
 	}
+	if c.Bool("normalize-quotes") {
+		pipeline.Register(transform.Text(transform.NormalizeQuotes))
+	}
+	if c.Bool("smart-quotes") {
+		pipeline.Register(transform.Text(transform.SmartQuotes))
+	}
+	if c.Bool("first-heading-as-title") {
+		pipeline.Register(transform.Text(transform.FirstHeadingAsTitle))
+	}
+	if c.Bool("number-headings") {
+		pipeline.Register(transform.Text(transform.NumberHeadings))
+	}
+	if c.Bool("title-case") {
+		pipeline.Register(transform.Text(transform.TitleCaseHeadings))
+	}
+	if c.Bool("sentence-case") {
+		pipeline.Register(transform.Text(transform.SentenceCaseHeadings))
+	}
+	if c.IsSet("flatten-headings-to-bold") {
+		keep := c.Int("flatten-headings-to-bold")
+		pipeline.Register(transform.Text(func(md string) string {
+			return transform.FlattenHeadings(md, keep)
+		}))
+	}
+	if c.Bool("reference-links") {
+		pipeline.Register(transform.Text(transform.ReferenceLinks))
+	}
+	if c.Bool("trim-whitespace") {
+		pipeline.Register(transform.Text(transform.TrimWhitespace))
+	}
+	if c.Bool("include-metadata-table") {
+		pipeline.Register(transform.MetadataTable(metadataPosition, converter.ExtractMetadata))
+	}
+	if path := c.String("template"); path != "" {
+		tmpl, err := transform.ParseTemplate(path)
+		if err != nil {
+			return transform.Pipeline{}, err
+		}
+		pipeline.Register(transform.Template(tmpl))
+	}
+	if c.Bool("ascii") {
+		pipeline.Register(transform.Text(transform.ASCII(unmapped)))
+	}
+	var replacements []transform.Replacement
+	for _, rule := range c.StringSlice("replace") {
+		replacement, err := transform.ParseReplacement(rule)
+		if err != nil {
+			return transform.Pipeline{}, err
+		}
+		replacements = append(replacements, replacement)
+	}
+	// Replacements see the Markdown as it is written, after every other pass
+	if len(replacements) > 0 {
+		pipeline.Register(transform.Replace(replacements))
+	}
+	return pipeline, nil
 }
 
-func convertFile(inputPath, outputOption, assetsDir string, verbose bool) error {
+// listLayers prints the optional content groups of each PDF input
+func listLayers(inputPaths []string) error {
+	for _, inputPath := range inputPaths {
+		layers, err := pdf.ListLayers(inputPath)
+		if err != nil {
+			return fmt.Errorf("failed to read layers of %s: %v", inputPath, err)
+		}
+
+		fmt.Printf("%s:\n", inputPath)
+		for _, layer := range layers {
+			state := "off"
+			if layer.Visible {
+				state = "on"
+			}
+			fmt.Printf("  %s (%s)\n", layer.Name, state)
+		}
+	}
+	return nil
+}
+
+func convertFile(inputPath, outputOption string, opts converter.Options, out outputOptions, fetcher *remote.Fetcher, verbose bool) error {
+	// Determine output path
+	outputPath, err := utils.GetOutputPath(inputName(inputPath), outputOption, out.Template)
+	if err != nil {
+		return fmt.Errorf("failed to determine output path: %v", err)
+	}
+	outputPath = out.Layout.place(inputPath, outputPath, outputOption)
+
+	markdown, document, err := convertToMarkdown(inputPath, outputPath, opts, out.ReadMetadata, fetcher, verbose)
+	if err != nil {
+		return err
+	}
+
+	return writeOutput(inputPath, outputPath, markdown, document, out, verbose)
+}
+
+// mergeFiles converts every input and writes them to a single file. Each input
+// becomes an H1 section named after the file, with its own headings demoted
+// one level beneath it.
+func mergeFiles(inputPaths []string, outputOption string, opts converter.Options, out outputOptions, fetcher *remote.Fetcher, verbose bool) error {
+	outputPath, err := utils.GetOutputPath("merged", outputOption, out.Template)
+	if err != nil {
+		return fmt.Errorf("failed to determine output path: %v", err)
+	}
+
+	nested := transform.Nested(1)
+	var merged strings.Builder
+	for _, inputPath := range inputPaths {
+		if verbose {
+			log.Printf("Processing: %s", inputPath)
+		}
+
+		markdown, _, err := convertToMarkdown(inputPath, outputPath, opts, nil, fetcher, verbose)
+		if err != nil {
+			return fmt.Errorf("failed to convert %s: %w", inputPath, err)
+		}
+
+		name := inputName(inputPath)
+		title := strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))
+		body, err := nested.Apply(markdown, transform.Metadata{InputPath: inputPath, OutputPath: outputPath})
+		if err != nil {
+			return fmt.Errorf("failed to merge %s: %w", inputPath, err)
+		}
+
+		if merged.Len() > 0 {
+			merged.WriteString("\n")
+		}
+		merged.WriteString("# " + title + "\n\n")
+		merged.WriteString(strings.TrimRight(body, "\n") + "\n")
+	}
+
+	return writeOutput("", outputPath, merged.String(), nil, out, verbose)
+}
+
+// stdinInput is the input argument that reads a PDF from standard input
+const stdinInput = "-"
+
+// stdinName is the file name standard input is known by
+const stdinName = "stdin.pdf"
+
+// inputName returns the file name an input is known by, which for a URL is the
+// last element of its path
+func inputName(inputPath string) string {
+	if inputPath == stdinInput {
+		return stdinName
+	}
+	if remote.IsURL(inputPath) {
+		return remote.FileName(inputPath)
+	}
+	return inputPath
+}
+
+// convertToMarkdown converts a single input, linking assets relative to outputPath.
+// URL inputs are downloaded to a temporary file first, while a PDF on standard
+// input is converted from memory. With readMetadata, the metadata of a download
+// is read and returned as well, since its copy is gone once the conversion ends.
+func convertToMarkdown(inputPath, outputPath string, opts converter.Options, readMetadata transform.MetadataReader, fetcher *remote.Fetcher, verbose bool) (string, *markdown.Metadata, error) {
+	if inputPath == stdinInput {
+		if readMetadata != nil && verbose {
+			log.Printf("Metadata table left out: standard input is not read for metadata")
+		}
+		md, err := convertStdin(outputPath, opts)
+		return md, nil, err
+	}
+	var document *markdown.Metadata
+	if remote.IsURL(inputPath) {
+		if verbose {
+			log.Printf("Downloading: %s", inputPath)
+		}
+		localPath, cleanup, err := fetcher.Fetch(inputPath)
+		if err != nil {
+			return "", nil, err
+		}
+		defer cleanup()
+		inputPath = localPath
+		if readMetadata != nil {
+			if meta, err := readMetadata(localPath); err == nil {
+				document = &meta
+			}
+		}
+	}
+
 	// Check if input file exists
 	if !utils.FileExists(inputPath) {
-		return fmt.Errorf("input file does not exist: %s", inputPath)
+		return "", nil, fmt.Errorf("%w: %s", errs.ErrNotFound, inputPath)
+	}
+
+	// Link assets relative to the output file
+	if rel, err := filepath.Rel(filepath.Dir(outputPath), opts.AssetsDir); err == nil {
+		opts.AssetsLink = rel
 	}
 
 	// Get appropriate converter
-	conv, fileType, err := converter.GetConverter(inputPath, assetsDir)
+	conv, fileType, err := converter.GetConverter(inputPath, opts)
 	if err != nil {
-		return err
+		return "", nil, err
 	}
 
 	if verbose {
 		log.Printf("Detected file type: %s", fileType)
 	}
 
-	// Determine output path
-	outputPath, err := utils.GetOutputPath(inputPath, outputOption)
+	// Perform conversion
+	var buf bytes.Buffer
+	if err := conv.ToMarkdown(inputPath, &buf); err != nil {
+		return "", nil, conversionError{err}
+	}
+
+	return buf.String(), document, nil
+}
+
+// convertStdin reads a PDF from standard input and converts it without
+// writing it to disk
+func convertStdin(outputPath string, opts converter.Options) (string, error) {
+	data, err := io.ReadAll(os.Stdin)
 	if err != nil {
-		return fmt.Errorf("failed to determine output path: %v", err)
+		return "", fmt.Errorf("failed to read standard input: %v", err)
+	}
+	if !bytes.Contains(data[:min(len(data), 1024)], []byte("%PDF-")) {
+		return "", fmt.Errorf("%w: standard input is not a PDF", errs.ErrUnsupportedType)
+	}
+
+	if rel, err := filepath.Rel(filepath.Dir(outputPath), opts.AssetsDir); err == nil {
+		opts.AssetsLink = rel
 	}
+	conv, _, err := converter.GetConverter(stdinName, opts)
+	if err != nil {
+		return "", err
+	}
+	rc, ok := conv.(converter.ReaderConverter)
+	if !ok {
+		return "", fmt.Errorf("%w: standard input cannot be read in memory", errs.ErrUnsupportedType)
+	}
+
+	var buf bytes.Buffer
+	if err := rc.ToMarkdownFromReader(bytes.NewReader(data), int64(len(data)), stdinName, &buf); err != nil {
+		return "", conversionError{err}
+	}
+	return buf.String(), nil
+}
 
+// writeOutput applies the output passes and writes the Markdown to outputPath.
+// document is the metadata read along with the conversion, if any.
+func writeOutput(inputPath, outputPath, md string, document *markdown.Metadata, out outputOptions, verbose bool) error {
 	// Create output directory if needed
 	if err := utils.EnsureDir(filepath.Dir(outputPath)); err != nil {
 		return fmt.Errorf("failed to create output directory: %v", err)
@@ -103,6 +859,33 @@ func convertFile(inputPath, outputOption, assetsDir string, verbose bool) error
 		log.Printf("Output: %s", outputPath)
 	}
 
-	// Perform conversion
-	return conv.ToMarkdown(inputPath, outputPath)
+	md, err := out.Transforms.Apply(md, transform.Metadata{InputPath: inputPath, OutputPath: outputPath, Document: document})
+	if err != nil {
+		return err
+	}
+	if out.FailOnEmpty && strings.TrimSpace(md) == "" {
+		return fmt.Errorf("%w: nothing to write to %s", errs.ErrEmptyOutput, outputPath)
+	}
+
+	chunks := transform.Chunk(md, out.ChunkSize, out.ChunkUnit)
+	if len(chunks) == 1 {
+		if err := os.WriteFile(outputPath, []byte(md), 0644); err != nil {
+			return fmt.Errorf("failed to write output file: %v", err)
+		}
+		return nil
+	}
+
+	// Chunks are numbered parts next to the requested output
+	base := strings.TrimSuffix(outputPath, filepath.Ext(outputPath))
+	for i, chunk := range chunks {
+		partPath := fmt.Sprintf("%s.part%d.md", base, i+1)
+		if verbose {
+			log.Printf("Output: %s", partPath)
+		}
+		if err := os.WriteFile(partPath, []byte(chunk), 0644); err != nil {
+			return fmt.Errorf("failed to write output file: %v", err)
+		}
+	}
+
+	return nil
 }
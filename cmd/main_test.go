@@ -0,0 +1,493 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/converter"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/transform"
+	"github.com/urfave/cli/v2"
+)
+
+// runOptions runs the command with args and returns the options it reads
+func runOptions(t *testing.T, args ...string) (converter.Options, error) {
+	t.Helper()
+	app := newApp()
+	var opts converter.Options
+	app.Action = func(c *cli.Context) error {
+		var err error
+		opts, err = readOptions(c)
+		return err
+	}
+	err := app.Run(append([]string{"doc2md"}, args...))
+	return opts, err
+}
+
+func TestOptionFlags(t *testing.T) {
+	tests := []struct {
+		args  []string
+		check func(converter.Options) bool
+	}{
+		{[]string{"--auto-tags"}, func(o converter.Options) bool { return o.AutoTags }},
+		{[]string{"--image-format", "png"}, func(o converter.Options) bool { return o.ImageFormat == "png" }},
+		{nil, func(o converter.Options) bool { return o.ImageFormat == "original" }},
+		{[]string{"--summary-only"}, func(o converter.Options) bool { return o.SummaryOnly }},
+		{[]string{"--math"}, func(o converter.Options) bool { return o.Math }},
+		{[]string{"--no-page-breaks"}, func(o converter.Options) bool { return o.NoPageBreaks }},
+		{[]string{"--convert-attachments"}, func(o converter.Options) bool { return o.ConvertAttachments }},
+		{[]string{"--underline-as-emphasis"}, func(o converter.Options) bool { return o.UnderlineEmphasis }},
+		{[]string{"--kv-pairs"}, func(o converter.Options) bool { return o.KVPairs }},
+		{[]string{"--keep-page-numbers"}, func(o converter.Options) bool { return o.KeepPageNumbers }},
+		{[]string{"--drop-source-toc"}, func(o converter.Options) bool { return o.DropSourceTOC }},
+		{[]string{"--preserve-colors"}, func(o converter.Options) bool { return o.PreserveColors }},
+		{[]string{"--preserve-empty-cells"}, func(o converter.Options) bool { return o.PreserveEmptyCells }},
+		{[]string{"--flatten-tables"}, func(o converter.Options) bool { return o.FlattenTables }},
+		{[]string{"--anchor-links"}, func(o converter.Options) bool { return o.AnchorLinks }},
+		{[]string{"--max-heading-level", "4"}, func(o converter.Options) bool { return o.MaxHeadingLevel == 4 }},
+		{[]string{"--show-revisions", "--strip-comments"}, func(o converter.Options) bool { return o.ShowRevisions && o.StripComments }},
+		{[]string{"--merge-portfolio"}, func(o converter.Options) bool { return o.MergePortfolio }},
+		{[]string{"--soft-break-mode", "break"}, func(o converter.Options) bool { return o.SoftBreakMode == "break" }},
+		{[]string{"--strip-watermarks"}, func(o converter.Options) bool { return o.StripWatermarks }},
+		{[]string{"--max-rows", "100"}, func(o converter.Options) bool { return o.MaxRows == 100 }},
+		{[]string{"--max-cols", "5", "--truncation-marker"}, func(o converter.Options) bool { return o.MaxCols == 5 && o.TruncationMarker }},
+		{[]string{"--gap-breaks"}, func(o converter.Options) bool { return o.GapBreaks && o.GapBreakThreshold == 3 }},
+		{[]string{"--gap-breaks", "--gap-threshold", "5"}, func(o converter.Options) bool { return o.GapBreaks && o.GapBreakThreshold == 5 }},
+		{[]string{"--notes-appendix"}, func(o converter.Options) bool { return o.NotesAppendix }},
+		{[]string{"--strip-code-line-numbers"}, func(o converter.Options) bool { return o.StripCodeLineNumbers }},
+		{[]string{"--include-comments"}, func(o converter.Options) bool { return o.IncludeComments }},
+		{[]string{"--detect-lists-aggressiveness", "low"}, func(o converter.Options) bool { return o.ListDetection == "low" }},
+		{[]string{"--merge-tables"}, func(o converter.Options) bool { return o.MergeTables }},
+		{[]string{"--keep-font-info"}, func(o converter.Options) bool { return o.KeepFontInfo }},
+		{[]string{"--score-headings", "--heading-threshold", "0.6"}, func(o converter.Options) bool { return o.ScoreHeadings && o.HeadingThreshold == 0.6 }},
+		{[]string{"--preserve-tabs"}, func(o converter.Options) bool { return o.PreserveTabs }},
+		{[]string{"--preserve-cell-spacing"}, func(o converter.Options) bool { return o.PreserveCellSpacing }},
+		{[]string{"--bullet-marker", "*", "--ordered-style", ")"}, func(o converter.Options) bool { return o.BulletMarker == "*" && o.OrderedStyle == ")" }},
+	}
+	for _, tt := range tests {
+		opts, err := runOptions(t, tt.args...)
+		if err != nil {
+			t.Fatalf("run %v: %v", tt.args, err)
+		}
+		if !tt.check(opts) {
+			t.Errorf("options for %v = %+v", tt.args, opts)
+		}
+	}
+}
+
+func TestPresetFlags(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  []string
+		check func(converter.Options) bool
+	}{
+		{
+			name:  "fast leaves images out",
+			args:  []string{"--preset", "fast"},
+			check: func(o converter.Options) bool { return o.ImageFormat == "none" && !o.OCR && !o.UseTags },
+		},
+		{
+			name: "accurate turns on the costly heuristics",
+			args: []string{"--preset", "accurate"},
+			check: func(o converter.Options) bool {
+				return o.UseTags && o.OCR && o.Dehyphenate && o.DetectColumns && o.ScoreHeadings && o.HeadingThreshold == 0.5
+			},
+		},
+		{
+			name:  "explicit flags override fast",
+			args:  []string{"--preset", "fast", "--ocr", "--image-format", "png", "--use-tags"},
+			check: func(o converter.Options) bool { return o.ImageFormat == "png" && o.OCR && o.UseTags },
+		},
+		{
+			name: "explicit flags override accurate",
+			args: []string{"--preset", "accurate", "--dehyphenate=false", "--detect-columns=false", "--score-headings=false", "--ocr=false"},
+			check: func(o converter.Options) bool {
+				return !o.Dehyphenate && !o.DetectColumns && !o.ScoreHeadings && !o.OCR && o.UseTags
+			},
+		},
+		{
+			name:  "heading threshold under accurate",
+			args:  []string{"--preset", "accurate", "--heading-threshold", "0.7"},
+			check: func(o converter.Options) bool { return o.ScoreHeadings && o.HeadingThreshold == 0.7 },
+		},
+		{
+			name: "no preset keeps the flags",
+			args: []string{"--dehyphenate"},
+			check: func(o converter.Options) bool {
+				return o.Dehyphenate && !o.DetectColumns && o.ImageFormat == "original"
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := runOptions(t, tt.args...)
+			if err != nil {
+				t.Fatalf("run %v: %v", tt.args, err)
+			}
+			if !tt.check(opts) {
+				t.Errorf("options for %v = %+v", tt.args, opts)
+			}
+		})
+	}
+}
+
+func TestPresetUnknown(t *testing.T) {
+	if _, err := runOptions(t, "--preset", "thorough"); err == nil {
+		t.Error("run --preset thorough: error = nil, want an error")
+	}
+}
+
+// runTransforms runs the command with args and applies the transforms it
+// composes to md
+func runTransforms(t *testing.T, md string, args ...string) string {
+	t.Helper()
+	app := newApp()
+	var got string
+	app.Action = func(c *cli.Context) error {
+		pipeline, err := readTransforms(c)
+		if err != nil {
+			return err
+		}
+		got, err = pipeline.Apply(md, transform.Metadata{})
+		return err
+	}
+	if err := app.Run(append([]string{"doc2md"}, args...)); err != nil {
+		t.Fatalf("run %v: %v", args, err)
+	}
+	return got
+}
+
+func TestReplaceRunsLast(t *testing.T) {
+	template := filepath.Join(t.TempDir(), "template.md")
+	if err := os.WriteFile(template, []byte("Header\n\n{{ .Content }}\nFooter\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		md   string
+		args []string
+		want string
+	}{
+		{
+			name: "rules apply in order",
+			md:   "Mail a@example.com today",
+			args: []string{"--replace", `/\S+@\S+/[email]/`, "--replace", "/today/now/"},
+			want: "Mail [email] now",
+		},
+		{
+			name: "after the template",
+			md:   "Body\n",
+			args: []string{"--template", template, "--replace", "/Header|Footer/--/"},
+			want: "--\n\nBody\n\n--\n",
+		},
+		{
+			name: "after ascii",
+			md:   "Un café",
+			args: []string{"--ascii", "--replace", "/cafe/the/"},
+			want: "Un the",
+		},
+		{
+			name: "after ascii dropping unmapped characters",
+			md:   "Tea 🍵 time",
+			args: []string{"--ascii", "--ascii-unmapped", "drop", "--replace", "/Tea  time/Coffee time/"},
+			want: "Coffee time",
+		},
+		{
+			name: "after trim whitespace",
+			md:   "a   b  ",
+			args: []string{"--trim-whitespace", "--replace", "/a b/ok/"},
+			want: "ok",
+		},
+		{
+			name: "after normalize quotes",
+			md:   "“hi”—there",
+			args: []string{"--normalize-quotes", "--replace", `/"hi"--/hello, /`},
+			want: "hello, there",
+		},
+		{
+			name: "after flattened headings",
+			md:   "## Part\n",
+			args: []string{"--flatten-headings-to-bold", "0", "--replace", `/\*\*(\w+)\*\*/__${1}__/`},
+			want: "__Part__\n",
+		},
+		{
+			name: "after reference links",
+			md:   "[Go](https://go.dev)",
+			args: []string{"--reference-links", "--replace", `/\]\[1\]/][go]/`},
+			want: "[Go][go]\n\n[1]: https://go.dev\n",
+		},
+		{
+			name: "after first heading as title",
+			md:   "## Intro\n\n## Usage\n",
+			args: []string{"--first-heading-as-title", "--replace", "/# Intro/# Guide/"},
+			want: "# Guide\n\n## Usage\n",
+		},
+		{
+			name: "after numbered headings",
+			md:   "## Intro\n\n## Usage\n",
+			args: []string{"--number-headings", "--replace", `/## 1\. /## First: /`},
+			want: "## First: Intro\n\n## 2. Usage\n",
+		},
+		{
+			name: "after title case",
+			md:   "## the state of the art\n",
+			args: []string{"--title-case", "--replace", `/of the/of The/`},
+			want: "## The State of The Art\n",
+		},
+		{
+			name: "after smart quotes",
+			md:   `Say "hi"`,
+			args: []string{"--smart-quotes", "--replace", "/“/«/"},
+			want: "Say «hi”",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := runTransforms(t, tt.md, tt.args...); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMetadataTableFlag(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "paper.pdf")
+	// The page has no text, leaving the table all there is to the output
+	info := "<< /Title (Field Notes) /Author (Ana Lima) /CreationDate (D:20240115093000Z) >>"
+	if err := os.WriteFile(input, infoPDF("0 0 1 rg 100 100 200 200 re f", info), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	const table = "| Field | Value |\n| --- | --- |\n| Title | Field Notes |\n| Author | Ana Lima |\n| Pages | 1 |\n| Created | 2024-01-15 |"
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"top", []string{"--include-metadata-table"}, table + "\n\n"},
+		{"bottom", []string{"--include-metadata-table", "--metadata-table-position", "bottom"}, "\n\n" + table + "\n"},
+		{"replace runs after the table", []string{"--include-metadata-table", "--replace", "/Ana Lima/A. Lima/"}, strings.Replace(table, "Ana Lima", "A. Lima", 1) + "\n\n"},
+		{"left out by default", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := t.TempDir()
+			args := append([]string{"doc2md", "--assets-dir", filepath.Join(out, "assets"), "-o", out}, tt.args...)
+			if err := newApp().Run(append(args, input)); err != nil {
+				t.Fatalf("run %v: %v", tt.args, err)
+			}
+			data, err := os.ReadFile(filepath.Join(out, "paper.md"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("got %q, want %q", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestMetadataTableURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(infoPDF("0 0 1 rg 100 100 200 200 re f", "<< /Title (Field Notes) >>"))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	if err := newApp().Run([]string{"doc2md", "--include-metadata-table", "--assets-dir", filepath.Join(dir, "assets"), "-o", dir, server.URL + "/paper.pdf"}); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "paper.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "| Field | Value |\n| --- | --- |\n| Title | Field Notes |\n| Pages | 1 |\n\n"; string(data) != want {
+		t.Errorf("got %q, want %q", data, want)
+	}
+}
+
+func TestSummaryOnlyFlag(t *testing.T) {
+	dir := t.TempDir()
+	inputs := map[string]string{
+		"paper.tex": "\\section{Intro}\nOne.\n\\subsection{Detail}\nTwo.\n",
+		"mail.eml":  "Subject: Plan\r\nContent-Type: text/html\r\n\r\n<h2>Goals</h2><p>Ship it.</p><pre># not a heading</pre>\r\n",
+	}
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"paper.tex", "# Intro\n\n## Detail\n"},
+		{"mail.eml", "# Plan\n\n## Goals\n"},
+	}
+	for _, tt := range tests {
+		input := filepath.Join(dir, tt.name)
+		if err := os.WriteFile(input, []byte(inputs[tt.name]), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		out := t.TempDir()
+		if err := newApp().Run([]string{"doc2md", "--summary-only", "--assets-dir", filepath.Join(out, "assets"), "-o", out, input}); err != nil {
+			t.Fatalf("%s: run error = %v", tt.name, err)
+		}
+		data, err := os.ReadFile(filepath.Join(out, strings.TrimSuffix(tt.name, filepath.Ext(tt.name))+".md"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, data, tt.want)
+		}
+	}
+}
+
+func TestTransformFlagsInvalid(t *testing.T) {
+	app := newApp()
+	app.Action = func(c *cli.Context) error {
+		_, err := readTransforms(c)
+		return err
+	}
+	for _, args := range [][]string{
+		{"--normalize-quotes", "--smart-quotes"},
+		{"--title-case", "--sentence-case"},
+		{"--ascii", "--ascii-unmapped", "keep"},
+		{"--include-metadata-table", "--metadata-table-position", "middle"},
+	} {
+		if err := app.Run(append([]string{"doc2md"}, args...)); err == nil {
+			t.Errorf("run %v: error = nil, want an error", args)
+		}
+	}
+}
+
+func TestReplaceInvalid(t *testing.T) {
+	app := newApp()
+	app.Action = func(c *cli.Context) error {
+		_, err := readTransforms(c)
+		return err
+	}
+	for _, rule := range []string{"/(/x/", "/a/b", "//x/"} {
+		if err := app.Run([]string{"doc2md", "--replace", rule}); err == nil {
+			t.Errorf("--replace %q: error = nil, want an error", rule)
+		}
+	}
+}
+
+func TestMergeDemotesHeadings(t *testing.T) {
+	dir := t.TempDir()
+	inputs := map[string]string{
+		"first.tex":  "\\section{Intro}\nOne.\n\\subsection{Detail}\nTwo.\n",
+		"second.tex": "\\section{Usage}\n\\subsubsection{Flags}\nThree.\n",
+	}
+	var paths []string
+	for _, name := range []string{"first.tex", "second.tex"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(inputs[name]), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+
+	output := filepath.Join(dir, "merged.md")
+	if err := mergeFiles(paths, output, converter.Options{}, outputOptions{}, nil, false); err != nil {
+		t.Fatalf("mergeFiles() error = %v", err)
+	}
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var headings []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.HasPrefix(line, "#") {
+			headings = append(headings, line)
+		}
+	}
+	want := []string{"# first", "## Intro", "### Detail", "# second", "## Usage", "#### Flags"}
+	if strings.Join(headings, "\n") != strings.Join(want, "\n") {
+		t.Errorf("headings = %q, want %q\n%s", headings, want, data)
+	}
+}
+
+func TestConvertURL(t *testing.T) {
+	var userAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.UserAgent()
+		w.Write([]byte("Fetched from the web.\n"))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	app := newApp()
+	if err := app.Run([]string{"doc2md", "--user-agent", "test-agent", "--assets-dir", filepath.Join(dir, "assets"), "-o", dir, server.URL + "/notes.txt"}); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "notes.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(data); got != "Fetched from the web.\n" {
+		t.Errorf("output = %q", got)
+	}
+	if userAgent != "test-agent" {
+		t.Errorf("User-Agent = %q, want test-agent", userAgent)
+	}
+}
+
+func TestOutputTemplate(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(input, []byte("Some notes.\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(dir, "out")
+	if err := os.Mkdir(out, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := newApp().Run([]string{"doc2md", "--output-template", "{ext}-{name}.md", "--assets-dir", filepath.Join(dir, "assets"), "-o", out, input}); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(out, "txt-notes.md")); err != nil {
+		t.Errorf("templated output: %v", err)
+	}
+	if err := newApp().Run([]string{"doc2md", "--output-template", "{title}.md", "--assets-dir", filepath.Join(dir, "assets"), "-o", out, input}); err == nil {
+		t.Error("run --output-template {title}.md: error = nil, want an error")
+	}
+}
+
+func TestChunkSize(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(input, []byte("First paragraph.\n\nSecond paragraph.\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := newApp().Run([]string{"doc2md", "--chunk-size", "20", "--assets-dir", filepath.Join(dir, "assets"), "-o", dir, input}); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	parts := map[string]string{"notes.part1.md": "First paragraph.\n", "notes.part2.md": "Second paragraph.\n"}
+	for name, want := range parts {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != want {
+			t.Errorf("%s = %q, want %q", name, data, want)
+		}
+	}
+	if err := newApp().Run([]string{"doc2md", "--chunk-size", "20", "--chunk-unit", "words", "-o", dir, input}); err == nil {
+		t.Error("run --chunk-unit words: error = nil, want an error")
+	}
+}
+
+func TestAssetsNamingInvalid(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(input, []byte("Some notes.\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := newApp().Run([]string{"doc2md", "--assets-naming", "random", "--assets-dir", filepath.Join(dir, "assets"), "-o", dir, input}); err == nil {
+		t.Error("run --assets-naming random: error = nil, want an error")
+	}
+	if err := newApp().Run([]string{"doc2md", "--assets-naming", "hash", "--assets-dir", filepath.Join(dir, "assets"), "-o", dir, input}); err != nil {
+		t.Errorf("run --assets-naming hash: %v", err)
+	}
+}
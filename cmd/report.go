@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/pdf"
+)
+
+// structureReport collects the line classifications of the converted PDFs
+// for --report
+type structureReport struct {
+	path      string
+	Documents []reportDocument `json:"documents"`
+}
+
+// reportDocument is the report of one input
+type reportDocument struct {
+	Source string           `json:"source"`
+	Pages  []pdf.PageReport `json:"pages"`
+}
+
+// add records the report of an input, replacing an earlier one for the same
+// input so watch mode keeps the latest
+func (r *structureReport) add(inputPath string, pages []pdf.PageReport) {
+	for i, doc := range r.Documents {
+		if doc.Source == inputPath {
+			r.Documents[i].Pages = pages
+			return
+		}
+	}
+	r.Documents = append(r.Documents, reportDocument{Source: inputPath, Pages: pages})
+}
+
+// write saves the report as indented JSON, leaving the Markdown in line
+// texts unescaped
+func (r *structureReport) write() error {
+	if r.Documents == nil {
+		r.Documents = []reportDocument{}
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(r); err != nil {
+		return fmt.Errorf("failed to encode report: %v", err)
+	}
+	if err := os.WriteFile(r.path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write report: %v", err)
+	}
+	return nil
+}
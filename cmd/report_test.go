@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/pdf"
+)
+
+func TestReportFlag(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "paper.pdf")
+	content := "BT /F1 12 Tf 72 700 Td (First line.) Tj ET\nBT /F1 12 Tf 72 682 Td (Second line.) Tj ET"
+	if err := os.WriteFile(input, onePagePDF(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	notes := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(notes, []byte("Not a PDF.\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	reportPath := filepath.Join(dir, "report.json")
+	args := []string{"doc2md", "--report", reportPath, "--assets-dir", filepath.Join(dir, "assets"), "-o", t.TempDir(), input, notes}
+	if err := newApp().Run(args); err != nil {
+		t.Fatalf("run error = %v", err)
+	}
+
+	data, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report struct {
+		Documents []struct {
+			Source string           `json:"source"`
+			Pages  []pdf.PageReport `json:"pages"`
+		} `json:"documents"`
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("report %s: %v", data, err)
+	}
+	if len(report.Documents) != 1 || report.Documents[0].Source != input {
+		t.Fatalf("report documents = %+v, want only %s", report.Documents, input)
+	}
+	pages := report.Documents[0].Pages
+	if len(pages) != 1 || len(pages[0].Lines) != 2 {
+		t.Fatalf("report pages = %+v, want one page of two lines", pages)
+	}
+	for i, y := range []float64{700, 682} {
+		if line := pages[0].Lines[i]; line.Kind != "paragraph" || line.Y != y || line.FontSize != 12 {
+			t.Errorf("line %d = %+v, want a paragraph at %g", i+1, line, y)
+		}
+	}
+}
+
+func TestStructureReportAdd(t *testing.T) {
+	r := &structureReport{}
+	r.add("a.pdf", []pdf.PageReport{{Page: 1}})
+	r.add("b.pdf", nil)
+	r.add("a.pdf", []pdf.PageReport{{Page: 1}, {Page: 2}})
+	if len(r.Documents) != 2 || len(r.Documents[0].Pages) != 2 {
+		t.Errorf("documents = %+v, want a.pdf replaced by its latest report", r.Documents)
+	}
+
+	// An empty report is written as an empty list, not null
+	r = &structureReport{path: filepath.Join(t.TempDir(), "report.json")}
+	if err := r.write(); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(r.path); string(data) != "{\n  \"documents\": []\n}\n" {
+		t.Errorf("empty report = %q", data)
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/errs"
+)
+
+// withStdin runs f with standard input reading data
+func withStdin(t *testing.T, data []byte, f func()) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		w.Write(data)
+		w.Close()
+	}()
+	stdin := os.Stdin
+	os.Stdin = r
+	defer func() {
+		os.Stdin = stdin
+		r.Close()
+	}()
+	f()
+}
+
+func TestConvertStdin(t *testing.T) {
+	// Temporary files would be created under TMPDIR
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out.md")
+
+	content := "BT /F1 12 Tf 72 700 Td (Piped text.) Tj ET"
+	withStdin(t, onePagePDF(content), func() {
+		if err := newApp().Run([]string{"doc2md", "--assets-dir", filepath.Join(dir, "assets"), "-o", out, "-"}); err != nil {
+			t.Fatalf("run - error = %v", err)
+		}
+	})
+	if data, err := os.ReadFile(out); err != nil || len(data) == 0 {
+		t.Errorf("output = %q, %v, want the converted page", data, err)
+	}
+	if entries, _ := os.ReadDir(tmp); len(entries) > 0 {
+		t.Errorf("created %s in the temporary directory, want no temporary files", entries[0].Name())
+	}
+
+	withStdin(t, []byte("Plain text, not a PDF.\n"), func() {
+		err := newApp().Run([]string{"doc2md", "--assets-dir", filepath.Join(dir, "assets"), "-o", out, "-"})
+		if !errors.Is(err, errs.ErrUnsupportedType) {
+			t.Errorf("run - with text: error = %v, want %v", err, errs.ErrUnsupportedType)
+		}
+	})
+}
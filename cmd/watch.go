@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"io/fs"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/remote"
+)
+
+// watchDebounce is how long watched inputs must stay quiet after a change
+// before they are reconverted
+const watchDebounce = 300 * time.Millisecond
+
+// fileState identifies a version of a file
+type fileState struct {
+	ModTime time.Time
+	Size    int64
+}
+
+// watchInputs waits for changes to the inputs until interrupted, calling
+// convert with the files that changed and the full input list. Changes are
+// acted on once the inputs have been quiet for watchDebounce, so editors that
+// save in several writes trigger a single reconversion. The directories
+// holding the inputs are watched rather than the files, which keeps files
+// replaced on save watched, and directories are expanded again on every
+// change, picking up files added to them.
+func watchInputs(args []string, filter inputFilter, convert func(changed, all []string) error) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return watch(ctx, args, filter, convert)
+}
+
+// watch runs watchInputs until ctx is done
+func watch(ctx context.Context, args []string, filter inputFilter, convert func(changed, all []string) error) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+	for _, dir := range watchDirs(args, filter) {
+		if err := watcher.Add(dir); err != nil {
+			log.Printf("Failed to watch %s: %v", dir, err)
+		}
+	}
+
+	converted, _ := snapshot(args, filter)
+	log.Printf("Watching %d input(s) for changes", len(converted))
+
+	debounce := time.NewTimer(watchDebounce)
+	debounce.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.Printf("Watch error: %v", err)
+			continue
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Has(fsnotify.Create) && filter.Recursive {
+				// Watch directories created inside watched ones
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					for _, dir := range subdirs(event.Name) {
+						if err := watcher.Add(dir); err != nil {
+							log.Printf("Failed to watch %s: %v", dir, err)
+						}
+					}
+				}
+			}
+			debounce.Reset(watchDebounce)
+			continue
+		case <-debounce.C:
+		}
+
+		// Events also fire for files that are not inputs, and for writes that
+		// leave a file as it was
+		current, all := snapshot(args, filter)
+		var changed []string
+		for _, path := range all {
+			if state := current[path]; state != converted[path] {
+				changed = append(changed, path)
+				converted[path] = state
+			}
+		}
+		if len(changed) == 0 {
+			continue
+		}
+
+		for _, path := range changed {
+			log.Printf("Reconverting: %s", path)
+		}
+		if err := convert(changed, all); err != nil {
+			// Keep watching so the next save can fix the input
+			log.Printf("Conversion failed: %v", err)
+		}
+	}
+}
+
+// watchDirs returns the directories to watch for changes to the inputs: the
+// directory holding each file and, with --recursive, every directory below
+// the directories given
+func watchDirs(args []string, filter inputFilter) []string {
+	seen := make(map[string]bool)
+	var dirs []string
+	add := func(dir string) {
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	for _, arg := range args {
+		if remote.IsURL(arg) {
+			continue
+		}
+		if info, err := os.Stat(arg); err == nil && info.IsDir() {
+			if filter.Recursive {
+				for _, dir := range subdirs(arg) {
+					add(dir)
+				}
+			}
+			continue
+		}
+		add(filepath.Dir(arg))
+	}
+	return dirs
+}
+
+// subdirs returns a directory and every directory below it
+func subdirs(root string) []string {
+	var dirs []string
+	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && d.IsDir() {
+			dirs = append(dirs, path)
+		}
+		return nil
+	})
+	return dirs
+}
+
+// snapshot returns the state of every local input, in input order
+func snapshot(args []string, filter inputFilter) (map[string]fileState, []string) {
+	inputs, err := expandInputs(args, filter)
+	if err != nil {
+		log.Printf("Failed to list inputs: %v", err)
+	}
+
+	states := make(map[string]fileState)
+	var paths []string
+	for _, path := range inputs {
+		if remote.IsURL(path) {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		states[path] = fileState{ModTime: info.ModTime(), Size: info.Size()}
+		paths = append(paths, path)
+	}
+	return states, paths
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "notes.txt")
+	other := filepath.Join(dir, "other.txt")
+	for _, path := range []string{input, other} {
+		if err := os.WriteFile(path, []byte("first"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var mu sync.Mutex
+	var calls [][]string
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- watch(ctx, []string{input}, inputFilter{}, func(changed, all []string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			calls = append(calls, changed)
+			return nil
+		})
+	}()
+	// Let the watcher start before changing the input
+	time.Sleep(100 * time.Millisecond)
+
+	// A save in several writes triggers one conversion, and files that are
+	// not inputs none
+	for _, content := range []string{"second", "second and third", "second, third and fourth"} {
+		if err := os.WriteFile(input, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(other, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(watchDebounce / 10)
+	}
+	time.Sleep(3 * watchDebounce)
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("watch() error = %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if want := [][]string{{input}}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("converted %v, want %v", calls, want)
+	}
+}
+
+// lockedBuffer is a buffer that the log and a test may use at once
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestWatchAfterFailedConversion(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "broken.pdf")
+	if err := os.WriteFile(input, []byte("not a PDF"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var logged lockedBuffer
+	log.SetOutput(&logged)
+	defer log.SetOutput(os.Stderr)
+
+	done := make(chan error)
+	go func() {
+		done <- newApp().Run([]string{"doc2md", "--watch", "--assets-dir", filepath.Join(dir, "assets"), "-o", dir, input})
+	}()
+	// The interrupt is caught once the watcher has started
+	for deadline := time.Now().Add(5 * time.Second); !strings.Contains(logged.String(), "Watching 1 input(s)"); {
+		if time.Now().After(deadline) {
+			t.Fatalf("watcher not started after a failed conversion:\n%s", logged.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	process, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := process.Signal(os.Interrupt); err != nil {
+		t.Skipf("cannot interrupt the watcher: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if !strings.Contains(logged.String(), "Conversion failed: failed to convert "+input) {
+		t.Errorf("initial failure not logged:\n%s", logged.String())
+	}
+}
+
+func TestWatchDirs(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "docs", "nested")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(root, "a.txt")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		args   []string
+		filter inputFilter
+		want   []string
+	}{
+		{"file", []string{file}, inputFilter{}, []string{root}},
+		{"url", []string{"https://example.com/a.pdf"}, inputFilter{}, nil},
+		{"recursive", []string{filepath.Join(root, "docs")}, inputFilter{Recursive: true},
+			[]string{filepath.Join(root, "docs"), nested}},
+		{"duplicate", []string{file, filepath.Join(root, "b.txt")}, inputFilter{}, []string{root}},
+	}
+	for _, tt := range tests {
+		if got := watchDirs(tt.args, tt.filter); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: watchDirs() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package converter
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/utils"
+)
+
+// writePDF writes a PDF of pages each showing a line of text
+func writePDF(t testing.TB, pages int) string {
+	t.Helper()
+	objects := []string{"<< /Type /Catalog /Pages 2 0 R >>", "", "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>"}
+	var kids []string
+	for i := 1; i <= pages; i++ {
+		content := fmt.Sprintf("BT /F1 12 Tf 72 700 Td (Text of page %d.) Tj ET", i)
+		objects = append(objects, fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content))
+		objects = append(objects, fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>", len(objects)))
+		kids = append(kids, fmt.Sprintf("%d 0 R", len(objects)))
+	}
+	objects[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), pages)
+
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.7\n")
+	offsets := make([]int, len(objects))
+	for i, body := range objects {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, body)
+	}
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	path := filepath.Join(t.TempDir(), "test.pdf")
+	if err := os.WriteFile(path, b.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// writeFile writes a file of a name into a temporary directory
+func writeFile(t testing.TB, name, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// concurrentInputs returns inputs of several formats
+func concurrentInputs(t testing.TB) []string {
+	return []string{
+		writePDF(t, 8),
+		writeDocx(t, "<w:p><w:r><w:t>Intro</w:t></w:r></w:p>"+docxTable([]string{"Name", "Qty"}, []string{"Apples", "3"})),
+		writeFile(t, "notes.txt", "Plain notes\nover two lines.\n\nNext paragraph.\n"),
+		writeFile(t, "paper.tex", "\\section{Intro}\nSome \\emph{text}.\n\\begin{itemize}\n\\item one\n\\end{itemize}\n"),
+		writeFile(t, "mail.eml", "From: ana@example.com\r\nSubject: Hello\r\nContent-Type: text/plain\r\n\r\nBody of the mail.\r\n"),
+	}
+}
+
+// convertFile converts an input through GetConverter
+func convertFile(t testing.TB, path string, opts Options) string {
+	conv, _, err := GetConverter(path, opts)
+	if err != nil {
+		t.Errorf("GetConverter(%s) error = %v", path, err)
+		return ""
+	}
+	var out bytes.Buffer
+	if err := conv.ToMarkdown(path, &out); err != nil {
+		t.Errorf("ToMarkdown(%s) error = %v", path, err)
+	}
+	return out.String()
+}
+
+// TestConcurrentConversion converts several inputs from many goroutines at
+// once through shared options and converters; run it with -race
+func TestConcurrentConversion(t *testing.T) {
+	inputs := concurrentInputs(t)
+	opts := Options{AssetsDir: t.TempDir(), Jobs: 4}
+	want := make(map[string]string)
+	for _, path := range inputs {
+		if want[path] = convertFile(t, path, opts); want[path] == "" {
+			t.Fatalf("%s converted to no output", filepath.Base(path))
+		}
+	}
+
+	namer, _ := utils.NewAssetNamer(utils.AssetNamingSequential)
+	opts.AssetNamer = namer
+	shared := make(map[string]Converter)
+	for _, path := range inputs {
+		conv, _, err := GetConverter(path, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		shared[path] = conv
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		for _, path := range inputs {
+			wg.Add(2)
+			// A converter of its own and one shared with other goroutines
+			go func() {
+				defer wg.Done()
+				if got := convertFile(t, path, opts); got != want[path] {
+					t.Errorf("%s: got %q, want %q", filepath.Base(path), got, want[path])
+				}
+			}()
+			go func() {
+				defer wg.Done()
+				var out bytes.Buffer
+				if err := shared[path].ToMarkdown(path, &out); err != nil || out.String() != want[path] {
+					t.Errorf("%s with a shared converter: got %q, %v, want %q", filepath.Base(path), out.String(), err, want[path])
+				}
+			}()
+		}
+	}
+	wg.Wait()
+}
+
+func BenchmarkConcurrentConversion(b *testing.B) {
+	inputs := concurrentInputs(b)
+	opts := Options{AssetsDir: b.TempDir()}
+	b.RunParallel(func(pb *testing.PB) {
+		for i := 0; pb.Next(); i++ {
+			convertFile(b, inputs[i%len(inputs)], opts)
+		}
+	})
+}
@@ -2,14 +2,42 @@ package converter
 
 import (
 	"fmt"
+	"io"
 	"path/filepath"
 	"strings"
 
+	"github.com/leandrowiemesfilho/markdown-converter/internal/djvu"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/docx"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/email"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/errs"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/imagefile"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/ipynb"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/latex"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/odf"
 	"github.com/leandrowiemesfilho/markdown-converter/internal/pdf"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/plaintext"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/pptx"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/preview"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/utils"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/xlsx"
 )
 
+// Converter converts a document into Markdown written to w. Converters hold
+// only their configuration: every call opens its own reader on the input and
+// keeps its parsing state to itself, so one converter may convert distinct
+// inputs from several goroutines at once. Assets shared between calls are
+// named through an AssetNamer, which is safe for concurrent use.
 type Converter interface {
-	ToMarkdown(inputPath, outputPath string) error
+	ToMarkdown(inputPath string, w io.Writer) error
+}
+
+// ReaderConverter is a Converter that can also read a document held in
+// memory, or any other io.ReaderAt, without a file on disk. name stands in
+// for the file name where the output refers to its source.
+type ReaderConverter interface {
+	Converter
+	ToMarkdownFromReader(r io.ReaderAt, size int64, name string, w io.Writer) error
 }
 
 // FileType represents supported file types
@@ -20,15 +48,352 @@ const (
 	DOCX FileType = "docx"
 	XLSX FileType = "xlsx"
 	PPTX FileType = "pptx"
+	EML  FileType = "eml"
+	MSG  FileType = "msg"
+	TeX  FileType = "tex"
+	ODP  FileType = "odp"
+	ODS  FileType = "ods"
+	Text FileType = "txt"
+	DjVu FileType = "djvu"
+	// Notebook is a Jupyter notebook
+	Notebook FileType = "ipynb"
+	// IWork is a Pages, Keynote or Numbers package, read by its PDF preview
+	IWork FileType = "iwork"
+	// Image covers standalone PNG, JPEG and GIF files
+	Image FileType = "image"
 )
 
+// Options configures the converters returned by GetConverter
+type Options struct {
+	AssetsDir string
+	// AssetsLink is the path prefix used to reference assets from the Markdown
+	AssetsLink string
+	// AssetNamer names extracted assets, shared by every document of a run
+	AssetNamer     *utils.AssetNamer
+	AutoTags       bool
+	UseTags        bool
+	ImageFormat    string
+	Dialect        string
+	PreserveColors bool
+	// SummaryOnly leaves the body text of PDF pages unclassified; the outline
+	// of every format is taken by transform.Outline
+	SummaryOnly  bool
+	BulletMarker string
+	OrderedStyle string
+	Math         bool
+	// KeepFontInfo wraps each PDF text run in a span naming its font and size
+	KeepFontInfo bool
+	// Layers names the PDF layers to show instead of the default visibility
+	Layers       []string
+	NoPageBreaks bool
+	// MergeTables joins a PDF table continued at the top of the next page to
+	// the table ending the page before it
+	MergeTables bool
+	// UnderlineEmphasis renders underlined text as emphasis instead of HTML
+	UnderlineEmphasis bool
+	// ConvertAttachments converts supported files embedded in a document
+	ConvertAttachments bool
+	// MergePortfolio renders the documents of a PDF portfolio as sections of its Markdown
+	MergePortfolio bool
+	// KVPairs renders label/value lines of PDFs as bold label pairs instead of tables
+	KVPairs bool
+	// KeepPageNumbers records running page numbers of PDFs as HTML comments
+	KeepPageNumbers bool
+	// DropSourceTOC leaves out the table of contents pages of PDFs
+	DropSourceTOC bool
+	// Jobs is the number of PDF pages converted concurrently
+	Jobs int
+	// PreserveEmptyCells keeps empty cells of borderless PDF tables in their column
+	PreserveEmptyCells bool
+	// KeepLaTeXCommands passes LaTeX commands without a Markdown equivalent through verbatim
+	KeepLaTeXCommands bool
+	// FlattenTables renders table rows as "**Header:** value" pairs instead of pipe tables
+	FlattenTables bool
+	// AnchorLinks resolves internal PDF links to links to the heading they point at
+	AnchorLinks bool
+	// MaxHeadingLevel is the deepest heading level of PDFs, zero for no cap
+	MaxHeadingLevel int
+	// StripWatermarks leaves out the watermark text of PDFs
+	StripWatermarks bool
+	// GapBreaks marks large vertical gaps between blocks of PDF text with a
+	// thematic break
+	GapBreaks bool
+	// GapBreakThreshold is the gap, in multiples of the line spacing, that
+	// GapBreaks marks
+	GapBreakThreshold float64
+	// ScoreHeadings detects PDF headings by a score weighing their size,
+	// boldness, centering, capitals, isolation and numbering
+	ScoreHeadings bool
+	// HeadingThreshold is the score at which ScoreHeadings takes a line as a
+	// heading
+	HeadingThreshold float64
+	// NotesAppendix collects PDF comments into a "Notes" section at the end
+	NotesAppendix bool
+	// ListDetection is how readily PDF lines are taken as list items: low,
+	// medium or high
+	ListDetection string
+	// StripCodeLineNumbers renders PDF code listings as fenced code without
+	// their printed line numbers
+	StripCodeLineNumbers bool
+	// PreserveTabs renders PDF text aligned at tab stops as fenced code with
+	// literal tabs
+	PreserveTabs bool
+	// PreserveCellSpacing keeps the leading, trailing and repeated spaces of
+	// PDF table cells, escaped as non-breaking spaces
+	PreserveCellSpacing bool
+	// Dehyphenate rejoins words hyphenated at the end of PDF lines
+	Dehyphenate bool
+	// DetectColumns reads PDF pages set in two columns column by column
+	DetectColumns bool
+	// OCR recognizes the text of DOCX and PPTX pictures and of DjVu pages
+	// without a text layer with tesseract, when installed
+	OCR bool
+	// ShowRevisions marks tracked changes of DOCX files and shows their comments inline
+	ShowRevisions bool
+	// StripComments leaves out the comments shown with ShowRevisions
+	StripComments bool
+	// IncludeComments renders DOCX comment threads as footnotes with their
+	// replies and authors
+	IncludeComments bool
+	// MaxRows is the number of rows written per spreadsheet sheet, zero for all
+	MaxRows int
+	// MaxCols is the number of columns written per spreadsheet sheet, zero for all
+	MaxCols int
+	// TruncationMarker notes the rows and columns left out of spreadsheet sheets
+	TruncationMarker bool
+	// Logf, when set, reports notes about the conversion such as left out rows
+	Logf func(format string, args ...interface{})
+	// Report, when set, receives the classification of the lines of each page
+	// of a PDF input, for debugging structure detection
+	Report func(inputPath string, pages []pdf.PageReport)
+	// SoftBreakMode renders the line breaks inside paragraphs of plain text and
+	// LaTeX sources as spaces, hard breaks or newlines
+	SoftBreakMode string
+	// attachmentDepth counts the enclosing documents of a converted attachment
+	attachmentDepth int
+}
+
+// Validate reports options that cannot be honored by any converter
+func (o Options) Validate() error {
+	switch o.ImageFormat {
+	case "", pdf.ImageFormatOriginal, pdf.ImageFormatPNG, pdf.ImageFormatJPEG, pdf.ImageFormatNone:
+	default:
+		return fmt.Errorf("unsupported image format: %s", o.ImageFormat)
+	}
+	switch o.ListDetection {
+	case "", pdf.ListDetectionLow, pdf.ListDetectionMedium, pdf.ListDetectionHigh:
+	default:
+		return fmt.Errorf("unsupported list detection: %s (expected low, medium or high)", o.ListDetection)
+	}
+	if _, err := markdown.ParseDialect(o.Dialect); err != nil {
+		return err
+	}
+	if _, err := markdown.ParseListStyle(o.BulletMarker, o.OrderedStyle); err != nil {
+		return err
+	}
+	if _, err := markdown.ParseSoftBreak(o.SoftBreakMode); err != nil {
+		return err
+	}
+	if o.MaxRows < 0 {
+		return fmt.Errorf("unsupported max rows: %d", o.MaxRows)
+	}
+	if o.MaxCols < 0 {
+		return fmt.Errorf("unsupported max columns: %d", o.MaxCols)
+	}
+	if o.GapBreaks && o.GapBreakThreshold <= 1 {
+		return fmt.Errorf("unsupported gap break threshold: %g (expected more than 1)", o.GapBreakThreshold)
+	}
+	if o.ScoreHeadings && o.HeadingThreshold <= 0 {
+		return fmt.Errorf("unsupported heading threshold: %g (expected more than 0)", o.HeadingThreshold)
+	}
+	if o.MaxHeadingLevel < 0 || o.MaxHeadingLevel > 6 {
+		return fmt.Errorf("unsupported max heading level: %d (expected 1 to 6)", o.MaxHeadingLevel)
+	}
+	return nil
+}
+
+// IsSupported reports whether a file has an extension GetConverter handles
+func IsSupported(filePath string) bool {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".pdf", ".docx", ".xlsx", ".pptx", ".eml", ".msg", ".tex", ".odp", ".ods", ".txt", ".djvu", ".ipynb", ".pages", ".key", ".numbers", ".png", ".jpg", ".jpeg", ".gif":
+		return true
+	}
+	return false
+}
+
 // GetConverter returns the appropriate converter based on file extension
-func GetConverter(filePath string, assetsDir string) (Converter, FileType, error) {
+func GetConverter(filePath string, opts Options) (Converter, FileType, error) {
+	dialect, err := markdown.ParseDialect(opts.Dialect)
+	if err != nil {
+		return nil, "", err
+	}
+	listStyle, err := markdown.ParseListStyle(opts.BulletMarker, opts.OrderedStyle)
+	if err != nil {
+		return nil, "", err
+	}
+	softBreak, err := markdown.ParseSoftBreak(opts.SoftBreakMode)
+	if err != nil {
+		return nil, "", err
+	}
+
 	ext := strings.ToLower(filepath.Ext(filePath))
 	switch ext {
 	case ".pdf":
-		return &pdf.Converter{AssetsDir: assetsDir}, PDF, nil
+		return newPDFConverter(filePath, opts, dialect, listStyle), PDF, nil
+	case ".ipynb":
+		return &ipynb.Converter{AssetsDir: opts.AssetsDir, AssetsLink: opts.AssetsLink, AssetNamer: opts.AssetNamer}, Notebook, nil
+	case ".pages", ".key", ".numbers":
+		return preview.New(newPDFConverter(filePath, opts, dialect, listStyle), preview.IWork...), IWork, nil
+	case ".docx":
+		return &docx.Converter{
+			AssetsDir:         opts.AssetsDir,
+			AssetsLink:        opts.AssetsLink,
+			AssetNamer:        opts.AssetNamer,
+			AutoTags:          opts.AutoTags,
+			Dialect:           dialect,
+			ListStyle:         listStyle,
+			UnderlineEmphasis: opts.UnderlineEmphasis,
+			FlattenTables:     opts.FlattenTables,
+			OCR:               opts.OCR,
+			ShowRevisions:     opts.ShowRevisions,
+			StripComments:     opts.StripComments,
+			IncludeComments:   opts.IncludeComments,
+		}, DOCX, nil
+	case ".xlsx":
+		return &xlsx.Converter{MaxRows: opts.MaxRows, MaxCols: opts.MaxCols, TruncationMarker: opts.TruncationMarker, Logf: opts.Logf}, XLSX, nil
+	case ".pptx":
+		return &pptx.Converter{AssetsDir: opts.AssetsDir, AssetsLink: opts.AssetsLink, AssetNamer: opts.AssetNamer, ListStyle: listStyle, OCR: opts.OCR}, PPTX, nil
+	case ".eml":
+		return &email.Converter{AssetsDir: opts.AssetsDir, AssetsLink: opts.AssetsLink, AssetNamer: opts.AssetNamer, ListStyle: listStyle}, EML, nil
+	case ".msg":
+		return &email.Converter{AssetsDir: opts.AssetsDir, AssetsLink: opts.AssetsLink, AssetNamer: opts.AssetNamer, ListStyle: listStyle}, MSG, nil
+	case ".odp", ".ods":
+		conv := &odf.Converter{
+			AssetsDir:        opts.AssetsDir,
+			AssetsLink:       opts.AssetsLink,
+			AssetNamer:       opts.AssetNamer,
+			ListStyle:        listStyle,
+			MaxRows:          opts.MaxRows,
+			MaxCols:          opts.MaxCols,
+			TruncationMarker: opts.TruncationMarker,
+			Logf:             opts.Logf,
+		}
+		if ext == ".ods" {
+			return conv, ODS, nil
+		}
+		return conv, ODP, nil
+	case ".tex":
+		return &latex.Converter{ListStyle: listStyle, KeepUnknownCommands: opts.KeepLaTeXCommands, SoftBreak: softBreak}, TeX, nil
+	case ".txt":
+		return &plaintext.Converter{ListStyle: listStyle, SoftBreak: softBreak}, Text, nil
+	case ".djvu":
+		return &djvu.Converter{OCR: opts.OCR, NoPageBreaks: opts.NoPageBreaks}, DjVu, nil
+	case ".png", ".jpg", ".jpeg", ".gif":
+		return &imagefile.Converter{AssetsDir: opts.AssetsDir, AssetsLink: opts.AssetsLink, AssetNamer: opts.AssetNamer}, Image, nil
 	default:
-		return nil, "", fmt.Errorf("unsupported file type: %s", ext)
+		return nil, "", fmt.Errorf("%w: %s", errs.ErrUnsupportedType, ext)
+	}
+}
+
+// newPDFConverter returns the PDF converter for filePath, which also converts
+// the PDF previews of other containers
+func newPDFConverter(filePath string, opts Options, dialect markdown.Dialect, listStyle markdown.ListStyle) *pdf.Converter {
+	var convertAttachment pdf.AttachmentConverter
+	if opts.ConvertAttachments && opts.attachmentDepth < maxAttachmentDepth {
+		convertAttachment = attachmentConverter(opts)
+	}
+	var convertPortfolio pdf.AttachmentConverter
+	if opts.MergePortfolio && opts.attachmentDepth < maxAttachmentDepth {
+		convertPortfolio = portfolioConverter(opts)
+	}
+	gapBreaks := 0.0
+	if opts.GapBreaks {
+		gapBreaks = opts.GapBreakThreshold
+	}
+	headingThreshold := 0.0
+	if opts.ScoreHeadings {
+		headingThreshold = opts.HeadingThreshold
+	}
+	var report func([]pdf.PageReport)
+	if opts.Report != nil {
+		report = func(pages []pdf.PageReport) { opts.Report(filePath, pages) }
+	}
+	return &pdf.Converter{
+		AssetsDir:            opts.AssetsDir,
+		AssetsLink:           opts.AssetsLink,
+		AssetNamer:           opts.AssetNamer,
+		AutoTags:             opts.AutoTags,
+		UseTags:              opts.UseTags,
+		ImageFormat:          opts.ImageFormat,
+		Dialect:              dialect,
+		PreserveColors:       opts.PreserveColors,
+		KeepFontInfo:         opts.KeepFontInfo,
+		SummaryOnly:          opts.SummaryOnly,
+		ListStyle:            listStyle,
+		Math:                 opts.Math,
+		Layers:               opts.Layers,
+		NoPageBreaks:         opts.NoPageBreaks,
+		MergeTables:          opts.MergeTables,
+		UnderlineEmphasis:    opts.UnderlineEmphasis,
+		ConvertAttachment:    convertAttachment,
+		PortfolioConverter:   convertPortfolio,
+		KVPairs:              opts.KVPairs,
+		KeepPageNumbers:      opts.KeepPageNumbers,
+		DropSourceTOC:        opts.DropSourceTOC,
+		Jobs:                 opts.Jobs,
+		PreserveEmptyCells:   opts.PreserveEmptyCells,
+		FlattenTables:        opts.FlattenTables,
+		AnchorLinks:          opts.AnchorLinks,
+		MaxHeadingLevel:      opts.MaxHeadingLevel,
+		StripWatermarks:      opts.StripWatermarks,
+		GapBreaks:            gapBreaks,
+		HeadingThreshold:     headingThreshold,
+		NotesAppendix:        opts.NotesAppendix,
+		StripCodeLineNumbers: opts.StripCodeLineNumbers,
+		PreserveTabs:         opts.PreserveTabs,
+		PreserveCellSpacing:  opts.PreserveCellSpacing,
+		ListDetection:        opts.ListDetection,
+		Dehyphenate:          opts.Dehyphenate,
+		DetectColumns:        opts.DetectColumns,
+		Report:               report,
+	}
+}
+
+// maxAttachmentDepth limits how deeply attachments of attachments are converted
+const maxAttachmentDepth = 3
+
+// attachmentConverter converts attachments with the same options as their
+// document. The result is stored next to the attachment in the assets directory,
+// so its own assets are linked relative to that directory.
+func attachmentConverter(opts Options) pdf.AttachmentConverter {
+	nested := opts
+	nested.AssetsLink = "."
+	nested.attachmentDepth++
+	// Reports describe the inputs named on the command line
+	nested.Report = nil
+
+	return func(path string, w io.Writer) (bool, error) {
+		conv, _, err := GetConverter(path, nested)
+		if err != nil {
+			return false, nil
+		}
+		return true, conv.ToMarkdown(path, w)
+	}
+}
+
+// portfolioConverter converts the documents of a portfolio with the same
+// options as the portfolio. Their Markdown is merged into the portfolio's, so
+// their assets are linked the same way as the portfolio's own.
+func portfolioConverter(opts Options) pdf.AttachmentConverter {
+	nested := opts
+	nested.attachmentDepth++
+	nested.Report = nil
+
+	return func(path string, w io.Writer) (bool, error) {
+		conv, _, err := GetConverter(path, nested)
+		if err != nil {
+			return false, nil
+		}
+		return true, conv.ToMarkdown(path, w)
 	}
 }
@@ -0,0 +1,50 @@
+package converter
+
+import "testing"
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    Options
+		wantErr bool
+	}{
+		{"defaults", Options{}, false},
+		{"png images", Options{ImageFormat: "png"}, false},
+		{"no images", Options{ImageFormat: "none"}, false},
+		{"unknown image format", Options{ImageFormat: "gif"}, true},
+		{"list style", Options{BulletMarker: "*", OrderedStyle: ")"}, false},
+		{"unknown bullet marker", Options{BulletMarker: "•"}, true},
+		{"unknown ordered style", Options{OrderedStyle: ":"}, true},
+		{"max heading level", Options{MaxHeadingLevel: 4}, false},
+		{"max heading level too deep", Options{MaxHeadingLevel: 7}, true},
+		{"negative max heading level", Options{MaxHeadingLevel: -1}, true},
+		{"soft break mode", Options{SoftBreakMode: "newline"}, false},
+		{"unknown soft break mode", Options{SoftBreakMode: "tab"}, true},
+		{"max rows", Options{MaxRows: 100}, false},
+		{"negative max rows", Options{MaxRows: -1}, true},
+		{"negative max columns", Options{MaxCols: -1}, true},
+		{"gap break threshold", Options{GapBreaks: true, GapBreakThreshold: 3}, false},
+		{"gap break threshold of one line", Options{GapBreaks: true, GapBreakThreshold: 1}, true},
+		{"gap break threshold without gap breaks", Options{GapBreakThreshold: 0}, false},
+		{"list detection", Options{ListDetection: "high"}, false},
+		{"unknown list detection", Options{ListDetection: "extreme"}, true},
+		{"scored headings", Options{ScoreHeadings: true, HeadingThreshold: 0.5}, false},
+		{"scored headings without a threshold", Options{ScoreHeadings: true}, true},
+		{"heading threshold without scored headings", Options{HeadingThreshold: -1}, false},
+	}
+	for _, tt := range tests {
+		if err := tt.opts.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() error = %v, want error %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestGetConverterNotebook(t *testing.T) {
+	path := writeFile(t, "notes.ipynb", `{"metadata": {"language_info": {"name": "python"}}, "cells": [{"cell_type": "code", "source": "print(1)", "outputs": []}]}`)
+	if _, fileType, err := GetConverter(path, Options{AssetsDir: t.TempDir()}); err != nil || fileType != Notebook {
+		t.Fatalf("GetConverter(%s) = %v, %v, want %v", path, fileType, err, Notebook)
+	}
+	if got, want := convertFile(t, path, Options{AssetsDir: t.TempDir()}), "```python\nprint(1)\n```\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,13 @@
+package converter
+
+import "github.com/leandrowiemesfilho/markdown-converter/internal/errs"
+
+// Errors wrapped by the converters, for use with errors.Is
+var (
+	ErrUnsupportedType = errs.ErrUnsupportedType
+	ErrEncrypted       = errs.ErrEncrypted
+	ErrCorrupt         = errs.ErrCorrupt
+	ErrNoPages         = errs.ErrNoPages
+	ErrNotFound        = errs.ErrNotFound
+	ErrEmptyOutput     = errs.ErrEmptyOutput
+)
@@ -0,0 +1,28 @@
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/errs"
+)
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		exported, internal error
+	}{
+		{ErrUnsupportedType, errs.ErrUnsupportedType},
+		{ErrEncrypted, errs.ErrEncrypted},
+		{ErrCorrupt, errs.ErrCorrupt},
+		{ErrNoPages, errs.ErrNoPages},
+		{ErrNotFound, errs.ErrNotFound},
+		{ErrEmptyOutput, errs.ErrEmptyOutput},
+	}
+	for _, tt := range tests {
+		wrapped := fmt.Errorf("%w: input.pdf", tt.internal)
+		if !errors.Is(wrapped, tt.exported) {
+			t.Errorf("errors.Is(%q, %q) = false", wrapped, tt.exported)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package converter
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/docx"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/errs"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/odf"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/pdf"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/pptx"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/xlsx"
+)
+
+// ExtractMetadata reads the title, author, dates, page count and language a
+// document declares without converting it. PDFs are read from their
+// information dictionary, DOCX, PPTX and XLSX files from their core
+// properties and OpenDocument files from meta.xml; other inputs wrap
+// ErrUnsupportedType.
+func ExtractMetadata(inputPath string) (markdown.Metadata, error) {
+	switch ext := strings.ToLower(filepath.Ext(inputPath)); ext {
+	case ".pdf":
+		return pdf.ReadMetadata(inputPath)
+	case ".docx":
+		return docx.ReadMetadata(inputPath)
+	case ".pptx":
+		return pptx.ReadMetadata(inputPath)
+	case ".xlsx":
+		return xlsx.ReadMetadata(inputPath)
+	case ".odp", ".ods":
+		return odf.ReadMetadata(inputPath)
+	default:
+		return markdown.Metadata{}, fmt.Errorf("%w: no metadata reader for %s", errs.ErrUnsupportedType, ext)
+	}
+}
@@ -0,0 +1,16 @@
+package converter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/errs"
+)
+
+func TestExtractMetadataUnsupported(t *testing.T) {
+	for _, path := range []string{"notes.txt", "page.html", "archive"} {
+		if _, err := ExtractMetadata(path); !errors.Is(err, errs.ErrUnsupportedType) {
+			t.Errorf("ExtractMetadata(%s) error = %v, want ErrUnsupportedType", path, err)
+		}
+	}
+}
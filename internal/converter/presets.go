@@ -0,0 +1,66 @@
+package converter
+
+import (
+	"fmt"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/pdf"
+)
+
+// Presets accepted by ApplyPreset
+const (
+	PresetFast     = "fast"
+	PresetBalanced = "balanced"
+	PresetAccurate = "accurate"
+)
+
+// defaultHeadingThreshold is the heading score the accurate preset uses when
+// no threshold is configured
+const defaultHeadingThreshold = 0.5
+
+// ApplyPreset tunes several options at once for a speed/accuracy trade-off.
+// Every preset sets each of ImageFormat, UseTags, OCR, Dehyphenate,
+// DetectColumns and ScoreHeadings:
+//
+//   - fast: images are not extracted (ImageFormat "none"), pictures are not
+//     recognized (OCR false) and the structure tree is ignored (UseTags
+//     false), leaving only the basic layout heuristics
+//   - balanced: the defaults, with images extracted in their original format,
+//     no OCR and layout heuristics only
+//   - accurate: images are extracted and pictures recognized (OCR true), the
+//     structure tree of tagged PDFs is used (UseTags true), hyphenated words
+//     are rejoined (Dehyphenate true), two-column pages are read column by
+//     column (DetectColumns true) and headings are scored (ScoreHeadings
+//     true, at HeadingThreshold or 0.5 when it is unset)
+//
+// Options set explicitly afterwards take precedence over the preset.
+func (o *Options) ApplyPreset(name string) error {
+	switch name {
+	case PresetFast:
+		o.ImageFormat = pdf.ImageFormatNone
+		o.UseTags = false
+		o.OCR = false
+		o.Dehyphenate = false
+		o.DetectColumns = false
+		o.ScoreHeadings = false
+	case PresetBalanced:
+		o.ImageFormat = pdf.ImageFormatOriginal
+		o.UseTags = false
+		o.OCR = false
+		o.Dehyphenate = false
+		o.DetectColumns = false
+		o.ScoreHeadings = false
+	case PresetAccurate:
+		o.ImageFormat = pdf.ImageFormatOriginal
+		o.UseTags = true
+		o.OCR = true
+		o.Dehyphenate = true
+		o.DetectColumns = true
+		o.ScoreHeadings = true
+		if o.HeadingThreshold <= 0 {
+			o.HeadingThreshold = defaultHeadingThreshold
+		}
+	default:
+		return fmt.Errorf("unsupported preset: %s", name)
+	}
+	return nil
+}
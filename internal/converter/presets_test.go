@@ -0,0 +1,58 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/pdf"
+)
+
+func TestApplyPreset(t *testing.T) {
+	tests := []struct {
+		preset string
+		start  Options
+		want   Options
+	}{
+		{
+			preset: PresetFast,
+			start:  Options{ImageFormat: pdf.ImageFormatPNG, UseTags: true, OCR: true, Dehyphenate: true, DetectColumns: true, ScoreHeadings: true},
+			want:   Options{ImageFormat: pdf.ImageFormatNone},
+		},
+		{
+			preset: PresetBalanced,
+			start:  Options{ImageFormat: pdf.ImageFormatNone, UseTags: true, OCR: true, Dehyphenate: true, DetectColumns: true, ScoreHeadings: true},
+			want:   Options{ImageFormat: pdf.ImageFormatOriginal},
+		},
+		{
+			preset: PresetAccurate,
+			want:   Options{ImageFormat: pdf.ImageFormatOriginal, UseTags: true, OCR: true, Dehyphenate: true, DetectColumns: true, ScoreHeadings: true, HeadingThreshold: 0.5},
+		},
+		{
+			preset: PresetAccurate,
+			start:  Options{HeadingThreshold: 0.8},
+			want:   Options{ImageFormat: pdf.ImageFormatOriginal, UseTags: true, OCR: true, Dehyphenate: true, DetectColumns: true, ScoreHeadings: true, HeadingThreshold: 0.8},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.preset, func(t *testing.T) {
+			got := tt.start
+			if err := got.ApplyPreset(tt.preset); err != nil {
+				t.Fatalf("ApplyPreset(%q) error = %v", tt.preset, err)
+			}
+			if got.ImageFormat != tt.want.ImageFormat || got.UseTags != tt.want.UseTags || got.OCR != tt.want.OCR ||
+				got.Dehyphenate != tt.want.Dehyphenate || got.DetectColumns != tt.want.DetectColumns ||
+				got.ScoreHeadings != tt.want.ScoreHeadings || got.HeadingThreshold != tt.want.HeadingThreshold {
+				t.Errorf("ApplyPreset(%q) = %+v, want %+v", tt.preset, got, tt.want)
+			}
+			if err := got.Validate(); err != nil {
+				t.Errorf("Validate() after ApplyPreset(%q) error = %v", tt.preset, err)
+			}
+		})
+	}
+}
+
+func TestApplyPresetUnknown(t *testing.T) {
+	var o Options
+	if err := o.ApplyPreset("thorough"); err == nil {
+		t.Error("ApplyPreset(\"thorough\") error = nil, want an error")
+	}
+}
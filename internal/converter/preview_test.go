@@ -0,0 +1,41 @@
+package converter
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIWorkPreview(t *testing.T) {
+	pdfData, err := os.ReadFile(writePDF(t, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantPDF := convertFile(t, writePDF(t, 1), Options{AssetsDir: t.TempDir()})
+
+	for _, name := range []string{"report.pages", "talk.key", "sheet.numbers"} {
+		var buf bytes.Buffer
+		z := zip.NewWriter(&buf)
+		f, _ := z.Create("QuickLook/Preview.pdf")
+		f.Write(pdfData)
+		z.Close()
+		path := filepath.Join(t.TempDir(), name)
+		if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		conv, fileType, err := GetConverter(path, Options{AssetsDir: t.TempDir()})
+		if err != nil || fileType != IWork {
+			t.Fatalf("GetConverter(%s) = %v, %v, want %v", name, fileType, err, IWork)
+		}
+		var out bytes.Buffer
+		if err := conv.ToMarkdown(path, &out); err != nil || out.String() != wantPDF {
+			t.Errorf("%s: got %q, %v, want the preview converted as %q", name, out.String(), err, wantPDF)
+		}
+		if _, ok := conv.(ReaderConverter); !ok {
+			t.Errorf("%s: converter cannot read from memory", name)
+		}
+	}
+}
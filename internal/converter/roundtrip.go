@@ -0,0 +1,68 @@
+package converter
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/docx"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/errs"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/pdf"
+)
+
+// RoundTripReport compares the structure of a source document with the
+// structure of the Markdown it was converted to
+type RoundTripReport struct {
+	Source markdown.Structure
+	Output markdown.Structure
+	// Diffs lists the structural counts that were not preserved
+	Diffs []string
+}
+
+// OK reports whether the conversion preserved the structure of the source
+func (r RoundTripReport) OK() bool {
+	return len(r.Diffs) == 0
+}
+
+// RoundTripCheck converts a document, parses the resulting Markdown and
+// reports the headings, list items and tables that were lost or added on the
+// way. The source structure comes from Word styles for DOCX and from the
+// structure tree for tagged PDFs; other inputs wrap ErrUnsupportedType.
+func RoundTripCheck(inputPath string, opts Options) (RoundTripReport, error) {
+	var report RoundTripReport
+
+	switch ext := strings.ToLower(filepath.Ext(inputPath)); ext {
+	case ".docx":
+		source, err := docx.ReadStructure(inputPath)
+		if err != nil {
+			return report, err
+		}
+		report.Source = source
+	case ".pdf":
+		source, tagged, err := pdf.ReadStructure(inputPath)
+		if err != nil {
+			return report, err
+		}
+		if !tagged {
+			return report, fmt.Errorf("%w: untagged PDFs have no source structure to compare", errs.ErrUnsupportedType)
+		}
+		report.Source = source
+	default:
+		return report, fmt.Errorf("%w: no source structure for %s", errs.ErrUnsupportedType, ext)
+	}
+
+	conv, _, err := GetConverter(inputPath, opts)
+	if err != nil {
+		return report, err
+	}
+	var out bytes.Buffer
+	if err := conv.ToMarkdown(inputPath, &out); err != nil {
+		return report, err
+	}
+
+	report.Output = markdown.ParseStructure(out.String())
+	report.Diffs = report.Source.Diff(report.Output)
+	return report, nil
+}
@@ -0,0 +1,77 @@
+package converter
+
+import (
+	"archive/zip"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeDocx writes a DOCX package whose body holds the given WordprocessingML
+func writeDocx(t testing.TB, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "test.docx")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	z := zip.NewWriter(f)
+	w, err := z.Create("word/document.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` + body + `</w:body></w:document>`))
+	if err := z.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// docxTable returns a table of rows of cell texts
+func docxTable(rows ...[]string) string {
+	var b strings.Builder
+	b.WriteString("<w:tbl>")
+	for _, row := range rows {
+		b.WriteString("<w:tr>")
+		for _, cell := range row {
+			b.WriteString("<w:tc><w:p><w:r><w:t>" + cell + "</w:t></w:r></w:p></w:tc>")
+		}
+		b.WriteString("</w:tr>")
+	}
+	b.WriteString("</w:tbl>")
+	return b.String()
+}
+
+func TestRoundTripCheck(t *testing.T) {
+	table := docxTable([]string{"Name", "Qty"}, []string{"Apples", "3"})
+	tests := []struct {
+		name  string
+		body  string
+		diffs []string
+	}{
+		{"preserved", "<w:p><w:r><w:t>Intro</w:t></w:r></w:p>" + table, nil},
+		{"dropped table", table + "<w:tbl></w:tbl>", []string{"tables: 2 in source, 1 in output"}},
+	}
+	for _, tt := range tests {
+		report, err := RoundTripCheck(writeDocx(t, tt.body), Options{AssetsDir: t.TempDir()})
+		if err != nil {
+			t.Fatalf("%s: RoundTripCheck() error = %v", tt.name, err)
+		}
+		if strings.Join(report.Diffs, "; ") != strings.Join(tt.diffs, "; ") || report.OK() != (len(tt.diffs) == 0) {
+			t.Errorf("%s: diffs = %q, want %q", tt.name, report.Diffs, tt.diffs)
+		}
+	}
+}
+
+func TestRoundTripCheckUnsupported(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(path, []byte("text"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := RoundTripCheck(path, Options{}); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("RoundTripCheck(.txt) error = %v, want ErrUnsupportedType", err)
+	}
+}
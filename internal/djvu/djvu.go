@@ -0,0 +1,152 @@
+// Package djvu converts DjVu documents to Markdown from the hidden text layer
+// of their pages. Pages without a text layer are recognized with OCR when it
+// is enabled.
+package djvu
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/errs"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/ocr"
+)
+
+const (
+	// textCommand is the DjVuLibre tool that prints compressed text layers,
+	// which are BZZ encoded
+	textCommand = "djvutxt"
+	// renderCommand is the DjVuLibre tool that renders pages for OCR
+	renderCommand = "ddjvu"
+)
+
+// Converter converts DjVu (.djvu) documents to Markdown
+type Converter struct {
+	// OCR recognizes the text of pages without a text layer with tesseract
+	OCR bool
+	// NoPageBreaks omits the separators between pages
+	NoPageBreaks bool
+}
+
+// separators end the columns, regions and paragraphs of a text layer; lines
+// end with a newline
+var separators = strings.NewReplacer("\v", "\n\n", "\x1d", "\n\n", "\x1f", "\n\n")
+
+func (c *Converter) ToMarkdown(inputPath string, w io.Writer) error {
+	data, err := os.ReadFile(inputPath)
+	if err != nil {
+		return fmt.Errorf("failed to open DjVu document: %v", err)
+	}
+	form, err := parseFile(data)
+	if err != nil {
+		return err
+	}
+	pages, err := documentPages(form)
+	if err != nil {
+		return err
+	}
+	if len(pages) == 0 {
+		return errs.ErrNoPages
+	}
+
+	var body []string
+	for i, page := range pages {
+		text, err := c.pageText(inputPath, i+1, page)
+		if err != nil {
+			return fmt.Errorf("failed to extract text from page %d: %w", i+1, err)
+		}
+		if paragraphs := splitParagraphs(text); len(paragraphs) > 0 {
+			body = append(body, strings.Join(paragraphs, "\n\n"))
+		}
+	}
+
+	separator := "\n\n---\n\n"
+	if c.NoPageBreaks {
+		separator = "\n\n"
+	}
+	if _, err := io.WriteString(w, strings.Join(body, separator)+"\n"); err != nil {
+		return fmt.Errorf("failed to write markdown: %v", err)
+	}
+	return nil
+}
+
+// documentPages returns the page forms of a single page or bundled document.
+// Indirect documents keep their pages in separate files and are not supported.
+func documentPages(form chunk) ([]chunk, error) {
+	switch form.Kind {
+	case "DJVU":
+		return []chunk{form}, nil
+	case "DJVM":
+		if dirm, ok := form.child("DIRM"); !ok || len(dirm.Data) == 0 || dirm.Data[0]&0x80 == 0 {
+			return nil, fmt.Errorf("%w: indirect DjVu documents", errs.ErrUnsupportedType)
+		}
+		var pages []chunk
+		for _, child := range form.Children {
+			if child.ID == "FORM" && child.Kind == "DJVU" {
+				pages = append(pages, child)
+			}
+		}
+		return pages, nil
+	}
+	return nil, fmt.Errorf("%w: unknown DjVu form %s", errs.ErrCorrupt, form.Kind)
+}
+
+// pageText returns the text layer of a page. Compressed layers are read with
+// djvutxt, and pages without one are rendered and recognized when OCR is set.
+func (c *Converter) pageText(inputPath string, pageNum int, page chunk) (string, error) {
+	if txt, ok := page.child("TXTa"); ok {
+		return textLayer(txt.Data)
+	}
+	if _, ok := page.child("TXTz"); ok {
+		out, err := exec.Command(textCommand, "--page="+strconv.Itoa(pageNum), inputPath).Output()
+		if err != nil {
+			return "", fmt.Errorf("reading the compressed text layer requires %s: %v", textCommand, err)
+		}
+		return string(out), nil
+	}
+	if !c.OCR || !ocr.Available() {
+		return "", nil
+	}
+
+	dir, err := os.MkdirTemp("", "doc2md-djvu-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	image := filepath.Join(dir, "page.pnm")
+	if err := exec.Command(renderCommand, "-format=pnm", "-page="+strconv.Itoa(pageNum), inputPath, image).Run(); err != nil {
+		return "", nil // Without DjVuLibre the page has no text
+	}
+	return ocr.RecognizeText(image), nil
+}
+
+// textLayer returns the text of an uncompressed text chunk, which starts with
+// its 24-bit length and is followed by the zones the text is laid out in
+func textLayer(data []byte) (string, error) {
+	if len(data) < 3 {
+		return "", nil
+	}
+	n := int(data[0])<<16 | int(data[1])<<8 | int(data[2])
+	if 3+n > len(data) {
+		return "", fmt.Errorf("%w: truncated DjVu text layer", errs.ErrCorrupt)
+	}
+	return string(data[3 : 3+n]), nil
+}
+
+// splitParagraphs splits page text into paragraphs at the layer's separators
+// and at blank lines, joining the lines of each paragraph
+func splitParagraphs(text string) []string {
+	text = strings.ReplaceAll(separators.Replace(text), "\r\n", "\n")
+	var paragraphs []string
+	for _, block := range strings.Split(text, "\n\n") {
+		if paragraph := strings.Join(strings.Fields(block), " "); paragraph != "" {
+			paragraphs = append(paragraphs, paragraph)
+		}
+	}
+	return paragraphs
+}
@@ -0,0 +1,121 @@
+package djvu
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/errs"
+)
+
+// iffChunk encodes a chunk, padded to an even length
+func iffChunk(id string, data []byte) []byte {
+	b := append([]byte(id), 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(b[4:], uint32(len(data)))
+	b = append(b, data...)
+	if len(data)%2 == 1 {
+		b = append(b, 0)
+	}
+	return b
+}
+
+// form encodes a FORM chunk of a kind holding chunks
+func form(kind string, chunks ...[]byte) []byte {
+	return iffChunk("FORM", append([]byte(kind), bytes.Join(chunks, nil)...))
+}
+
+// textPage encodes a page whose uncompressed text layer holds text
+func textPage(text string) []byte {
+	n := len(text)
+	return form("DJVU", iffChunk("INFO", make([]byte, 10)), iffChunk("TXTa", append([]byte{byte(n >> 16), byte(n >> 8), byte(n)}, text...)))
+}
+
+// bundled encodes a bundled document of pages
+func bundled(pages ...[]byte) []byte {
+	return form("DJVM", append([][]byte{iffChunk("DIRM", []byte{0x81, 0, byte(len(pages))})}, pages...)...)
+}
+
+func TestToMarkdown(t *testing.T) {
+	tests := []struct {
+		name    string
+		c       Converter
+		data    []byte
+		want    string
+		wantErr error
+	}{
+		{
+			name: "single page",
+			data: textPage("First line of the\nfirst paragraph.\vSecond paragraph\x1fThird"),
+			want: "First line of the first paragraph.\n\nSecond paragraph\n\nThird\n",
+		},
+		{
+			name: "bundled",
+			data: bundled(textPage("Page one."), textPage("Page two!")),
+			want: "Page one.\n\n---\n\nPage two!\n",
+		},
+		{
+			name: "no page breaks",
+			c:    Converter{NoPageBreaks: true},
+			data: bundled(textPage("Page one."), textPage("Page two!")),
+			want: "Page one.\n\nPage two!\n",
+		},
+		{
+			name: "page without a text layer",
+			data: bundled(form("DJVU", iffChunk("INFO", make([]byte, 10))), textPage("Page two.")),
+			want: "Page two.\n",
+		},
+		{
+			name:    "indirect",
+			data:    form("DJVM", iffChunk("DIRM", []byte{0x01, 0, 1})),
+			wantErr: errs.ErrUnsupportedType,
+		},
+		{
+			name:    "no pages",
+			data:    bundled(),
+			wantErr: errs.ErrNoPages,
+		},
+		{
+			name:    "truncated text layer",
+			data:    form("DJVU", iffChunk("TXTa", []byte{0, 0, 9, 'a'})),
+			wantErr: errs.ErrCorrupt,
+		},
+		{
+			name:    "truncated chunk",
+			data:    form("DJVU", iffChunk("TXTa", []byte("text")))[:14],
+			wantErr: errs.ErrCorrupt,
+		},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "test.djvu")
+		if err := os.WriteFile(path, append([]byte(iffMagic), tt.data...), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		var out bytes.Buffer
+		err := tt.c.ToMarkdown(path, &out)
+		if tt.wantErr != nil {
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("%s: error = %v, want %v", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: ToMarkdown() error = %v", tt.name, err)
+		}
+		if out.String() != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, out.String(), tt.want)
+		}
+	}
+}
+
+func TestNotDjVu(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.djvu")
+	if err := os.WriteFile(path, []byte("%PDF-1.7\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := (&Converter{}).ToMarkdown(path, &bytes.Buffer{}); !errors.Is(err, errs.ErrCorrupt) {
+		t.Errorf("ToMarkdown() error = %v, want ErrCorrupt", err)
+	}
+}
@@ -0,0 +1,79 @@
+package djvu
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/errs"
+)
+
+// iffMagic precedes the outer FORM chunk of every DjVu file
+const iffMagic = "AT&T"
+
+// chunk is an IFF chunk. FORM chunks hold the chunks of their Kind as Children.
+type chunk struct {
+	ID       string
+	Kind     string
+	Data     []byte
+	Children []chunk
+}
+
+// parseFile reads the outer FORM chunk of a DjVu file
+func parseFile(data []byte) (chunk, error) {
+	if len(data) < len(iffMagic) || string(data[:len(iffMagic)]) != iffMagic {
+		return chunk{}, fmt.Errorf("%w: not a DjVu file", errs.ErrCorrupt)
+	}
+	chunks, err := parseChunks(data[len(iffMagic):])
+	if err != nil {
+		return chunk{}, err
+	}
+	if len(chunks) == 0 || chunks[0].ID != "FORM" {
+		return chunk{}, fmt.Errorf("%w: missing DjVu FORM chunk", errs.ErrCorrupt)
+	}
+	return chunks[0], nil
+}
+
+// parseChunks reads a sequence of chunks, each padded to an even length
+func parseChunks(data []byte) ([]chunk, error) {
+	var chunks []chunk
+	for len(data) > 0 {
+		if len(data) < 8 {
+			return nil, fmt.Errorf("%w: truncated DjVu chunk header", errs.ErrCorrupt)
+		}
+		c := chunk{ID: string(data[:4])}
+		size := binary.BigEndian.Uint32(data[4:8])
+		data = data[8:]
+		if uint64(size) > uint64(len(data)) {
+			return nil, fmt.Errorf("%w: truncated DjVu %s chunk", errs.ErrCorrupt, c.ID)
+		}
+		c.Data = data[:size]
+		data = data[size:]
+		if size%2 == 1 && len(data) > 0 {
+			data = data[1:]
+		}
+
+		if c.ID == "FORM" {
+			if len(c.Data) < 4 {
+				return nil, fmt.Errorf("%w: truncated DjVu FORM chunk", errs.ErrCorrupt)
+			}
+			c.Kind = string(c.Data[:4])
+			children, err := parseChunks(c.Data[4:])
+			if err != nil {
+				return nil, err
+			}
+			c.Children = children
+		}
+		chunks = append(chunks, c)
+	}
+	return chunks, nil
+}
+
+// child returns the first child chunk with an ID
+func (c chunk) child(id string) (chunk, bool) {
+	for _, child := range c.Children {
+		if child.ID == id {
+			return child, true
+		}
+	}
+	return chunk{}, false
+}
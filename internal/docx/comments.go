@@ -0,0 +1,84 @@
+package docx
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// threadRoot returns the ID of the comment that opens the thread of a comment
+func (p *pkg) threadRoot(id string) string {
+	seen := make(map[string]bool)
+	for !seen[id] {
+		seen[id] = true
+		c, ok := p.comments[id]
+		if !ok || c.Parent == "" {
+			break
+		}
+		id = c.Parent
+	}
+	return id
+}
+
+// thread returns the comments of the thread opened by root, in the order
+// they were written
+func (p *pkg) thread(root string) []comment {
+	var comments []comment
+	for id, c := range p.comments {
+		if p.threadRoot(id) == root && c.Text != "" {
+			comments = append(comments, c)
+		}
+	}
+	sort.Slice(comments, func(i, j int) bool { return comments[i].Index < comments[j].Index })
+	return comments
+}
+
+// commentReference adds the reference to the thread of a comment where its
+// first comment is anchored. Replies are anchored there too and need none.
+func (bp *bodyParser) commentReference(id string) {
+	if bp.p.threadRoot(id) != id || len(bp.p.thread(id)) == 0 {
+		return
+	}
+	for _, root := range bp.threads {
+		if root == id {
+			return
+		}
+	}
+	bp.threads = append(bp.threads, id)
+	n := strconv.Itoa(len(bp.threads))
+	if bp.c.Dialect.SupportsFootnotes() {
+		bp.addRaw("[^comment-" + n + "]")
+	} else {
+		bp.addRaw(`<sup><a href="#comment-` + n + `">` + n + "</a></sup>")
+	}
+}
+
+// renderComments renders the referenced comment threads as footnotes, or as
+// a "Comments" section for dialects without them, each comment of a thread
+// on its own paragraph after its author
+func (bp *bodyParser) renderComments() string {
+	if len(bp.threads) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	if !bp.c.Dialect.SupportsFootnotes() {
+		b.WriteString("## Comments\n\n")
+	}
+	for i, root := range bp.threads {
+		var paragraphs []string
+		for _, c := range bp.p.thread(root) {
+			text := c.Text
+			if c.Author != "" {
+				text = "**" + c.Author + ":** " + text
+			}
+			paragraphs = append(paragraphs, text)
+		}
+		n := strconv.Itoa(i + 1)
+		if bp.c.Dialect.SupportsFootnotes() {
+			b.WriteString("[^comment-" + n + "]: " + strings.Join(paragraphs, "\n\n    ") + "\n\n")
+		} else {
+			b.WriteString(n + `. <a id="comment-` + n + `"></a>` + strings.Join(paragraphs, "\n\n   ") + "\n\n")
+		}
+	}
+	return b.String()
+}
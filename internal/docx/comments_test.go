@@ -0,0 +1,102 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+)
+
+// threadParts holds a comment with two replies, in word/comments.xml out of
+// the order the replies are linked, and a separate comment
+var threadParts = map[string]string{
+	"word/comments.xml": `<w:comments xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"` +
+		` xmlns:w14="http://schemas.microsoft.com/office/word/2010/wordml">` +
+		`<w:comment w:id="0" w:author="Ana"><w:p w14:paraId="0A"><w:r><w:t>Is this figure right?</w:t></w:r></w:p></w:comment>` +
+		`<w:comment w:id="1" w:author="Bo"><w:p w14:paraId="0B"><w:r><w:t>It is from the 2023 report.</w:t></w:r></w:p></w:comment>` +
+		`<w:comment w:id="2" w:author="Ana"><w:p w14:paraId="0C"><w:r><w:t>Thanks, resolved.</w:t></w:r></w:p></w:comment>` +
+		`<w:comment w:id="3"><w:p w14:paraId="0D"><w:r><w:t>Typo</w:t></w:r></w:p></w:comment>` +
+		`</w:comments>`,
+	"word/commentsExtended.xml": `<w15:commentsEx xmlns:w15="http://schemas.microsoft.com/office/word/2012/wordml">` +
+		`<w15:commentEx w15:paraId="0A"/>` +
+		`<w15:commentEx w15:paraId="0B" w15:paraIdParent="0A"/>` +
+		`<w15:commentEx w15:paraId="0C" w15:paraIdParent="0A"/>` +
+		`<w15:commentEx w15:paraId="0D"/>` +
+		`</w15:commentsEx>`,
+}
+
+// threadBody references the thread and the separate comment. Word anchors
+// the replies where their thread is.
+const threadBody = `<w:p><w:r><w:t>Sales grew 40%.</w:t></w:r>` +
+	`<w:r><w:commentReference w:id="0"/></w:r><w:r><w:commentReference w:id="1"/></w:r><w:r><w:commentReference w:id="2"/></w:r></w:p>` +
+	`<w:p><w:r><w:t>Teh end.</w:t></w:r><w:r><w:commentReference w:id="3"/></w:r></w:p>`
+
+func TestCommentThreads(t *testing.T) {
+	tests := []struct {
+		name  string
+		c     *Converter
+		parts map[string]string
+		want  string
+	}{
+		{
+			name:  "footnotes",
+			c:     &Converter{IncludeComments: true},
+			parts: threadParts,
+			want: "Sales grew 40%.[^comment-1]\n\nTeh end.[^comment-2]\n\n" +
+				"[^comment-1]: **Ana:** Is this figure right?\n\n    **Bo:** It is from the 2023 report.\n\n    **Ana:** Thanks, resolved.\n\n" +
+				"[^comment-2]: Typo",
+		},
+		{
+			name:  "commonmark",
+			c:     &Converter{IncludeComments: true, Dialect: markdown.CommonMark},
+			parts: threadParts,
+			want: `Sales grew 40%.<sup><a href="#comment-1">1</a></sup>` + "\n\n" + `Teh end.<sup><a href="#comment-2">2</a></sup>` + "\n\n" +
+				"## Comments\n\n" +
+				`1. <a id="comment-1"></a>**Ana:** Is this figure right?` + "\n\n   **Bo:** It is from the 2023 report.\n\n   **Ana:** Thanks, resolved.\n\n" +
+				`2. <a id="comment-2"></a>Typo`,
+		},
+		{
+			name:  "without replies linked",
+			c:     &Converter{IncludeComments: true},
+			parts: map[string]string{"word/comments.xml": threadParts["word/comments.xml"]},
+			want: "Sales grew 40%.[^comment-1][^comment-2][^comment-3]\n\nTeh end.[^comment-4]\n\n" +
+				"[^comment-1]: **Ana:** Is this figure right?\n\n[^comment-2]: **Bo:** It is from the 2023 report.\n\n" +
+				"[^comment-3]: **Ana:** Thanks, resolved.\n\n[^comment-4]: Typo",
+		},
+		{
+			name:  "left out by default",
+			c:     &Converter{},
+			parts: threadParts,
+			want:  "Sales grew 40%.\n\nTeh end.",
+		},
+	}
+	for _, tt := range tests {
+		tt.c.AssetsDir = t.TempDir()
+		var out strings.Builder
+		if err := tt.c.ToMarkdown(writeDocx(t, threadBody, tt.parts), &out); err != nil {
+			t.Fatalf("%s: ToMarkdown() error = %v", tt.name, err)
+		}
+		if got := strings.TrimSpace(out.String()); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestThreadRoot(t *testing.T) {
+	p := &pkg{comments: map[string]comment{
+		"0": {Text: "Root"},
+		"1": {Text: "Reply", Parent: "0"},
+		"2": {Text: "Reply to the reply", Parent: "1"},
+		// A cycle must not loop forever
+		"3": {Text: "Loop", Parent: "4"},
+		"4": {Text: "Loop", Parent: "3"},
+	}}
+	for id, want := range map[string]string{"0": "0", "1": "0", "2": "0", "missing": "missing"} {
+		if got := p.threadRoot(id); got != want {
+			t.Errorf("threadRoot(%s) = %s, want %s", id, got, want)
+		}
+	}
+	if got := p.threadRoot("3"); got != "3" && got != "4" {
+		t.Errorf("threadRoot(3) = %s, want a comment of the cycle", got)
+	}
+}
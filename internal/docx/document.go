@@ -0,0 +1,520 @@
+package docx
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/errs"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/ocr"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/smartart"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/utils"
+)
+
+// altEscaper escapes the brackets of recognized text used as image alt text
+var altEscaper = strings.NewReplacer("[", "\\[", "]", "\\]")
+
+// runFormat is the character formatting of a run that maps to Markdown
+type runFormat struct {
+	Bold      bool
+	Italic    bool
+	Underline bool
+	Strike    bool
+	// Inserted and Deleted mark tracked changes shown with ShowRevisions
+	Inserted bool
+	Deleted  bool
+}
+
+// span is a piece of paragraph content with uniform formatting
+type span struct {
+	Text   string
+	Format runFormat
+	Link   string
+	// Raw spans, such as images and line breaks, are emitted without formatting
+	Raw bool
+}
+
+// paragraph is a w:p element
+type paragraph struct {
+	Style string
+	NumID string
+	Level int
+	Spans []span
+}
+
+// table is a w:tbl element whose cells hold rendered Markdown text
+type table struct {
+	Rows [][]string
+	row  []string
+	cell []string
+}
+
+// bodyParser walks the WordprocessingML body, rendering blocks as they close
+type bodyParser struct {
+	c        *Converter
+	p        *pkg
+	result   strings.Builder
+	para     *paragraph
+	format   runFormat
+	inRun    bool
+	inText   bool
+	link     string
+	tables   []*table
+	counters map[string][]int
+	// lastList is the numbering of the preceding list item, if the previous block was one
+	lastList string
+	// revision is the tracked change enclosing the current runs: "ins", "del" or empty
+	revision string
+	// diagrams are the SmartArt lists of the current paragraph, written after it
+	diagrams []string
+	// threads are the IDs of the comments opening each referenced comment
+	// thread, in the order of their references, when IncludeComments is set
+	threads []string
+}
+
+// renderDocument converts word/document.xml to Markdown
+func (c *Converter) renderDocument(p *pkg, r io.Reader) (string, error) {
+	bp := &bodyParser{c: c, p: p, counters: make(map[string][]int)}
+	d := xml.NewDecoder(r)
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("%w: failed to parse document: %v", errs.ErrCorrupt, err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if err := bp.start(d, t); err != nil {
+				return "", err
+			}
+		case xml.EndElement:
+			bp.end(t.Name.Local)
+		case xml.CharData:
+			if bp.inText && bp.para != nil {
+				bp.addText(string(t))
+			}
+		}
+	}
+
+	body := strings.TrimSpace(bp.result.String())
+	if comments := bp.renderComments(); comments != "" {
+		body += "\n\n" + strings.TrimSpace(comments)
+	}
+	return body + "\n", nil
+}
+
+func (bp *bodyParser) start(d *xml.Decoder, t xml.StartElement) error {
+	switch t.Name.Local {
+	case "p":
+		bp.para = &paragraph{}
+	case "pStyle":
+		if bp.para != nil {
+			bp.para.Style = attr(t, "val")
+		}
+	case "numId":
+		if bp.para != nil {
+			bp.para.NumID = attr(t, "val")
+		}
+	case "ilvl":
+		if bp.para != nil {
+			bp.para.Level, _ = strconv.Atoi(attr(t, "val"))
+		}
+	case "r":
+		bp.inRun = true
+		bp.format = runFormat{Inserted: bp.revision == "ins", Deleted: bp.revision == "del"}
+	case "b":
+		bp.format.Bold = bp.inRun && isOn(t)
+	case "i":
+		bp.format.Italic = bp.inRun && isOn(t)
+	case "u":
+		bp.format.Underline = bp.inRun && attr(t, "val") != "none"
+	case "strike", "dstrike":
+		bp.format.Strike = bp.inRun && isOn(t)
+	case "t":
+		bp.inText = true
+	case "tab":
+		if bp.inRun {
+			bp.addText("\t")
+		}
+	case "br", "cr":
+		if bp.inRun && attr(t, "type") != "page" {
+			bp.addRaw("  \n")
+		}
+	case "hyperlink":
+		if rel, ok := bp.p.rels[relID(t, "id")]; ok && rel.External {
+			bp.link = rel.Target
+		} else if anchor := attr(t, "anchor"); anchor != "" {
+			bp.link = "#" + anchor
+		}
+	case "drawing", "pict":
+		return bp.image(d, t)
+	case "oMath", "oMathPara":
+		return bp.equation(d, t)
+	case "tbl":
+		bp.tables = append(bp.tables, &table{})
+	case "ins", "moveTo":
+		if bp.c.ShowRevisions {
+			bp.revision = "ins"
+		}
+	case "del", "moveFrom":
+		if bp.c.ShowRevisions {
+			bp.revision = "del"
+		} else if t.Name.Local == "moveFrom" {
+			// Moved text also appears at its destination
+			return d.Skip()
+		}
+	case "delText":
+		if bp.c.ShowRevisions {
+			bp.inText = true
+			return nil
+		}
+		return d.Skip()
+	case "commentReference":
+		if bp.c.IncludeComments {
+			bp.commentReference(attr(t, "id"))
+		} else if bp.c.ShowRevisions && !bp.c.StripComments {
+			if c, ok := bp.p.comments[attr(t, "id")]; ok && c.Text != "" {
+				text := c.Text
+				if c.Author != "" {
+					text = c.Author + ": " + text
+				}
+				bp.addRaw("{>>" + text + "<<}")
+			}
+		}
+	case "instrText", "rPrChange", "pPrChange", "sectPrChange", "tblPrChange", "trPrChange", "tcPrChange":
+		// Field codes and the formatting before a tracked change are not document content
+		return d.Skip()
+	}
+	return nil
+}
+
+func (bp *bodyParser) end(name string) {
+	switch name {
+	case "t", "delText":
+		bp.inText = false
+	case "ins", "del", "moveTo", "moveFrom":
+		bp.revision = ""
+	case "r":
+		bp.inRun = false
+	case "hyperlink":
+		bp.link = ""
+	case "p":
+		if bp.para != nil {
+			bp.closeParagraph(bp.para)
+			bp.para = nil
+		}
+		bp.writeDiagrams()
+	case "tc":
+		if t := bp.currentTable(); t != nil {
+			t.row = append(t.row, strings.Join(t.cell, "<br>"))
+			t.cell = nil
+		}
+	case "tr":
+		if t := bp.currentTable(); t != nil {
+			t.Rows = append(t.Rows, t.row)
+			t.row = nil
+		}
+	case "tbl":
+		if t := bp.currentTable(); t != nil {
+			bp.tables = bp.tables[:len(bp.tables)-1]
+			bp.writeTable(t)
+		}
+	}
+}
+
+func (bp *bodyParser) addText(s string) {
+	spans := bp.para.Spans
+	if n := len(spans); n > 0 && !spans[n-1].Raw && spans[n-1].Format == bp.format && spans[n-1].Link == bp.link {
+		spans[n-1].Text += s
+		return
+	}
+	bp.para.Spans = append(spans, span{Text: s, Format: bp.format, Link: bp.link})
+}
+
+func (bp *bodyParser) addRaw(s string) {
+	if bp.para != nil {
+		bp.para.Spans = append(bp.para.Spans, span{Text: s, Raw: true})
+	}
+}
+
+// image extracts the picture referenced by a drawing and adds it to the
+// paragraph, or the text of a SmartArt diagram
+func (bp *bodyParser) image(d *xml.Decoder, start xml.StartElement) error {
+	var embed, alt, diagram string
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return fmt.Errorf("%w: failed to parse drawing: %v", errs.ErrCorrupt, err)
+		}
+		if t, ok := tok.(xml.StartElement); ok {
+			switch t.Name.Local {
+			case "blip", "imagedata":
+				if id := relID(t, "embed"); id != "" {
+					embed = id
+				} else if id := relID(t, "id"); id != "" {
+					embed = id
+				}
+			case "docPr":
+				alt = attr(t, "descr")
+			case "relIds":
+				diagram = relID(t, "dm")
+			}
+		}
+		if t, ok := tok.(xml.EndElement); ok && t.Name.Local == start.Name.Local {
+			break
+		}
+	}
+
+	if diagram != "" {
+		bp.addDiagram(diagram)
+		return nil
+	}
+
+	rel, ok := bp.p.rels[embed]
+	if !ok || rel.External {
+		return nil
+	}
+	r, err := bp.p.open(rel.Target)
+	if err != nil {
+		return nil
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("%w: failed to read %s: %v", errs.ErrCorrupt, rel.Target, err)
+	}
+	name := bp.c.AssetNamer.Name(fmt.Sprintf("%s-%s", bp.p.name, path.Base(rel.Target)), data)
+	imagePath := filepath.Join(bp.c.AssetsDir, name)
+	if err := os.WriteFile(imagePath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write image: %v", err)
+	}
+
+	// Scanned figures carry their text only as pixels
+	if alt == "" && bp.c.OCR {
+		alt = altEscaper.Replace(ocr.Recognize(imagePath))
+	}
+
+	bp.addRaw("![" + alt + "](" + utils.AssetLink(bp.c.AssetsLink, bp.c.AssetsDir, name) + ")")
+	return nil
+}
+
+// addDiagram renders the text of a SmartArt data part as a nested list
+func (bp *bodyParser) addDiagram(id string) {
+	rel, ok := bp.p.rels[id]
+	if !ok || rel.External {
+		return
+	}
+	r, err := bp.p.open(rel.Target)
+	if err != nil {
+		return
+	}
+	defer r.Close()
+
+	// A diagram that cannot be read leaves nothing to render
+	if nodes, err := smartart.Parse(r); err == nil {
+		if list := smartart.Render(nodes, bp.c.ListStyle); list != "" {
+			bp.diagrams = append(bp.diagrams, list)
+		}
+	}
+}
+
+// writeDiagrams writes the SmartArt lists of a finished paragraph as blocks.
+// In a table cell the items are kept on the cell's row.
+func (bp *bodyParser) writeDiagrams() {
+	for _, list := range bp.diagrams {
+		if t := bp.currentTable(); t != nil {
+			t.cell = append(t.cell, strings.ReplaceAll(strings.ReplaceAll(list, "|", "\\|"), "\n", "<br>"))
+			continue
+		}
+		if bp.lastList != "" {
+			bp.result.WriteString("\n")
+			bp.lastList = ""
+		}
+		bp.result.WriteString(list + "\n\n")
+	}
+	bp.diagrams = nil
+}
+
+func (bp *bodyParser) currentTable() *table {
+	if len(bp.tables) == 0 {
+		return nil
+	}
+	return bp.tables[len(bp.tables)-1]
+}
+
+// closeParagraph renders a finished paragraph as a heading, list item or
+// paragraph, or adds it to the enclosing table cell
+func (bp *bodyParser) closeParagraph(para *paragraph) {
+	text := strings.TrimSpace(bp.renderSpans(para.Spans))
+
+	if t := bp.currentTable(); t != nil {
+		if text != "" {
+			t.cell = append(t.cell, strings.ReplaceAll(text, "|", "\\|"))
+		}
+		return
+	}
+	if text == "" {
+		return
+	}
+
+	level, isHeading := bp.p.headings[para.Style]
+	listLevels, isList := bp.p.numbering[para.NumID]
+	if para.NumID == "" || para.NumID == "0" {
+		isList = false
+	}
+
+	if bp.lastList != "" && (!isList || para.NumID != bp.lastList) {
+		bp.result.WriteString("\n")
+	}
+	bp.lastList = ""
+	if isList {
+		bp.lastList = para.NumID
+	}
+
+	switch {
+	case isHeading && !isList:
+		if level > 6 {
+			level = 6
+		}
+		bp.result.WriteString(strings.Repeat("#", level) + " " + text + "\n\n")
+	case isList:
+		counters := bp.counters[para.NumID]
+		for len(counters) <= para.Level {
+			counters = append(counters, 0)
+		}
+		counters[para.Level]++
+		// Deeper levels restart when a shallower item appears
+		counters = counters[:para.Level+1]
+		bp.counters[para.NumID] = counters
+
+		marker := bp.c.ListStyle.BulletMarker()
+		if listLevels[para.Level].Ordered {
+			marker = bp.c.ListStyle.OrderedMarker(counters[para.Level])
+		}
+		bp.result.WriteString(strings.Repeat("  ", para.Level) + marker + " " + text + "\n")
+	default:
+		bp.result.WriteString(text + "\n\n")
+	}
+}
+
+// renderSpans applies inline markup to the spans of a paragraph
+func (bp *bodyParser) renderSpans(spans []span) string {
+	var b strings.Builder
+	for _, s := range spans {
+		if s.Raw {
+			b.WriteString(s.Text)
+			continue
+		}
+
+		text := strings.ReplaceAll(s.Text, "\t", " ")
+		if strings.TrimSpace(text) == "" {
+			b.WriteString(text)
+			continue
+		}
+		if s.Format.Strike {
+			text = bp.c.Dialect.Strikethrough(text)
+		}
+		if s.Format.Deleted {
+			text = bp.c.Dialect.Strikethrough(text)
+		}
+		if s.Format.Inserted {
+			text = bp.c.Dialect.Insertion(text)
+		}
+		if s.Format.Underline {
+			if bp.c.UnderlineEmphasis {
+				text = markdown.Wrap(text, "_", "_")
+			} else {
+				text = bp.c.Dialect.Underline(text)
+			}
+		}
+		if s.Format.Italic {
+			text = markdown.Wrap(text, "*", "*")
+		}
+		if s.Format.Bold {
+			text = markdown.Wrap(text, "**", "**")
+		}
+		if s.Link != "" {
+			text = markdown.Wrap(text, "[", "]("+s.Link+")")
+		}
+		b.WriteString(text)
+	}
+	return b.String()
+}
+
+func (bp *bodyParser) writeTable(t *table) {
+	if len(t.Rows) == 0 {
+		return
+	}
+
+	// Nested tables are flattened into the enclosing cell
+	if outer := bp.currentTable(); outer != nil {
+		for _, row := range t.Rows {
+			outer.cell = append(outer.cell, strings.Join(row, " "))
+		}
+		return
+	}
+
+	columns := 0
+	for _, row := range t.Rows {
+		if len(row) > columns {
+			columns = len(row)
+		}
+	}
+
+	if bp.lastList != "" {
+		bp.result.WriteString("\n")
+		bp.lastList = ""
+	}
+	if bp.c.FlattenTables {
+		bp.result.WriteString(markdown.FlattenTable(t.Rows) + "\n\n")
+		return
+	}
+	for i, row := range t.Rows {
+		for len(row) < columns {
+			row = append(row, "")
+		}
+		bp.result.WriteString("| " + strings.Join(row, " | ") + " |\n")
+		if i == 0 {
+			bp.result.WriteString("|" + strings.Repeat(" --- |", columns) + "\n")
+		}
+	}
+	bp.result.WriteString("\n")
+}
+
+func attr(t xml.StartElement, name string) string {
+	for _, a := range t.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// relID returns a relationship reference attribute such as r:id or r:embed
+func relID(t xml.StartElement, name string) string {
+	for _, a := range t.Attr {
+		if a.Name.Local == name && strings.Contains(a.Name.Space, "relationships") {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// isOn reports whether a toggle property such as w:b is enabled
+func isOn(t xml.StartElement) bool {
+	switch attr(t, "val") {
+	case "0", "false", "off":
+		return false
+	}
+	return true
+}
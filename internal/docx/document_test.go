@@ -0,0 +1,150 @@
+package docx
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/utils"
+)
+
+var assetName = regexp.MustCompile(`!\[[^\]]*\]\([^)]*?([^/)]+)\)`)
+
+func TestUnderline(t *testing.T) {
+	body := `<w:p><w:r><w:t xml:space="preserve">Read </w:t></w:r>` +
+		`<w:r><w:rPr><w:u w:val="single"/></w:rPr><w:t>this part</w:t></w:r>` +
+		`<w:r><w:rPr><w:u w:val="none"/></w:rPr><w:t xml:space="preserve"> now</w:t></w:r></w:p>`
+	tests := []struct {
+		name string
+		c    *Converter
+		want string
+	}{
+		{"html", &Converter{}, "Read <u>this part</u> now"},
+		{"pandoc", &Converter{Dialect: markdown.Pandoc}, "Read [this part]{.underline} now"},
+		{"emphasis", &Converter{UnderlineEmphasis: true}, "Read _this part_ now"},
+	}
+	for _, tt := range tests {
+		if got := strings.TrimSpace(convertDocx(t, tt.c, body)); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFlattenTables(t *testing.T) {
+	cell := func(s string) string { return "<w:tc><w:p><w:r><w:t>" + s + "</w:t></w:r></w:p></w:tc>" }
+	table := "<w:tbl><w:tr>" + cell("Name") + cell("Role") + "</w:tr><w:tr>" + cell("Ana") + cell("Editor") + "</w:tr></w:tbl>"
+	tests := []struct {
+		flatten bool
+		want    string
+	}{
+		{false, "| Name | Role |\n| --- | --- |\n| Ana | Editor |"},
+		{true, "**Name:** Ana  \n**Role:** Editor"},
+	}
+	for _, tt := range tests {
+		if md := convertDocx(t, &Converter{FlattenTables: tt.flatten}, table); !strings.Contains(md, tt.want) {
+			t.Errorf("FlattenTables %v: output = %q, want %q", tt.flatten, md, tt.want)
+		}
+	}
+}
+
+func TestRevisions(t *testing.T) {
+	body := `<w:p><w:r><w:t xml:space="preserve">The </w:t></w:r>` +
+		`<w:del w:id="1" w:author="Ana"><w:r><w:delText>old</w:delText></w:r></w:del>` +
+		`<w:ins w:id="2" w:author="Ana"><w:r><w:t>new</w:t></w:r></w:ins>` +
+		`<w:commentRangeStart w:id="0"/><w:r><w:t xml:space="preserve"> plan</w:t></w:r><w:commentRangeEnd w:id="0"/>` +
+		`<w:r><w:commentReference w:id="0"/></w:r></w:p>`
+	comments := map[string]string{
+		"word/comments.xml": `<w:comments xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+			`<w:comment w:id="0" w:author="Bo"><w:p><w:r><w:t>Check the dates</w:t></w:r></w:p></w:comment></w:comments>`,
+	}
+	tests := []struct {
+		name string
+		c    *Converter
+		want string
+	}{
+		{"final text", &Converter{}, "The new plan"},
+		{"show revisions", &Converter{ShowRevisions: true}, "The ~~old~~++new++ plan{>>Bo: Check the dates<<}"},
+		{"commonmark", &Converter{ShowRevisions: true, StripComments: true, Dialect: markdown.CommonMark}, "The <del>old</del><ins>new</ins> plan"},
+		{"strip comments", &Converter{ShowRevisions: true, StripComments: true}, "The ~~old~~++new++ plan"},
+	}
+	for _, tt := range tests {
+		tt.c.AssetsDir = t.TempDir()
+		var out strings.Builder
+		if err := tt.c.ToMarkdown(writeDocx(t, body, comments), &out); err != nil {
+			t.Fatalf("%s: ToMarkdown() error = %v", tt.name, err)
+		}
+		if got := strings.TrimSpace(out.String()); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAssetNaming(t *testing.T) {
+	picture, parts := pictureDocx("Scan")
+	tests := []struct {
+		scheme string
+		want   []string
+	}{
+		{utils.AssetNamingPositional, []string{"test-scan.png", "test-scan.png"}},
+		{utils.AssetNamingSequential, []string{"asset1.png", "asset2.png"}},
+	}
+	for _, tt := range tests {
+		namer, _ := utils.NewAssetNamer(tt.scheme)
+		c := &Converter{AssetsDir: t.TempDir(), AssetNamer: namer}
+		var out strings.Builder
+		if err := c.ToMarkdown(writeDocx(t, picture+picture, parts), &out); err != nil {
+			t.Fatalf("%s: ToMarkdown() error = %v", tt.scheme, err)
+		}
+		var got []string
+		for _, m := range assetName.FindAllStringSubmatch(out.String(), -1) {
+			got = append(got, m[1])
+		}
+		if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+			t.Errorf("%s: assets = %v, want %v", tt.scheme, got, tt.want)
+		}
+		for _, name := range got {
+			if _, err := os.Stat(filepath.Join(c.AssetsDir, name)); err != nil {
+				t.Errorf("%s: %v", tt.scheme, err)
+			}
+		}
+	}
+}
+
+func TestSmartArt(t *testing.T) {
+	data := `<dgm:dataModel xmlns:dgm="http://schemas.openxmlformats.org/drawingml/2006/diagram" xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main"><dgm:ptLst>` +
+		`<dgm:pt modelId="0" type="doc"/>` +
+		`<dgm:pt modelId="1"><dgm:t><a:p><a:r><a:t>Plan</a:t></a:r></a:p></dgm:t></dgm:pt>` +
+		`<dgm:pt modelId="2"><dgm:t><a:p><a:r><a:t>Scope | goals</a:t></a:r></a:p></dgm:t></dgm:pt>` +
+		`</dgm:ptLst><dgm:cxnLst><dgm:cxn srcId="0" destId="1"/><dgm:cxn srcId="1" destId="2"/></dgm:cxnLst></dgm:dataModel>`
+	parts := map[string]string{
+		"word/_rels/document.xml.rels": `<?xml version="1.0" encoding="UTF-8"?><Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rIdDm" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/diagramData" Target="diagrams/data1.xml"/></Relationships>`,
+		"word/diagrams/data1.xml": data,
+	}
+	drawing := `<w:r><w:drawing><wp:inline xmlns:wp="http://schemas.openxmlformats.org/drawingml/2006/wordprocessingDrawing">` +
+		`<a:graphic xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main"><a:graphicData>` +
+		`<dgm:relIds xmlns:dgm="http://schemas.openxmlformats.org/drawingml/2006/diagram" r:dm="rIdDm"/>` +
+		`</a:graphicData></a:graphic></wp:inline></w:drawing></w:r>`
+	cell := func(s string) string { return "<w:tc><w:p>" + s + "</w:p></w:tc>" }
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"paragraph", `<w:p><w:r><w:t>Steps:</w:t></w:r>` + drawing + `</w:p><w:p><w:r><w:t>After.</w:t></w:r></w:p>`, "Steps:\n\n- Plan\n  - Scope | goals\n\nAfter."},
+		{"table cell", "<w:tbl><w:tr>" + cell("<w:r><w:t>Steps</w:t></w:r>") + "</w:tr><w:tr>" + cell(drawing) + "</w:tr></w:tbl>", "| Steps |\n| --- |\n| - Plan<br>  - Scope \\| goals |"},
+	}
+	for _, tt := range tests {
+		c := &Converter{AssetsDir: t.TempDir()}
+		var out strings.Builder
+		if err := c.ToMarkdown(writeDocx(t, tt.body, parts), &out); err != nil {
+			t.Fatalf("%s: ToMarkdown() error = %v", tt.name, err)
+		}
+		if got := strings.TrimSpace(out.String()); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,335 @@
+package docx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/errs"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/utils"
+)
+
+// autoTagCount is the number of tags derived when AutoTags is set
+const autoTagCount = 5
+
+// Converter converts Word (.docx) documents to Markdown
+type Converter struct {
+	AssetsDir string
+	// AssetsLink is the path prefix used to reference assets from the Markdown,
+	// defaulting to AssetsDir
+	AssetsLink string
+	// AssetNamer names extracted assets, keeping positional names when nil
+	AssetNamer *utils.AssetNamer
+	AutoTags   bool
+	Dialect    markdown.Dialect
+	// ListStyle selects the list item markers
+	ListStyle markdown.ListStyle
+	// UnderlineEmphasis renders underlined text as emphasis instead of HTML
+	UnderlineEmphasis bool
+	// FlattenTables renders each table row as "**Header:** value" pairs instead of a pipe table
+	FlattenTables bool
+	// OCR recognizes the text of pictures without a description and uses it as their alt text
+	OCR bool
+	// ShowRevisions marks tracked insertions and deletions, and shows comments
+	// where they are anchored, instead of rendering the final text
+	ShowRevisions bool
+	// StripComments leaves out the comments shown by ShowRevisions
+	StripComments bool
+	// IncludeComments renders each comment thread as a footnote holding its
+	// comments and replies in order with their authors, in place of the
+	// comments shown by ShowRevisions
+	IncludeComments bool
+}
+
+// relationship is an entry of a part's relationships, such as a hyperlink target or image
+type relationship struct {
+	Target   string
+	External bool
+}
+
+// numberingLevel is the list format of one level of a numbering definition
+type numberingLevel struct {
+	Ordered bool
+}
+
+// pkg holds the parts of a document package needed to render its body
+type pkg struct {
+	zip       *zip.ReadCloser
+	name      string
+	rels      map[string]relationship
+	headings  map[string]int
+	numbering map[string]map[int]numberingLevel
+	// comments maps comment IDs to their author and text
+	comments map[string]comment
+}
+
+// comment is an entry of word/comments.xml
+type comment struct {
+	Author string
+	Text   string
+	// Index is the position of the comment in word/comments.xml, which keeps
+	// replies in the order they were written
+	Index int
+	// Parent is the ID of the comment this one replies to, empty for the
+	// first comment of a thread
+	Parent string
+}
+
+func (c *Converter) ToMarkdown(inputPath string, w io.Writer) error {
+	p, err := openPackage(inputPath)
+	if err != nil {
+		return err
+	}
+	defer p.zip.Close()
+
+	doc, err := p.open("word/document.xml")
+	if err != nil {
+		return err
+	}
+	defer doc.Close()
+
+	body, err := c.renderDocument(p, doc)
+	if err != nil {
+		return err
+	}
+
+	fm, err := c.buildFrontMatter(p, body)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, fm.String()+body); err != nil {
+		return fmt.Errorf("failed to write markdown: %v", err)
+	}
+
+	return nil
+}
+
+// openPackage opens a document and reads the parts shared by its body
+func openPackage(inputPath string) (*pkg, error) {
+	z, err := zip.OpenReader(inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to open DOCX: %v", errs.ErrCorrupt, err)
+	}
+
+	p := &pkg{
+		zip:  z,
+		name: strings.TrimSuffix(filepath.Base(inputPath), filepath.Ext(inputPath)),
+	}
+	if p.rels, err = p.readRelationships("word/_rels/document.xml.rels"); err == nil {
+		if p.headings, err = p.readHeadingStyles(); err == nil {
+			if p.numbering, err = p.readNumbering(); err == nil {
+				p.comments, err = p.readComments()
+			}
+		}
+	}
+	if err != nil {
+		z.Close()
+		return nil, err
+	}
+	return p, nil
+}
+
+// open returns a reader for a package part
+func (p *pkg) open(name string) (io.ReadCloser, error) {
+	for _, f := range p.zip.File {
+		if f.Name == name {
+			r, err := f.Open()
+			if err != nil {
+				return nil, fmt.Errorf("%w: failed to open %s: %v", errs.ErrCorrupt, name, err)
+			}
+			return r, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: missing part %s", errs.ErrCorrupt, name)
+}
+
+// decodePart unmarshals an optional XML part, leaving v untouched when the part is absent
+func (p *pkg) decodePart(name string, v interface{}) error {
+	r, err := p.open(name)
+	if err != nil {
+		return nil
+	}
+	defer r.Close()
+
+	if err := xml.NewDecoder(r).Decode(v); err != nil {
+		return fmt.Errorf("%w: failed to parse %s: %v", errs.ErrCorrupt, name, err)
+	}
+	return nil
+}
+
+func (p *pkg) readRelationships(name string) (map[string]relationship, error) {
+	var parsed struct {
+		Relationships []struct {
+			ID         string `xml:"Id,attr"`
+			Target     string `xml:"Target,attr"`
+			TargetMode string `xml:"TargetMode,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := p.decodePart(name, &parsed); err != nil {
+		return nil, err
+	}
+
+	rels := make(map[string]relationship)
+	for _, rel := range parsed.Relationships {
+		external := rel.TargetMode == "External"
+		target := rel.Target
+		if !external {
+			if strings.HasPrefix(target, "/") {
+				target = strings.TrimPrefix(target, "/")
+			} else {
+				target = path.Join(path.Dir(path.Dir(name)), target)
+			}
+		}
+		rels[rel.ID] = relationship{Target: target, External: external}
+	}
+	return rels, nil
+}
+
+// readHeadingStyles maps paragraph style IDs to heading levels, using the
+// built-in "heading N" and "Title" styles and outline levels
+func (p *pkg) readHeadingStyles() (map[string]int, error) {
+	var parsed struct {
+		Styles []struct {
+			ID   string `xml:"styleId,attr"`
+			Name struct {
+				Val string `xml:"val,attr"`
+			} `xml:"name"`
+			Outline *struct {
+				Val int `xml:"val,attr"`
+			} `xml:"pPr>outlineLvl"`
+		} `xml:"style"`
+	}
+	if err := p.decodePart("word/styles.xml", &parsed); err != nil {
+		return nil, err
+	}
+
+	headings := make(map[string]int)
+	for _, style := range parsed.Styles {
+		name := strings.ToLower(style.Name.Val)
+		switch {
+		case name == "title":
+			headings[style.ID] = 1
+		case strings.HasPrefix(name, "heading "):
+			if level, err := strconv.Atoi(strings.TrimPrefix(name, "heading ")); err == nil {
+				headings[style.ID] = level
+			}
+		case style.Outline != nil && style.Outline.Val < 9:
+			headings[style.ID] = style.Outline.Val + 1
+		}
+	}
+	return headings, nil
+}
+
+// readNumbering resolves each numbering instance to the list format of its levels
+func (p *pkg) readNumbering() (map[string]map[int]numberingLevel, error) {
+	var parsed struct {
+		Abstract []struct {
+			ID     string `xml:"abstractNumId,attr"`
+			Levels []struct {
+				Level  int `xml:"ilvl,attr"`
+				Format struct {
+					Val string `xml:"val,attr"`
+				} `xml:"numFmt"`
+			} `xml:"lvl"`
+		} `xml:"abstractNum"`
+		Nums []struct {
+			ID       string `xml:"numId,attr"`
+			Abstract struct {
+				Val string `xml:"val,attr"`
+			} `xml:"abstractNumId"`
+		} `xml:"num"`
+	}
+	if err := p.decodePart("word/numbering.xml", &parsed); err != nil {
+		return nil, err
+	}
+
+	abstract := make(map[string]map[int]numberingLevel)
+	for _, a := range parsed.Abstract {
+		levels := make(map[int]numberingLevel)
+		for _, lvl := range a.Levels {
+			levels[lvl.Level] = numberingLevel{Ordered: lvl.Format.Val != "bullet" && lvl.Format.Val != "none"}
+		}
+		abstract[a.ID] = levels
+	}
+
+	numbering := make(map[string]map[int]numberingLevel)
+	for _, num := range parsed.Nums {
+		numbering[num.ID] = abstract[num.Abstract.Val]
+	}
+	return numbering, nil
+}
+
+// readComments collects the comments of the document with their paragraphs
+// joined into one line. Replies are linked to the comment they answer by
+// word/commentsExtended.xml, which identifies comments by the paraId of their
+// last paragraph.
+func (p *pkg) readComments() (map[string]comment, error) {
+	var parsed struct {
+		Comments []struct {
+			ID         string `xml:"id,attr"`
+			Author     string `xml:"author,attr"`
+			Paragraphs []struct {
+				ParaID string   `xml:"paraId,attr"`
+				Texts  []string `xml:"r>t"`
+			} `xml:"p"`
+		} `xml:"comment"`
+	}
+	if err := p.decodePart("word/comments.xml", &parsed); err != nil {
+		return nil, err
+	}
+	var extended struct {
+		Comments []struct {
+			ParaID string `xml:"paraId,attr"`
+			Parent string `xml:"paraIdParent,attr"`
+		} `xml:"commentEx"`
+	}
+	if err := p.decodePart("word/commentsExtended.xml", &extended); err != nil {
+		return nil, err
+	}
+
+	comments := make(map[string]comment)
+	byParaID := make(map[string]string)
+	for i, c := range parsed.Comments {
+		var paragraphs []string
+		for _, para := range c.Paragraphs {
+			if text := strings.TrimSpace(strings.Join(para.Texts, "")); text != "" {
+				paragraphs = append(paragraphs, text)
+			}
+		}
+		if n := len(c.Paragraphs); n > 0 && c.Paragraphs[n-1].ParaID != "" {
+			byParaID[c.Paragraphs[n-1].ParaID] = c.ID
+		}
+		comments[c.ID] = comment{Author: c.Author, Text: strings.Join(paragraphs, " "), Index: i}
+	}
+	for _, ex := range extended.Comments {
+		id, parent := byParaID[ex.ParaID], byParaID[ex.Parent]
+		if c, ok := comments[id]; ok && parent != "" && parent != id {
+			c.Parent = parent
+			comments[id] = c
+		}
+	}
+	return comments, nil
+}
+
+// buildFrontMatter takes tags from the document keywords, or derives them from
+// the body when AutoTags is set
+func (c *Converter) buildFrontMatter(p *pkg, body string) (markdown.FrontMatter, error) {
+	var core struct {
+		Keywords string `xml:"keywords"`
+	}
+	if err := p.decodePart("docProps/core.xml", &core); err != nil {
+		return markdown.FrontMatter{}, err
+	}
+
+	fm := markdown.FrontMatter{Tags: markdown.ParseKeywords(core.Keywords)}
+	if len(fm.Tags) == 0 && c.AutoTags {
+		fm.Tags = markdown.DeriveTags(body, autoTagCount)
+	}
+	return fm, nil
+}
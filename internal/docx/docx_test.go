@@ -0,0 +1,53 @@
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeDocx writes a package holding a document body, given as the
+// paragraphs inside <w:body>, and other parts by name
+func writeDocx(t *testing.T, body string, parts map[string]string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	z := zip.NewWriter(&buf)
+	files := map[string]string{
+		"word/document.xml": `<?xml version="1.0" encoding="UTF-8"?>` +
+			`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"` +
+			` xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><w:body>` + body + `</w:body></w:document>`,
+	}
+	for name, data := range parts {
+		files[name] = data
+	}
+	for name, data := range files {
+		f, err := z.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(data))
+	}
+	if err := z.Close(); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "test.docx")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// convertDocx converts a document body, failing the test on errors
+func convertDocx(t *testing.T, c *Converter, body string) string {
+	t.Helper()
+	if c.AssetsDir == "" {
+		c.AssetsDir = t.TempDir()
+	}
+	var out bytes.Buffer
+	if err := c.ToMarkdown(writeDocx(t, body, nil), &out); err != nil {
+		t.Fatalf("ToMarkdown() error = %v", err)
+	}
+	return out.String()
+}
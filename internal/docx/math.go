@@ -0,0 +1,332 @@
+package docx
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/errs"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+)
+
+// mathEscaper escapes the characters of equation text that LaTeX reserves
+var mathEscaper = strings.NewReplacer(`{`, `\{`, `}`, `\}`, `%`, `\%`, `#`, `\#`, `&`, `\&`, `$`, `\$`)
+
+// accents maps the combining characters of m:acc to their LaTeX commands
+var accents = map[string]string{
+	"̂": `\hat`, "̃": `\tilde`, "̇": `\dot`, "̈": `\ddot`,
+	"⃗": `\vec`, "̄": `\bar`, "̅": `\bar`, "́": `\acute`,
+	"̀": `\grave`, "̌": `\check`, "̆": `\breve`,
+}
+
+// delimiters maps the delimiter characters of m:d to their LaTeX forms
+var delimiters = map[string]string{
+	"{": `\{`, "}": `\}`, "⟨": `\langle`, "⟩": `\rangle`, "〈": `\langle`, "〉": `\rangle`,
+	"⌈": `\lceil`, "⌉": `\rceil`, "⌊": `\lfloor`, "⌋": `\rfloor`, "‖": `\|`, "": ".",
+}
+
+// mathNode is an element of an Office MathML equation, or a piece of text
+// when Name is empty
+type mathNode struct {
+	Name     string
+	Attr     []xml.Attr
+	Text     string
+	Children []*mathNode
+}
+
+// equation reads an m:oMath or m:oMathPara element and adds it to the
+// paragraph as LaTeX math, displayed for an m:oMathPara
+func (bp *bodyParser) equation(d *xml.Decoder, start xml.StartElement) error {
+	root, err := readMath(d, start)
+	if err != nil {
+		return err
+	}
+	tex := strings.TrimSpace(root.latex())
+	if tex == "" {
+		return nil
+	}
+	if root.Name == "oMathPara" {
+		bp.addRaw("$$\n" + tex + "\n$$")
+	} else {
+		bp.addRaw("$" + tex + "$")
+	}
+	return nil
+}
+
+// readMath reads the tree of an equation element
+func readMath(d *xml.Decoder, start xml.StartElement) (*mathNode, error) {
+	root := &mathNode{Name: start.Name.Local, Attr: start.Attr}
+	stack := []*mathNode{root}
+	for len(stack) > 0 {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to parse equation: %v", errs.ErrCorrupt, err)
+		}
+		parent := stack[len(stack)-1]
+		switch t := tok.(type) {
+		case xml.StartElement:
+			n := &mathNode{Name: t.Name.Local, Attr: t.Attr}
+			parent.Children = append(parent.Children, n)
+			stack = append(stack, n)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			parent.Children = append(parent.Children, &mathNode{Text: string(t)})
+		}
+	}
+	return root, nil
+}
+
+// latex renders an equation element as LaTeX. Elements without a LaTeX
+// construct render their content, so unsupported parts keep their text.
+func (n *mathNode) latex() string {
+	switch n.Name {
+	case "":
+		return ""
+	case "oMathPara":
+		var lines []string
+		for _, child := range n.Children {
+			if child.Name == "oMath" {
+				lines = append(lines, strings.TrimSpace(child.latex()))
+			}
+		}
+		return strings.Join(lines, ` \\ `)
+	case "r":
+		var text strings.Builder
+		for _, child := range n.Children {
+			if child.Name == "t" {
+				text.WriteString(child.text())
+			}
+		}
+		return markdown.LaTeXText(mathEscaper.Replace(text.String()))
+	case "f":
+		num, den := n.arg("num"), n.arg("den")
+		switch n.prop("fPr", "type") {
+		case "lin":
+			return group(num) + "/" + group(den)
+		case "noBar":
+			return `\binom{` + num + "}{" + den + "}"
+		}
+		return `\frac{` + num + "}{" + den + "}"
+	case "sSup":
+		return group(n.arg("e")) + "^{" + n.arg("sup") + "}"
+	case "sSub":
+		return group(n.arg("e")) + "_{" + n.arg("sub") + "}"
+	case "sSubSup":
+		return group(n.arg("e")) + "_{" + n.arg("sub") + "}^{" + n.arg("sup") + "}"
+	case "sPre":
+		return "{}_{" + n.arg("sub") + "}^{" + n.arg("sup") + "}" + group(n.arg("e"))
+	case "rad":
+		if deg := n.arg("deg"); deg != "" && !n.flag("radPr", "degHide") {
+			return `\sqrt[` + deg + "]{" + n.arg("e") + "}"
+		}
+		return `\sqrt{` + n.arg("e") + "}"
+	case "nary":
+		chr := n.prop("naryPr", "chr")
+		if chr == "" {
+			chr = "∫"
+		}
+		op := strings.TrimSpace(markdown.LaTeXText(chr))
+		if sub := n.arg("sub"); sub != "" && !n.flag("naryPr", "subHide") {
+			op += "_{" + sub + "}"
+		}
+		if sup := n.arg("sup"); sup != "" && !n.flag("naryPr", "supHide") {
+			op += "^{" + sup + "}"
+		}
+		return op + " " + n.arg("e")
+	case "d":
+		beg, end, sep := "(", ")", "|"
+		if props := n.child("dPr"); props != nil {
+			if c := props.child("begChr"); c != nil {
+				beg = c.attr("val")
+			}
+			if c := props.child("endChr"); c != nil {
+				end = c.attr("val")
+			}
+			if c := props.child("sepChr"); c != nil {
+				sep = c.attr("val")
+			}
+		}
+		var parts []string
+		for _, child := range n.Children {
+			if child.Name == "e" {
+				parts = append(parts, strings.TrimSpace(child.latex()))
+			}
+		}
+		return `\left` + delimiter(beg) + " " + strings.Join(parts, " "+delimiter(sep)+" ") + ` \right` + delimiter(end)
+	case "func":
+		name := strings.TrimSpace(n.arg("fName"))
+		if markdown.IsLaTeXOperator(name) {
+			name = `\` + name
+		}
+		return name + " " + n.arg("e")
+	case "limLow", "limUpp":
+		base := strings.TrimSpace(n.arg("e"))
+		if markdown.IsLaTeXOperator(base) {
+			base = `\` + base
+		} else {
+			base = group(base)
+		}
+		if n.Name == "limUpp" {
+			return base + "^{" + n.arg("lim") + "}"
+		}
+		return base + "_{" + n.arg("lim") + "}"
+	case "acc":
+		chr := n.prop("accPr", "chr")
+		if chr == "" {
+			chr = "̂"
+		}
+		if cmd, ok := accents[chr]; ok {
+			return cmd + "{" + n.arg("e") + "}"
+		}
+		return n.arg("e")
+	case "bar":
+		if n.prop("barPr", "pos") == "top" {
+			return `\overline{` + n.arg("e") + "}"
+		}
+		return `\underline{` + n.arg("e") + "}"
+	case "groupChr":
+		chr, pos := n.prop("groupChrPr", "chr"), n.prop("groupChrPr", "pos")
+		switch {
+		case chr == "⏞" || chr == "" && pos == "top":
+			return `\overbrace{` + n.arg("e") + "}"
+		case chr == "⏟" || chr == "":
+			return `\underbrace{` + n.arg("e") + "}"
+		}
+		return n.arg("e")
+	case "borderBox":
+		return `\boxed{` + n.arg("e") + "}"
+	case "m":
+		var rows []string
+		for _, row := range n.Children {
+			if row.Name != "mr" {
+				continue
+			}
+			var cells []string
+			for _, cell := range row.Children {
+				if cell.Name == "e" {
+					cells = append(cells, strings.TrimSpace(cell.latex()))
+				}
+			}
+			rows = append(rows, strings.Join(cells, " & "))
+		}
+		return `\begin{matrix} ` + strings.Join(rows, ` \\ `) + ` \end{matrix}`
+	case "eqArr":
+		var rows []string
+		for _, child := range n.Children {
+			if child.Name == "e" {
+				rows = append(rows, strings.TrimSpace(child.latex()))
+			}
+		}
+		return `\begin{gathered} ` + strings.Join(rows, ` \\ `) + ` \end{gathered}`
+	}
+
+	// Properties describe the formatting of their element
+	if strings.HasSuffix(n.Name, "Pr") {
+		return ""
+	}
+	var b strings.Builder
+	for _, child := range n.Children {
+		s := child.latex()
+		// A command must not run into the letters that follow it
+		if r, _ := utf8.DecodeRuneInString(s); unicode.IsLetter(r) && endsWithCommand(b.String()) {
+			b.WriteString(" ")
+		}
+		b.WriteString(s)
+	}
+	return b.String()
+}
+
+// endsWithCommand reports whether LaTeX ends with a command name such as \alpha
+func endsWithCommand(s string) bool {
+	i := strings.LastIndex(s, `\`)
+	if i < 0 || i == len(s)-1 {
+		return false
+	}
+	for _, r := range s[i+1:] {
+		if !unicode.IsLetter(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// arg renders the content of the first child with a local name
+func (n *mathNode) arg(name string) string {
+	if child := n.child(name); child != nil {
+		return strings.TrimSpace(child.latex())
+	}
+	return ""
+}
+
+// prop returns the value of a property of an element, as the m:chr of m:naryPr
+func (n *mathNode) prop(props, name string) string {
+	if p := n.child(props); p != nil {
+		if v := p.child(name); v != nil {
+			return v.attr("val")
+		}
+	}
+	return ""
+}
+
+// flag reports whether a toggle property of an element is on
+func (n *mathNode) flag(props, name string) bool {
+	if p := n.child(props); p != nil {
+		if v := p.child(name); v != nil {
+			switch v.attr("val") {
+			case "0", "false", "off":
+				return false
+			}
+			return true
+		}
+	}
+	return false
+}
+
+func (n *mathNode) child(name string) *mathNode {
+	for _, child := range n.Children {
+		if child.Name == name {
+			return child
+		}
+	}
+	return nil
+}
+
+func (n *mathNode) attr(name string) string {
+	for _, a := range n.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// text returns the character data of a node and its descendants
+func (n *mathNode) text() string {
+	if n.Name == "" {
+		return n.Text
+	}
+	var b strings.Builder
+	for _, child := range n.Children {
+		b.WriteString(child.text())
+	}
+	return b.String()
+}
+
+// group braces a base of more than one character so a script applies to all of it
+func group(s string) string {
+	if utf8.RuneCountInString(s) <= 1 {
+		return s
+	}
+	return "{" + s + "}"
+}
+
+// delimiter returns the LaTeX form of a delimiter character
+func delimiter(chr string) string {
+	if d, ok := delimiters[chr]; ok {
+		return d
+	}
+	return markdown.LaTeXText(chr)
+}
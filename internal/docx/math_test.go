@@ -0,0 +1,66 @@
+package docx
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/errs"
+)
+
+// mathRun is an equation run of text
+func mathRun(s string) string {
+	return "<m:r><m:t>" + s + "</m:t></m:r>"
+}
+
+// mathParagraph is a paragraph with text around an equation element
+func mathParagraph(before, math, after string) string {
+	return `<w:p xmlns:m="http://schemas.openxmlformats.org/officeDocument/2006/math">` +
+		"<w:r><w:t xml:space=\"preserve\">" + before + "</w:t></w:r>" + math +
+		"<w:r><w:t xml:space=\"preserve\">" + after + "</w:t></w:r></w:p>"
+}
+
+func TestEquations(t *testing.T) {
+	fraction := "<m:f><m:num>" + mathRun("a") + "</m:num><m:den>" + mathRun("b+1") + "</m:den></m:f>"
+	tests := []struct {
+		name string
+		math string
+		want string
+	}{
+		{"fraction", "<m:oMath>" + fraction + "</m:oMath>", `Ratio $\frac{a}{b+1}$ here.`},
+		{"superscript", "<m:oMath><m:sSup><m:e>" + mathRun("x") + "</m:e><m:sup>" + mathRun("2") + "</m:sup></m:sSup></m:oMath>", "Ratio $x^{2}$ here."},
+		{"subscript of a longer base", "<m:oMath><m:sSub><m:e>" + mathRun("ab") + "</m:e><m:sub>" + mathRun("i") + "</m:sub></m:sSub></m:oMath>", "Ratio ${ab}_{i}$ here."},
+		{"linear fraction", "<m:oMath><m:f><m:fPr><m:type m:val=\"lin\"/></m:fPr><m:num>" + mathRun("x") + "</m:num><m:den>" + mathRun("2y") + "</m:den></m:f></m:oMath>", "Ratio $x/{2y}$ here."},
+		{"square root", "<m:oMath><m:rad><m:radPr><m:degHide m:val=\"1\"/></m:radPr><m:deg/><m:e>" + mathRun("x") + "</m:e></m:rad></m:oMath>", `Ratio $\sqrt{x}$ here.`},
+		{"cube root", "<m:oMath><m:rad><m:deg>" + mathRun("3") + "</m:deg><m:e>" + mathRun("x") + "</m:e></m:rad></m:oMath>", `Ratio $\sqrt[3]{x}$ here.`},
+		{"sum", "<m:oMath><m:nary><m:naryPr><m:chr m:val=\"∑\"/></m:naryPr><m:sub>" + mathRun("i=1") + "</m:sub><m:sup>" + mathRun("n") + "</m:sup><m:e>" + mathRun("i") + "</m:e></m:nary></m:oMath>", `Ratio $\sum_{i=1}^{n} i$ here.`},
+		{"operators and symbols", "<m:oMath>" + mathRun("α≤β×2") + "</m:oMath>", `Ratio $\alpha \leq \beta \times 2$ here.`},
+		{"delimiters", "<m:oMath><m:d><m:e>" + mathRun("x") + "</m:e><m:e>" + mathRun("y") + "</m:e></m:d></m:oMath>", `Ratio $\left( x | y \right)$ here.`},
+		{"function", "<m:oMath><m:func><m:fName>" + mathRun("sin") + "</m:fName><m:e>" + mathRun("x") + "</m:e></m:func></m:oMath>", `Ratio $\sin x$ here.`},
+		{"reserved characters", "<m:oMath>" + mathRun("50%") + "</m:oMath>", `Ratio $50\%$ here.`},
+		{"unsupported element keeps its text", "<m:oMath><m:box><m:e>" + mathRun("x=1") + "</m:e></m:box></m:oMath>", "Ratio $x=1$ here."},
+		{"empty equation", "<m:oMath></m:oMath>", "Ratio  here."},
+	}
+	for _, tt := range tests {
+		got := strings.TrimSpace(convertDocx(t, &Converter{}, mathParagraph("Ratio ", tt.math, " here.")))
+		if got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDisplayEquation(t *testing.T) {
+	math := "<m:oMathPara><m:oMath>" + mathRun("y=mx+c") + "</m:oMath><m:oMath>" + mathRun("E=m") + "</m:oMath></m:oMathPara>"
+	got := convertDocx(t, &Converter{}, mathParagraph("", math, ""))
+	if want := "$$\ny=mx+c \\\\ E=m\n$$"; !strings.Contains(got, want) {
+		t.Errorf("got %q, want it to contain %q", got, want)
+	}
+}
+
+func TestEquationCorrupt(t *testing.T) {
+	var out strings.Builder
+	path := writeDocx(t, mathParagraph("", "<m:oMath><m:r>", ""), nil)
+	if err := (&Converter{AssetsDir: t.TempDir()}).ToMarkdown(path, &out); !errors.Is(err, errs.ErrCorrupt) {
+		t.Errorf("ToMarkdown() with a truncated equation: error = %v, want %v", err, errs.ErrCorrupt)
+	}
+}
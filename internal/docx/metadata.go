@@ -0,0 +1,62 @@
+package docx
+
+import (
+	"strings"
+	"time"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+)
+
+// ReadMetadata reads the core and extended properties of a document, taking
+// the language from the default run properties when the core properties do
+// not declare one
+func ReadMetadata(inputPath string) (markdown.Metadata, error) {
+	p, err := openPackage(inputPath)
+	if err != nil {
+		return markdown.Metadata{}, err
+	}
+	defer p.zip.Close()
+
+	var core struct {
+		Title    string `xml:"title"`
+		Creator  string `xml:"creator"`
+		Subject  string `xml:"subject"`
+		Keywords string `xml:"keywords"`
+		Created  string `xml:"created"`
+		Modified string `xml:"modified"`
+		Language string `xml:"language"`
+	}
+	if err := p.decodePart("docProps/core.xml", &core); err != nil {
+		return markdown.Metadata{}, err
+	}
+	var app struct {
+		Pages int `xml:"Pages"`
+	}
+	if err := p.decodePart("docProps/app.xml", &app); err != nil {
+		return markdown.Metadata{}, err
+	}
+
+	meta := markdown.Metadata{
+		Title:    strings.TrimSpace(core.Title),
+		Author:   strings.TrimSpace(core.Creator),
+		Subject:  strings.TrimSpace(core.Subject),
+		Keywords: markdown.ParseKeywords(core.Keywords),
+		Pages:    app.Pages,
+		Language: strings.TrimSpace(core.Language),
+	}
+	meta.Created, _ = time.Parse(time.RFC3339, strings.TrimSpace(core.Created))
+	meta.Modified, _ = time.Parse(time.RFC3339, strings.TrimSpace(core.Modified))
+
+	if meta.Language == "" {
+		var styles struct {
+			Lang struct {
+				Val string `xml:"val,attr"`
+			} `xml:"docDefaults>rPrDefault>rPr>lang"`
+		}
+		if err := p.decodePart("word/styles.xml", &styles); err != nil {
+			return markdown.Metadata{}, err
+		}
+		meta.Language = styles.Lang.Val
+	}
+	return meta, nil
+}
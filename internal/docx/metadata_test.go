@@ -0,0 +1,49 @@
+package docx
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadMetadata(t *testing.T) {
+	core := `<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties"` +
+		` xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:dcterms="http://purl.org/dc/terms/">` +
+		`<dc:title>Field Notes</dc:title><dc:creator>Ana Lima</dc:creator><dc:subject>Survey</dc:subject>` +
+		`<cp:keywords>rivers, birds</cp:keywords><dcterms:created>2024-03-01T10:00:00Z</dcterms:created>` +
+		`<dcterms:modified>2024-03-02T11:30:00Z</dcterms:modified>%s</cp:coreProperties>`
+	app := `<Properties xmlns="http://schemas.openxmlformats.org/officeDocument/2006/extended-properties"><Pages>3</Pages></Properties>`
+	styles := `<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:docDefaults><w:rPrDefault><w:rPr>` +
+		`<w:lang w:val="en-GB"/></w:rPr></w:rPrDefault></w:docDefaults></w:styles>`
+	tests := []struct {
+		name     string
+		language string
+		want     string
+	}{
+		{"core language", "<dc:language>fr-FR</dc:language>", "fr-FR"},
+		{"default run language", "", "en-GB"},
+	}
+	for _, tt := range tests {
+		path := writeDocx(t, "<w:p/>", map[string]string{
+			"docProps/core.xml": strings.Replace(core, "%s", tt.language, 1),
+			"docProps/app.xml":  app,
+			"word/styles.xml":   styles,
+		})
+		meta, err := ReadMetadata(path)
+		if err != nil {
+			t.Fatalf("%s: ReadMetadata() error = %v", tt.name, err)
+		}
+		if meta.Title != "Field Notes" || meta.Author != "Ana Lima" || meta.Subject != "Survey" || meta.Pages != 3 {
+			t.Errorf("%s: metadata = %+v", tt.name, meta)
+		}
+		if got := strings.Join(meta.Keywords, ","); got != "rivers,birds" {
+			t.Errorf("%s: Keywords = %v, want [rivers birds]", tt.name, meta.Keywords)
+		}
+		if !meta.Created.Equal(time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)) || !meta.Modified.Equal(time.Date(2024, 3, 2, 11, 30, 0, 0, time.UTC)) {
+			t.Errorf("%s: Created, Modified = %v, %v", tt.name, meta.Created, meta.Modified)
+		}
+		if meta.Language != tt.want {
+			t.Errorf("%s: Language = %q, want %q", tt.name, meta.Language, tt.want)
+		}
+	}
+}
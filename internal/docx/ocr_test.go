@@ -0,0 +1,101 @@
+package docx
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/ocr"
+)
+
+// glyphRows are 5×7 bitmaps of the letters textImage draws
+var glyphRows = map[rune][7]string{
+	'H': {"#...#", "#...#", "#...#", "#####", "#...#", "#...#", "#...#"},
+	'E': {"#####", "#....", "#....", "####.", "#....", "#....", "#####"},
+	'L': {"#....", "#....", "#....", "#....", "#....", "#....", "#####"},
+	'O': {".###.", "#...#", "#...#", "#...#", "#...#", "#...#", ".###."},
+}
+
+// textImage draws s in large block letters, black on white, as a PNG
+func textImage(s string) []byte {
+	const scale = 8
+	img := image.NewGray(image.Rect(0, 0, (len(s)*6+2)*scale, 11*scale))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	for i, r := range s {
+		for y, row := range glyphRows[r] {
+			for x, px := range row {
+				if px != '#' {
+					continue
+				}
+				for dy := 0; dy < scale; dy++ {
+					for dx := 0; dx < scale; dx++ {
+						img.SetGray((2+i*6+x)*scale+dx, (2+y)*scale+dy, color.Gray{})
+					}
+				}
+			}
+		}
+	}
+	var buf bytes.Buffer
+	png.Encode(&buf, img)
+	return buf.Bytes()
+}
+
+// pictureDocx returns the body and parts of a document holding one picture
+// with a description
+func pictureDocx(descr string) (string, map[string]string) {
+	body := `<w:p><w:r><w:drawing><wp:inline xmlns:wp="http://schemas.openxmlformats.org/drawingml/2006/wordprocessingDrawing">` +
+		`<wp:docPr id="1" name="Picture 1" descr="` + descr + `"/>` +
+		`<a:graphic xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main"><a:graphicData><a:blip r:embed="rIdImg"/></a:graphicData></a:graphic>` +
+		`</wp:inline></w:drawing></w:r></w:p>`
+	parts := map[string]string{
+		"word/_rels/document.xml.rels": `<?xml version="1.0" encoding="UTF-8"?><Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rIdImg" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/image" Target="media/scan.png"/></Relationships>`,
+		"word/media/scan.png": string(textImage("HELLO")),
+	}
+	return body, parts
+}
+
+// convertPictureDocx converts a document holding one picture
+func convertPictureDocx(t *testing.T, c *Converter, descr string) string {
+	t.Helper()
+	body, parts := pictureDocx(descr)
+	c.AssetsDir = t.TempDir()
+	var out bytes.Buffer
+	if err := c.ToMarkdown(writeDocx(t, body, parts), &out); err != nil {
+		t.Fatalf("ToMarkdown() error = %v", err)
+	}
+	return out.String()
+}
+
+func TestOCRAltText(t *testing.T) {
+	if !ocr.Available() {
+		t.Skip("tesseract is not installed")
+	}
+	md := convertPictureDocx(t, &Converter{OCR: true}, "")
+	if !strings.Contains(strings.ToUpper(md), "![HELLO](") {
+		t.Errorf("output = %q, want the recognized text as alt text", md)
+	}
+}
+
+func TestOCRFallback(t *testing.T) {
+	tests := []struct {
+		name  string
+		path  string
+		descr string
+		want  string
+	}{
+		{"without tesseract", "", "", "![]("},
+		{"description kept", "", "A scan", "![A scan]("},
+	}
+	for _, tt := range tests {
+		t.Setenv("PATH", tt.path)
+		if md := convertPictureDocx(t, &Converter{OCR: true}, tt.descr); !strings.HasPrefix(md, tt.want) {
+			t.Errorf("%s: output = %q, want it to start with %q", tt.name, md, tt.want)
+		}
+	}
+}
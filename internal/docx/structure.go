@@ -0,0 +1,90 @@
+package docx
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/errs"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+)
+
+// ReadStructure counts the headings, list items and tables of a document's
+// body as Word defines them. Paragraphs inside tables and nested tables are
+// not counted, matching how they are rendered.
+func ReadStructure(inputPath string) (markdown.Structure, error) {
+	p, err := openPackage(inputPath)
+	if err != nil {
+		return markdown.Structure{}, err
+	}
+	defer p.zip.Close()
+
+	doc, err := p.open("word/document.xml")
+	if err != nil {
+		return markdown.Structure{}, err
+	}
+	defer doc.Close()
+
+	var s markdown.Structure
+	var para *paragraph
+	hasText := false
+	tables := 0
+	d := xml.NewDecoder(doc)
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return markdown.Structure{}, fmt.Errorf("%w: failed to parse document: %v", errs.ErrCorrupt, err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "tbl":
+				if tables == 0 {
+					s.Tables++
+				}
+				tables++
+			case "p":
+				para = &paragraph{}
+				hasText = false
+			case "pStyle":
+				if para != nil {
+					para.Style = attr(t, "val")
+				}
+			case "numId":
+				if para != nil {
+					para.NumID = attr(t, "val")
+				}
+			case "instrText", "delText":
+				if err := d.Skip(); err != nil {
+					return markdown.Structure{}, fmt.Errorf("%w: failed to parse document: %v", errs.ErrCorrupt, err)
+				}
+			}
+		case xml.CharData:
+			if para != nil && strings.TrimSpace(string(t)) != "" {
+				hasText = true
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "tbl":
+				tables--
+			case "p":
+				if para != nil && hasText && tables == 0 {
+					_, isList := p.numbering[para.NumID]
+					isList = isList && para.NumID != "" && para.NumID != "0"
+					if isList {
+						s.ListItems++
+					} else if _, ok := p.headings[para.Style]; ok {
+						s.Headings++
+					}
+				}
+				para = nil
+			}
+		}
+	}
+	return s, nil
+}
@@ -0,0 +1,239 @@
+package email
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"unicode/utf16"
+)
+
+// Compound File Binary constants (MS-CFB)
+const (
+	cfbSignature     = "\xd0\xcf\x11\xe0\xa1\xb1\x1a\xe1"
+	cfbEndOfChain    = 0xfffffffe
+	cfbFreeSector    = 0xffffffff
+	cfbNoStream      = 0xffffffff
+	cfbDirEntrySize  = 128
+	cfbHeaderDIFAT   = 109
+	cfbTypeStorage   = 1
+	cfbTypeStream    = 2
+	cfbTypeRoot      = 5
+	cfbMaxChainSteps = 1 << 20
+)
+
+// cfbEntry is a storage or stream of a compound file
+type cfbEntry struct {
+	Name  string
+	Type  byte
+	left  uint32
+	right uint32
+	child uint32
+	start uint32
+	size  uint64
+}
+
+// cfbFile is a read-only view of a compound file held in memory, as used by Outlook .msg files
+type cfbFile struct {
+	data       []byte
+	sectorSize int
+	fat        []uint32
+	miniFAT    []uint32
+	miniCutoff uint64
+	miniStream []byte
+	entries    []cfbEntry
+}
+
+func openCFB(data []byte) (*cfbFile, error) {
+	if len(data) < 512 || string(data[:8]) != cfbSignature {
+		return nil, fmt.Errorf("not a compound file")
+	}
+
+	f := &cfbFile{data: data, sectorSize: 1 << binary.LittleEndian.Uint16(data[30:])}
+	if f.sectorSize != 512 && f.sectorSize != 4096 {
+		return nil, fmt.Errorf("invalid sector size %d", f.sectorSize)
+	}
+	f.miniCutoff = uint64(binary.LittleEndian.Uint32(data[56:]))
+
+	// Collect the FAT sectors from the header DIFAT and any DIFAT sectors
+	var fatSectors []uint32
+	for i := 0; i < cfbHeaderDIFAT; i++ {
+		if s := binary.LittleEndian.Uint32(data[76+4*i:]); s != cfbFreeSector {
+			fatSectors = append(fatSectors, s)
+		}
+	}
+	next := binary.LittleEndian.Uint32(data[68:])
+	for steps := 0; next != cfbEndOfChain && next != cfbFreeSector && steps < cfbMaxChainSteps; steps++ {
+		sector, err := f.sector(next)
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i < f.sectorSize/4-1; i++ {
+			if s := binary.LittleEndian.Uint32(sector[4*i:]); s != cfbFreeSector {
+				fatSectors = append(fatSectors, s)
+			}
+		}
+		next = binary.LittleEndian.Uint32(sector[f.sectorSize-4:])
+	}
+
+	for _, s := range fatSectors {
+		sector, err := f.sector(s)
+		if err != nil {
+			return nil, err
+		}
+		f.fat = append(f.fat, readUint32s(sector)...)
+	}
+
+	dir, err := f.chain(binary.LittleEndian.Uint32(data[48:]), 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory: %v", err)
+	}
+	for off := 0; off+cfbDirEntrySize <= len(dir); off += cfbDirEntrySize {
+		f.entries = append(f.entries, parseCFBEntry(dir[off:off+cfbDirEntrySize]))
+	}
+	if len(f.entries) == 0 || f.entries[0].Type != cfbTypeRoot {
+		return nil, fmt.Errorf("missing root entry")
+	}
+
+	miniFAT, err := f.chain(binary.LittleEndian.Uint32(data[60:]), 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mini FAT: %v", err)
+	}
+	f.miniFAT = readUint32s(miniFAT)
+
+	root := f.entries[0]
+	f.miniStream, err = f.chain(root.start, root.size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mini stream: %v", err)
+	}
+
+	return f, nil
+}
+
+func parseCFBEntry(b []byte) cfbEntry {
+	nameLen := int(binary.LittleEndian.Uint16(b[64:]))
+	if nameLen > 64 {
+		nameLen = 64
+	}
+	var name []uint16
+	for i := 0; i+1 < nameLen-1; i += 2 {
+		name = append(name, binary.LittleEndian.Uint16(b[i:]))
+	}
+
+	return cfbEntry{
+		Name:  string(utf16.Decode(name)),
+		Type:  b[66],
+		left:  binary.LittleEndian.Uint32(b[68:]),
+		right: binary.LittleEndian.Uint32(b[72:]),
+		child: binary.LittleEndian.Uint32(b[76:]),
+		start: binary.LittleEndian.Uint32(b[116:]),
+		size:  binary.LittleEndian.Uint64(b[120:]),
+	}
+}
+
+func (f *cfbFile) sector(id uint32) ([]byte, error) {
+	start := (int(id) + 1) * f.sectorSize
+	if id >= cfbEndOfChain-1 || start+f.sectorSize > len(f.data) {
+		return nil, fmt.Errorf("sector %d out of range", id)
+	}
+	return f.data[start : start+f.sectorSize], nil
+}
+
+// chain reads a sector chain from the FAT, truncated to size when it is non-zero
+func (f *cfbFile) chain(start uint32, size uint64) ([]byte, error) {
+	var buf bytes.Buffer
+	for id, steps := start, 0; id != cfbEndOfChain && id != cfbFreeSector; id, steps = f.fat[id], steps+1 {
+		if int(id) >= len(f.fat) || steps > cfbMaxChainSteps {
+			return nil, fmt.Errorf("corrupt sector chain")
+		}
+		sector, err := f.sector(id)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(sector)
+		if size > 0 && uint64(buf.Len()) >= size {
+			break
+		}
+	}
+
+	data := buf.Bytes()
+	if size > 0 && uint64(len(data)) > size {
+		data = data[:size]
+	}
+	return data, nil
+}
+
+// miniChain reads a chain of 64-byte sectors from the mini stream
+func (f *cfbFile) miniChain(start uint32, size uint64) ([]byte, error) {
+	const miniSectorSize = 64
+	var buf bytes.Buffer
+	for id, steps := start, 0; id != cfbEndOfChain && id != cfbFreeSector; steps++ {
+		if int(id) >= len(f.miniFAT) || steps > cfbMaxChainSteps {
+			return nil, fmt.Errorf("corrupt mini sector chain")
+		}
+		off := int(id) * miniSectorSize
+		if off+miniSectorSize > len(f.miniStream) {
+			return nil, fmt.Errorf("mini sector %d out of range", id)
+		}
+		buf.Write(f.miniStream[off : off+miniSectorSize])
+		if uint64(buf.Len()) >= size {
+			break
+		}
+		id = f.miniFAT[id]
+	}
+
+	data := buf.Bytes()
+	if uint64(len(data)) > size {
+		data = data[:size]
+	}
+	return data, nil
+}
+
+// children returns the entries stored directly under a storage entry
+func (f *cfbFile) children(parent cfbEntry) []cfbEntry {
+	var result []cfbEntry
+	seen := make(map[uint32]bool)
+	var walk func(id uint32)
+	walk = func(id uint32) {
+		if id == cfbNoStream || int(id) >= len(f.entries) || seen[id] {
+			return
+		}
+		seen[id] = true
+		entry := f.entries[id]
+		walk(entry.left)
+		result = append(result, entry)
+		walk(entry.right)
+	}
+	walk(parent.child)
+	return result
+}
+
+// streams returns the named streams directly under a storage entry
+func (f *cfbFile) streams(parent cfbEntry) (map[string][]byte, error) {
+	streams := make(map[string][]byte)
+	for _, entry := range f.children(parent) {
+		if entry.Type != cfbTypeStream {
+			continue
+		}
+
+		var data []byte
+		var err error
+		if entry.size < f.miniCutoff {
+			data, err = f.miniChain(entry.start, entry.size)
+		} else {
+			data, err = f.chain(entry.start, entry.size)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read stream %s: %v", entry.Name, err)
+		}
+		streams[entry.Name] = data
+	}
+	return streams, nil
+}
+
+func readUint32s(b []byte) []uint32 {
+	values := make([]uint32, len(b)/4)
+	for i := range values {
+		values[i] = binary.LittleEndian.Uint32(b[4*i:])
+	}
+	return values
+}
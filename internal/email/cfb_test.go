@@ -0,0 +1,221 @@
+package email
+
+import (
+	"encoding/binary"
+	"testing"
+	"unicode/utf16"
+)
+
+// cfbNode is a stream, or a storage when it has kids, of a test compound file
+type cfbNode struct {
+	name    string
+	data    []byte
+	storage bool
+	kids    []cfbNode
+}
+
+// buildCFB writes a compound file of 512-byte sectors with the nodes under
+// its root storage. Streams shorter than the mini stream cutoff are stored in
+// the mini stream, as Outlook does.
+func buildCFB(nodes ...cfbNode) []byte {
+	const sectorSize, miniSize, cutoff = 512, 64, 4096
+	le := binary.LittleEndian
+
+	// Number the directory entries, chaining siblings through their right links
+	type dirEntry struct {
+		node               cfbNode
+		typ                byte
+		right, child       uint32
+		start              uint32
+		size               uint64
+		mini               bool
+		sectors, miniStart int
+	}
+	entries := []dirEntry{{node: cfbNode{name: "Root Entry"}, typ: cfbTypeRoot, right: cfbNoStream, child: cfbNoStream}}
+	var add func(parent int, kids []cfbNode)
+	add = func(parent int, kids []cfbNode) {
+		prev := -1
+		for _, kid := range kids {
+			id := len(entries)
+			typ := byte(cfbTypeStream)
+			if kid.storage {
+				typ = cfbTypeStorage
+			}
+			entries = append(entries, dirEntry{node: kid, typ: typ, right: cfbNoStream, child: cfbNoStream, start: cfbEndOfChain, size: uint64(len(kid.data))})
+			if prev < 0 {
+				entries[parent].child = uint32(id)
+			} else {
+				entries[prev].right = uint32(id)
+			}
+			prev = id
+			if kid.storage {
+				add(id, kid.kids)
+			}
+		}
+	}
+	add(0, nodes)
+
+	// Lay out the mini stream and its FAT
+	var miniStream []byte
+	var miniFAT []uint32
+	for i := range entries {
+		e := &entries[i]
+		if e.typ != cfbTypeStream || e.size == 0 || e.size >= cutoff {
+			continue
+		}
+		e.mini = true
+		e.start = uint32(len(miniFAT))
+		n := (len(e.node.data) + miniSize - 1) / miniSize
+		for j := 0; j < n; j++ {
+			next := uint32(len(miniFAT) + 1)
+			if j == n-1 {
+				next = cfbEndOfChain
+			}
+			miniFAT = append(miniFAT, next)
+		}
+		chunk := make([]byte, n*miniSize)
+		copy(chunk, e.node.data)
+		miniStream = append(miniStream, chunk...)
+	}
+
+	// Lay out the sectors: FAT, directory, mini FAT, mini stream, then streams
+	var fat []uint32
+	var sectors [][]byte
+	chain := func(data []byte) uint32 {
+		if len(data) == 0 {
+			return cfbEndOfChain
+		}
+		start := uint32(len(sectors))
+		n := (len(data) + sectorSize - 1) / sectorSize
+		for j := 0; j < n; j++ {
+			sector := make([]byte, sectorSize)
+			copy(sector, data[j*sectorSize:])
+			sectors = append(sectors, sector)
+			next := uint32(len(sectors))
+			if j == n-1 {
+				next = cfbEndOfChain
+			}
+			fat = append(fat, next)
+		}
+		return start
+	}
+	sectors = append(sectors, make([]byte, sectorSize))
+	fat = append(fat, 0xfffffffd)
+	dirStart := chain(make([]byte, len(entries)*cfbDirEntrySize))
+	miniFATBytes := make([]byte, 4*len(miniFAT))
+	for i, next := range miniFAT {
+		le.PutUint32(miniFATBytes[4*i:], next)
+	}
+	miniFATStart := chain(miniFATBytes)
+	entries[0].start = chain(miniStream)
+	entries[0].size = uint64(len(miniStream))
+	for i := range entries {
+		if e := &entries[i]; e.typ == cfbTypeStream && !e.mini {
+			e.start = chain(e.node.data)
+		}
+	}
+
+	// Write the directory and the FAT
+	dir := make([]byte, 0, len(entries)*cfbDirEntrySize)
+	for _, e := range entries {
+		b := make([]byte, cfbDirEntrySize)
+		units := utf16.Encode([]rune(e.node.name))
+		for j, u := range units {
+			le.PutUint16(b[2*j:], u)
+		}
+		le.PutUint16(b[64:], uint16(2*len(units)+2))
+		b[66] = e.typ
+		le.PutUint32(b[68:], cfbNoStream)
+		le.PutUint32(b[72:], e.right)
+		le.PutUint32(b[76:], e.child)
+		le.PutUint32(b[116:], e.start)
+		le.PutUint64(b[120:], e.size)
+		dir = append(dir, b...)
+	}
+	for j := 0; j*sectorSize < len(dir); j++ {
+		copy(sectors[int(dirStart)+j], dir[j*sectorSize:])
+	}
+	for i := 0; i < sectorSize/4; i++ {
+		next := uint32(cfbFreeSector)
+		if i < len(fat) {
+			next = fat[i]
+		}
+		le.PutUint32(sectors[0][4*i:], next)
+	}
+
+	header := make([]byte, sectorSize)
+	copy(header, cfbSignature)
+	le.PutUint16(header[30:], 9)
+	le.PutUint32(header[48:], dirStart)
+	le.PutUint32(header[56:], cutoff)
+	le.PutUint32(header[60:], miniFATStart)
+	le.PutUint32(header[68:], cfbEndOfChain)
+	for i := 0; i < cfbHeaderDIFAT; i++ {
+		le.PutUint32(header[76+4*i:], cfbFreeSector)
+	}
+	le.PutUint32(header[76:], 0)
+	for _, sector := range sectors {
+		header = append(header, sector...)
+	}
+	return header
+}
+
+func TestOpenCFB(t *testing.T) {
+	long := make([]byte, 5000)
+	for i := range long {
+		long[i] = byte(i)
+	}
+	data := buildCFB(
+		cfbNode{name: "short", data: []byte("mini stream data")},
+		cfbNode{name: "long", data: long},
+		cfbNode{name: "storage", storage: true, kids: []cfbNode{{name: "inner", data: []byte("nested")}}},
+	)
+	f, err := openCFB(data)
+	if err != nil {
+		t.Fatalf("openCFB() error = %v", err)
+	}
+	streams, err := f.streams(f.entries[0])
+	if err != nil {
+		t.Fatalf("streams() error = %v", err)
+	}
+	if got := string(streams["short"]); got != "mini stream data" {
+		t.Errorf("short = %q", got)
+	}
+	if got := streams["long"]; string(got) != string(long) {
+		t.Errorf("long holds %d bytes, want %d", len(got), len(long))
+	}
+	var storage cfbEntry
+	for _, entry := range f.children(f.entries[0]) {
+		if entry.Name == "storage" {
+			storage = entry
+		}
+	}
+	inner, err := f.streams(storage)
+	if err != nil || string(inner["inner"]) != "nested" {
+		t.Errorf("storage streams = %q, %v", inner, err)
+	}
+}
+
+func TestOpenCFBInvalid(t *testing.T) {
+	valid := buildCFB(cfbNode{name: "a", data: []byte("x")})
+	badSector := append([]byte(nil), valid...)
+	binary.LittleEndian.PutUint16(badSector[30:], 10)
+	badDirectory := append([]byte(nil), valid...)
+	binary.LittleEndian.PutUint32(badDirectory[48:], 1000)
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"short", []byte(cfbSignature)},
+		{"signature", append([]byte("not a compound file"), make([]byte, 600)...)},
+		{"sector size", badSector},
+		{"directory out of range", badDirectory},
+	}
+	for _, tt := range tests {
+		if _, err := openCFB(tt.data); err == nil {
+			t.Errorf("%s: openCFB() error = nil", tt.name)
+		}
+	}
+}
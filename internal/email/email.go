@@ -0,0 +1,136 @@
+package email
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/html"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/utils"
+)
+
+// Converter converts .eml (RFC 822) and Outlook .msg messages to Markdown
+type Converter struct {
+	AssetsDir string
+	// AssetsLink is the path prefix used to reference assets from the Markdown,
+	// defaulting to AssetsDir
+	AssetsLink string
+	// AssetNamer names extracted assets, keeping positional names when nil
+	AssetNamer *utils.AssetNamer
+	// ListStyle selects the list item markers
+	ListStyle markdown.ListStyle
+}
+
+// message is the format-independent content of an email
+type message struct {
+	From        string
+	To          string
+	Cc          string
+	Subject     string
+	Date        string
+	Text        string
+	HTML        string
+	Attachments []attachment
+}
+
+type attachment struct {
+	Name string
+	Data []byte
+}
+
+func (c *Converter) ToMarkdown(inputPath string, w io.Writer) error {
+	data, err := os.ReadFile(inputPath)
+	if err != nil {
+		return fmt.Errorf("failed to open message: %v", err)
+	}
+
+	var msg *message
+	if strings.EqualFold(filepath.Ext(inputPath), ".msg") {
+		msg, err = parseMSG(data)
+	} else {
+		msg, err = parseEML(data)
+	}
+	if err != nil {
+		return err
+	}
+
+	name := strings.TrimSuffix(filepath.Base(inputPath), filepath.Ext(inputPath))
+	markdown, err := c.render(msg, name)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, markdown); err != nil {
+		return fmt.Errorf("failed to write markdown: %v", err)
+	}
+
+	return nil
+}
+
+// render writes the subject as the title, the other headers as a definition list,
+// then the body and links to the saved attachments
+func (c *Converter) render(msg *message, name string) (string, error) {
+	var result strings.Builder
+
+	if msg.Subject != "" {
+		result.WriteString("# " + msg.Subject + "\n\n")
+	}
+
+	var headers []string
+	for _, field := range []struct{ label, value string }{
+		{"From", msg.From},
+		{"To", msg.To},
+		{"Cc", msg.Cc},
+		{"Date", msg.Date},
+	} {
+		if field.value != "" {
+			headers = append(headers, "**"+field.label+":** "+field.value)
+		}
+	}
+	if len(headers) > 0 {
+		// Trailing double spaces keep each header on its own line
+		result.WriteString(strings.Join(headers, "  \n") + "\n\n")
+	}
+
+	body := strings.TrimSpace(strings.ReplaceAll(msg.Text, "\r\n", "\n"))
+	if msg.HTML != "" {
+		// A body that cannot be read as HTML leaves the text part to stand
+		// in for it
+		if converted, err := html.Convert(strings.NewReader(msg.HTML), c.ListStyle); err == nil {
+			body = strings.TrimSpace(converted)
+		}
+	}
+	if body != "" {
+		result.WriteString(body + "\n\n")
+	}
+
+	if len(msg.Attachments) > 0 {
+		result.WriteString("## Attachments\n\n")
+		for i, att := range msg.Attachments {
+			fileName := c.AssetNamer.Name(attachmentFileName(name, att.Name, i+1), att.Data)
+			if err := os.WriteFile(filepath.Join(c.AssetsDir, fileName), att.Data, 0644); err != nil {
+				return "", fmt.Errorf("failed to save attachment %s: %v", att.Name, err)
+			}
+
+			label := att.Name
+			if label == "" {
+				label = fileName
+			}
+			result.WriteString(c.ListStyle.BulletMarker() + " [" + label + "](" + utils.AssetLink(c.AssetsLink, c.AssetsDir, fileName) + ")\n")
+		}
+	}
+
+	return strings.TrimSpace(result.String()) + "\n", nil
+}
+
+// attachmentFileName builds a collision-resistant asset name for an attachment
+func attachmentFileName(docName, attName string, index int) string {
+	base := filepath.Base(strings.ReplaceAll(attName, "\\", "/"))
+	if base == "." || base == "/" || base == "" {
+		base = fmt.Sprintf("attachment%d", index)
+	}
+	return docName + "-" + base
+}
@@ -0,0 +1,79 @@
+package email
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+)
+
+func TestToMarkdown(t *testing.T) {
+	const eml = "From: Ada <ada@example.com>\r\nTo: bob@example.com\r\nSubject: Report\r\nDate: Tue, 05 Mar 2024 14:30:00 +0000\r\n" +
+		"Content-Type: multipart/mixed; boundary=b\r\n\r\n" +
+		"--b\r\nContent-Type: text/html\r\n\r\n<p>See <b>attached</b>.</p><ul><li>one</li></ul>\r\n" +
+		"--b\r\nContent-Type: application/octet-stream\r\nContent-Disposition: attachment; filename=\"data (1).bin\"\r\n\r\nDATA\r\n" +
+		"--b--\r\n"
+	want := "# Report\n\n" +
+		"**From:** Ada <ada@example.com>  \n**To:** bob@example.com  \n**Date:** Tue, 05 Mar 2024 14:30:00 +0000\n\n" +
+		"See **attached**.\n\n* one\n\n" +
+		"## Attachments\n\n* [data (1).bin](assets/mail-data%20%281%29.bin)\n"
+
+	dir := t.TempDir()
+	input := filepath.Join(dir, "mail.eml")
+	if err := os.WriteFile(input, []byte(eml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	assets := filepath.Join(dir, "assets")
+	if err := os.Mkdir(assets, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	c := &Converter{AssetsDir: assets, AssetsLink: "assets", ListStyle: markdown.ListStyle{Bullet: "*"}}
+	if err := c.ToMarkdown(input, &out); err != nil {
+		t.Fatalf("ToMarkdown() error = %v", err)
+	}
+	if got := out.String(); got != want {
+		t.Errorf("ToMarkdown() =\n%s\nwant:\n%s", got, want)
+	}
+	if data, err := os.ReadFile(filepath.Join(assets, "mail-data (1).bin")); err != nil || string(data) != "DATA" {
+		t.Errorf("attachment = %q, %v", data, err)
+	}
+}
+
+func TestToMarkdownLooseHTML(t *testing.T) {
+	const eml = "Subject: Offer\r\nContent-Type: text/html\r\n\r\n" +
+		"<table width=100%><tr><td><a href=http://example.com/x>Deal</a></td></tr></table></div><p>a < b</p>\r\n"
+	input := filepath.Join(t.TempDir(), "offer.eml")
+	if err := os.WriteFile(input, []byte(eml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := (&Converter{AssetsDir: t.TempDir()}).ToMarkdown(input, &out); err != nil {
+		t.Fatalf("ToMarkdown() error = %v", err)
+	}
+	if want := "# Offer\n\n| [Deal](http://example.com/x) |\n| --- |\n\na < b\n"; out.String() != want {
+		t.Errorf("ToMarkdown() = %q, want %q", out.String(), want)
+	}
+}
+
+func TestAttachmentFileName(t *testing.T) {
+	tests := []struct {
+		attName string
+		index   int
+		want    string
+	}{
+		{"report.pdf", 1, "mail-report.pdf"},
+		{`C:\Users\ada\report.pdf`, 1, "mail-report.pdf"},
+		{"../../etc/passwd", 2, "mail-passwd"},
+		{"", 3, "mail-attachment3"},
+		{"/", 4, "mail-attachment4"},
+	}
+	for _, tt := range tests {
+		if got := attachmentFileName("mail", tt.attName, tt.index); got != tt.want {
+			t.Errorf("attachmentFileName(%q) = %q, want %q", tt.attName, got, tt.want)
+		}
+	}
+}
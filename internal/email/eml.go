@@ -0,0 +1,170 @@
+package email
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/errs"
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// maxMIMEDepth bounds the nesting of multipart bodies
+const maxMIMEDepth = 16
+
+var wordDecoder = &mime.WordDecoder{CharsetReader: charsetReader}
+
+// parseEML reads an RFC 822 message, walking multipart bodies for text and attachments
+func parseEML(data []byte) (*message, error) {
+	m, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to parse message: %v", errs.ErrCorrupt, err)
+	}
+
+	msg := &message{
+		From:    decodeHeader(m.Header.Get("From")),
+		To:      decodeHeader(m.Header.Get("To")),
+		Cc:      decodeHeader(m.Header.Get("Cc")),
+		Subject: decodeHeader(m.Header.Get("Subject")),
+		Date:    m.Header.Get("Date"),
+	}
+
+	header := mimeHeader{
+		contentType: m.Header.Get("Content-Type"),
+		encoding:    m.Header.Get("Content-Transfer-Encoding"),
+		disposition: m.Header.Get("Content-Disposition"),
+	}
+	if err := msg.addPart(header, m.Body, 0); err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+type mimeHeader struct {
+	contentType string
+	encoding    string
+	disposition string
+}
+
+func (msg *message) addPart(header mimeHeader, body io.Reader, depth int) error {
+	if depth > maxMIMEDepth {
+		return fmt.Errorf("%w: message nesting too deep", errs.ErrCorrupt)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(header.contentType)
+	if err != nil {
+		mediaType, params = "text/plain", map[string]string{}
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		mr := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := mr.NextRawPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("%w: failed to read message part: %v", errs.ErrCorrupt, err)
+			}
+			partHeader := mimeHeader{
+				contentType: part.Header.Get("Content-Type"),
+				encoding:    part.Header.Get("Content-Transfer-Encoding"),
+				disposition: part.Header.Get("Content-Disposition"),
+			}
+			if err := msg.addPart(partHeader, part, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+
+	data, err := io.ReadAll(transferDecoder(header.encoding, body))
+	if err != nil {
+		return fmt.Errorf("%w: failed to decode message part: %v", errs.ErrCorrupt, err)
+	}
+
+	disposition, dispParams, _ := mime.ParseMediaType(header.disposition)
+	fileName := dispParams["filename"]
+	if fileName == "" {
+		fileName = params["name"]
+	}
+	fileName = decodeHeader(fileName)
+
+	switch {
+	case disposition == "attachment" || fileName != "" && !strings.HasPrefix(mediaType, "text/"):
+		msg.Attachments = append(msg.Attachments, attachment{Name: fileName, Data: data})
+	case mediaType == "text/plain" && msg.Text == "":
+		msg.Text = decodeCharset(data, params["charset"])
+	case mediaType == "text/html" && msg.HTML == "":
+		msg.HTML = decodeCharset(data, params["charset"])
+	case fileName != "":
+		msg.Attachments = append(msg.Attachments, attachment{Name: fileName, Data: data})
+	}
+
+	return nil
+}
+
+func transferDecoder(encoding string, r io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, &whitespaceStripper{r: r})
+	case "quoted-printable":
+		return quotedprintable.NewReader(r)
+	}
+	return r
+}
+
+// whitespaceStripper drops the line breaks that wrap base64 bodies
+type whitespaceStripper struct {
+	r io.Reader
+}
+
+func (s *whitespaceStripper) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	kept := 0
+	for _, b := range p[:n] {
+		if b != '\r' && b != '\n' && b != ' ' && b != '\t' {
+			p[kept] = b
+			kept++
+		}
+	}
+	return kept, err
+}
+
+func decodeHeader(value string) string {
+	decoded, err := wordDecoder.DecodeHeader(value)
+	if err != nil {
+		return value
+	}
+	return decoded
+}
+
+func decodeCharset(data []byte, charset string) string {
+	if charset == "" || strings.EqualFold(charset, "utf-8") || strings.EqualFold(charset, "us-ascii") {
+		return string(data)
+	}
+
+	r, err := charsetReader(charset, bytes.NewReader(data))
+	if err != nil {
+		return string(data)
+	}
+	decoded, err := io.ReadAll(r)
+	if err != nil {
+		return string(data)
+	}
+	return string(decoded)
+}
+
+func charsetReader(charset string, input io.Reader) (io.Reader, error) {
+	enc, err := htmlindex.Get(charset)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported charset %s", charset)
+	}
+	return enc.NewDecoder().Reader(input), nil
+}
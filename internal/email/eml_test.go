@@ -0,0 +1,76 @@
+package email
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/errs"
+)
+
+func TestParseEML(t *testing.T) {
+	tests := []struct {
+		name string
+		eml  string
+		want *message
+	}{
+		{
+			name: "plain",
+			eml:  "From: Ada <ada@example.com>\r\nTo: bob@example.com\r\nSubject: Hello\r\nDate: Tue, 05 Mar 2024 14:30:00 +0000\r\n\r\nJust text.\r\n",
+			want: &message{From: "Ada <ada@example.com>", To: "bob@example.com", Subject: "Hello", Date: "Tue, 05 Mar 2024 14:30:00 +0000", Text: "Just text.\r\n"},
+		},
+		{
+			name: "encoded words",
+			eml:  "Subject: =?UTF-8?B?UsOpc3Vtw6k=?=\r\nFrom: =?ISO-8859-1?Q?Andr=E9?= <a@example.com>\r\n\r\nBody",
+			want: &message{From: "André <a@example.com>", Subject: "Résumé", Text: "Body"},
+		},
+		{
+			name: "quoted printable in a legacy charset",
+			eml:  "Content-Type: text/plain; charset=iso-8859-1\r\nContent-Transfer-Encoding: quoted-printable\r\n\r\nCaf=E9 au lait=\r\n, s'il vous pla=EEt",
+			want: &message{Text: "Café au lait, s'il vous plaît"},
+		},
+		{
+			name: "alternative with attachment",
+			eml: "Subject: Report\r\nContent-Type: multipart/mixed; boundary=outer\r\n\r\n" +
+				"--outer\r\nContent-Type: multipart/alternative; boundary=inner\r\n\r\n" +
+				"--inner\r\nContent-Type: text/plain\r\n\r\nPlain\r\n" +
+				"--inner\r\nContent-Type: text/html\r\n\r\n<p>Rich</p>\r\n" +
+				"--inner--\r\n" +
+				"--outer\r\nContent-Type: application/pdf; name=\"report.pdf\"\r\nContent-Transfer-Encoding: base64\r\nContent-Disposition: attachment\r\n\r\nJVBE\r\nRi0x\r\n" +
+				"--outer\r\nContent-Type: text/plain\r\nContent-Disposition: attachment; filename=notes.txt\r\n\r\nnotes\r\n" +
+				"--outer--\r\n",
+			want: &message{
+				Subject: "Report",
+				Text:    "Plain",
+				HTML:    "<p>Rich</p>",
+				Attachments: []attachment{
+					{Name: "report.pdf", Data: []byte("%PDF-1")},
+					{Name: "notes.txt", Data: []byte("notes")},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		got, err := parseEML([]byte(tt.eml))
+		if err != nil {
+			t.Errorf("%s: parseEML() error = %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: parseEML() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseEMLCorrupt(t *testing.T) {
+	nested := "Content-Type: multipart/mixed; boundary=b\r\n\r\n"
+	for i := 0; i < maxMIMEDepth+2; i++ {
+		nested += "--b\r\nContent-Type: multipart/mixed; boundary=b\r\n\r\n"
+	}
+	for _, eml := range []string{"not a header line\r\n\r\n", nested} {
+		if _, err := parseEML([]byte(eml)); !errors.Is(err, errs.ErrCorrupt) {
+			t.Errorf("parseEML(%q) error = %v, want ErrCorrupt", strings.SplitN(eml, "\r\n", 2)[0], err)
+		}
+	}
+}
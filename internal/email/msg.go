@@ -0,0 +1,159 @@
+package email
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/errs"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// MAPI property IDs read from Outlook messages
+const (
+	propSubject        = "0037"
+	propSenderName     = "0C1A"
+	propSenderEmail    = "0C1F"
+	propSenderSMTP     = "5D01"
+	propDisplayTo      = "0E04"
+	propDisplayCc      = "0E03"
+	propBody           = "1000"
+	propHTML           = "1013"
+	propAttachLongName = "3707"
+	propAttachName     = "3704"
+	propAttachData     = "3701"
+	propSubmitTime     = 0x0039
+	propDeliveryTime   = 0x0E06
+)
+
+const (
+	typeUnicode = "001F"
+	typeString8 = "001E"
+	typeBinary  = "0102"
+	typeSysTime = 0x0040
+)
+
+// IsMSG reports whether data is an Outlook message: a compound file whose
+// root storage holds MAPI property streams. Other documents stored as compound
+// files, such as .doc and .xls, hold none.
+func IsMSG(data []byte) bool {
+	f, err := openCFB(data)
+	if err != nil {
+		return false
+	}
+	for _, entry := range f.children(f.entries[0]) {
+		if strings.HasPrefix(entry.Name, "__substg1.0_") || entry.Name == "__properties_version1.0" {
+			return true
+		}
+	}
+	return false
+}
+
+// parseMSG reads an Outlook message stored as a compound file
+func parseMSG(data []byte) (*message, error) {
+	f, err := openCFB(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to parse message: %v", errs.ErrCorrupt, err)
+	}
+
+	root := f.entries[0]
+	props, err := f.streams(root)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := &message{
+		Subject: stringProp(props, propSubject),
+		To:      stringProp(props, propDisplayTo),
+		Cc:      stringProp(props, propDisplayCc),
+		Text:    stringProp(props, propBody),
+		HTML:    stringProp(props, propHTML),
+	}
+	if msg.HTML == "" {
+		msg.HTML = string(props[substg(propHTML, typeBinary)])
+	}
+
+	msg.From = stringProp(props, propSenderName)
+	email := stringProp(props, propSenderSMTP)
+	if email == "" {
+		email = stringProp(props, propSenderEmail)
+	}
+	switch {
+	case msg.From == "":
+		msg.From = email
+	case email != "" && email != msg.From:
+		msg.From += " <" + email + ">"
+	}
+
+	if date, ok := fixedTimeProp(props["__properties_version1.0"], propSubmitTime, propDeliveryTime); ok {
+		msg.Date = date.UTC().Format(time.RFC1123Z)
+	}
+
+	for _, entry := range f.children(root) {
+		if entry.Type != cfbTypeStorage || !strings.HasPrefix(entry.Name, "__attach_version1.0_") {
+			continue
+		}
+		attProps, err := f.streams(entry)
+		if err != nil {
+			return nil, err
+		}
+		data, ok := attProps[substg(propAttachData, typeBinary)]
+		if !ok {
+			continue // embedded messages and OLE objects are not extracted
+		}
+		name := stringProp(attProps, propAttachLongName)
+		if name == "" {
+			name = stringProp(attProps, propAttachName)
+		}
+		msg.Attachments = append(msg.Attachments, attachment{Name: name, Data: data})
+	}
+
+	return msg, nil
+}
+
+func substg(id, typ string) string {
+	return "__substg1.0_" + id + typ
+}
+
+// stringProp reads a string property stored either as UTF-16 or in the ANSI code page
+func stringProp(props map[string][]byte, id string) string {
+	if data, ok := props[substg(id, typeUnicode)]; ok {
+		units := make([]uint16, len(data)/2)
+		for i := range units {
+			units[i] = binary.LittleEndian.Uint16(data[2*i:])
+		}
+		return strings.TrimRight(string(utf16.Decode(units)), "\x00")
+	}
+	if data, ok := props[substg(id, typeString8)]; ok {
+		decoded, err := charmap.Windows1252.NewDecoder().Bytes(data)
+		if err != nil {
+			return strings.TrimRight(string(data), "\x00")
+		}
+		return strings.TrimRight(string(decoded), "\x00")
+	}
+	return ""
+}
+
+// fixedTimeProp finds the first of the given time properties in a top-level
+// properties stream, which stores fixed-size values after a 32-byte header
+func fixedTimeProp(stream []byte, ids ...uint16) (time.Time, bool) {
+	const headerSize, entrySize = 32, 16
+	values := make(map[uint16]uint64)
+	for off := headerSize; off+entrySize <= len(stream); off += entrySize {
+		tag := binary.LittleEndian.Uint32(stream[off:])
+		if tag&0xffff == typeSysTime {
+			values[uint16(tag>>16)] = binary.LittleEndian.Uint64(stream[off+8:])
+		}
+	}
+
+	for _, id := range ids {
+		if ft, ok := values[id]; ok && ft > 0 {
+			// FILETIME counts 100ns intervals since 1601-01-01
+			const epochDelta = 116444736000000000
+			return time.Unix(0, (int64(ft)-epochDelta)*100), true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,107 @@
+package email
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+	"time"
+	"unicode/utf16"
+)
+
+// unicodeProp encodes a string property stream as UTF-16
+func unicodeProp(s string) []byte {
+	units := utf16.Encode([]rune(s))
+	data := make([]byte, 2*len(units))
+	for i, u := range units {
+		binary.LittleEndian.PutUint16(data[2*i:], u)
+	}
+	return data
+}
+
+// propertiesStream holds the submit time of a message in the fixed-size
+// values of its top-level properties stream
+func propertiesStream(submitted time.Time) []byte {
+	data := make([]byte, 32+16)
+	binary.LittleEndian.PutUint32(data[32:], uint32(propSubmitTime)<<16|typeSysTime)
+	binary.LittleEndian.PutUint64(data[40:], uint64(submitted.UnixNano()/100+116444736000000000))
+	return data
+}
+
+func TestParseMSG(t *testing.T) {
+	submitted := time.Date(2024, 3, 5, 14, 30, 0, 0, time.UTC)
+	data := buildCFB(
+		cfbNode{name: "__properties_version1.0", data: propertiesStream(submitted)},
+		cfbNode{name: substg(propSubject, typeUnicode), data: unicodeProp("Quarterly résumé\x00")},
+		cfbNode{name: substg(propSenderName, typeUnicode), data: unicodeProp("Ada Lovelace")},
+		cfbNode{name: substg(propSenderSMTP, typeUnicode), data: unicodeProp("ada@example.com")},
+		cfbNode{name: substg(propDisplayTo, typeString8), data: []byte("Caf\xe9 Team")},
+		cfbNode{name: substg(propBody, typeUnicode), data: unicodeProp("Plain body")},
+		cfbNode{name: substg(propHTML, typeBinary), data: []byte("<p>HTML body</p>")},
+		cfbNode{name: "__attach_version1.0_#00000000", storage: true, kids: []cfbNode{
+			{name: substg(propAttachLongName, typeUnicode), data: unicodeProp("report.pdf")},
+			{name: substg(propAttachData, typeBinary), data: []byte("%PDF-1.7")},
+		}},
+		cfbNode{name: "__attach_version1.0_#00000001", storage: true, kids: []cfbNode{
+			{name: substg(propAttachName, typeString8), data: []byte("embedded.msg")},
+		}},
+	)
+
+	got, err := parseMSG(data)
+	if err != nil {
+		t.Fatalf("parseMSG() error = %v", err)
+	}
+	want := &message{
+		From:        "Ada Lovelace <ada@example.com>",
+		To:          "Café Team",
+		Subject:     "Quarterly résumé",
+		Date:        "Tue, 05 Mar 2024 14:30:00 +0000",
+		Text:        "Plain body",
+		HTML:        "<p>HTML body</p>",
+		Attachments: []attachment{{Name: "report.pdf", Data: []byte("%PDF-1.7")}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseMSG() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseMSGSender(t *testing.T) {
+	tests := []struct {
+		name  string
+		nodes []cfbNode
+		want  string
+	}{
+		{"address only", []cfbNode{{name: substg(propSenderEmail, typeUnicode), data: unicodeProp("bob@example.com")}}, "bob@example.com"},
+		{"name only", []cfbNode{{name: substg(propSenderName, typeUnicode), data: unicodeProp("Bob")}}, "Bob"},
+		{"name is the address", []cfbNode{
+			{name: substg(propSenderName, typeUnicode), data: unicodeProp("bob@example.com")},
+			{name: substg(propSenderSMTP, typeUnicode), data: unicodeProp("bob@example.com")},
+		}, "bob@example.com"},
+	}
+	for _, tt := range tests {
+		msg, err := parseMSG(buildCFB(tt.nodes...))
+		if err != nil {
+			t.Fatalf("%s: parseMSG() error = %v", tt.name, err)
+		}
+		if msg.From != tt.want {
+			t.Errorf("%s: From = %q, want %q", tt.name, msg.From, tt.want)
+		}
+	}
+}
+
+func TestIsMSG(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want bool
+	}{
+		{"message", buildCFB(cfbNode{name: substg(propSubject, typeUnicode), data: unicodeProp("Hi")}), true},
+		{"properties only", buildCFB(cfbNode{name: "__properties_version1.0", data: make([]byte, 32)}), true},
+		{"word document", buildCFB(cfbNode{name: "WordDocument", data: []byte("doc")}), false},
+		{"not a compound file", []byte("%PDF-1.7"), false},
+	}
+	for _, tt := range tests {
+		if got := IsMSG(tt.data); got != tt.want {
+			t.Errorf("%s: IsMSG() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
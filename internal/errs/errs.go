@@ -0,0 +1,22 @@
+// Package errs defines the error categories shared by the converters. Errors
+// returned by the converters wrap one of these so callers can test them with errors.Is.
+package errs
+
+import "errors"
+
+var (
+	// ErrUnsupportedType is returned for inputs no converter handles
+	ErrUnsupportedType = errors.New("unsupported file type")
+	// ErrEncrypted is returned for documents that cannot be read without a password
+	// or whose encrypted content is not supported
+	ErrEncrypted = errors.New("document is encrypted")
+	// ErrCorrupt is returned for documents that cannot be parsed
+	ErrCorrupt = errors.New("document is corrupt")
+	// ErrNoPages is returned for documents without any page content
+	ErrNoPages = errors.New("document contains no pages")
+	// ErrNotFound is returned for inputs that do not exist
+	ErrNotFound = errors.New("input file does not exist")
+	// ErrEmptyOutput is returned when a conversion that must produce text
+	// produces none
+	ErrEmptyOutput = errors.New("conversion produced no output")
+)
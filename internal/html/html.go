@@ -0,0 +1,337 @@
+package html
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+)
+
+// listState tracks an open <ul> or <ol>
+type listState struct {
+	ordered bool
+	next    int
+	// width is the width of the marker of the last item written, which
+	// items of nested lists are indented by
+	width int
+}
+
+// renderer converts a stream of HTML tokens into Markdown blocks
+type renderer struct {
+	listStyle  markdown.ListStyle
+	result     strings.Builder
+	inline     strings.Builder
+	skipDepth  int
+	preDepth   int
+	quoteDepth int
+	heading    int
+	lists      []listState
+	links      []string
+	linkStarts []int
+	table      [][]string
+	row        []string
+	inTable    bool
+	// open counts the elements started and not yet ended, by tag, so that
+	// stray end tags are ignored
+	open map[string]int
+}
+
+// Convert renders an HTML document or fragment as Markdown using the given list
+// markers. The tokenizer never fails on bad markup, such as the unquoted
+// attributes, unclosed elements and stray end tags found in email bodies, so
+// the only errors are those reading r.
+func Convert(r io.Reader, listStyle markdown.ListStyle) (string, error) {
+	z := html.NewTokenizer(r)
+	rd := renderer{listStyle: listStyle, open: make(map[string]int)}
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			// Elements left open at the end of the document are closed there
+			if err := z.Err(); err != io.EOF {
+				return "", fmt.Errorf("failed to read HTML: %v", err)
+			}
+			rd.flush()
+			return strings.TrimSpace(rd.result.String()) + "\n", nil
+		case html.StartTagToken:
+			t := z.Token()
+			rd.start(t.Data, t.Attr)
+		case html.SelfClosingTagToken:
+			t := z.Token()
+			rd.start(t.Data, t.Attr)
+			if !voidElements[t.Data] {
+				rd.end(t.Data)
+			}
+		case html.EndTagToken:
+			rd.end(z.Token().Data)
+		case html.TextToken:
+			rd.text(string(z.Text()))
+		}
+	}
+}
+
+// voidElements are the elements that have no end tag
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "param": true, "source": true, "track": true, "wbr": true,
+}
+
+// sameElements maps the tags rendered alike to one of them, so that one can
+// end the other
+var sameElements = map[string]string{"b": "strong", "i": "em"}
+
+// skippedElements are the elements whose content is left out of the Markdown
+var skippedElements = map[string]bool{"script": true, "style": true, "head": true, "title": true}
+
+func (rd *renderer) start(tag string, attrs []html.Attribute) {
+	// Only skipped elements are counted while skipping, so void and stray
+	// tags within them cannot end the skip early or keep it from ending
+	if skippedElements[tag] {
+		rd.skipDepth++
+	}
+	if rd.skipDepth > 0 {
+		return
+	}
+	if !voidElements[tag] {
+		rd.open[openKey(tag)]++
+	}
+
+	switch tag {
+	case "p", "div", "section", "article", "header", "footer":
+		rd.flush()
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		rd.flush()
+		rd.heading = int(tag[1] - '0')
+	case "br":
+		if rd.preDepth > 0 {
+			rd.inline.WriteString("\n")
+		} else {
+			rd.inline.WriteString("  \n")
+		}
+	case "hr":
+		rd.flush()
+		rd.result.WriteString("---\n\n")
+	case "ul", "ol":
+		rd.flush()
+		rd.lists = append(rd.lists, listState{ordered: tag == "ol", next: 1})
+	case "li":
+		rd.flush()
+	case "blockquote":
+		rd.flush()
+		rd.quoteDepth++
+	case "pre":
+		rd.flush()
+		rd.preDepth++
+	case "strong", "b":
+		rd.inline.WriteString("**")
+	case "em", "i":
+		rd.inline.WriteString("*")
+	case "code":
+		if rd.preDepth == 0 {
+			rd.inline.WriteString("`")
+		}
+	case "a":
+		rd.links = append(rd.links, attr(attrs, "href"))
+		rd.linkStarts = append(rd.linkStarts, rd.inline.Len())
+	case "img":
+		if src := attr(attrs, "src"); src != "" {
+			rd.inline.WriteString("![" + attr(attrs, "alt") + "](" + src + ")")
+		}
+	case "table":
+		rd.flush()
+		rd.inTable = true
+		rd.table = nil
+	case "tr":
+		rd.row = nil
+	case "td", "th":
+		rd.inline.Reset()
+	}
+}
+
+func (rd *renderer) end(tag string) {
+	if rd.skipDepth > 0 {
+		if skippedElements[tag] {
+			rd.skipDepth--
+		}
+		return
+	}
+	if rd.open[openKey(tag)] == 0 {
+		return
+	}
+	rd.open[openKey(tag)]--
+
+	switch tag {
+	case "p", "div", "section", "article", "header", "footer", "li":
+		rd.flush()
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		rd.flush()
+		rd.heading = 0
+	case "ul", "ol":
+		rd.flush()
+		if len(rd.lists) > 0 {
+			rd.lists = rd.lists[:len(rd.lists)-1]
+		}
+		if len(rd.lists) == 0 {
+			rd.result.WriteString("\n")
+		}
+	case "blockquote":
+		rd.flush()
+		if rd.quoteDepth > 0 {
+			rd.quoteDepth--
+		}
+	case "pre":
+		code := strings.Trim(rd.inline.String(), "\n")
+		rd.inline.Reset()
+		if rd.preDepth > 0 {
+			rd.preDepth--
+		}
+		if code != "" {
+			rd.result.WriteString("```\n" + code + "\n```\n\n")
+		}
+	case "strong", "b":
+		rd.inline.WriteString("**")
+	case "em", "i":
+		rd.inline.WriteString("*")
+	case "code":
+		if rd.preDepth == 0 {
+			rd.inline.WriteString("`")
+		}
+	case "a":
+		if n := len(rd.links); n > 0 {
+			href, start := rd.links[n-1], rd.linkStarts[n-1]
+			rd.links, rd.linkStarts = rd.links[:n-1], rd.linkStarts[:n-1]
+			if href != "" && start <= rd.inline.Len() {
+				text := rd.inline.String()
+				rd.inline.Reset()
+				rd.inline.WriteString(text[:start] + "[" + strings.TrimSpace(text[start:]) + "](" + href + ")")
+			}
+		}
+	case "td", "th":
+		cell := collapseSpace(rd.inline.String())
+		rd.inline.Reset()
+		rd.row = append(rd.row, strings.ReplaceAll(cell, "|", "\\|"))
+	case "tr":
+		if len(rd.row) > 0 {
+			rd.table = append(rd.table, rd.row)
+		}
+		rd.row = nil
+	case "table":
+		rd.inTable = false
+		rd.writeTable()
+	}
+}
+
+func (rd *renderer) text(s string) {
+	if rd.skipDepth > 0 {
+		return
+	}
+	if rd.preDepth > 0 {
+		rd.inline.WriteString(s)
+		return
+	}
+
+	// Collapse whitespace the way a browser would
+	if strings.TrimSpace(s) == "" {
+		if rd.inline.Len() > 0 {
+			rd.inline.WriteString(" ")
+		}
+		return
+	}
+	if s[0] == ' ' || s[0] == '\n' || s[0] == '\t' || s[0] == '\r' {
+		rd.inline.WriteString(" ")
+	}
+	rd.inline.WriteString(strings.Join(strings.Fields(s), " "))
+	if last := s[len(s)-1]; last == ' ' || last == '\n' || last == '\t' || last == '\r' {
+		rd.inline.WriteString(" ")
+	}
+}
+
+// flush writes the pending inline text as a block
+func (rd *renderer) flush() {
+	if rd.inTable || rd.preDepth > 0 {
+		return
+	}
+
+	text := strings.TrimSpace(rd.inline.String())
+	rd.inline.Reset()
+	if text == "" {
+		return
+	}
+
+	switch {
+	case rd.heading > 0:
+		text = strings.Repeat("#", rd.heading) + " " + text
+	case len(rd.lists) > 0:
+		indent := 0
+		for _, parent := range rd.lists[:len(rd.lists)-1] {
+			indent += parent.width
+		}
+		list := &rd.lists[len(rd.lists)-1]
+		marker := rd.listStyle.BulletMarker()
+		if list.ordered {
+			marker = rd.listStyle.OrderedMarker(list.next)
+			list.next++
+		}
+		list.width = len(marker) + 1
+		text = strings.Repeat(" ", indent) + marker + " " + text
+	}
+
+	if rd.quoteDepth > 0 {
+		prefix := strings.Repeat("> ", rd.quoteDepth)
+		text = prefix + strings.ReplaceAll(text, "\n", "\n"+prefix)
+	}
+
+	if len(rd.lists) > 0 && rd.heading == 0 {
+		rd.result.WriteString(text + "\n")
+		return
+	}
+	rd.result.WriteString(text + "\n\n")
+}
+
+func (rd *renderer) writeTable() {
+	if len(rd.table) == 0 {
+		return
+	}
+
+	columns := 0
+	for _, row := range rd.table {
+		if len(row) > columns {
+			columns = len(row)
+		}
+	}
+
+	for i, row := range rd.table {
+		for len(row) < columns {
+			row = append(row, "")
+		}
+		rd.result.WriteString("| " + strings.Join(row, " | ") + " |\n")
+		if i == 0 {
+			rd.result.WriteString("|" + strings.Repeat(" --- |", columns) + "\n")
+		}
+	}
+	rd.result.WriteString("\n")
+	rd.table = nil
+}
+
+// openKey is the tag elements are counted as open under
+func openKey(tag string) string {
+	if same, ok := sameElements[tag]; ok {
+		return same
+	}
+	return tag
+}
+
+func attr(attrs []html.Attribute, name string) string {
+	for _, a := range attrs {
+		if a.Key == name {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
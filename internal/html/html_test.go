@@ -0,0 +1,51 @@
+package html
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name  string
+		html  string
+		style markdown.ListStyle
+		want  string
+	}{
+		{"paragraphs", "<p>One</p><p>Two  words</p>", markdown.ListStyle{}, "One\n\nTwo words\n"},
+		{"headings", "<h1>Title</h1><h3>Sub</h3>", markdown.ListStyle{}, "# Title\n\n### Sub\n"},
+		{"emphasis", "<p><b>bold</b>, <em>it</em> and <code>x()</code></p>", markdown.ListStyle{}, "**bold**, *it* and `x()`\n"},
+		{"link", `<p>See <a href="https://example.com">the site</a>.</p>`, markdown.ListStyle{}, "See [the site](https://example.com).\n"},
+		{"image", `<p><img src="cid:logo" alt="Logo"></p>`, markdown.ListStyle{}, "![Logo](cid:logo)\n"},
+		{"line break", "<p>a<br>b</p>", markdown.ListStyle{}, "a  \nb\n"},
+		{"bullets", "<ul><li>a</li><li>b</li></ul>", markdown.ListStyle{}, "- a\n- b\n"},
+		{"bullet style", "<ul><li>a</li></ul>", markdown.ListStyle{Bullet: "*"}, "* a\n"},
+		{"ordered", "<ol><li>a</li><li>b</li></ol>", markdown.ListStyle{}, "1. a\n2. b\n"},
+		{"nested", "<ul><li>a<ul><li>b</li></ul></li></ul>", markdown.ListStyle{}, "- a\n  - b\n"},
+		{"nested style", "<ol><li>a<ul><li>b<ul><li>c</li></ul></li></ul></li><li>d</li></ol>", markdown.ListStyle{Bullet: "*", Ordered: ")"}, "1) a\n   * b\n     * c\n2) d\n"},
+		{"quote", "<blockquote><p>Quoted</p></blockquote>", markdown.ListStyle{}, "> Quoted\n"},
+		{"pre", "<pre>  x := 1\n  y := 2</pre>", markdown.ListStyle{}, "```\n  x := 1\n  y := 2\n```\n"},
+		{"table", "<table><tr><th>A</th><th>B</th></tr><tr><td>1|2</td></tr></table>", markdown.ListStyle{}, "| A | B |\n| --- | --- |\n| 1\\|2 |  |\n"},
+		{"skipped elements", "<head><title>T</title><style>p{}</style></head><p>Body</p><script>x()</script>", markdown.ListStyle{}, "Body\n"},
+		{"rule", "<p>a</p><hr><p>b</p>", markdown.ListStyle{}, "a\n\n---\n\nb\n"},
+		{"unquoted attributes", `<p><a href=http://example.com/x>site</a> and <a href=foo.html>page</a></p>`, markdown.ListStyle{}, "[site](http://example.com/x) and [page](foo.html)\n"},
+		{"attribute without a valid value", "<table width=100%><tr><td>a</td></tr></table>", markdown.ListStyle{}, "| a |\n| --- |\n"},
+		{"stray end tags", "<p>One</p></div></b><p>Two</p>", markdown.ListStyle{}, "One\n\nTwo\n"},
+		{"bare less-than and ampersand", "<p>a < b & c</p>", markdown.ListStyle{}, "a < b & c\n"},
+		{"void and stray tags in skipped elements", "<head><meta charset=utf-8><link rel=stylesheet></p></head><p>Body</p>", markdown.ListStyle{}, "Body\n"},
+		{"self-closing tags", "<p>a<br/>b</p><hr/><p>c</p>", markdown.ListStyle{}, "a  \nb\n\n---\n\nc\n"},
+		{"unclosed and entities", "<p>Fish &amp; chips<p>Caf&eacute;", markdown.ListStyle{}, "Fish & chips\n\nCafé\n"},
+	}
+	for _, tt := range tests {
+		got, err := Convert(strings.NewReader(tt.html), tt.style)
+		if err != nil {
+			t.Errorf("%s: Convert() error = %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: Convert(%q) = %q, want %q", tt.name, tt.html, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+// Package imagefile converts standalone image files to Markdown that embeds them.
+package imagefile
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/utils"
+)
+
+// Converter copies an image into the assets directory and references it from the Markdown
+type Converter struct {
+	AssetsDir string
+	// AssetsLink is the path prefix used to reference assets from the Markdown,
+	// defaulting to AssetsDir
+	AssetsLink string
+	// AssetNamer names extracted assets, keeping positional names when nil
+	AssetNamer *utils.AssetNamer
+}
+
+func (c *Converter) ToMarkdown(inputPath string, w io.Writer) error {
+	data, err := os.ReadFile(inputPath)
+	if err != nil {
+		return fmt.Errorf("failed to open image: %v", err)
+	}
+
+	alt := strings.TrimSuffix(filepath.Base(inputPath), filepath.Ext(inputPath))
+	name := c.AssetNamer.Name(assetName(inputPath), data)
+	if err := os.WriteFile(filepath.Join(c.AssetsDir, name), data, 0644); err != nil {
+		return fmt.Errorf("failed to write image: %v", err)
+	}
+
+	link := utils.AssetLink(c.AssetsLink, c.AssetsDir, name)
+	if _, err := io.WriteString(w, "!["+alt+"]("+link+")\n"); err != nil {
+		return fmt.Errorf("failed to write markdown: %v", err)
+	}
+	return nil
+}
+
+// assetName returns the positional name of the copy of an image: the
+// document name followed by a digest of the path it was read from, so images
+// of the same name in different directories do not overwrite each other
+func assetName(inputPath string) string {
+	if abs, err := filepath.Abs(inputPath); err == nil {
+		inputPath = abs
+	}
+	base := filepath.Base(inputPath)
+	sum := sha256.Sum256([]byte(inputPath))
+	return fmt.Sprintf("%s-%s%s", strings.TrimSuffix(base, filepath.Ext(base)), hex.EncodeToString(sum[:4]), filepath.Ext(base))
+}
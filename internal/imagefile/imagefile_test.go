@@ -0,0 +1,50 @@
+package imagefile
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestToMarkdown(t *testing.T) {
+	root := t.TempDir()
+	assets := filepath.Join(root, "assets")
+	if err := os.Mkdir(assets, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	// Images of the same name in two directories keep a copy each
+	links := make(map[string]bool)
+	for _, dir := range []string{"dir1", "dir2", "with space"} {
+		input := filepath.Join(root, dir, "logo (1).png")
+		if err := os.MkdirAll(filepath.Dir(input), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(input, []byte(dir), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		var out bytes.Buffer
+		c := &Converter{AssetsDir: assets, AssetsLink: "assets"}
+		if err := c.ToMarkdown(input, &out); err != nil {
+			t.Fatalf("ToMarkdown(%s) error = %v", input, err)
+		}
+		md := strings.TrimSpace(out.String())
+		if !strings.HasPrefix(md, "![logo (1)](assets/logo%20%281%29-") || !strings.HasSuffix(md, ".png)") {
+			t.Errorf("ToMarkdown(%s) = %q", input, md)
+		}
+		links[md] = true
+	}
+	if len(links) != 3 {
+		t.Errorf("links = %v, want one per directory", links)
+	}
+	entries, err := os.ReadDir(assets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Errorf("assets directory holds %d files, want 3", len(entries))
+	}
+}
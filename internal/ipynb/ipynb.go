@@ -0,0 +1,201 @@
+// Package ipynb converts Jupyter notebooks (.ipynb) to Markdown. Markdown
+// cells pass through, code cells become fenced code in the language of the
+// notebook's kernel and their outputs follow them: text as fenced code,
+// Markdown as it is and images as assets.
+package ipynb
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/errs"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/utils"
+)
+
+// Converter converts Jupyter notebooks to Markdown
+type Converter struct {
+	AssetsDir string
+	// AssetsLink is the path prefix used to reference assets from the Markdown,
+	// defaulting to AssetsDir
+	AssetsLink string
+	// AssetNamer names extracted assets, keeping positional names when nil
+	AssetNamer *utils.AssetNamer
+}
+
+// source is a multiline string of a notebook, stored either whole or as a
+// list of lines that keep their line endings
+type source string
+
+func (s *source) UnmarshalJSON(data []byte) error {
+	var lines []string
+	if err := json.Unmarshal(data, &lines); err == nil {
+		*s = source(strings.Join(lines, ""))
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	*s = source(text)
+	return nil
+}
+
+// notebook is the nbformat 4 document
+type notebook struct {
+	Cells    []cell `json:"cells"`
+	Metadata struct {
+		KernelSpec struct {
+			Language string `json:"language"`
+		} `json:"kernelspec"`
+		LanguageInfo struct {
+			Name string `json:"name"`
+		} `json:"language_info"`
+	} `json:"metadata"`
+}
+
+type cell struct {
+	Type    string   `json:"cell_type"`
+	Source  source   `json:"source"`
+	Outputs []output `json:"outputs"`
+}
+
+type output struct {
+	Type string `json:"output_type"`
+	// Text is the text of a stream output
+	Text source `json:"text"`
+	// Data holds the representations of a result or display output by MIME
+	// type, left undecoded since JSON types such as widget views hold objects
+	Data map[string]json.RawMessage `json:"data"`
+	// Name and Value are the exception of an error output
+	Name  string `json:"ename"`
+	Value string `json:"evalue"`
+}
+
+// imageTypes maps the image representations of an output to their file
+// extensions, in order of preference
+var imageTypes = []struct{ mime, ext string }{
+	{"image/png", ".png"},
+	{"image/jpeg", ".jpg"},
+	{"image/gif", ".gif"},
+	{"image/svg+xml", ".svg"},
+}
+
+func (c *Converter) ToMarkdown(inputPath string, w io.Writer) error {
+	data, err := os.ReadFile(inputPath)
+	if err != nil {
+		return fmt.Errorf("failed to open notebook: %v", err)
+	}
+	var nb notebook
+	if err := json.Unmarshal(data, &nb); err != nil {
+		return fmt.Errorf("%w: failed to parse notebook: %v", errs.ErrCorrupt, err)
+	}
+
+	language := nb.Metadata.LanguageInfo.Name
+	if language == "" {
+		language = nb.Metadata.KernelSpec.Language
+	}
+	name := strings.TrimSuffix(filepath.Base(inputPath), filepath.Ext(inputPath))
+
+	var blocks []string
+	for i, cell := range nb.Cells {
+		text := strings.TrimRight(string(cell.Source), " \n")
+		switch cell.Type {
+		case "markdown", "raw":
+			if strings.TrimSpace(text) != "" {
+				blocks = append(blocks, text)
+			}
+		case "code":
+			if strings.TrimSpace(text) != "" {
+				blocks = append(blocks, fenced(text, language))
+			}
+			for j, out := range cell.Outputs {
+				block, err := c.renderOutput(out, fmt.Sprintf("%s-cell%d-output%d", name, i+1, j+1))
+				if err != nil {
+					return err
+				}
+				if block != "" {
+					blocks = append(blocks, block)
+				}
+			}
+		}
+	}
+
+	if _, err := io.WriteString(w, strings.Join(blocks, "\n\n")+"\n"); err != nil {
+		return fmt.Errorf("failed to write markdown: %v", err)
+	}
+	return nil
+}
+
+// renderOutput renders an output of a code cell, saving an image under the
+// asset name base with its extension
+func (c *Converter) renderOutput(out output, base string) (string, error) {
+	switch out.Type {
+	case "stream":
+		if text := strings.TrimRight(string(out.Text), " \n"); text != "" {
+			return fenced(text, ""), nil
+		}
+		return "", nil
+	case "error":
+		return fenced(out.Name+": "+out.Value, ""), nil
+	}
+
+	for _, image := range imageTypes {
+		encoded, ok, err := out.text(image.mime)
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			continue
+		}
+		data := []byte(encoded)
+		if image.mime != "image/svg+xml" {
+			decoded, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(string(encoded)), ""))
+			if err != nil {
+				return "", fmt.Errorf("%w: failed to decode %s output: %v", errs.ErrCorrupt, image.mime, err)
+			}
+			data = decoded
+		}
+		fileName := c.AssetNamer.Name(base+image.ext, data)
+		if err := os.WriteFile(filepath.Join(c.AssetsDir, fileName), data, 0644); err != nil {
+			return "", fmt.Errorf("failed to write image: %v", err)
+		}
+		return "![](" + utils.AssetLink(c.AssetsLink, c.AssetsDir, fileName) + ")", nil
+	}
+	if text, ok, err := out.text("text/markdown"); err != nil || ok {
+		return strings.TrimSpace(text), err
+	}
+	text, ok, err := out.text("text/plain")
+	if text = strings.TrimRight(text, " \n"); err != nil || !ok || text == "" {
+		return "", err
+	}
+	return fenced(text, ""), nil
+}
+
+// text decodes the representation of an output of a text or image MIME type,
+// reporting whether the output has one
+func (out output) text(mime string) (string, bool, error) {
+	raw, ok := out.Data[mime]
+	if !ok {
+		return "", false, nil
+	}
+	var text source
+	if err := json.Unmarshal(raw, &text); err != nil {
+		return "", false, fmt.Errorf("%w: failed to parse %s output: %v", errs.ErrCorrupt, mime, err)
+	}
+	return string(text), true, nil
+}
+
+// fenced renders text as fenced code, with a fence longer than any run of
+// backticks in the text
+func fenced(text, language string) string {
+	fence := "```"
+	for strings.Contains(text, fence) {
+		fence += "`"
+	}
+	return fence + language + "\n" + text + "\n" + fence
+}
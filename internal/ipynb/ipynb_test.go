@@ -0,0 +1,139 @@
+package ipynb
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/errs"
+)
+
+// pixel stands in for the PNG data of an image output; the converter saves
+// it without decoding the image
+var pixel = []byte("\x89PNG\r\n\x1a\nimage data")
+
+// writeNotebook writes a notebook of the given JSON, failing the test on errors
+func writeNotebook(t *testing.T, dir, notebook string) string {
+	t.Helper()
+	path := filepath.Join(dir, "analysis.ipynb")
+	if err := os.WriteFile(path, []byte(notebook), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestToMarkdown(t *testing.T) {
+	image := base64.StdEncoding.EncodeToString(pixel)
+	tests := []struct {
+		name     string
+		notebook string
+		want     string
+	}{
+		{
+			name: "markdown, code and image output",
+			notebook: `{"metadata": {"language_info": {"name": "python"}}, "cells": [
+				{"cell_type": "markdown", "source": ["# Analysis\n", "\n", "Plots the *data*."]},
+				{"cell_type": "code", "source": "plot(data)", "outputs": [
+					{"output_type": "display_data", "data": {"image/png": "` + image[:8] + `\n` + image[8:] + `", "text/plain": "<Figure>"}}
+				]}
+			]}`,
+			want: "# Analysis\n\nPlots the *data*.\n\n```python\nplot(data)\n```\n\n![](assets/analysis-cell2-output1.png)\n",
+		},
+		{
+			name: "text outputs",
+			notebook: `{"metadata": {"kernelspec": {"language": "r"}}, "cells": [
+				{"cell_type": "code", "source": ["x <- 1\n", "print(x)\n"], "outputs": [
+					{"output_type": "stream", "name": "stdout", "text": ["[1] 1\n"]},
+					{"output_type": "execute_result", "data": {"text/plain": "1"}},
+					{"output_type": "error", "ename": "Error", "evalue": "object not found"}
+				]}
+			]}`,
+			want: "```r\nx <- 1\nprint(x)\n```\n\n```\n[1] 1\n```\n\n```\n1\n```\n\n```\nError: object not found\n```\n",
+		},
+		{
+			name: "markdown output",
+			notebook: `{"cells": [
+				{"cell_type": "code", "source": "show()", "outputs": [
+					{"output_type": "display_data", "data": {"text/markdown": "**Done**", "text/plain": "Done"}}
+				]}
+			]}`,
+			want: "```\nshow()\n```\n\n**Done**\n",
+		},
+		{
+			name: "JSON outputs",
+			notebook: `{"cells": [
+				{"cell_type": "code", "source": "bar = tqdm(range(3))", "outputs": [
+					{"output_type": "display_data", "data": {
+						"application/vnd.jupyter.widget-view+json": {"model_id": "4f2a", "version_major": 2, "version_minor": 0},
+						"text/plain": "  0%|          | 0/3"
+					}},
+					{"output_type": "execute_result", "data": {"application/json": {"rows": [1, 2]}}}
+				]}
+			]}`,
+			want: "```\nbar = tqdm(range(3))\n```\n\n```\n  0%|          | 0/3\n```\n",
+		},
+		{
+			name: "empty cells left out",
+			notebook: `{"cells": [
+				{"cell_type": "markdown", "source": []},
+				{"cell_type": "code", "source": "", "outputs": []},
+				{"cell_type": "raw", "source": "Raw text"}
+			]}`,
+			want: "Raw text\n",
+		},
+		{
+			name: "fence longer than backticks in the code",
+			notebook: `{"cells": [
+				{"cell_type": "code", "source": "s = '` + "```" + `'", "outputs": []}
+			]}`,
+			want: "````\ns = '```'\n````\n",
+		},
+	}
+	for _, tt := range tests {
+		dir := t.TempDir()
+		assets := filepath.Join(dir, "assets")
+		if err := os.Mkdir(assets, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		c := &Converter{AssetsDir: assets, AssetsLink: "assets"}
+		var out bytes.Buffer
+		if err := c.ToMarkdown(writeNotebook(t, dir, tt.notebook), &out); err != nil {
+			t.Fatalf("%s: ToMarkdown() error = %v", tt.name, err)
+		}
+		if out.String() != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, out.String(), tt.want)
+		}
+		for _, m := range strings.Split(tt.want, "](assets/")[1:] {
+			name := m[:strings.Index(m, ")")]
+			data, err := os.ReadFile(filepath.Join(assets, name))
+			if err != nil {
+				t.Errorf("%s: image not extracted: %v", tt.name, err)
+			} else if !bytes.Equal(data, pixel) {
+				t.Errorf("%s: %s = %q, want %q", tt.name, name, data, pixel)
+			}
+		}
+	}
+}
+
+func TestToMarkdownCorrupt(t *testing.T) {
+	tests := []struct {
+		name     string
+		notebook string
+	}{
+		{"not JSON", "not a notebook"},
+		{"text output not a string", `{"cells": [{"cell_type": "code", "source": "", "outputs": [{"output_type": "execute_result", "data": {"text/plain": {"a": 1}}}]}]}`},
+		{"bad image data", `{"cells": [{"cell_type": "code", "source": "", "outputs": [{"output_type": "display_data", "data": {"image/png": "!!"}}]}]}`},
+	}
+	for _, tt := range tests {
+		dir := t.TempDir()
+		c := &Converter{AssetsDir: dir}
+		err := c.ToMarkdown(writeNotebook(t, dir, tt.notebook), &bytes.Buffer{})
+		if !errors.Is(err, errs.ErrCorrupt) {
+			t.Errorf("%s: ToMarkdown() error = %v, want %v", tt.name, err, errs.ErrCorrupt)
+		}
+	}
+}
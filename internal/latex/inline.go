@@ -0,0 +1,230 @@
+package latex
+
+import "strings"
+
+// emphasis maps text style commands to their Markdown delimiters
+var emphasis = map[string]string{
+	"textbf": "**",
+	"emph":   "*",
+	"textit": "*",
+	"textsl": "*",
+	"texttt": "`",
+}
+
+// symbols maps argument-less commands to the text they print
+var symbols = map[string]string{
+	"LaTeX":      "LaTeX",
+	"TeX":        "TeX",
+	"ldots":      "…",
+	"dots":       "…",
+	"textendash": "–",
+	"textemdash": "—",
+	"S":          "§",
+	"copyright":  "©",
+	"quad":       " ",
+	"qquad":      " ",
+}
+
+// dropped are commands whose arguments are not document text, such as
+// cross-reference targets, spacing and the title fields rendered separately
+var dropped = map[string]bool{
+	"label": true, "ref": true, "eqref": true, "pageref": true, "cite": true, "index": true,
+	"vspace": true, "hspace": true, "usepackage": true, "documentclass": true,
+	"newcommand": true, "renewcommand": true, "setlength": true,
+	"pagestyle": true, "thispagestyle": true, "bibliographystyle": true, "bibliography": true,
+	"title": true, "author": true, "date": true,
+}
+
+// escapes maps control symbols to their Markdown text
+var escapes = map[byte]string{
+	'%': "%", '&': "&", '#': `\#`, '_': `\_`, '$': `\$`, '{': "{", '}': "}",
+	' ': " ", ',': " ", ';': " ", '-': "", '@': "", '/': "",
+}
+
+// inline renders the commands, groups, math and ligatures of running text
+func (c *Converter) inline(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		switch ch := s[i]; {
+		case ch == '\\':
+			i = c.command(&b, s, i)
+		case ch == '$':
+			// Math is passed through with its delimiters
+			end := mathEnd(s, i)
+			b.WriteString(s[i:end])
+			i = end
+		case ch == '{':
+			group, rest := readGroup(s, i)
+			b.WriteString(c.inline(group))
+			i = len(s) - len(rest)
+		case ch == '}':
+			i++
+		case ch == '~':
+			b.WriteString(" ")
+			i++
+		case strings.HasPrefix(s[i:], "---"):
+			b.WriteString("—")
+			i += 3
+		case strings.HasPrefix(s[i:], "--"):
+			b.WriteString("–")
+			i += 2
+		case strings.HasPrefix(s[i:], "``"):
+			b.WriteString("“")
+			i += 2
+		case strings.HasPrefix(s[i:], "''"):
+			b.WriteString("”")
+			i += 2
+		default:
+			b.WriteByte(ch)
+			i++
+		}
+	}
+	return b.String()
+}
+
+// command renders the command starting at s[i] and returns the index after it
+func (c *Converter) command(b *strings.Builder, s string, i int) int {
+	start := i
+	i++
+	if i == len(s) {
+		return i
+	}
+
+	// Control symbols are a backslash and one non-letter
+	if !isLetter(s[i]) {
+		switch s[i] {
+		case '\\':
+			b.WriteString("  \n")
+			i++
+			// A line break may specify extra space, as in \\[2pt]
+			if i < len(s) && s[i] == '[' {
+				if end := strings.IndexByte(s[i:], ']'); end >= 0 {
+					i += end + 1
+				}
+			}
+			for i < len(s) && (s[i] == ' ' || s[i] == '\n') {
+				i++
+			}
+			return i
+		case '(':
+			// \( ... \) is inline math, written with $ delimiters
+			if end := strings.Index(s[i:], `\)`); end >= 0 {
+				b.WriteString("$" + strings.TrimSpace(s[i+1:i+end]) + "$")
+				return i + end + 2
+			}
+		}
+		if text, ok := escapes[s[i]]; ok {
+			b.WriteString(text)
+		} else if c.KeepUnknownCommands {
+			b.WriteString(s[start : i+1])
+		}
+		return i + 1
+	}
+
+	for i < len(s) && isLetter(s[i]) {
+		i++
+	}
+	name := s[start+1 : i]
+	if i < len(s) && s[i] == '*' {
+		i++
+	}
+
+	if name == "verb" && i < len(s) {
+		// \verb|code| ends at the next occurrence of its delimiter
+		if end := strings.IndexByte(s[i+1:], s[i]); end >= 0 {
+			b.WriteString("`" + s[i+1:i+1+end] + "`")
+			return i + 2 + end
+		}
+	}
+
+	var args []string
+	for i < len(s) && (s[i] == '{' || s[i] == '[') {
+		if s[i] == '[' {
+			end := strings.IndexByte(s[i:], ']')
+			if end < 0 {
+				break
+			}
+			i += end + 1
+			continue
+		}
+		arg, rest := readGroup(s, i)
+		args = append(args, arg)
+		i = len(s) - len(rest)
+	}
+	if len(args) == 0 {
+		// Control words swallow the spaces that follow them
+		for i < len(s) && (s[i] == ' ' || s[i] == '\t') {
+			i++
+		}
+	}
+
+	switch {
+	case emphasis[name] != "" && len(args) > 0:
+		delim := emphasis[name]
+		text := c.inline(args[0])
+		if delim == "`" {
+			text = args[0]
+		}
+		b.WriteString(delim + text + delim)
+	case name == "href" && len(args) > 1:
+		b.WriteString("[" + c.inline(args[1]) + "](" + args[0] + ")")
+	case name == "url" && len(args) > 0:
+		b.WriteString("<" + args[0] + ">")
+	case name == "includegraphics" && len(args) > 0:
+		b.WriteString("![](" + args[0] + ")")
+	case symbols[name] != "":
+		b.WriteString(symbols[name])
+	case dropped[name]:
+	case c.KeepUnknownCommands:
+		b.WriteString(s[start:i])
+	default:
+		// Unknown commands are stripped, keeping text they were applied to
+		for _, arg := range args {
+			b.WriteString(c.inline(arg))
+		}
+	}
+	return i
+}
+
+// readGroup returns the contents of the braced group starting at s[i] and the
+// text after it. An unclosed group runs to the end of s.
+func readGroup(s string, i int) (group, rest string) {
+	depth := 0
+	for j := i; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return s[i+1 : j], s[j+1:]
+			}
+		}
+	}
+	return s[min(i+1, len(s)):], ""
+}
+
+// mathEnd returns the index after the math starting at s[i], which is
+// delimited by $ or $$
+func mathEnd(s string, i int) int {
+	delim := "$"
+	if strings.HasPrefix(s[i:], "$$") {
+		delim = "$$"
+	}
+	for j := i + len(delim); j < len(s); j++ {
+		if s[j] == '\\' {
+			j++
+			continue
+		}
+		if strings.HasPrefix(s[j:], delim) {
+			return j + len(delim)
+		}
+	}
+	return len(s)
+}
+
+func isLetter(ch byte) bool {
+	return ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z'
+}
@@ -0,0 +1,378 @@
+// Package latex converts LaTeX source files to Markdown. It handles the
+// structure most documents are written with: sectioning, emphasis, lists,
+// links, code listings and math, which is passed through unchanged.
+package latex
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+)
+
+// Converter converts LaTeX (.tex) sources to Markdown
+type Converter struct {
+	// ListStyle selects the list item markers
+	ListStyle markdown.ListStyle
+	// KeepUnknownCommands passes commands and environments without a Markdown
+	// equivalent through verbatim instead of stripping them
+	KeepUnknownCommands bool
+	// SoftBreak selects how the line breaks inside a paragraph are rendered
+	SoftBreak markdown.SoftBreak
+}
+
+// sectionLevels is the depth of each sectioning command, part being the outermost
+var sectionLevels = map[string]int{
+	"part":          0,
+	"chapter":       1,
+	"section":       2,
+	"subsection":    3,
+	"subsubsection": 4,
+	"paragraph":     5,
+	"subparagraph":  6,
+}
+
+var (
+	sectionPattern = regexp.MustCompile(`^\\(part|chapter|section|subsection|subsubsection|paragraph|subparagraph)\*?\s*(?:\[[^\]]*\])?\s*\{`)
+	beginPattern   = regexp.MustCompile(`^\\begin\{([^}]+)\}`)
+	endPattern     = regexp.MustCompile(`^\\end\{([^}]+)\}`)
+	itemPattern    = regexp.MustCompile(`^\\item\b`)
+	// languagePattern finds the language option of a listing, as in [language=Go]
+	languagePattern = regexp.MustCompile(`language\s*=\s*\{?([\w+#-]+)`)
+)
+
+// codeEnvironments are rendered as fenced code blocks
+var codeEnvironments = map[string]bool{"verbatim": true, "verbatim*": true, "lstlisting": true}
+
+// mathEnvironments are display math, rendered between $$ delimiters
+var mathEnvironments = map[string]bool{
+	"equation": true, "equation*": true, "align": true, "align*": true,
+	"displaymath": true, "gather": true, "gather*": true, "multline": true, "multline*": true,
+}
+
+func (c *Converter) ToMarkdown(inputPath string, w io.Writer) error {
+	data, err := os.ReadFile(inputPath)
+	if err != nil {
+		return fmt.Errorf("failed to open LaTeX source: %v", err)
+	}
+
+	fm, body := c.render(string(data))
+	if _, err := io.WriteString(w, fm.String()+body); err != nil {
+		return fmt.Errorf("failed to write markdown: %v", err)
+	}
+	return nil
+}
+
+// list is an itemize or enumerate environment being rendered
+type list struct {
+	ordered bool
+	count   int
+}
+
+// renderer walks the lines of a document body, rendering blocks as they end
+type renderer struct {
+	c      *Converter
+	result strings.Builder
+	para   []string
+	lists  []list
+	// item is the text of the list item being collected
+	item   []string
+	marker string
+	// shift is added to the depth of sectioning commands to get the heading level
+	shift int
+}
+
+// render converts a LaTeX source to its front matter and Markdown body
+func (c *Converter) render(src string) (markdown.FrontMatter, string) {
+	preamble, body := splitDocument(src)
+	// Front matter fields are plain text, so commands in them are always stripped
+	plain := &Converter{}
+	fm := markdown.FrontMatter{
+		Title:  plain.inline(commandArgument(preamble+body, "title")),
+		Author: plain.inline(commandArgument(preamble+body, "author")),
+		Date:   plain.inline(commandArgument(preamble+body, "date")),
+	}
+
+	r := &renderer{c: c, shift: 1 - topSectionLevel(body)}
+	if fm.Title != "" && strings.Contains(body, `\maketitle`) {
+		// The title is the H1, so the outermost sections are demoted below it
+		r.shift++
+	}
+
+	lines := strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(stripComment(lines[i]))
+
+		if m := beginPattern.FindStringSubmatch(line); m != nil {
+			switch env := m[1]; {
+			case codeEnvironments[env]:
+				r.flush()
+				i = r.code(lines, i, env, strings.TrimSpace(line[len(m[0]):]))
+				continue
+			case mathEnvironments[env]:
+				r.flush()
+				i = r.displayMath(lines, i, `\end{`+env+`}`)
+				continue
+			case env == "itemize" || env == "enumerate" || env == "description":
+				r.flushItem()
+				r.flushParagraph()
+				r.lists = append(r.lists, list{ordered: env == "enumerate"})
+				line = strings.TrimSpace(line[len(m[0]):])
+			case env == "document":
+				line = strings.TrimSpace(line[len(m[0]):])
+			default:
+				if c.KeepUnknownCommands {
+					r.flush()
+					r.result.WriteString(m[0] + "\n\n")
+				}
+				line = strings.TrimSpace(line[len(m[0]):])
+			}
+		}
+		if strings.HasPrefix(line, `\[`) {
+			r.flush()
+			i = r.displayMath(lines, i, `\]`)
+			continue
+		}
+		if m := endPattern.FindStringSubmatch(line); m != nil {
+			switch env := m[1]; {
+			case env == "itemize" || env == "enumerate" || env == "description":
+				r.flushItem()
+				if len(r.lists) > 0 {
+					r.lists = r.lists[:len(r.lists)-1]
+				}
+				if len(r.lists) == 0 {
+					r.result.WriteString("\n")
+				}
+				line = strings.TrimSpace(line[len(m[0]):])
+			default:
+				if c.KeepUnknownCommands {
+					r.flush()
+					r.result.WriteString(m[0] + "\n\n")
+				}
+				line = strings.TrimSpace(line[len(m[0]):])
+			}
+		}
+
+		switch {
+		case line == "":
+			// A blank line ends a paragraph; within a list item it is ignored
+			if r.marker == "" {
+				r.flushParagraph()
+			}
+		case line == `\maketitle`:
+			r.flush()
+			if fm.Title != "" {
+				r.result.WriteString("# " + fm.Title + "\n\n")
+			}
+		case sectionPattern.MatchString(line):
+			r.flush()
+			m := sectionPattern.FindStringSubmatch(line)
+			title, rest := readGroup(line, len(m[0])-1)
+			level := min(max(sectionLevels[m[1]]+r.shift, 1), 6)
+			r.result.WriteString(strings.Repeat("#", level) + " " + c.inline(title) + "\n\n")
+			if rest = strings.TrimSpace(rest); rest != "" {
+				r.para = append(r.para, rest)
+			}
+		case itemPattern.MatchString(line) && len(r.lists) > 0:
+			r.flushItem()
+			r.startItem(strings.TrimSpace(strings.TrimPrefix(line, `\item`)))
+		case r.marker != "":
+			r.item = append(r.item, line)
+		default:
+			r.para = append(r.para, line)
+		}
+	}
+	r.flush()
+
+	return fm, strings.TrimSpace(r.result.String()) + "\n"
+}
+
+// startItem begins a list item. The label of an \item[label], such as the term
+// of a description list, is kept in bold before the item text.
+func (r *renderer) startItem(rest string) {
+	l := &r.lists[len(r.lists)-1]
+	l.count++
+
+	r.marker = r.c.ListStyle.BulletMarker()
+	if l.ordered {
+		r.marker = r.c.ListStyle.OrderedMarker(l.count)
+	}
+	if strings.HasPrefix(rest, "[") {
+		if end := strings.Index(rest, "]"); end > 0 {
+			rest = "**" + strings.TrimSpace(rest[1:end]) + "** " + strings.TrimSpace(rest[end+1:])
+		}
+	}
+	r.item = []string{rest}
+}
+
+// flushItem writes the list item being collected
+func (r *renderer) flushItem() {
+	if r.marker == "" {
+		return
+	}
+	indent := strings.Repeat("  ", len(r.lists)-1)
+	text := strings.TrimSpace(r.c.paragraph(r.item))
+	continuation := "\n" + strings.Repeat(" ", len(indent+r.marker)+1)
+	text = strings.ReplaceAll(text, "\n", continuation)
+	r.result.WriteString(indent + r.marker + " " + text + "\n")
+	r.marker = ""
+	r.item = nil
+}
+
+// flushParagraph writes the paragraph being collected
+func (r *renderer) flushParagraph() {
+	if len(r.para) == 0 {
+		return
+	}
+	if text := strings.TrimSpace(r.c.paragraph(r.para)); text != "" {
+		r.result.WriteString(text + "\n\n")
+	}
+	r.para = nil
+}
+
+// paragraph renders the source lines of a paragraph, keeping the breaks
+// between them when SoftBreak asks for it. Lines already ended by \\ keep
+// their hard break.
+func (c *Converter) paragraph(lines []string) string {
+	if c.SoftBreak != markdown.SoftBreakHard && c.SoftBreak != markdown.SoftBreakNewline {
+		return c.inline(strings.Join(lines, " "))
+	}
+
+	rendered := strings.Split(c.inline(strings.Join(lines, "\n")), "\n")
+	if c.SoftBreak == markdown.SoftBreakHard {
+		for i := range rendered[:len(rendered)-1] {
+			rendered[i] = strings.TrimRight(rendered[i], " ") + "  "
+		}
+	}
+	return strings.Join(rendered, "\n")
+}
+
+// flush ends the open paragraph and list item before a block is written
+func (r *renderer) flush() {
+	r.flushItem()
+	r.flushParagraph()
+}
+
+// code writes a verbatim environment starting at lines[start] as a fenced code
+// block and returns the index of its last line
+func (r *renderer) code(lines []string, start int, env, options string) int {
+	lang := ""
+	if m := languagePattern.FindStringSubmatch(options); m != nil {
+		lang = strings.ToLower(m[1])
+	}
+
+	end := `\end{` + env + `}`
+	var body []string
+	i := start + 1
+	for ; i < len(lines); i++ {
+		if idx := strings.Index(lines[i], end); idx >= 0 {
+			if text := lines[i][:idx]; strings.TrimSpace(text) != "" {
+				body = append(body, text)
+			}
+			break
+		}
+		body = append(body, lines[i])
+	}
+
+	fence := "```"
+	for strings.Contains(strings.Join(body, "\n"), fence) {
+		fence += "`"
+	}
+	r.result.WriteString(fence + lang + "\n" + strings.Join(body, "\n") + "\n" + fence + "\n\n")
+	return i
+}
+
+// displayMath writes display math starting at lines[start] between $$
+// delimiters and returns the index of its last line
+func (r *renderer) displayMath(lines []string, start int, end string) int {
+	first := strings.TrimSpace(stripComment(lines[start]))
+	if m := beginPattern.FindString(first); m != "" {
+		first = first[len(m):]
+	} else {
+		first = strings.TrimPrefix(first, `\[`)
+	}
+
+	var body []string
+	i := start
+	line := first
+	for {
+		if idx := strings.Index(line, end); idx >= 0 {
+			body = append(body, line[:idx])
+			break
+		}
+		body = append(body, line)
+		i++
+		if i == len(lines) {
+			break
+		}
+		line = stripComment(lines[i])
+	}
+
+	var math []string
+	for _, l := range body {
+		if l = strings.TrimSpace(l); l != "" {
+			math = append(math, l)
+		}
+	}
+	r.result.WriteString("$$\n" + strings.Join(math, "\n") + "\n$$\n\n")
+	return i
+}
+
+// splitDocument separates the preamble from the body of a document. Sources
+// without a document environment, such as included chapters, are all body.
+func splitDocument(src string) (preamble, body string) {
+	start := strings.Index(src, `\begin{document}`)
+	if start < 0 {
+		return "", src
+	}
+	preamble, body = src[:start], src[start+len(`\begin{document}`):]
+	if end := strings.Index(body, `\end{document}`); end >= 0 {
+		body = body[:end]
+	}
+	return preamble, body
+}
+
+// topSectionLevel returns the depth of the outermost sectioning command used
+func topSectionLevel(body string) int {
+	top := sectionLevels["section"]
+	found := false
+	for _, line := range strings.Split(body, "\n") {
+		if m := sectionPattern.FindStringSubmatch(strings.TrimSpace(stripComment(line))); m != nil {
+			if level := sectionLevels[m[1]]; !found || level < top {
+				top = level
+				found = true
+			}
+		}
+	}
+	return top
+}
+
+// commandArgument returns the braced argument of the first use of a command
+func commandArgument(src, name string) string {
+	for _, line := range strings.Split(src, "\n") {
+		line = stripComment(line)
+		idx := strings.Index(line, `\`+name+`{`)
+		if idx < 0 {
+			continue
+		}
+		arg, _ := readGroup(line, idx+len(name)+1)
+		return strings.TrimSpace(arg)
+	}
+	return ""
+}
+
+// stripComment removes a % comment from a line, leaving escaped \% alone
+func stripComment(line string) string {
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '%':
+			return line[:i]
+		}
+	}
+	return line
+}
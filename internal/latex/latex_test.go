@@ -0,0 +1,84 @@
+package latex
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+)
+
+// paper is a small document using each construct the converter handles
+const paper = `\documentclass{article}
+\title{A Small Paper}
+\author{Ana Lima}
+\begin{document}
+\maketitle
+\section{Intro}
+Some \textbf{bold} and \emph{soft} text, see \href{https://example.com}{the site} or \url{https://go.dev}.
+Math $a^2 + b^2$ stays. % a comment
+
+\begin{itemize}
+\item First
+\item Second
+\begin{enumerate}
+\item One
+\item Two
+\end{enumerate}
+\end{itemize}
+
+\begin{lstlisting}[language=Go]
+fmt.Println("hi")
+\end{lstlisting}
+\subsection{More}
+\unknown{x} Text 50\% done.
+\end{document}
+`
+
+func TestToMarkdown(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "paper.tex")
+	if err := os.WriteFile(path, []byte(paper), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var out strings.Builder
+	if err := (&Converter{}).ToMarkdown(path, &out); err != nil {
+		t.Fatalf("ToMarkdown() error = %v", err)
+	}
+	want := "---\ntitle: A Small Paper\nauthor: Ana Lima\n---\n\n" +
+		"# A Small Paper\n\n## Intro\n\n" +
+		"Some **bold** and *soft* text, see [the site](https://example.com) or <https://go.dev>. Math $a^2 + b^2$ stays.\n\n" +
+		"- First\n- Second\n  1. One\n  2. Two\n\n" +
+		"```go\nfmt.Println(\"hi\")\n```\n\n" +
+		"### More\n\nx Text 50% done.\n"
+	if got := out.String(); got != want {
+		t.Errorf("ToMarkdown() = %q, want %q", got, want)
+	}
+}
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name string
+		c    Converter
+		src  string
+		want string
+	}{
+		{"sections without a document", Converter{}, "\\section{A}\n\\subsection{B}\nText.", "# A\n\n## B\n\nText.\n"},
+		{"starred section", Converter{}, "\\section*{Notes}", "# Notes\n"},
+		{"verbatim", Converter{}, "\\begin{verbatim}\n  x = 1\n\\end{verbatim}", "```\n  x = 1\n```\n"},
+		{"display math", Converter{}, "\\begin{equation}\nE = mc^2\n\\end{equation}", "$$\nE = mc^2\n$$\n"},
+		{"bracket math", Converter{}, "\\[ x + y \\]", "$$\nx + y\n$$\n"},
+		{"description", Converter{}, "\\begin{description}\n\\item[Go] A language\n\\end{description}", "- **Go** A language\n"},
+		{"list style", Converter{ListStyle: markdown.ListStyle{Bullet: "*", Ordered: ")"}}, "\\begin{itemize}\n\\item a\n\\begin{enumerate}\n\\item b\n\\end{enumerate}\n\\end{itemize}", "* a\n  1) b\n"},
+		{"unknown stripped", Converter{}, "\\begin{center}\nMid \\foo{x}\n\\end{center}", "Mid x\n"},
+		{"unknown kept", Converter{KeepUnknownCommands: true}, "\\begin{center}\nMid \\foo{x}\n\\end{center}", "\\begin{center}\n\nMid \\foo{x}\n\n\\end{center}\n"},
+		{"spaces", Converter{}, "one\ntwo", "one two\n"},
+		{"hard breaks", Converter{SoftBreak: markdown.SoftBreakHard}, "one\ntwo", "one  \ntwo\n"},
+		{"newlines", Converter{SoftBreak: markdown.SoftBreakNewline}, "one\ntwo", "one\ntwo\n"},
+	}
+	for _, tt := range tests {
+		if _, got := tt.c.render(tt.src); got != tt.want {
+			t.Errorf("%s: render() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
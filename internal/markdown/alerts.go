@@ -0,0 +1,50 @@
+package markdown
+
+import "strings"
+
+// Alert is the kind of a callout box, named as in GitHub's alert syntax
+type Alert string
+
+const (
+	AlertNote      Alert = "NOTE"
+	AlertTip       Alert = "TIP"
+	AlertImportant Alert = "IMPORTANT"
+	AlertWarning   Alert = "WARNING"
+	AlertCaution   Alert = "CAUTION"
+)
+
+// alertKeywords maps the words callouts are introduced with to their kind
+var alertKeywords = map[string]Alert{
+	"note":      AlertNote,
+	"info":      AlertNote,
+	"tip":       AlertTip,
+	"hint":      AlertTip,
+	"important": AlertImportant,
+	"warning":   AlertWarning,
+	"caution":   AlertCaution,
+	"danger":    AlertCaution,
+}
+
+// ParseAlert returns the kind of callout introduced by a keyword such as "Note" or "WARNING"
+func ParseAlert(keyword string) (Alert, bool) {
+	kind, ok := alertKeywords[strings.ToLower(keyword)]
+	return kind, ok
+}
+
+// SupportsAlerts reports whether "> [!NOTE]" blockquotes render as callouts
+func (d Dialect) SupportsAlerts() bool {
+	return d == GFM || d == Obsidian
+}
+
+// Callout renders lines of text as a callout blockquote. Dialects without
+// alerts get a blockquote led by the bold kind, as in "> **Note:** ...".
+func (d Dialect) Callout(kind Alert, lines []string) string {
+	if d.SupportsAlerts() {
+		return "> [!" + string(kind) + "]\n> " + strings.Join(lines, "\n> ")
+	}
+	label := string(kind[:1]) + strings.ToLower(string(kind[1:]))
+	if len(lines) == 0 {
+		return "> **" + label + ":**"
+	}
+	return "> **" + label + ":** " + strings.Join(lines, "\n> ")
+}
@@ -0,0 +1,30 @@
+package markdown
+
+import "testing"
+
+func TestCallout(t *testing.T) {
+	tests := []struct {
+		dialect Dialect
+		kind    Alert
+		lines   []string
+		want    string
+	}{
+		{GFM, AlertNote, []string{"Save often.", "Really."}, "> [!NOTE]\n> Save often.\n> Really."},
+		{Obsidian, AlertWarning, []string{"Hot."}, "> [!WARNING]\n> Hot."},
+		{CommonMark, AlertTip, []string{"Use tabs.", "Or not."}, "> **Tip:** Use tabs.\n> Or not."},
+		{Pandoc, AlertCaution, nil, "> **Caution:**"},
+	}
+	for _, tt := range tests {
+		if got := tt.dialect.Callout(tt.kind, tt.lines); got != tt.want {
+			t.Errorf("%s Callout(%s) = %q, want %q", tt.dialect, tt.kind, got, tt.want)
+		}
+	}
+}
+
+func TestParseAlert(t *testing.T) {
+	for keyword, want := range map[string]Alert{"Note": AlertNote, "INFO": AlertNote, "hint": AlertTip, "Danger": AlertCaution, "remark": ""} {
+		if got, ok := ParseAlert(keyword); got != want || ok != (want != "") {
+			t.Errorf("ParseAlert(%q) = %q, %v, want %q", keyword, got, ok, want)
+		}
+	}
+}
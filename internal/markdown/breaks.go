@@ -0,0 +1,46 @@
+package markdown
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SoftBreak selects how the line breaks inside a source paragraph are rendered
+type SoftBreak string
+
+const (
+	// SoftBreakSpace joins the lines of a paragraph with spaces
+	SoftBreakSpace SoftBreak = "space"
+	// SoftBreakHard ends each line but the last with a hard line break
+	SoftBreakHard SoftBreak = "break"
+	// SoftBreakNewline keeps the line breaks as written, which Markdown
+	// renderers show as spaces
+	SoftBreakNewline SoftBreak = "newline"
+)
+
+// ParseSoftBreak validates a soft break mode. An empty name selects SoftBreakSpace.
+func ParseSoftBreak(name string) (SoftBreak, error) {
+	switch b := SoftBreak(strings.ToLower(name)); b {
+	case "":
+		return SoftBreakSpace, nil
+	case SoftBreakSpace, SoftBreakHard, SoftBreakNewline:
+		return b, nil
+	}
+	return "", fmt.Errorf("unsupported soft break mode: %s (expected space, break or newline)", name)
+}
+
+// Separator returns the text placed between the lines of a paragraph
+func (b SoftBreak) Separator() string {
+	switch b {
+	case SoftBreakHard:
+		return "  \n"
+	case SoftBreakNewline:
+		return "\n"
+	}
+	return " "
+}
+
+// Join joins the lines of a paragraph
+func (b SoftBreak) Join(lines []string) string {
+	return strings.Join(lines, b.Separator())
+}
@@ -0,0 +1,28 @@
+package markdown
+
+import "testing"
+
+func TestSoftBreak(t *testing.T) {
+	lines := []string{"221B Baker Street", "London", "NW1 6XE"}
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"", "221B Baker Street London NW1 6XE"},
+		{"space", "221B Baker Street London NW1 6XE"},
+		{"break", "221B Baker Street  \nLondon  \nNW1 6XE"},
+		{"Newline", "221B Baker Street\nLondon\nNW1 6XE"},
+	}
+	for _, tt := range tests {
+		b, err := ParseSoftBreak(tt.name)
+		if err != nil {
+			t.Fatalf("ParseSoftBreak(%q) error = %v", tt.name, err)
+		}
+		if got := b.Join(lines); got != tt.want {
+			t.Errorf("%q: Join() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+	if _, err := ParseSoftBreak("tab"); err == nil {
+		t.Error("ParseSoftBreak(tab): error = nil, want an error")
+	}
+}
@@ -0,0 +1,125 @@
+package markdown
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dialect is a Markdown flavor whose extensions the converters may target
+type Dialect string
+
+const (
+	CommonMark Dialect = "commonmark"
+	GFM        Dialect = "gfm"
+	Pandoc     Dialect = "pandoc"
+	Obsidian   Dialect = "obsidian"
+)
+
+// ParseDialect validates a dialect name, defaulting to GFM when empty
+func ParseDialect(name string) (Dialect, error) {
+	switch d := Dialect(strings.ToLower(name)); d {
+	case "":
+		return GFM, nil
+	case CommonMark, GFM, Pandoc, Obsidian:
+		return d, nil
+	}
+	return "", fmt.Errorf("unsupported markdown dialect: %s", name)
+}
+
+// SupportsHighlight reports whether ==text== renders as highlighted text
+func (d Dialect) SupportsHighlight() bool {
+	return d == Pandoc || d == Obsidian
+}
+
+// SupportsSubscript reports whether ~text~ renders as a subscript. GFM reads
+// single tildes as strikethrough, so only Pandoc qualifies.
+func (d Dialect) SupportsSubscript() bool {
+	return d == Pandoc
+}
+
+// SupportsFootnotes reports whether [^label] references render as footnotes,
+// an extension every dialect but CommonMark has
+func (d Dialect) SupportsFootnotes() bool {
+	return d != CommonMark
+}
+
+// Subscript renders text as a subscript
+func (d Dialect) Subscript(text string) string {
+	if d.SupportsSubscript() {
+		return "~" + text + "~"
+	}
+	return "<sub>" + text + "</sub>"
+}
+
+// Highlight wraps text in highlight markup, keeping surrounding spaces outside the markers.
+// A non-empty color is preserved as an inline style, which requires HTML.
+func (d Dialect) Highlight(text, color string) string {
+	return wrapTrimmed(text, func(inner string) string {
+		switch {
+		case color != "":
+			return `<mark style="background-color:` + color + `">` + inner + "</mark>"
+		case d.SupportsHighlight():
+			return "==" + inner + "=="
+		default:
+			return "<mark>" + inner + "</mark>"
+		}
+	})
+}
+
+// TaskBox returns the checkbox of a task list item. CommonMark has no task
+// lists, so the item keeps a checkbox character instead.
+func (d Dialect) TaskBox(checked bool) string {
+	switch {
+	case d == CommonMark && checked:
+		return "☑"
+	case d == CommonMark:
+		return "☐"
+	case checked:
+		return "[x]"
+	default:
+		return "[ ]"
+	}
+}
+
+// Strikethrough marks text as deleted. CommonMark has no strikethrough syntax.
+func (d Dialect) Strikethrough(text string) string {
+	if d == CommonMark {
+		return Wrap(text, "<del>", "</del>")
+	}
+	return Wrap(text, "~~", "~~")
+}
+
+// Insertion marks text inserted by a tracked change, using the "++" of the
+// markdown-it ins plugin or HTML for CommonMark
+func (d Dialect) Insertion(text string) string {
+	if d == CommonMark {
+		return Wrap(text, "<ins>", "</ins>")
+	}
+	return Wrap(text, "++", "++")
+}
+
+// Underline marks text as underlined, which Markdown only supports through
+// HTML or Pandoc's bracketed spans
+func (d Dialect) Underline(text string) string {
+	if d == Pandoc {
+		return Wrap(text, "[", "]{.underline}")
+	}
+	return Wrap(text, "<u>", "</u>")
+}
+
+// Wrap surrounds text with inline markup, keeping surrounding spaces outside it
+func Wrap(text, open, close string) string {
+	return wrapTrimmed(text, func(inner string) string {
+		return open + inner + close
+	})
+}
+
+// wrapTrimmed applies wrap to text without its leading and trailing spaces
+func wrapTrimmed(text string, wrap func(string) string) string {
+	inner := strings.TrimSpace(text)
+	if inner == "" {
+		return text
+	}
+	start := strings.Index(text, inner)
+	return text[:start] + wrap(inner) + text[start+len(inner):]
+}
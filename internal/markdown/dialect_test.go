@@ -0,0 +1,44 @@
+package markdown
+
+import "testing"
+
+func TestParseDialect(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Dialect
+		wantErr bool
+	}{
+		{"", GFM, false},
+		{"GFM", GFM, false},
+		{"commonmark", CommonMark, false},
+		{"Pandoc", Pandoc, false},
+		{"obsidian", Obsidian, false},
+		{"markdown-extra", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseDialect(tt.name)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("ParseDialect(%q) = %q, %v, want %q", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		dialect     Dialect
+		text, color string
+		want        string
+	}{
+		{GFM, "word", "", "<mark>word</mark>"},
+		{CommonMark, " word ", "", " <mark>word</mark> "},
+		{Obsidian, "two words", "", "==two words=="},
+		{Pandoc, "x", "", "==x=="},
+		{Obsidian, "x", "#ff0000", `<mark style="background-color:#ff0000">x</mark>`},
+		{GFM, "  ", "", "  "},
+	}
+	for _, tt := range tests {
+		if got := tt.dialect.Highlight(tt.text, tt.color); got != tt.want {
+			t.Errorf("%s.Highlight(%q, %q) = %q, want %q", tt.dialect, tt.text, tt.color, got, tt.want)
+		}
+	}
+}
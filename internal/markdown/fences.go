@@ -0,0 +1,52 @@
+package markdown
+
+import "strings"
+
+// CodeFence tracks the fenced code blocks of Markdown read line by line. A
+// block opens with a run of three or more backticks or tildes and closes
+// with a run of the same character at least as long, so a ```` fence is not
+// closed by the ``` lines it quotes.
+type CodeFence struct {
+	char   byte
+	length int
+}
+
+// Line reads the next line, reporting whether it belongs to a fenced code
+// block, the fences opening and closing it included
+func (f *CodeFence) Line(line string) bool {
+	trimmed := strings.TrimLeft(line, " \t")
+	if f.length > 0 {
+		if n := fenceRun(trimmed); n >= f.length && trimmed[0] == f.char && strings.TrimSpace(trimmed[n:]) == "" {
+			f.length = 0
+		}
+		return true
+	}
+	n := fenceRun(trimmed)
+	if n < 3 {
+		return false
+	}
+	// The info string of a backtick fence cannot hold backticks, or the line
+	// would be a code span
+	if trimmed[0] == '`' && strings.Contains(trimmed[n:], "`") {
+		return false
+	}
+	f.char, f.length = trimmed[0], n
+	return true
+}
+
+// Open reports whether the lines read so far leave a code block open
+func (f *CodeFence) Open() bool {
+	return f.length > 0
+}
+
+// fenceRun returns the length of the run of backticks or tildes opening line
+func fenceRun(line string) int {
+	if line == "" || line[0] != '`' && line[0] != '~' {
+		return 0
+	}
+	n := 1
+	for n < len(line) && line[n] == line[0] {
+		n++
+	}
+	return n
+}
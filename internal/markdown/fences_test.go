@@ -0,0 +1,40 @@
+package markdown
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCodeFence(t *testing.T) {
+	tests := []struct {
+		name string
+		md   string
+		// want marks the lines inside a code block with 1
+		want string
+		open bool
+	}{
+		{"backticks", "text\n```go\ncode\n```\ntext", "01110", false},
+		{"tildes", "~~~\n```\n~~~", "111", false},
+		{"longer fence", "````\n```\ncode\n````\ntext", "11110", false},
+		{"closing fence with info", "```\n```go\n```", "111", false},
+		{"indented fence", "  ```\n  code\n  ```", "111", false},
+		{"inline code", "```code``` span\ntext", "00", false},
+		{"unclosed", "```\ncode", "11", true},
+		{"two backticks", "``\ntext", "00", false},
+	}
+	for _, tt := range tests {
+		var fence CodeFence
+		var got strings.Builder
+		for _, line := range strings.Split(tt.md, "\n") {
+			in := 0
+			if fence.Line(line) {
+				in = 1
+			}
+			fmt.Fprint(&got, in)
+		}
+		if got.String() != tt.want || fence.Open() != tt.open {
+			t.Errorf("%s: lines %s, open %v, want %s, %v", tt.name, got.String(), fence.Open(), tt.want, tt.open)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package markdown
+
+import (
+	"regexp"
+	"strings"
+)
+
+// FrontMatter holds the fields emitted in a document's YAML front matter
+type FrontMatter struct {
+	Title    string
+	Subtitle string
+	Author   string
+	Date     string
+	Tags     []string
+}
+
+// IsEmpty reports whether there is nothing to emit
+func (fm FrontMatter) IsEmpty() bool {
+	return fm.Title == "" && fm.Subtitle == "" && fm.Author == "" && fm.Date == "" && len(fm.Tags) == 0
+}
+
+// String renders the front matter block, including the trailing blank line.
+// An empty front matter renders as an empty string.
+func (fm FrontMatter) String() string {
+	if fm.IsEmpty() {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("---\n")
+	for _, field := range []struct{ key, value string }{
+		{"title", fm.Title},
+		{"subtitle", fm.Subtitle},
+		{"author", fm.Author},
+		{"date", fm.Date},
+	} {
+		if field.value != "" {
+			b.WriteString(field.key + ": " + yamlString(field.value) + "\n")
+		}
+	}
+	if len(fm.Tags) > 0 {
+		b.WriteString("tags:\n")
+		for _, tag := range fm.Tags {
+			b.WriteString("  - " + yamlString(tag) + "\n")
+		}
+	}
+	b.WriteString("---\n\n")
+
+	return b.String()
+}
+
+// ParseFrontMatter reads a leading front matter block in the form String
+// emits, returning its fields and the Markdown after it. Markdown without
+// front matter is returned whole with empty fields.
+func ParseFrontMatter(md string) (FrontMatter, string) {
+	var fm FrontMatter
+	if !strings.HasPrefix(md, "---\n") {
+		return fm, md
+	}
+	end := strings.Index(md[4:], "\n---\n")
+	if end < 0 {
+		return fm, md
+	}
+
+	inTags := false
+	for _, line := range strings.Split(md[4:4+end], "\n") {
+		if inTags && strings.HasPrefix(line, "  - ") {
+			fm.Tags = append(fm.Tags, yamlValue(line[4:]))
+			continue
+		}
+		inTags = false
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = yamlValue(strings.TrimSpace(value))
+		switch key {
+		case "title":
+			fm.Title = value
+		case "subtitle":
+			fm.Subtitle = value
+		case "author":
+			fm.Author = value
+		case "date":
+			fm.Date = value
+		case "tags":
+			inTags = true
+		}
+	}
+	return fm, strings.TrimLeft(md[4+end+5:], "\n")
+}
+
+// ParseKeywords splits a document keyword string into individual tags.
+// Keywords are commonly separated by commas or semicolons; duplicates are dropped.
+func ParseKeywords(keywords string) []string {
+	fields := strings.FieldsFunc(keywords, func(r rune) bool {
+		return r == ',' || r == ';'
+	})
+
+	var tags []string
+	seen := make(map[string]bool)
+	for _, field := range fields {
+		tag := strings.Join(strings.Fields(field), " ")
+		if tag == "" || seen[strings.ToLower(tag)] {
+			continue
+		}
+		seen[strings.ToLower(tag)] = true
+		tags = append(tags, tag)
+	}
+
+	return tags
+}
+
+// yamlKeywords are the plain scalars YAML parsers read as booleans or null
+// rather than strings
+var yamlKeywords = map[string]bool{
+	"true": true, "false": true, "yes": true, "no": true, "on": true, "off": true,
+	"y": true, "n": true, "null": true, "~": true,
+}
+
+// yamlNumber matches the plain scalars YAML parsers read as numbers
+var yamlNumber = regexp.MustCompile(`^[-+]?(?:[0-9][0-9_]*(?:\.[0-9_]*)?|\.[0-9]+)(?:[eE][-+]?[0-9]+)?$|^0[xo][0-9a-fA-F_]+$|^[-+]?\.(?:inf|Inf|INF)$|^\.(?:nan|NaN|NAN)$`)
+
+var (
+	yamlEscaper   = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	yamlUnescaper = strings.NewReplacer(`\\`, `\`, `\"`, `"`, `\n`, "\n", `\r`, "\r", `\t`, "\t")
+)
+
+// yamlString quotes a scalar when it could otherwise be misread by a YAML
+// parser, as a boolean, null or number, or as YAML syntax. Line breaks and tabs
+// are escaped so the scalar stays on one line.
+func yamlString(s string) string {
+	if s == "" || strings.ContainsAny(s, ":#{}[],&*!|>'\"%@`\n\r\t") ||
+		strings.TrimSpace(s) != s || strings.HasPrefix(s, "-") || strings.HasPrefix(s, "?") ||
+		yamlKeywords[strings.ToLower(s)] || yamlNumber.MatchString(s) {
+		return `"` + yamlEscaper.Replace(s) + `"`
+	}
+	return s
+}
+
+// yamlValue reads a scalar as written by yamlString
+func yamlValue(s string) string {
+	if len(s) >= 2 && strings.HasPrefix(s, `"`) && strings.HasSuffix(s, `"`) {
+		return yamlUnescaper.Replace(s[1 : len(s)-1])
+	}
+	return s
+}
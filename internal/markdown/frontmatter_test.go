@@ -0,0 +1,106 @@
+package markdown
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestYAMLString(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Annual Report", "Annual Report"},
+		{"", `""`},
+		{"Part 1: Intro", `"Part 1: Intro"`},
+		{"true", `"true"`},
+		{"False", `"False"`},
+		{"null", `"null"`},
+		{"~", `"~"`},
+		{"yes", `"yes"`},
+		{"NO", `"NO"`},
+		{"off", `"off"`},
+		{"2024", `"2024"`},
+		{"3.14", `"3.14"`},
+		{"+1", `"+1"`},
+		{"1e10", `"1e10"`},
+		{".5", `".5"`},
+		{"0x1F", `"0x1F"`},
+		{".inf", `".inf"`},
+		{".NaN", `".NaN"`},
+		{"1.2.3", "1.2.3"},
+		{"2024-01-15", "2024-01-15"},
+		{"@handle", `"@handle"`},
+		{"`code`", "\"`code`\""},
+		{"-dash", `"-dash"`},
+		{" padded", `" padded"`},
+		{"two\nlines", `"two\nlines"`},
+		{"tab\there", `"tab\there"`},
+		{`say "hi"`, `"say \"hi\""`},
+		{`C:\path`, `"C:\\path"`},
+	}
+	for _, tt := range tests {
+		if got := yamlString(tt.in); got != tt.want {
+			t.Errorf("yamlString(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+		if got := yamlValue(yamlString(tt.in)); got != tt.in {
+			t.Errorf("yamlValue(yamlString(%q)) = %q", tt.in, got)
+		}
+	}
+}
+
+func TestFrontMatterRoundTrip(t *testing.T) {
+	fm := FrontMatter{
+		Title:  "true",
+		Author: "Ada\nLovelace",
+		Date:   "2024-01-15",
+		Tags:   []string{"42", "null", "go"},
+	}
+	want := "---\ntitle: \"true\"\nauthor: \"Ada\\nLovelace\"\ndate: 2024-01-15\ntags:\n  - \"42\"\n  - \"null\"\n  - go\n---\n\n"
+	if got := fm.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	got, rest := ParseFrontMatter(fm.String() + "# Body\n")
+	if !reflect.DeepEqual(got, fm) || rest != "# Body\n" {
+		t.Errorf("ParseFrontMatter() = %+v, %q", got, rest)
+	}
+}
+
+func TestParseKeywords(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"go, pdf, markdown", []string{"go", "pdf", "markdown"}},
+		{"go; pdf;markdown", []string{"go", "pdf", "markdown"}},
+		{"machine   learning,  data\tscience", []string{"machine learning", "data science"}},
+		{"Go, go, GO, Rust", []string{"Go", "Rust"}},
+		{" , ;; ", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := ParseKeywords(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseKeywords(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseFrontMatter(t *testing.T) {
+	tests := []struct {
+		name     string
+		md       string
+		want     FrontMatter
+		wantRest string
+	}{
+		{"none", "# Title\n", FrontMatter{}, "# Title\n"},
+		{"unclosed", "---\ntitle: Draft\n", FrontMatter{}, "---\ntitle: Draft\n"},
+		{"thematic break", "---\n\nText\n", FrontMatter{}, "---\n\nText\n"},
+		{"unknown keys", "---\ntitle: Report\nlang: en\n---\n\nText\n", FrontMatter{Title: "Report"}, "Text\n"},
+		{"tags end at the next key", "---\ntags:\n  - go\ndate: 2024\n---\nText\n", FrontMatter{Tags: []string{"go"}, Date: "2024"}, "Text\n"},
+	}
+	for _, tt := range tests {
+		got, rest := ParseFrontMatter(tt.md)
+		if !reflect.DeepEqual(got, tt.want) || rest != tt.wantRest {
+			t.Errorf("%s: got %+v, %q, want %+v, %q", tt.name, got, rest, tt.want, tt.wantRest)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package markdown
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ListStyle selects the markers emitted for bullet and ordered list items.
+// The zero value uses "-" bullets and "." ordinals.
+type ListStyle struct {
+	Bullet  string
+	Ordered string
+}
+
+// ParseListStyle validates a bullet marker ("-", "*" or "+") and an ordered
+// delimiter ("." or ")"). Empty values select the defaults.
+func ParseListStyle(bullet, ordered string) (ListStyle, error) {
+	switch bullet {
+	case "", "-", "*", "+":
+	default:
+		return ListStyle{}, fmt.Errorf("unsupported bullet marker: %s", bullet)
+	}
+	switch ordered {
+	case "", ".", ")":
+	default:
+		return ListStyle{}, fmt.Errorf("unsupported ordered list style: %s", ordered)
+	}
+	return ListStyle{Bullet: bullet, Ordered: ordered}, nil
+}
+
+// BulletMarker returns the marker for an unordered list item
+func (s ListStyle) BulletMarker() string {
+	if s.Bullet == "" {
+		return "-"
+	}
+	return s.Bullet
+}
+
+// OrderedMarker returns the marker for the nth item of an ordered list
+func (s ListStyle) OrderedMarker(n int) string {
+	return strconv.Itoa(n) + s.delimiter()
+}
+
+// Relabel returns the marker for an item labeled with an existing ordinal
+// such as "1." or "b)". Numbers keep their value under the ordered delimiter.
+// CommonMark only numbers lists with digits, so letters and roman numerals
+// open a bullet item that keeps the label as its first word.
+func (s ListStyle) Relabel(label string) string {
+	number := strings.TrimRight(label, ".)")
+	if strings.Trim(number, "0123456789") == "" && number != "" {
+		return number + s.delimiter()
+	}
+	return s.BulletMarker() + " " + label
+}
+
+func (s ListStyle) delimiter() string {
+	if s.Ordered == "" {
+		return "."
+	}
+	return s.Ordered
+}
@@ -0,0 +1,61 @@
+package markdown
+
+import "testing"
+
+func TestRelabel(t *testing.T) {
+	tests := []struct {
+		style ListStyle
+		label string
+		want  string
+	}{
+		{ListStyle{}, "1.", "1."},
+		{ListStyle{}, "12)", "12."},
+		{ListStyle{Ordered: ")"}, "3.", "3)"},
+		{ListStyle{}, "b.", "- b."},
+		{ListStyle{}, "iv)", "- iv)"},
+		{ListStyle{Bullet: "+", Ordered: ")"}, "C.", "+ C."},
+	}
+	for _, tt := range tests {
+		if got := tt.style.Relabel(tt.label); got != tt.want {
+			t.Errorf("%+v.Relabel(%q) = %q, want %q", tt.style, tt.label, got, tt.want)
+		}
+	}
+}
+
+func TestListMarkers(t *testing.T) {
+	tests := []struct {
+		style         ListStyle
+		bullet, third string
+	}{
+		{ListStyle{}, "-", "3."},
+		{ListStyle{Bullet: "*"}, "*", "3."},
+		{ListStyle{Bullet: "+", Ordered: ")"}, "+", "3)"},
+	}
+	for _, tt := range tests {
+		if got := tt.style.BulletMarker(); got != tt.bullet {
+			t.Errorf("%+v.BulletMarker() = %q, want %q", tt.style, got, tt.bullet)
+		}
+		if got := tt.style.OrderedMarker(3); got != tt.third {
+			t.Errorf("%+v.OrderedMarker(3) = %q, want %q", tt.style, got, tt.third)
+		}
+	}
+}
+
+func TestParseListStyle(t *testing.T) {
+	tests := []struct {
+		bullet, ordered string
+		wantErr         bool
+	}{
+		{"", "", false},
+		{"*", ")", false},
+		{"+", ".", false},
+		{"•", "", true},
+		{"-", ":", true},
+	}
+	for _, tt := range tests {
+		_, err := ParseListStyle(tt.bullet, tt.ordered)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseListStyle(%q, %q) error = %v, wantErr %v", tt.bullet, tt.ordered, err, tt.wantErr)
+		}
+	}
+}
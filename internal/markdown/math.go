@@ -0,0 +1,55 @@
+package markdown
+
+import "strings"
+
+// latexOperators are the operator names TeX sets upright with a command of
+// their own, such as \sin
+var latexOperators = map[string]bool{
+	"sin": true, "cos": true, "tan": true, "cot": true, "sec": true, "csc": true,
+	"sinh": true, "cosh": true, "tanh": true, "arcsin": true, "arccos": true, "arctan": true,
+	"log": true, "exp": true, "lim": true, "max": true, "min": true, "sup": true,
+	"inf": true, "det": true, "dim": true, "ker": true, "deg": true, "gcd": true, "arg": true,
+}
+
+// latexSymbols maps Unicode math symbols to their LaTeX commands
+var latexSymbols = map[rune]string{
+	'α': `\alpha`, 'β': `\beta`, 'γ': `\gamma`, 'δ': `\delta`, 'ε': `\epsilon`,
+	'ζ': `\zeta`, 'η': `\eta`, 'θ': `\theta`, 'ι': `\iota`, 'κ': `\kappa`,
+	'λ': `\lambda`, 'μ': `\mu`, 'ν': `\nu`, 'ξ': `\xi`, 'π': `\pi`,
+	'ρ': `\rho`, 'σ': `\sigma`, 'τ': `\tau`, 'υ': `\upsilon`, 'φ': `\phi`,
+	'χ': `\chi`, 'ψ': `\psi`, 'ω': `\omega`, 'Γ': `\Gamma`, 'Δ': `\Delta`,
+	'Θ': `\Theta`, 'Λ': `\Lambda`, 'Ξ': `\Xi`, 'Π': `\Pi`, 'Σ': `\Sigma`,
+	'Φ': `\Phi`, 'Ψ': `\Psi`, 'Ω': `\Omega`,
+	'∑': `\sum`, '∏': `\prod`, '∫': `\int`, '∂': `\partial`, '∇': `\nabla`,
+	'∞': `\infty`, '√': `\sqrt`, '±': `\pm`, '∓': `\mp`, '×': `\times`,
+	'÷': `\div`, '·': `\cdot`, '∘': `\circ`, '≤': `\leq`, '≥': `\geq`,
+	'≠': `\neq`, '≈': `\approx`, '≡': `\equiv`, '∼': `\sim`, '∝': `\propto`,
+	'∈': `\in`, '∉': `\notin`, '⊂': `\subset`, '⊆': `\subseteq`, '∪': `\cup`,
+	'∩': `\cap`, '∅': `\emptyset`, '∀': `\forall`, '∃': `\exists`, '¬': `\neg`,
+	'∧': `\wedge`, '∨': `\vee`, '→': `\to`, '←': `\leftarrow`, '⇒': `\Rightarrow`,
+	'⇔': `\Leftrightarrow`, '↔': `\leftrightarrow`, '−': `-`,
+}
+
+// LaTeXText writes text in LaTeX math, replacing Unicode math symbols with
+// their commands. A command is followed by a space so it cannot run into a
+// following letter.
+func LaTeXText(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if cmd, ok := latexSymbols[r]; ok {
+			b.WriteString(cmd)
+			if strings.HasPrefix(cmd, `\`) {
+				b.WriteString(" ")
+			}
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// IsLaTeXOperator reports whether TeX has a command for an operator name, as
+// \sin for "sin"
+func IsLaTeXOperator(name string) bool {
+	return latexOperators[name]
+}
@@ -0,0 +1,28 @@
+package markdown
+
+import "testing"
+
+func TestLaTeXText(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"x+1", "x+1"},
+		{"α+β", `\alpha +\beta `},
+		{"a≤b", `a\leq b`},
+		{"2−1", "2-1"},
+		{"∑x", `\sum x`},
+	}
+	for _, tt := range tests {
+		if got := LaTeXText(tt.in); got != tt.want {
+			t.Errorf("LaTeXText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestIsLaTeXOperator(t *testing.T) {
+	for name, want := range map[string]bool{"sin": true, "lim": true, "sine": false, "f": false} {
+		if got := IsLaTeXOperator(name); got != want {
+			t.Errorf("IsLaTeXOperator(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package markdown
+
+import "time"
+
+// Metadata describes a source document as its properties declare it
+type Metadata struct {
+	Title    string
+	Author   string
+	Subject  string
+	Keywords []string
+	Created  time.Time
+	Modified time.Time
+	// Pages counts the pages of a PDF or DOCX, the slides of a presentation or
+	// the sheets of a spreadsheet. Zero when the document does not record it.
+	Pages int
+	// Language is the declared language tag, such as "en-US"
+	Language string
+}
@@ -0,0 +1,21 @@
+package markdown
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Slug returns the anchor GitHub-style renderers generate for a heading:
+// lowercased, with punctuation removed and spaces replaced by hyphens
+func Slug(heading string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(heading)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			b.WriteRune(r)
+		case unicode.IsSpace(r):
+			b.WriteRune('-')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,70 @@
+package markdown
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	headingLine   = regexp.MustCompile(`^#{1,6}(\s|$)`)
+	listItemLine  = regexp.MustCompile(`^\s*([-*+]|[0-9]+[.)]|[a-zA-Z][.)])\s+\S`)
+	tableSepLine  = regexp.MustCompile(`^\|?\s*:?-{3,}:?\s*(\|\s*:?-{3,}:?\s*)*\|?$`)
+	htmlTableOpen = regexp.MustCompile(`(?i)<table[\s>]`)
+)
+
+// Structure counts the block elements of a document
+type Structure struct {
+	Headings  int
+	ListItems int
+	Tables    int
+}
+
+// Diff describes how other differs from s, one entry per differing count
+func (s Structure) Diff(other Structure) []string {
+	var diffs []string
+	add := func(name string, a, b int) {
+		if a != b {
+			diffs = append(diffs, fmt.Sprintf("%s: %d in source, %d in output", name, a, b))
+		}
+	}
+	add("headings", s.Headings, other.Headings)
+	add("list items", s.ListItems, other.ListItems)
+	add("tables", s.Tables, other.Tables)
+	return diffs
+}
+
+// ParseStructure counts the headings, list items and tables of a Markdown
+// document. Front matter and fenced code blocks are ignored; HTML tables count
+// as tables.
+func ParseStructure(md string) Structure {
+	var s Structure
+	lines := strings.Split(md, "\n")
+	if strings.TrimSpace(lines[0]) == "---" {
+		for i := 1; i < len(lines); i++ {
+			if strings.TrimSpace(lines[i]) == "---" {
+				lines = lines[i+1:]
+				break
+			}
+		}
+	}
+
+	var fence CodeFence
+	for i, line := range lines {
+		if fence.Line(line) {
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case headingLine.MatchString(trimmed):
+			s.Headings++
+		case listItemLine.MatchString(line) && trimmed != "---":
+			s.ListItems++
+		case strings.Contains(trimmed, "|") && tableSepLine.MatchString(trimmed) && i > 0 && strings.Contains(lines[i-1], "|"):
+			s.Tables++
+		}
+		s.Tables += len(htmlTableOpen.FindAllString(line, -1))
+	}
+	return s
+}
@@ -0,0 +1,37 @@
+package markdown
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseStructure(t *testing.T) {
+	tests := []struct {
+		name string
+		md   string
+		want Structure
+	}{
+		{"headings", "# Title\n\n## Part\n\n####### not a heading", Structure{Headings: 2}},
+		{"lists", "- a\n* b\n1. c\n2) d\na. e\n---", Structure{ListItems: 5}},
+		{"pipe table", "| A | B |\n| --- | :---: |\n| 1 | 2 |", Structure{Tables: 1}},
+		{"html table", "<table><tr><td>x</td></tr></table>", Structure{Tables: 1}},
+		{"front matter", "---\ntitle: x\n- y\n---\n# T", Structure{Headings: 1}},
+		{"code", "```\n# comment\n- item\n```", Structure{}},
+	}
+	for _, tt := range tests {
+		if got := ParseStructure(tt.md); got != tt.want {
+			t.Errorf("%s: ParseStructure() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestStructureDiff(t *testing.T) {
+	source := Structure{Headings: 3, ListItems: 4, Tables: 1}
+	if diffs := source.Diff(source); diffs != nil {
+		t.Errorf("Diff(same) = %q, want none", diffs)
+	}
+	want := []string{"headings: 3 in source, 2 in output", "tables: 1 in source, 0 in output"}
+	if diffs := source.Diff(Structure{Headings: 2, ListItems: 4}); !reflect.DeepEqual(diffs, want) {
+		t.Errorf("Diff() = %q, want %q", diffs, want)
+	}
+}
@@ -0,0 +1,59 @@
+package markdown
+
+import (
+	"strconv"
+	"strings"
+)
+
+// FlattenTable renders the rows of a table, the first being the header, as
+// one block per row pairing each cell with its header, as in "**Header:** value".
+// Empty cells are left out. A table with only a header renders its cells as lines.
+func FlattenTable(rows [][]string) string {
+	if len(rows) == 0 {
+		return ""
+	}
+	if len(rows) == 1 {
+		return strings.Join(rows[0], "  \n")
+	}
+
+	header := rows[0]
+	var blocks []string
+	for _, row := range rows[1:] {
+		var pairs []string
+		for i, cell := range row {
+			if strings.TrimSpace(cell) == "" {
+				continue
+			}
+			label := "Column " + strconv.Itoa(i+1)
+			if i < len(header) && strings.TrimSpace(header[i]) != "" {
+				label = strings.TrimSuffix(strings.TrimSpace(header[i]), ":")
+			}
+			pairs = append(pairs, "**"+label+":** "+strings.TrimSpace(cell))
+		}
+		if len(pairs) > 0 {
+			blocks = append(blocks, strings.Join(pairs, "  \n"))
+		}
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+// TruncationNote describes the rows and columns left out of a table, as in
+// "… (120 more rows, 2 more columns)". It is empty when nothing was left out.
+func TruncationNote(rows, columns int) string {
+	var parts []string
+	for _, count := range []struct {
+		n    int
+		noun string
+	}{{rows, "row"}, {columns, "column"}} {
+		switch {
+		case count.n == 1:
+			parts = append(parts, "1 more "+count.noun)
+		case count.n > 1:
+			parts = append(parts, strconv.Itoa(count.n)+" more "+count.noun+"s")
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "… (" + strings.Join(parts, ", ") + ")"
+}
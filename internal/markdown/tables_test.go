@@ -0,0 +1,45 @@
+package markdown
+
+import "testing"
+
+func TestFlattenTable(t *testing.T) {
+	tests := []struct {
+		name string
+		rows [][]string
+		want string
+	}{
+		{"empty", nil, ""},
+		{"header only", [][]string{{"Name", "Role"}}, "Name  \nRole"},
+		{
+			"two columns",
+			[][]string{{"Name", "Role"}, {"Ana", "Editor"}, {"Bruno", "Author"}},
+			"**Name:** Ana  \n**Role:** Editor\n\n**Name:** Bruno  \n**Role:** Author",
+		},
+		{"empty cells left out", [][]string{{"Name", "Role"}, {"Ana", " "}}, "**Name:** Ana"},
+		{"header colon", [][]string{{"Name:", "Role"}, {"Ana", "Editor"}}, "**Name:** Ana  \n**Role:** Editor"},
+		{"missing header", [][]string{{"Name", ""}, {"Ana", "Editor", "x"}}, "**Name:** Ana  \n**Column 2:** Editor  \n**Column 3:** x"},
+	}
+	for _, tt := range tests {
+		if got := FlattenTable(tt.rows); got != tt.want {
+			t.Errorf("%s: FlattenTable() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTruncationNote(t *testing.T) {
+	tests := []struct {
+		rows, columns int
+		want          string
+	}{
+		{0, 0, ""},
+		{1, 0, "… (1 more row)"},
+		{250, 0, "… (250 more rows)"},
+		{0, 1, "… (1 more column)"},
+		{3, 2, "… (3 more rows, 2 more columns)"},
+	}
+	for _, tt := range tests {
+		if got := TruncationNote(tt.rows, tt.columns); got != tt.want {
+			t.Errorf("TruncationNote(%d, %d) = %q, want %q", tt.rows, tt.columns, got, tt.want)
+		}
+	}
+}
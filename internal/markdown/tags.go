@@ -0,0 +1,63 @@
+package markdown
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// minTagLength is the shortest word considered as an automatic tag
+const minTagLength = 3
+
+// stopwords are common English words never used as automatic tags
+var stopwords = map[string]bool{
+	"about": true, "above": true, "after": true, "again": true, "against": true, "all": true,
+	"also": true, "and": true, "any": true, "are": true, "because": true, "been": true,
+	"before": true, "being": true, "below": true, "between": true, "both": true, "but": true,
+	"can": true, "could": true, "did": true, "does": true, "doing": true, "down": true,
+	"during": true, "each": true, "few": true, "for": true, "from": true, "further": true,
+	"had": true, "has": true, "have": true, "having": true, "her": true, "here": true,
+	"hers": true, "herself": true, "him": true, "himself": true, "his": true, "how": true,
+	"into": true, "its": true, "itself": true, "just": true, "may": true, "more": true,
+	"most": true, "must": true, "not": true, "now": true, "off": true, "once": true,
+	"only": true, "other": true, "our": true, "ours": true, "out": true, "over": true,
+	"own": true, "same": true, "she": true, "should": true, "some": true, "such": true,
+	"than": true, "that": true, "the": true, "their": true, "theirs": true, "them": true,
+	"then": true, "there": true, "these": true, "they": true, "this": true, "those": true,
+	"through": true, "too": true, "under": true, "until": true, "very": true, "was": true,
+	"were": true, "what": true, "when": true, "where": true, "which": true, "while": true,
+	"who": true, "whom": true, "why": true, "will": true, "with": true, "would": true,
+	"you": true, "your": true, "yours": true, "page": true,
+}
+
+// DeriveTags returns up to n of the most frequent non-stopword terms in text.
+// Ties are broken alphabetically so the result is stable across runs.
+func DeriveTags(text string, n int) []string {
+	counts := make(map[string]int)
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '-'
+	})
+	for _, word := range words {
+		word = strings.Trim(word, "-")
+		if len([]rune(word)) < minTagLength || stopwords[word] {
+			continue
+		}
+		counts[word]++
+	}
+
+	terms := make([]string, 0, len(counts))
+	for term := range counts {
+		terms = append(terms, term)
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if counts[terms[i]] != counts[terms[j]] {
+			return counts[terms[i]] > counts[terms[j]]
+		}
+		return terms[i] < terms[j]
+	})
+
+	if len(terms) > n {
+		terms = terms[:n]
+	}
+	return terms
+}
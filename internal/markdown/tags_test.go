@@ -0,0 +1,27 @@
+package markdown
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDeriveTags(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		n    int
+		want []string
+	}{
+		{"by frequency", "Kernel modules load. The kernel schedules tasks; kernel tasks run.", 2, []string{"kernel", "tasks"}},
+		{"ties alphabetical", "zebra apple mango", 3, []string{"apple", "mango", "zebra"}},
+		{"stopwords and short words", "The cat and the dog were on a mat with their owners.", 5, []string{"cat", "dog", "mat", "owners"}},
+		{"hyphenated", "well-known well-known --dash--", 1, []string{"well-known"}},
+		{"case folded", "Go GOLANG golang Golang", 1, []string{"golang"}},
+		{"empty", "", 3, []string{}},
+	}
+	for _, tt := range tests {
+		if got := DeriveTags(tt.text, tt.n); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: DeriveTags(%q, %d) = %q, want %q", tt.name, tt.text, tt.n, got, tt.want)
+		}
+	}
+}
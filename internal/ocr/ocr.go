@@ -0,0 +1,37 @@
+// Package ocr recognizes the text of images with the tesseract command line
+// tool. Recognition is optional: without tesseract installed no text is found.
+package ocr
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// Command is the tesseract executable, looked up on the PATH
+const Command = "tesseract"
+
+// Available reports whether tesseract is installed
+func Available() bool {
+	_, err := exec.LookPath(Command)
+	return err == nil
+}
+
+// Recognize returns the text tesseract reads in an image file, on a single
+// line. It returns an empty string when tesseract is missing or cannot read
+// the image, such as a vector format it does not support.
+func Recognize(imagePath string) string {
+	return strings.Join(strings.Fields(RecognizeText(imagePath)), " ")
+}
+
+// RecognizeText returns the text tesseract reads in an image file as it lays
+// it out, with lines ending in newlines and paragraphs separated by blank lines
+func RecognizeText(imagePath string) string {
+	if !Available() {
+		return ""
+	}
+	out, err := exec.Command(Command, imagePath, "stdout").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
@@ -0,0 +1,97 @@
+package odf
+
+import (
+	"archive/zip"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/errs"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+)
+
+// ReadMetadata reads meta.xml of a presentation or spreadsheet and counts its
+// slides or sheets
+func ReadMetadata(inputPath string) (markdown.Metadata, error) {
+	z, err := zip.OpenReader(inputPath)
+	if err != nil {
+		return markdown.Metadata{}, fmt.Errorf("%w: failed to open OpenDocument file: %v", errs.ErrCorrupt, err)
+	}
+	defer z.Close()
+
+	p := &pkg{zip: z}
+	var meta markdown.Metadata
+	// meta.xml is optional
+	if doc, err := p.parse("meta.xml"); err == nil {
+		if props := doc.find("meta"); props != nil {
+			var keywords []string
+			for _, child := range props.Children {
+				text := strings.TrimSpace(child.text())
+				switch child.Name {
+				case "title":
+					meta.Title = text
+				case "initial-creator":
+					meta.Author = text
+				case "creator":
+					if meta.Author == "" {
+						meta.Author = text
+					}
+				case "subject":
+					meta.Subject = text
+				case "keyword":
+					keywords = append(keywords, text)
+				case "creation-date":
+					meta.Created = parseDate(text)
+				case "date":
+					meta.Modified = parseDate(text)
+				case "language":
+					meta.Language = text
+				}
+			}
+			meta.Keywords = markdown.ParseKeywords(strings.Join(keywords, ","))
+		}
+	}
+
+	content, err := p.parse("content.xml")
+	if err != nil {
+		return markdown.Metadata{}, err
+	}
+	if body := content.find("body"); body != nil {
+		container, part := body.find("presentation"), "page"
+		if strings.EqualFold(filepath.Ext(inputPath), ".ods") {
+			container, part = body.find("spreadsheet"), "table"
+		}
+		if container != nil {
+			for _, child := range container.Children {
+				if child.Name == part {
+					meta.Pages++
+				}
+			}
+		}
+	}
+	return meta, nil
+}
+
+// text returns the character data of a node and its descendants
+func (n *node) text() string {
+	if n.Name == "" {
+		return n.Text
+	}
+	var b strings.Builder
+	for _, child := range n.Children {
+		b.WriteString(child.text())
+	}
+	return b.String()
+}
+
+// parseDate parses an ISO 8601 date and time, which meta.xml writes with or
+// without a time zone and fractional seconds
+func parseDate(s string) time.Time {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999", "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
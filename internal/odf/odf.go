@@ -0,0 +1,259 @@
+// Package odf converts OpenDocument presentations (.odp) and spreadsheets
+// (.ods) to Markdown. Both are zip packages whose body is content.xml.
+package odf
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/errs"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/utils"
+)
+
+// Converter converts OpenDocument presentations and spreadsheets to Markdown
+type Converter struct {
+	AssetsDir string
+	// AssetsLink is the path prefix used to reference assets from the Markdown,
+	// defaulting to AssetsDir
+	AssetsLink string
+	// AssetNamer names extracted assets, keeping positional names when nil
+	AssetNamer *utils.AssetNamer
+	// ListStyle selects the list item markers
+	ListStyle markdown.ListStyle
+	// MaxRows is the number of rows written per sheet, the header included;
+	// zero writes every row
+	MaxRows int
+	// MaxCols is the number of columns written per sheet; zero writes every column
+	MaxCols int
+	// TruncationMarker notes the rows and columns left out below a table
+	TruncationMarker bool
+	// Logf, when set, reports the rows and columns left out of each sheet
+	Logf func(format string, args ...interface{})
+}
+
+// node is an element of content.xml, or a piece of text when Name is empty
+type node struct {
+	Name     string
+	Attr     []xml.Attr
+	Text     string
+	Children []*node
+}
+
+// runFormat is the character formatting of an automatic text style
+type runFormat struct {
+	Bold   bool
+	Italic bool
+}
+
+// pkg is an open OpenDocument package
+type pkg struct {
+	zip  *zip.ReadCloser
+	name string
+	// styles maps automatic style names to their formatting
+	styles map[string]runFormat
+}
+
+func (c *Converter) ToMarkdown(inputPath string, w io.Writer) error {
+	z, err := zip.OpenReader(inputPath)
+	if err != nil {
+		return fmt.Errorf("%w: failed to open OpenDocument file: %v", errs.ErrCorrupt, err)
+	}
+	defer z.Close()
+
+	p := &pkg{zip: z, name: strings.TrimSuffix(filepath.Base(inputPath), filepath.Ext(inputPath))}
+	content, err := p.parse("content.xml")
+	if err != nil {
+		return err
+	}
+	p.styles = readStyles(content)
+
+	body := content.find("body")
+	if body == nil {
+		return fmt.Errorf("%w: missing document body", errs.ErrCorrupt)
+	}
+
+	var result string
+	if strings.EqualFold(filepath.Ext(inputPath), ".ods") {
+		result = c.renderSpreadsheet(p, body)
+	} else {
+		result, err = c.renderPresentation(p, body)
+		if err != nil {
+			return err
+		}
+	}
+
+	if _, err := io.WriteString(w, strings.TrimSpace(result)+"\n"); err != nil {
+		return fmt.Errorf("failed to write markdown: %v", err)
+	}
+	return nil
+}
+
+// parse reads a package part into a tree of nodes
+func (p *pkg) parse(name string) (*node, error) {
+	f, err := p.open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	root := &node{}
+	stack := []*node{root}
+	d := xml.NewDecoder(f)
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to parse %s: %v", errs.ErrCorrupt, name, err)
+		}
+
+		parent := stack[len(stack)-1]
+		switch t := tok.(type) {
+		case xml.StartElement:
+			n := &node{Name: t.Name.Local, Attr: t.Attr}
+			parent.Children = append(parent.Children, n)
+			stack = append(stack, n)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			parent.Children = append(parent.Children, &node{Text: string(t)})
+		}
+	}
+	return root, nil
+}
+
+// open returns a reader for a package part
+func (p *pkg) open(name string) (io.ReadCloser, error) {
+	for _, f := range p.zip.File {
+		if f.Name == name {
+			r, err := f.Open()
+			if err != nil {
+				return nil, fmt.Errorf("%w: failed to open %s: %v", errs.ErrCorrupt, name, err)
+			}
+			return r, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: missing part %s", errs.ErrCorrupt, name)
+}
+
+// readStyles collects the bold and italic automatic text styles
+func readStyles(content *node) map[string]runFormat {
+	styles := make(map[string]runFormat)
+	auto := content.find("automatic-styles")
+	if auto == nil {
+		return styles
+	}
+	for _, style := range auto.Children {
+		if style.Name != "style" {
+			continue
+		}
+		props := style.find("text-properties")
+		if props == nil {
+			continue
+		}
+		styles[style.attr("name")] = runFormat{
+			Bold:   props.attr("font-weight") == "bold",
+			Italic: props.attr("font-style") == "italic",
+		}
+	}
+	return styles
+}
+
+// attr returns the value of an attribute by its local name
+func (n *node) attr(name string) string {
+	for _, a := range n.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// find returns the first descendant with the given local name
+func (n *node) find(name string) *node {
+	for _, child := range n.Children {
+		if child.Name == name {
+			return child
+		}
+		if found := child.find(name); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// inline renders the text of a paragraph or heading with its links and emphasis
+func (p *pkg) inline(n *node) string {
+	var b strings.Builder
+	for _, child := range n.Children {
+		switch child.Name {
+		case "":
+			b.WriteString(child.Text)
+		case "s":
+			// text:s is a run of spaces
+			count, err := strconv.Atoi(child.attr("c"))
+			if err != nil || count < 1 {
+				count = 1
+			}
+			b.WriteString(strings.Repeat(" ", count))
+		case "tab":
+			b.WriteString(" ")
+		case "line-break":
+			b.WriteString("  \n")
+		case "a":
+			text := p.inline(child)
+			if href := child.attr("href"); href != "" && strings.TrimSpace(text) != "" {
+				text = markdown.Wrap(text, "[", "]("+href+")")
+			}
+			b.WriteString(text)
+		case "span":
+			text := p.inline(child)
+			format := p.styles[child.attr("style-name")]
+			if format.Italic {
+				text = markdown.Wrap(text, "*", "*")
+			}
+			if format.Bold {
+				text = markdown.Wrap(text, "**", "**")
+			}
+			b.WriteString(text)
+		case "note", "annotation":
+			// Footnotes and comments are not part of the running text
+		default:
+			b.WriteString(p.inline(child))
+		}
+	}
+	return b.String()
+}
+
+// saveImage copies a picture of the package into the assets directory and
+// returns its Markdown reference
+func (c *Converter) saveImage(p *pkg, image *node, alt string) (string, error) {
+	href := image.attr("href")
+	if href == "" || strings.Contains(href, "://") {
+		return "", nil
+	}
+	r, err := p.open(strings.TrimPrefix(href, "./"))
+	if err != nil {
+		return "", nil
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("%w: failed to read %s: %v", errs.ErrCorrupt, href, err)
+	}
+	name := c.AssetNamer.Name(fmt.Sprintf("%s-%s", p.name, path.Base(href)), data)
+	if err := os.WriteFile(filepath.Join(c.AssetsDir, name), data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write image: %v", err)
+	}
+	return "![" + alt + "](" + utils.AssetLink(c.AssetsLink, c.AssetsDir, name) + ")", nil
+}
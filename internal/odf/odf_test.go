@@ -0,0 +1,138 @@
+package odf
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/errs"
+)
+
+// odfNamespaces declares the prefixes content.xml uses
+const odfNamespaces = `xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0"` +
+	` xmlns:style="urn:oasis:names:tc:opendocument:xmlns:style:1.0"` +
+	` xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0"` +
+	` xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0"` +
+	` xmlns:draw="urn:oasis:names:tc:opendocument:xmlns:drawing:1.0"` +
+	` xmlns:presentation="urn:oasis:names:tc:opendocument:xmlns:presentation:1.0"` +
+	` xmlns:fo="urn:oasis:names:tc:opendocument:xmlns:xsl-fo-compatible:1.0"` +
+	` xmlns:xlink="http://www.w3.org/1999/xlink"`
+
+// boldStyle is an automatic style setting text in bold
+const boldStyle = `<office:automatic-styles><style:style style:name="T1" style:family="text">` +
+	`<style:text-properties fo:font-weight="bold"/></style:style></office:automatic-styles>`
+
+// writeODF writes a package named name whose content.xml holds body inside
+// office:body, with other parts by name
+func writeODF(t *testing.T, name, body string, parts map[string]string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	z := zip.NewWriter(&buf)
+	files := map[string]string{
+		"content.xml": `<?xml version="1.0" encoding="UTF-8"?><office:document-content ` + odfNamespaces + `>` +
+			boldStyle + `<office:body>` + body + `</office:body></office:document-content>`,
+	}
+	for part, data := range parts {
+		files[part] = data
+	}
+	for part, data := range files {
+		f, err := z.Create(part)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(data))
+	}
+	if err := z.Close(); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// convertODF converts a package, failing the test on errors
+func convertODF(t *testing.T, c *Converter, path string) string {
+	t.Helper()
+	if c.AssetsDir == "" {
+		c.AssetsDir = t.TempDir()
+	}
+	var out bytes.Buffer
+	if err := c.ToMarkdown(path, &out); err != nil {
+		t.Fatalf("ToMarkdown() error = %v", err)
+	}
+	return out.String()
+}
+
+func TestPresentation(t *testing.T) {
+	body := `<office:presentation>` +
+		`<draw:page draw:name="p1"><draw:frame presentation:class="title"><draw:text-box><text:p>Welcome</text:p></draw:text-box></draw:frame>` +
+		`<draw:frame><draw:text-box><text:p>Some <text:span text:style-name="T1">bold</text:span> text, see <text:a xlink:href="https://example.com">the site</text:a>.</text:p>` +
+		`<text:list><text:list-item><text:p>First</text:p><text:list><text:list-item><text:p>Nested</text:p></text:list-item></text:list></text:list-item>` +
+		`<text:list-item><text:p>Second</text:p></text:list-item></text:list></draw:text-box></draw:frame></draw:page>` +
+		`<draw:page draw:name="p2"><draw:frame draw:name="Logo"><draw:image xlink:href="Pictures/logo.png"/></draw:frame></draw:page>` +
+		`</office:presentation>`
+	path := writeODF(t, "deck.odp", body, map[string]string{"Pictures/logo.png": "png data"})
+	assets := t.TempDir()
+	md := convertODF(t, &Converter{AssetsDir: assets}, path)
+	want := "## Welcome\n\nSome **bold** text, see [the site](https://example.com).\n\n- First\n  - Nested\n- Second\n\n" +
+		"## Slide 2\n\n![Logo](" + filepath.Join(assets, "deck-logo.png") + ")\n"
+	if md != want {
+		t.Errorf("output = %q, want %q", md, want)
+	}
+	if data, err := os.ReadFile(filepath.Join(assets, "deck-logo.png")); err != nil || string(data) != "png data" {
+		t.Errorf("saved image = %q, %v", data, err)
+	}
+}
+
+func TestSpreadsheet(t *testing.T) {
+	cell := func(s string) string { return `<table:table-cell><text:p>` + s + `</text:p></table:table-cell>` }
+	body := `<office:spreadsheet><table:table table:name="Stock">` +
+		`<table:table-row>` + cell("Fruit") + cell("Qty") + `<table:table-cell table:number-columns-repeated="1000"/></table:table-row>` +
+		`<table:table-row>` + cell("Apples") + cell("3") + `</table:table-row>` +
+		`<table:table-row table:number-rows-repeated="2">` + cell("Pears") + cell("a | b") + `</table:table-row>` +
+		`<table:table-row>` + cell("Plums") + `<table:covered-table-cell/>` + cell("x") + `</table:table-row>` +
+		`<table:table-row table:number-rows-repeated="1000"><table:table-cell/></table:table-row>` +
+		`</table:table><table:table table:name="Empty"/></office:spreadsheet>`
+	path := writeODF(t, "stock.ods", body, nil)
+
+	tests := []struct {
+		name string
+		c    Converter
+		want string
+	}{
+		{
+			name: "whole sheet",
+			want: "## Stock\n\n| Fruit | Qty |  |\n| --- | --- | --- |\n| Apples | 3 |  |\n| Pears | a \\| b |  |\n| Pears | a \\| b |  |\n| Plums |  | x |\n\n## Empty\n",
+		},
+		{
+			name: "limits",
+			c:    Converter{MaxRows: 2, MaxCols: 1, TruncationMarker: true},
+			want: "## Stock\n\n| Fruit |\n| --- |\n| Apples |\n\n" + "… (3 more rows, 2 more columns)" + "\n\n## Empty\n",
+		},
+	}
+	for _, tt := range tests {
+		if md := convertODF(t, &tt.c, path); md != tt.want {
+			t.Errorf("%s: output = %q, want %q", tt.name, md, tt.want)
+		}
+	}
+}
+
+func TestMissingContent(t *testing.T) {
+	var buf bytes.Buffer
+	z := zip.NewWriter(&buf)
+	z.Create("meta.xml")
+	z.Close()
+	path := filepath.Join(t.TempDir(), "broken.odp")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := (&Converter{}).ToMarkdown(path, &strings.Builder{}); !errors.Is(err, errs.ErrCorrupt) {
+		t.Errorf("ToMarkdown() error = %v, want ErrCorrupt", err)
+	}
+}
@@ -0,0 +1,118 @@
+package odf
+
+import (
+	"strconv"
+	"strings"
+)
+
+// renderPresentation renders each draw:page as a section headed by its title
+// frame, or by its slide number when it has none. Speaker notes are left out.
+func (c *Converter) renderPresentation(p *pkg, body *node) (string, error) {
+	presentation := body.find("presentation")
+	if presentation == nil {
+		return "", nil
+	}
+
+	var result strings.Builder
+	slide := 0
+	for _, page := range presentation.Children {
+		if page.Name != "page" {
+			continue
+		}
+		slide++
+
+		title := ""
+		var blocks []string
+		for _, frame := range page.Children {
+			if frame.Name != "frame" && frame.Name != "custom-shape" {
+				continue
+			}
+			if class := frame.attr("class"); class == "title" && title == "" {
+				title = strings.TrimSpace(p.inline(frame))
+				continue
+			}
+			text, err := c.renderFrame(p, frame)
+			if err != nil {
+				return "", err
+			}
+			if text != "" {
+				blocks = append(blocks, text)
+			}
+		}
+
+		if title == "" {
+			title = "Slide " + strconv.Itoa(slide)
+		}
+		result.WriteString("## " + title + "\n\n")
+		for _, block := range blocks {
+			result.WriteString(block + "\n\n")
+		}
+	}
+	return result.String(), nil
+}
+
+// renderFrame renders the text boxes and pictures of a frame
+func (c *Converter) renderFrame(p *pkg, frame *node) (string, error) {
+	var blocks []string
+	for _, child := range frame.Children {
+		switch child.Name {
+		case "text-box":
+			blocks = append(blocks, c.renderText(p, child, 0)...)
+		case "p", "h", "list":
+			// Custom shapes hold their text directly
+			blocks = append(blocks, c.renderText(p, &node{Children: []*node{child}}, 0)...)
+		case "image":
+			ref, err := c.saveImage(p, child, frame.attr("name"))
+			if err != nil {
+				return "", err
+			}
+			if ref != "" {
+				blocks = append(blocks, ref)
+			}
+		}
+	}
+	return strings.Join(blocks, "\n\n"), nil
+}
+
+// renderText renders the paragraphs and lists of a text box. Consecutive list
+// items are returned as one block so the list stays together.
+func (c *Converter) renderText(p *pkg, box *node, depth int) []string {
+	var blocks []string
+	for _, child := range box.Children {
+		switch child.Name {
+		case "p", "h":
+			if text := strings.TrimSpace(p.inline(child)); text != "" {
+				blocks = append(blocks, text)
+			}
+		case "list":
+			if items := c.renderList(p, child, depth); items != "" {
+				blocks = append(blocks, items)
+			}
+		}
+	}
+	return blocks
+}
+
+// renderList renders a text:list. Lists in presentations are bullets unless
+// their style numbers them, which is not resolved, so all items are bullets.
+func (c *Converter) renderList(p *pkg, list *node, depth int) string {
+	var lines []string
+	for _, item := range list.Children {
+		if item.Name != "list-item" && item.Name != "list-header" {
+			continue
+		}
+		for _, part := range item.Children {
+			switch part.Name {
+			case "p", "h":
+				if text := strings.TrimSpace(p.inline(part)); text != "" {
+					lines = append(lines, strings.Repeat("  ", depth)+c.ListStyle.BulletMarker()+" "+text)
+				}
+			case "list":
+				if nested := c.renderList(p, part, depth+1); nested != "" {
+					lines = append(lines, nested)
+				}
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,148 @@
+package odf
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+)
+
+// maxRepeat bounds the expansion of repeated rows and columns. Spreadsheets
+// commonly repeat an empty cell to the last column of the sheet.
+const maxRepeat = 1000
+
+// renderSpreadsheet renders each table:table as a section headed by the sheet
+// name, holding a GFM table whose first row is the header
+func (c *Converter) renderSpreadsheet(p *pkg, body *node) string {
+	spreadsheet := body.find("spreadsheet")
+	if spreadsheet == nil {
+		return ""
+	}
+
+	var result strings.Builder
+	for _, sheet := range spreadsheet.Children {
+		if sheet.Name != "table" {
+			continue
+		}
+		result.WriteString("## " + sheet.attr("name") + "\n\n")
+		rows, omittedRows, omittedCols := c.limitRows(sheetRows(p, sheet))
+		if table := renderTable(rows); table != "" {
+			result.WriteString(table + "\n\n")
+		}
+		if omittedRows > 0 || omittedCols > 0 {
+			if c.Logf != nil {
+				c.Logf("Sheet %s: left out %d rows and %d columns beyond the limits", sheet.attr("name"), omittedRows, omittedCols)
+			}
+			if c.TruncationMarker {
+				result.WriteString(markdown.TruncationNote(omittedRows, omittedCols) + "\n\n")
+			}
+		}
+	}
+	return result.String()
+}
+
+// limitRows applies MaxRows and MaxCols to the rows of a sheet, returning the
+// rows kept and the number of rows and columns left out
+func (c *Converter) limitRows(rows [][]string) ([][]string, int, int) {
+	omittedRows, widest := 0, 0
+	for _, row := range rows {
+		widest = max(widest, len(row))
+	}
+	if c.MaxRows > 0 && len(rows) > c.MaxRows {
+		rows, omittedRows = rows[:c.MaxRows], len(rows)-c.MaxRows
+	}
+	if c.MaxCols <= 0 || widest <= c.MaxCols {
+		return rows, omittedRows, 0
+	}
+	limited := make([][]string, len(rows))
+	for i, row := range rows {
+		limited[i] = row[:min(len(row), c.MaxCols)]
+	}
+	return limited, omittedRows, widest - c.MaxCols
+}
+
+// sheetRows returns the cell texts of a sheet with repeated rows and cells
+// expanded, leaving out trailing empty cells and rows
+func sheetRows(p *pkg, sheet *node) [][]string {
+	var rows [][]string
+	var addRows func(n *node)
+	addRows = func(n *node) {
+		for _, child := range n.Children {
+			switch child.Name {
+			case "table-row":
+				row := rowCells(p, child)
+				for i := 0; i < repeat(child, "number-rows-repeated"); i++ {
+					rows = append(rows, row)
+				}
+			case "table-header-rows", "table-rows", "table-row-group":
+				addRows(child)
+			}
+		}
+	}
+	addRows(sheet)
+
+	for len(rows) > 0 && len(rows[len(rows)-1]) == 0 {
+		rows = rows[:len(rows)-1]
+	}
+	return rows
+}
+
+// rowCells returns the texts of a row's cells. Cells covered by a merged cell
+// are kept empty so the columns stay aligned.
+func rowCells(p *pkg, row *node) []string {
+	var cells []string
+	for _, cell := range row.Children {
+		if cell.Name != "table-cell" && cell.Name != "covered-table-cell" {
+			continue
+		}
+		var parts []string
+		for _, para := range cell.Children {
+			if para.Name == "p" {
+				if text := strings.TrimSpace(p.inline(para)); text != "" {
+					parts = append(parts, strings.ReplaceAll(text, "|", "\\|"))
+				}
+			}
+		}
+		text := strings.Join(parts, "<br>")
+		for i := 0; i < repeat(cell, "number-columns-repeated"); i++ {
+			cells = append(cells, text)
+		}
+	}
+
+	for len(cells) > 0 && cells[len(cells)-1] == "" {
+		cells = cells[:len(cells)-1]
+	}
+	return cells
+}
+
+// repeat returns the repetition count of a row or cell
+func repeat(n *node, attr string) int {
+	count, err := strconv.Atoi(n.attr(attr))
+	if err != nil || count < 1 {
+		return 1
+	}
+	return min(count, maxRepeat)
+}
+
+// renderTable renders rows as a GFM table padded to the widest row
+func renderTable(rows [][]string) string {
+	columns := 0
+	for _, row := range rows {
+		columns = max(columns, len(row))
+	}
+	if columns == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	for i, row := range rows {
+		for len(row) < columns {
+			row = append(row, "")
+		}
+		b.WriteString("| " + strings.Join(row, " | ") + " |\n")
+		if i == 0 {
+			b.WriteString("|" + strings.Repeat(" --- |", columns) + "\n")
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -0,0 +1,125 @@
+package pdf
+
+import (
+	"fmt"
+
+	"github.com/rsc/pdf"
+)
+
+// defaultHighlightColor is used for highlight annotations without a color entry
+const defaultHighlightColor = "#ffff00"
+
+// rect is an axis-aligned rectangle in page space
+type rect struct {
+	MinX, MinY, MaxX, MaxY float64
+}
+
+func (r rect) contains(x, y float64) bool {
+	return x >= r.MinX && x <= r.MaxX && y >= r.MinY && y <= r.MaxY
+}
+
+func (r rect) area() float64 {
+	return (r.MaxX - r.MinX) * (r.MaxY - r.MinY)
+}
+
+// highlight is a Highlight annotation covering one or more areas of text
+type highlight struct {
+	Areas []rect
+	Color string
+}
+
+// readHighlights returns the highlight annotations of a page
+func readHighlights(page pdf.Page) []highlight {
+	var highlights []highlight
+	annots := page.V.Key("Annots")
+	for i := 0; i < annots.Len(); i++ {
+		annot := annots.Index(i)
+		if annot.Key("Subtype").Name() != "Highlight" {
+			continue
+		}
+
+		h := highlight{Color: deviceColor(arrayValues(annot.Key("C")))}
+		if h.Color == "" {
+			h.Color = defaultHighlightColor
+		}
+
+		// QuadPoints holds four corners per highlighted area; Rect bounds them all
+		quads := annot.Key("QuadPoints")
+		for q := 0; q+8 <= quads.Len(); q += 8 {
+			area := rect{MinX: quads.Index(q).Float64(), MinY: quads.Index(q + 1).Float64()}
+			area.MaxX, area.MaxY = area.MinX, area.MinY
+			for p := q + 2; p < q+8; p += 2 {
+				x, y := quads.Index(p).Float64(), quads.Index(p+1).Float64()
+				area.MinX, area.MaxX = min(area.MinX, x), max(area.MaxX, x)
+				area.MinY, area.MaxY = min(area.MinY, y), max(area.MaxY, y)
+			}
+			h.Areas = append(h.Areas, area)
+		}
+		if len(h.Areas) == 0 {
+			if r, ok := rectValue(annot.Key("Rect")); ok {
+				h.Areas = append(h.Areas, r)
+			}
+		}
+
+		highlights = append(highlights, h)
+	}
+	return highlights
+}
+
+// markHighlights sets the highlight color of every element whose glyph center is covered
+func markHighlights(elements []TextElement, highlights []highlight) {
+	for i := range elements {
+		e := &elements[i]
+		x, y := e.X+e.Width/2, e.Y+e.Size/3
+		for _, h := range highlights {
+			for _, area := range h.Areas {
+				if area.contains(x, y) {
+					e.Highlight = h.Color
+				}
+			}
+		}
+	}
+}
+
+func rectValue(v pdf.Value) (rect, bool) {
+	if v.Len() != 4 {
+		return rect{}, false
+	}
+	x1, y1, x2, y2 := v.Index(0).Float64(), v.Index(1).Float64(), v.Index(2).Float64(), v.Index(3).Float64()
+	return rect{MinX: min(x1, x2), MinY: min(y1, y2), MaxX: max(x1, x2), MaxY: max(y1, y2)}, true
+}
+
+// deviceColor converts gray, RGB or CMYK color components, such as those of
+// an annotation's color array or a fill color operator, into a hex color. It
+// returns an empty string for other component counts and non-numeric values.
+func deviceColor(components []pdf.Value) string {
+	for _, c := range components {
+		if c.Kind() != pdf.Integer && c.Kind() != pdf.Real {
+			return ""
+		}
+	}
+	var r, g, b float64
+	switch len(components) {
+	case 1:
+		r = components[0].Float64()
+		g, b = r, r
+	case 3:
+		r, g, b = components[0].Float64(), components[1].Float64(), components[2].Float64()
+	case 4:
+		k := components[3].Float64()
+		r = (1 - components[0].Float64()) * (1 - k)
+		g = (1 - components[1].Float64()) * (1 - k)
+		b = (1 - components[2].Float64()) * (1 - k)
+	default:
+		return ""
+	}
+	return hexColor(r, g, b)
+}
+
+// hexColor formats color components in the 0-1 range as #rrggbb
+func hexColor(r, g, b float64) string {
+	clamp := func(v float64) int {
+		return int(max(0, min(1, v))*255 + 0.5)
+	}
+	return fmt.Sprintf("#%02x%02x%02x", clamp(r), clamp(g), clamp(b))
+}
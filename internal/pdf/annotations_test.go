@@ -0,0 +1,85 @@
+package pdf
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+)
+
+// highlightPDF builds a page whose middle phrase is covered by a highlight
+// annotation with extra entries, such as its color
+func highlightPDF(extra string) *testPDF {
+	p := &testPDF{}
+	// Glyphs are 6 points wide at 12 points, so the phrase spans 114 to 186
+	content := showText(72, 700, "Before ", "F1", 12) + showText(114, 700, "marked words", "F1", 12) + showText(186, 700, " after.", "F1", 12)
+	annot := p.add("<< /Type /Annot /Subtype /Highlight /Rect [110 696 190 712] /QuadPoints [113 712 187 712 113 696 187 696] " + extra + " >>")
+	p.pageWith(content, fmt.Sprintf("/Annots [%d 0 R]", annot), "")
+	return p
+}
+
+func TestHighlights(t *testing.T) {
+	tests := []struct {
+		name     string
+		c        Converter
+		extra    string
+		want     string
+		wantNone bool
+	}{
+		{name: "gfm", want: "Before <mark>marked words</mark> after."},
+		{name: "obsidian", c: Converter{Dialect: markdown.Obsidian}, want: "Before ==marked words== after."},
+		{name: "pandoc", c: Converter{Dialect: markdown.Pandoc}, extra: "/C [0 1 0]", want: "Before ==marked words== after."},
+		{name: "preserved color", c: Converter{PreserveColors: true}, extra: "/C [0 1 0]", want: `Before <mark style="background-color:#00ff00">marked words</mark> after.`},
+		{name: "default color", c: Converter{PreserveColors: true, Dialect: markdown.Obsidian}, want: `Before <mark style="background-color:#ffff00">marked words</mark> after.`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := strings.TrimSpace(convert(t, &tt.c, highlightPDF(tt.extra)))
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadHighlightsRect(t *testing.T) {
+	// Without QuadPoints the annotation rectangle is the highlighted area
+	p := &testPDF{}
+	annot := p.add("<< /Type /Annot /Subtype /Highlight /Rect [190 712 110 696] >>")
+	other := p.add("<< /Type /Annot /Subtype /Text /Rect [0 0 10 10] >>")
+	p.pageWith("", fmt.Sprintf("/Annots [%d 0 R %d 0 R]", annot, other), "")
+	highlights := readHighlights(openTestPDF(t, p).Page(1))
+	if len(highlights) != 1 {
+		t.Fatalf("readHighlights() = %+v, want one highlight", highlights)
+	}
+	want := rect{MinX: 110, MinY: 696, MaxX: 190, MaxY: 712}
+	if h := highlights[0]; len(h.Areas) != 1 || h.Areas[0] != want || h.Color != defaultHighlightColor {
+		t.Errorf("readHighlights() = %+v, want area %+v", h, want)
+	}
+}
+
+func TestDeviceColor(t *testing.T) {
+	tests := []struct {
+		array string
+		want  string
+	}{
+		{"[0.5]", "#808080"},
+		{"[1 0 0]", "#ff0000"},
+		{"[0 0 1 0]", "#ffff00"},
+		{"[0 0 0 1]", "#000000"},
+		{"[2 -1 0.5]", "#ff0080"},
+		{"[]", ""},
+		{"[1 0]", ""},
+		{"[/Red]", ""},
+	}
+	for _, tt := range tests {
+		p := &testPDF{}
+		annot := p.add("<< /C " + tt.array + " >>")
+		p.pageWith("", fmt.Sprintf("/Annots [%d 0 R]", annot), "")
+		c := openTestPDF(t, p).Page(1).V.Key("Annots").Index(0).Key("C")
+		if got := deviceColor(arrayValues(c)); got != tt.want {
+			t.Errorf("deviceColor(%s) = %q, want %q", tt.array, got, tt.want)
+		}
+	}
+}
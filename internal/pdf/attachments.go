@@ -0,0 +1,139 @@
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/errs"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/utils"
+	"github.com/rsc/pdf"
+)
+
+// AttachmentConverter converts an extracted attachment to Markdown. It reports
+// false when the attachment's file type is not supported.
+type AttachmentConverter func(path string, w io.Writer) (bool, error)
+
+// embeddedFile is a file attached to the document through the EmbeddedFiles name tree
+type embeddedFile struct {
+	Name   string
+	Stream pdf.Value
+}
+
+// readEmbeddedFiles walks the EmbeddedFiles name tree of the document catalog
+func readEmbeddedFiles(reader *pdf.Reader) []embeddedFile {
+	var files []embeddedFile
+	seen := make(map[string]bool)
+
+	var walk func(node pdf.Value, depth int)
+	walk = func(node pdf.Value, depth int) {
+		if node.Kind() != pdf.Dict || depth > 32 || seen[node.String()] {
+			return
+		}
+		seen[node.String()] = true
+
+		names := node.Key("Names")
+		for i := 0; i+1 < names.Len(); i += 2 {
+			spec := names.Index(i + 1)
+			stream := spec.Key("EF").Key("UF")
+			if stream.Kind() != pdf.Stream {
+				stream = spec.Key("EF").Key("F")
+			}
+			if stream.Kind() != pdf.Stream {
+				continue
+			}
+
+			name := spec.Key("UF").Text()
+			if name == "" {
+				name = spec.Key("F").Text()
+			}
+			if name == "" {
+				name = names.Index(i).Text()
+			}
+			files = append(files, embeddedFile{Name: name, Stream: stream})
+		}
+
+		for _, kid := range arrayValues(node.Key("Kids")) {
+			walk(kid, depth+1)
+		}
+	}
+	walk(reader.Trailer().Key("Root").Key("Names").Key("EmbeddedFiles"), 0)
+
+	return files
+}
+
+// extractAttachments saves the embedded files of a document to the assets
+// directory and returns an "Attachments" section linking them. Supported
+// attachments are converted as well when ConvertAttachment is set. Those of
+// encrypted documents are listed by name without being extracted.
+func (c *Converter) extractAttachments(doc *document) (string, error) {
+	files := readEmbeddedFiles(doc.reader)
+	if len(files) == 0 {
+		return "", nil
+	}
+	var result strings.Builder
+	result.WriteString("## Attachments\n\n")
+	for i, file := range files {
+		base, fileName, err := c.saveEmbeddedFile(doc, file, i)
+		if errors.Is(err, errs.ErrEncrypted) {
+			result.WriteString(c.ListStyle.BulletMarker() + " " + base + " (not extracted from the encrypted PDF)\n")
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		path := filepath.Join(c.AssetsDir, fileName)
+
+		item := c.ListStyle.BulletMarker() + " [" + base + "](" + utils.AssetLink(c.AssetsLink, c.AssetsDir, fileName) + ")"
+		if c.ConvertAttachment != nil {
+			var converted bytes.Buffer
+			ok, err := c.ConvertAttachment(path, &converted)
+			if err != nil {
+				return "", fmt.Errorf("failed to convert attachment %s: %v", file.Name, err)
+			}
+			if ok {
+				mdName := strings.TrimSuffix(fileName, filepath.Ext(fileName)) + ".md"
+				if err := os.WriteFile(filepath.Join(c.AssetsDir, mdName), converted.Bytes(), 0644); err != nil {
+					return "", fmt.Errorf("failed to save converted attachment %s: %v", file.Name, err)
+				}
+				item += " ([Markdown](" + utils.AssetLink(c.AssetsLink, c.AssetsDir, mdName) + "))"
+			}
+		}
+		result.WriteString(item + "\n")
+	}
+
+	return result.String(), nil
+}
+
+// saveEmbeddedFile writes the i-th embedded file of a document to the assets
+// directory, returning its base name and the name it was saved under. Files
+// of encrypted documents, whose streams are read undecrypted, return their
+// base name with errs.ErrEncrypted.
+func (c *Converter) saveEmbeddedFile(doc *document, file embeddedFile, i int) (string, string, error) {
+	base := filepath.Base(strings.ReplaceAll(file.Name, "\\", "/"))
+	if base == "." || base == "/" || base == "" {
+		base = fmt.Sprintf("attachment%d", i+1)
+	}
+	if isEncrypted(doc.reader) {
+		return base, "", fmt.Errorf("%w: attachments in encrypted PDFs are not supported", errs.ErrEncrypted)
+	}
+
+	raw, err := rawStreamData(doc.file, file.Stream)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read attachment %s: %v", file.Name, err)
+	}
+	data, _, err := decodeStreamFilters(raw, file.Stream)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to decode attachment %s: %v", file.Name, err)
+	}
+
+	fileName := c.AssetNamer.Name(doc.name+"-"+base, data)
+	if err := os.WriteFile(filepath.Join(c.AssetsDir, fileName), data, 0644); err != nil {
+		return "", "", fmt.Errorf("failed to save attachment %s: %v", file.Name, err)
+	}
+	return base, fileName, nil
+}
@@ -0,0 +1,98 @@
+package pdf
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// attachmentPDF builds a page with embedded files of the given names and
+// contents, the last one in a kid of the name tree
+func attachmentPDF(files ...[2]string) *testPDF {
+	p := &testPDF{}
+	var names []string
+	for _, file := range files {
+		stream := p.stream(file[1], "/Type /EmbeddedFile")
+		spec := p.add(fmt.Sprintf("<< /Type /Filespec /F (%s) /EF << /F %d 0 R >> >>", file[0], stream))
+		names = append(names, fmt.Sprintf("(%s) %d 0 R", file[0], spec))
+	}
+	last := len(names) - 1
+	kid := p.add(fmt.Sprintf("<< /Names [%s] >>", names[last]))
+	tree := p.add(fmt.Sprintf("<< /Names [%s] /Kids [%d 0 R] >>", strings.Join(names[:last], " "), kid))
+	p.catalog = fmt.Sprintf("/Names << /EmbeddedFiles %d 0 R >>", tree)
+	p.page(body("Body text."))
+	return p
+}
+
+func TestAttachments(t *testing.T) {
+	convertText := func(path string, w io.Writer) (bool, error) {
+		if filepath.Ext(path) != ".txt" {
+			return false, nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return false, err
+		}
+		_, err = io.WriteString(w, "Converted: "+string(data))
+		return true, err
+	}
+	tests := []struct {
+		name    string
+		convert AttachmentConverter
+		want    []string
+	}{
+		{
+			name: "listed",
+			want: []string{"## Attachments\n\n- [notes.txt](ASSETS/test-notes.txt)\n- [data.bin](ASSETS/test-data.bin)\n"},
+		},
+		{
+			name:    "converted",
+			convert: convertText,
+			want: []string{
+				"- [notes.txt](ASSETS/test-notes.txt) ([Markdown](ASSETS/test-notes.md))\n",
+				"- [data.bin](ASSETS/test-data.bin)\n",
+			},
+		},
+	}
+	for _, tt := range tests {
+		dir := t.TempDir()
+		p := attachmentPDF([2]string{`docs\\notes.txt`, "Plain notes"}, [2]string{"data.bin", "\x00\x01"})
+		got := convert(t, &Converter{AssetsDir: dir, ConvertAttachment: tt.convert}, p)
+		for _, want := range tt.want {
+			want = strings.ReplaceAll(want, "ASSETS", dir)
+			if !strings.Contains(got, want) {
+				t.Errorf("%s: output lacks %q:\n%s", tt.name, want, got)
+			}
+		}
+		if data, err := os.ReadFile(filepath.Join(dir, "test-notes.txt")); err != nil || string(data) != "Plain notes" {
+			t.Errorf("%s: saved attachment = %q, %v", tt.name, data, err)
+		}
+		if data, err := os.ReadFile(filepath.Join(dir, "test-notes.md")); tt.convert != nil && (err != nil || string(data) != "Converted: Plain notes") {
+			t.Errorf("%s: converted attachment = %q, %v", tt.name, data, err)
+		}
+	}
+}
+
+func TestAttachmentsEncrypted(t *testing.T) {
+	convertText := func(string, io.Writer) (bool, error) {
+		t.Error("attachment of an encrypted PDF converted")
+		return false, nil
+	}
+	for _, convertAttachment := range []AttachmentConverter{nil, convertText} {
+		dir := t.TempDir()
+		p := attachmentPDF([2]string{"notes.txt", "Plain notes"})
+		p.encrypted = true
+		got := convert(t, &Converter{AssetsDir: dir, ConvertAttachment: convertAttachment}, p)
+		for _, want := range []string{"Body text.", "## Attachments\n\n- notes.txt (not extracted from the encrypted PDF)\n"} {
+			if !strings.Contains(got, want) {
+				t.Errorf("output lacks %q:\n%s", want, got)
+			}
+		}
+		if _, err := os.Stat(filepath.Join(dir, "test-notes.txt")); err == nil {
+			t.Errorf("attachment of an encrypted PDF saved")
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package pdf
+
+import "math"
+
+// Bounds on a vector bullet relative to the font size of the item text it
+// opens: its diameter, and the gap between it and the text
+const (
+	minDotSize = 0.15
+	maxDotSize = 0.6
+	maxDotGap  = 2.5
+)
+
+// pathDot returns the bounds of a filled path drawn as a small circle, made
+// of curves that span roughly as wide as they are high
+func (g graphicsState) pathDot(path []pathSegment) (rect, bool) {
+	if g.Fill == "#ffffff" || len(path) == 0 {
+		return rect{}, false
+	}
+	bounds := rect{MinX: math.Inf(1), MinY: math.Inf(1), MaxX: math.Inf(-1), MaxY: math.Inf(-1)}
+	for _, seg := range path {
+		if !seg.Curve {
+			return rect{}, false
+		}
+		for _, p := range [][2]float64{seg.From, seg.To} {
+			x, y := g.transform(p)
+			bounds = rect{MinX: math.Min(bounds.MinX, x), MinY: math.Min(bounds.MinY, y), MaxX: math.Max(bounds.MaxX, x), MaxY: math.Max(bounds.MaxY, y)}
+		}
+	}
+	w, h := bounds.MaxX-bounds.MinX, bounds.MaxY-bounds.MinY
+	if w <= 0 || h <= 0 || math.Abs(w-h) > 0.25*math.Max(w, h) {
+		return rect{}, false
+	}
+	return bounds, true
+}
+
+// vectorBullets adds a bullet glyph for each dot that opens a line of text:
+// one sized for the text that follows it, sitting at the height of its
+// lowercase letters with nothing close to its left
+func vectorBullets(elements []TextElement, dots []rect) []TextElement {
+	var bullets []TextElement
+	for _, dot := range dots {
+		cy := (dot.MinY + dot.MaxY) / 2
+		var next *TextElement
+		opens := true
+		for i := range elements {
+			e := &elements[i]
+			if e.Image || cy < e.Y || cy > e.Y+0.7*e.Size {
+				continue
+			}
+			if e.X < dot.MaxX {
+				// Text in a column further left is on another line
+				if e.X+e.Width > dot.MinX-maxDotGap*e.Size {
+					opens = false
+					break
+				}
+				continue
+			}
+			if next == nil || e.X < next.X {
+				next = e
+			}
+		}
+		if !opens || next == nil {
+			continue
+		}
+		size := dot.MaxX - dot.MinX
+		if size < minDotSize*next.Size || size > maxDotSize*next.Size || next.X-dot.MaxX > maxDotGap*next.Size {
+			continue
+		}
+		bullets = append(bullets, TextElement{
+			Text:  "•",
+			Font:  next.Font,
+			Size:  next.Size,
+			X:     dot.MinX,
+			Y:     next.Y,
+			Width: size,
+			// Tagged pages keep their own list labels
+			MCID: noMCID,
+		})
+	}
+	return append(elements, bullets...)
+}
@@ -0,0 +1,65 @@
+package pdf
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// circle returns the operators filling a circle of radius r at cx, cy, drawn
+// as four Bézier curves, in the current fill color
+func circle(cx, cy, r float64) string {
+	k := 0.5523 * r
+	return fmt.Sprintf("%g %g m %g %g %g %g %g %g c %g %g %g %g %g %g c %g %g %g %g %g %g c %g %g %g %g %g %g c f\n",
+		cx+r, cy,
+		cx+r, cy+k, cx+k, cy+r, cx, cy+r,
+		cx-k, cy+r, cx-r, cy+k, cx-r, cy,
+		cx-r, cy-k, cx-k, cy-r, cx, cy-r,
+		cx+k, cy-r, cx+r, cy-k, cx+r, cy)
+}
+
+// dottedList shows a line of text per item at x 86, each opened by a drawing
+func dottedList(dot func(y float64) string, items ...string) string {
+	var b strings.Builder
+	b.WriteString(showText(72, 720, "Shopping list for the week ahead.", "F1", 12))
+	for i, item := range items {
+		y := 700 - float64(i)*18
+		b.WriteString(dot(y) + showText(86, y, item, "F1", 12))
+	}
+	return b.String()
+}
+
+func TestVectorBullets(t *testing.T) {
+	tests := []struct {
+		name string
+		dot  func(y float64) string
+		list bool
+	}{
+		{"filled dots", func(y float64) string { return circle(76, y+4, 2) }, true},
+		{"colored dots", func(y float64) string { return "0.8 0 0 rg " + circle(76, y+4, 2) + "0 g\n" }, true},
+		{"white dots", func(y float64) string { return "1 g " + circle(76, y+4, 2) + "0 g\n" }, false},
+		{"large circles", func(y float64) string { return circle(70, y+4, 8) }, false},
+		{"below the text", func(y float64) string { return circle(76, y-6, 2) }, false},
+		{"squares", func(y float64) string { return fmt.Sprintf("74 %g 4 4 re f\n", y+2) }, false},
+	}
+	for _, tt := range tests {
+		p := &testPDF{}
+		p.page(dottedList(tt.dot, "Apples", "Pears"))
+		got := convert(t, &Converter{}, p)
+		if list := strings.Contains(got, "- Apples\n- Pears\n"); list != tt.list {
+			t.Errorf("%s: list %v, want %v:\n%s", tt.name, list, tt.list, got)
+		}
+		if !tt.list && strings.Contains(got, "- ") {
+			t.Errorf("%s: output has a list item:\n%s", tt.name, got)
+		}
+	}
+}
+
+func TestVectorBulletsMidLine(t *testing.T) {
+	// A dot between words is punctuation drawn as a path, not a list marker
+	p := &testPDF{}
+	p.page(showText(72, 700, "Left", "F1", 12) + circle(102, 704, 2) + showText(110, 700, "Right", "F1", 12))
+	if got := convert(t, &Converter{}, p); strings.Contains(got, "- ") {
+		t.Errorf("output has a list item:\n%s", got)
+	}
+}
@@ -0,0 +1,79 @@
+package pdf
+
+import (
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+)
+
+// calloutPattern matches the keyword a callout opens with, as in "Note: text" or "WARNING - text"
+var calloutPattern = regexp.MustCompile(`^\s*(?i:(note|info|tip|hint|important|warning|caution|danger))\s*[:!–—-]\s*(.*)$`)
+
+// callout is a note or warning box being collected across lines
+type callout struct {
+	Kind  markdown.Alert
+	Lines []string
+	// Box is the shaded box the callout is drawn in, 0 when it has none
+	Box int
+}
+
+// pathBoxes returns the shaded rectangles of a filled path, which may be the
+// background of a callout. White and black fills are ignored, as are rectangles
+// thin enough to be rules.
+func (g graphicsState) pathBoxes(path []pathSegment) []rect {
+	if g.Fill == "" || g.Fill == "#ffffff" {
+		return nil
+	}
+	var boxes []rect
+	for _, seg := range path {
+		if !seg.Rect {
+			continue
+		}
+		x0, y0 := g.transform(seg.From)
+		x1, y1 := g.transform(seg.To)
+		box := rect{MinX: math.Min(x0, x1), MinY: math.Min(y0, y1), MaxX: math.Max(x0, x1), MaxY: math.Max(y0, y1)}
+		if box.MaxX-box.MinX > maxRuleThickness && box.MaxY-box.MinY > maxRuleThickness {
+			boxes = append(boxes, box)
+		}
+	}
+	return boxes
+}
+
+// markBoxes records which shaded box each line lies in. Boxes painted later
+// are on top, so the last box containing a line wins.
+func markBoxes(lines []TextLine, boxes []rect) {
+	for i := range lines {
+		if len(lines[i].Elements) == 0 {
+			continue
+		}
+		left, right := lineBounds(lines[i])
+		y := lines[i].Y + lines[i].FontSize/3
+		for j, box := range boxes {
+			if box.contains(left, y) && box.contains(right, y) {
+				lines[i].Box = j + 1
+			}
+		}
+	}
+}
+
+// parseCallout reports whether a line opens a callout, returning its kind and
+// the text after the keyword. Within a shaded box the keyword may stand alone
+// as the box title.
+func parseCallout(line TextLine, text string) (callout, bool) {
+	if m := calloutPattern.FindStringSubmatch(text); m != nil {
+		kind, _ := markdown.ParseAlert(m[1])
+		co := callout{Kind: kind, Box: line.Box}
+		if rest := strings.TrimSpace(m[2]); rest != "" {
+			co.Lines = append(co.Lines, rest)
+		}
+		return co, true
+	}
+	if line.Box != 0 {
+		if kind, ok := markdown.ParseAlert(strings.TrimSpace(text)); ok {
+			return callout{Kind: kind, Box: line.Box}, true
+		}
+	}
+	return callout{}, false
+}
@@ -0,0 +1,33 @@
+package pdf
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+)
+
+func TestCallouts(t *testing.T) {
+	boxed := "0.9 0.9 1 rg 60 600 300 80 re f 0 g\n" +
+		showText(72, 660, "Warning", "F2", 12) + showText(72, 642, "The oven is hot.", "F1", 12) +
+		showText(72, 624, "Use gloves.", "F1", 12) + showText(72, 560, "Back to the text.", "F1", 12)
+	tests := []struct {
+		name    string
+		dialect markdown.Dialect
+		content string
+		want    string
+	}{
+		{"keyword", markdown.GFM, body("Intro text.", "Note: Save your work often."), "> [!NOTE]\n> Save your work often."},
+		{"keyword without alerts", markdown.CommonMark, body("Intro text.", "TIP - Use the shortcut."), "> **Tip:** Use the shortcut."},
+		{"shaded box", markdown.GFM, boxed, "> [!WARNING]\n> The oven is hot.\n> Use gloves.\n\nBack to the text."},
+		{"plain word", markdown.GFM, body("Note the difference."), "Note the difference."},
+	}
+	for _, tt := range tests {
+		p := &testPDF{}
+		p.page(tt.content)
+		md := convert(t, &Converter{AssetsDir: t.TempDir(), Dialect: tt.dialect}, p)
+		if !strings.Contains(md, tt.want) {
+			t.Errorf("%s: output lacks %q:\n%s", tt.name, tt.want, md)
+		}
+	}
+}
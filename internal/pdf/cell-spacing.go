@@ -0,0 +1,113 @@
+package pdf
+
+import (
+	"math"
+	"strings"
+)
+
+// nbsp is the entity a space Markdown would trim or collapse is kept as
+const nbsp = "&nbsp;"
+
+// spacedText returns the text of the line of a table cell with the spaces
+// it is set with: a space for every space width its text starts right of
+// left, the space glyphs it draws and as many spaces as fit in each gap that
+// separates its glyphs. The space width is measured over the whole table, so
+// rows without a space glyph are spaced alike.
+func spacedText(elements []TextElement, left, space float64) string {
+	if len(elements) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	if lead := int(math.Round((elements[0].X - left) / space)); lead > 0 {
+		b.WriteString(strings.Repeat(" ", lead))
+	}
+	for i, element := range elements {
+		if i > 0 {
+			prev := elements[i-1]
+			if gap := element.X - prev.X - prev.Width; gap > space/2 {
+				b.WriteString(strings.Repeat(" ", max(int(math.Round(gap/space)), 1)))
+			}
+		}
+		b.WriteString(element.Text)
+	}
+	return b.String()
+}
+
+// spaceWidth returns the width of a space glyph among elements or, when none
+// is drawn, the gap that reads as a word space
+func spaceWidth(elements []TextElement) float64 {
+	size := 0.0
+	for _, element := range elements {
+		if element.Text == " " && element.Width > 0 {
+			return element.Width
+		}
+		size = max(size, element.Size)
+	}
+	return max(size, 1) * wordGapScale
+}
+
+// keepSpaces escapes the spaces of cell text that Markdown would trim or
+// collapse: those opening or closing it and all but the first of a run
+func keepSpaces(text string) string {
+	var b strings.Builder
+	body := strings.TrimRight(text, " ")
+	run := 0
+	for _, r := range body {
+		if r != ' ' {
+			run = 0
+			b.WriteRune(r)
+			continue
+		}
+		if run > 0 || b.Len() == 0 {
+			b.WriteString(nbsp)
+		} else {
+			b.WriteByte(' ')
+		}
+		run++
+	}
+	b.WriteString(strings.Repeat(nbsp, len(text)-len(body)))
+	return b.String()
+}
+
+// columnLefts returns where the text of each column of a bordered table
+// starts, the leftmost glyph of its cells, and the width of a space in it
+func columnLefts(cells [][][]TextElement) ([]float64, float64) {
+	var lefts []float64
+	var elements []TextElement
+	for _, row := range cells {
+		for col, cell := range row {
+			for len(lefts) <= col {
+				lefts = append(lefts, math.Inf(1))
+			}
+			for _, element := range cell {
+				lefts[col] = min(lefts[col], element.X)
+			}
+			elements = append(elements, cell...)
+		}
+	}
+	return lefts, spaceWidth(elements)
+}
+
+// spacedCells sets the text of the cells of a borderless table as spacedText
+// does, from the left of the column band each lies in
+func spacedCells(rows [][]tableCell) [][]tableCell {
+	bands := columnBands(rows)
+	var elements []TextElement
+	for _, row := range rows {
+		for _, cell := range row {
+			elements = append(elements, cell.Elements...)
+		}
+	}
+	space := spaceWidth(elements)
+
+	spaced := make([][]tableCell, len(rows))
+	for i, row := range rows {
+		for _, cell := range row {
+			left := bands[bandOf(bands, (cell.X0+cell.X1)/2)][0]
+			cell.Text = keepSpaces(spacedText(cell.Elements, left, space))
+			spaced[i] = append(spaced[i], cell)
+		}
+	}
+	return spaced
+}
@@ -0,0 +1,94 @@
+package pdf
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPreserveCellSpacing(t *testing.T) {
+	// Glyphs are 6 points wide, as wide as the space the header draws, so the
+	// amounts right-aligned below it are padded by whole spaces
+	bordered := gridLines([]float64{72, 180, 300}, []float64{700, 680, 660, 640}) +
+		showText(76, 686, "Code", "F1", 12) + showText(184, 686, "Net amount", "F1", 12) +
+		showText(76, 666, "A1", "F1", 12) + showText(202, 666, "7", "F1", 12) +
+		showText(76, 646, "B2", "F1", 12) + showText(184, 646, "12  50", "F1", 12)
+	borderless := showText(72, 700, "Item name", "F1", 12) + showText(200, 700, "Qty", "F1", 12) +
+		showText(72, 682, "Nails", "F1", 12) + showText(200, 682, "120", "F1", 12) +
+		showText(72, 664, "Screws", "F1", 12) + showText(212, 664, "8", "F1", 12)
+	tests := []struct {
+		name     string
+		content  string
+		preserve bool
+		want     []string
+	}{
+		{"bordered", bordered, true, []string{"| Code | Net amount |", "| A1 | &nbsp;&nbsp;&nbsp;7 |", "| B2 | 12 &nbsp;50 |"}},
+		{"bordered by default", bordered, false, []string{"| A1 | 7 |", "| B2 | 12 50 |"}},
+		{"borderless", borderless, true, []string{"| Nails | 120 |", "| Screws | &nbsp;&nbsp;8 |"}},
+		{"borderless by default", borderless, false, []string{"| Screws | 8 |"}},
+	}
+	for _, tt := range tests {
+		p := &testPDF{}
+		p.page(tt.content)
+		got := convert(t, &Converter{AssetsDir: t.TempDir(), PreserveCellSpacing: tt.preserve}, p)
+		for _, row := range tt.want {
+			if !strings.Contains(got, row+"\n") {
+				t.Errorf("%s: row %q missing from:\n%s", tt.name, row, got)
+			}
+		}
+	}
+}
+
+func TestSpacedText(t *testing.T) {
+	tests := []struct {
+		name     string
+		elements []TextElement
+		left     float64
+		want     string
+	}{
+		{"padded", []TextElement{{Text: "7", X: 90, Width: 6}}, 72, "   7"},
+		{"space glyphs", []TextElement{{Text: "a", X: 72, Width: 6}, {Text: " ", X: 78, Width: 6}, {Text: "b", X: 84, Width: 6}}, 72, "a b"},
+		{"gap without a glyph", []TextElement{{Text: "a", X: 72, Width: 6}, {Text: "b", X: 90, Width: 6}}, 72, "a  b"},
+		{"narrow gap", []TextElement{{Text: "a", X: 72, Width: 6}, {Text: "b", X: 80, Width: 6}}, 72, "ab"},
+		{"no glyphs", nil, 72, ""},
+	}
+	for _, tt := range tests {
+		if got := spacedText(tt.elements, tt.left, 6); got != tt.want {
+			t.Errorf("%s: spacedText() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSpaceWidth(t *testing.T) {
+	tests := []struct {
+		name     string
+		elements []TextElement
+		want     float64
+	}{
+		{"space glyph", []TextElement{{Text: "a", Width: 6, Size: 12}, {Text: " ", Width: 5, Size: 12}}, 5},
+		{"word gap", []TextElement{{Text: "a", Width: 6, Size: 12}}, 3},
+		{"no size", nil, 0.25},
+	}
+	for _, tt := range tests {
+		if got := spaceWidth(tt.elements); got != tt.want {
+			t.Errorf("%s: spaceWidth() = %g, want %g", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestKeepSpaces(t *testing.T) {
+	tests := []struct {
+		text, want string
+	}{
+		{"plain", "plain"},
+		{"a b", "a b"},
+		{"  7", "&nbsp;&nbsp;7"},
+		{"a   b", "a &nbsp;&nbsp;b"},
+		{"x  ", "x&nbsp;&nbsp;"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := keepSpaces(tt.text); got != tt.want {
+			t.Errorf("keepSpaces(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package pdf
+
+import (
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+)
+
+// cellGapScale is the gap between elements, relative to the font size, that
+// separates the cells of a borderless table row
+const cellGapScale = 2.0
+
+var (
+	// currencyCell matches a cell holding only a currency symbol or code, which
+	// belongs to the amount that follows it
+	currencyCell = regexp.MustCompile(`^(?:[$€£¥₹¢₩₽]|USD|EUR|GBP|JPY|CHF)$`)
+	// unitCell matches a cell holding only a unit, which belongs to the value before it
+	unitCell = regexp.MustCompile(`^(?:%|‰|°[CF]?|kg|g|mg|t|km|m|cm|mm|l|ml|h|min|s|ms|kWh|[kMG]?B|USD|EUR|GBP)$`)
+	// numericCell matches an amount such as "1,234.00", "-5" or "(12.5)"
+	numericCell = regexp.MustCompile(`^[-+−(]?\d[\d,.' ]*\)?$`)
+)
+
+// tableCell is the text of a borderless table cell and its horizontal extent
+type tableCell struct {
+	Text   string
+	X0, X1 float64
+	// Elements are the glyphs the cell is set with
+	Elements []TextElement
+}
+
+// tableCells splits a line into the cells of a borderless table row at wide
+// gaps. A currency symbol or unit set apart from its number is merged back into
+// the number's cell, so "$", "1,234.00" is one cell rather than two columns.
+func (c *Converter) tableCells(line TextLine) []tableCell {
+	var cells []tableCell
+	start := 0
+	for i := 1; i <= len(line.Elements); i++ {
+		if i < len(line.Elements) {
+			prev := line.Elements[i-1]
+			if line.Elements[i].X-prev.X-prev.Width < line.FontSize*cellGapScale {
+				continue
+			}
+		}
+		cell := line
+		cell.Elements = line.Elements[start:i]
+		x0, x1 := lineBounds(cell)
+		cells = append(cells, tableCell{Text: strings.TrimSpace(c.extractLineText(cell)), X0: x0, X1: x1, Elements: cell.Elements})
+		start = i
+	}
+	return mergeUnitCells(cells)
+}
+
+// mergeUnitCells joins lone currency and unit cells with the adjacent number
+func mergeUnitCells(cells []tableCell) []tableCell {
+	var merged []tableCell
+	for i := 0; i < len(cells); i++ {
+		cell := cells[i]
+		switch {
+		case currencyCell.MatchString(cell.Text) && i+1 < len(cells) && numericCell.MatchString(cells[i+1].Text):
+			merged = append(merged, tableCell{
+				Text:     cell.Text + " " + cells[i+1].Text,
+				X0:       cell.X0,
+				X1:       cells[i+1].X1,
+				Elements: slices.Concat(cell.Elements, cells[i+1].Elements),
+			})
+			i++
+		case unitCell.MatchString(cell.Text) && len(merged) > 0 && numericCell.MatchString(merged[len(merged)-1].Text):
+			last := &merged[len(merged)-1]
+			last.Text += " " + cell.Text
+			last.X1 = cell.X1
+			last.Elements = slices.Concat(last.Elements, cell.Elements)
+		default:
+			merged = append(merged, cell)
+		}
+	}
+	return merged
+}
+
+// renderTableRows renders the rows of a borderless table, the first row being
+// the header. Every row is padded to the widest row; with PreserveEmptyCells
+// cells are placed in the column band they lie in, so a row missing a middle
+// cell keeps an empty cell there instead of shifting its later cells left.
+// Flattened tables are always aligned to columns, to pair cells with their header.
+func (c *Converter) renderTableRows(rows [][]tableCell) string {
+	if c.PreserveCellSpacing {
+		rows = spacedCells(rows)
+	}
+	var texts [][]string
+	if c.PreserveEmptyCells || c.FlattenTables {
+		bands := columnBands(rows)
+		for _, row := range rows {
+			cells := make([]string, len(bands))
+			for _, cell := range row {
+				col := bandOf(bands, (cell.X0+cell.X1)/2)
+				cells[col] = strings.TrimSpace(cells[col] + " " + cell.Text)
+			}
+			texts = append(texts, cells)
+		}
+	} else {
+		for _, row := range rows {
+			var cells []string
+			for _, cell := range row {
+				cells = append(cells, cell.Text)
+			}
+			texts = append(texts, cells)
+		}
+	}
+
+	if c.FlattenTables {
+		return markdown.FlattenTable(texts)
+	}
+
+	columns := 0
+	for _, row := range texts {
+		columns = max(columns, len(row))
+	}
+
+	var b strings.Builder
+	for i, row := range texts {
+		for len(row) < columns {
+			row = append(row, "")
+		}
+		b.WriteString("| " + strings.Join(escapeCells(row), " | ") + " |\n")
+		if i == 0 {
+			b.WriteString("|" + strings.Repeat(" --- |", columns) + "\n")
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// columnBands merges the horizontal extents of all cells into the columns of
+// the table: cells that overlap horizontally belong to the same column
+func columnBands(rows [][]tableCell) [][2]float64 {
+	var extents [][2]float64
+	for _, row := range rows {
+		for _, cell := range row {
+			extents = append(extents, [2]float64{cell.X0, cell.X1})
+		}
+	}
+	sort.Slice(extents, func(i, j int) bool {
+		return extents[i][0] < extents[j][0]
+	})
+
+	var bands [][2]float64
+	for _, e := range extents {
+		if n := len(bands); n > 0 && e[0] <= bands[n-1][1] {
+			bands[n-1][1] = max(bands[n-1][1], e[1])
+			continue
+		}
+		bands = append(bands, e)
+	}
+	return bands
+}
+
+// bandOf returns the column band containing x, or the nearest one
+func bandOf(bands [][2]float64, x float64) int {
+	best := 0
+	for i, band := range bands {
+		if x >= band[0] && x <= band[1] {
+			return i
+		}
+		if x > band[1] {
+			best = i
+		}
+	}
+	return best
+}
+
+// escapeCells escapes pipes inside cell text
+func escapeCells(cells []string) []string {
+	escaped := make([]string, len(cells))
+	for i, cell := range cells {
+		escaped[i] = strings.ReplaceAll(cell, "|", "\\|")
+	}
+	return escaped
+}
@@ -0,0 +1,107 @@
+package pdf
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// row shows the cells of a borderless table row at the x positions
+func row(y float64, cells ...any) string {
+	var b strings.Builder
+	for i := 0; i < len(cells); i += 2 {
+		b.WriteString(showText(cells[i].(float64), y, cells[i+1].(string), "F1", 12))
+	}
+	return b.String()
+}
+
+func TestMergeUnitCells(t *testing.T) {
+	tests := []struct {
+		cells []string
+		want  []string
+	}{
+		{[]string{"Total", "$", "1,234.00"}, []string{"Total", "$ 1,234.00"}},
+		{[]string{"Fee", "EUR", "(12.5)"}, []string{"Fee", "EUR (12.5)"}},
+		{[]string{"Growth", "12.5", "%"}, []string{"Growth", "12.5 %"}},
+		{[]string{"Weight", "3", "kg", "4", "kg"}, []string{"Weight", "3 kg", "4 kg"}},
+		{[]string{"$", "Price"}, []string{"$", "Price"}},
+		{[]string{"Name", "kg"}, []string{"Name", "kg"}},
+		{[]string{"$"}, []string{"$"}},
+	}
+	for _, tt := range tests {
+		var cells []tableCell
+		for i, text := range tt.cells {
+			cells = append(cells, tableCell{Text: text, X0: float64(i * 100), X1: float64(i*100 + 50)})
+		}
+		var got []string
+		for _, cell := range mergeUnitCells(cells) {
+			got = append(got, cell.Text)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("mergeUnitCells(%q) = %q, want %q", tt.cells, got, tt.want)
+		}
+	}
+}
+
+func TestCurrencyCells(t *testing.T) {
+	p := &testPDF{}
+	p.page(row(700, 72.0, "Item", 200.0, "Amount") +
+		row(682, 72.0, "Rent", 200.0, "$", 230.0, "1,234.00") +
+		row(664, 72.0, "Food", 200.0, "$", 230.0, "98.10"))
+	md := convert(t, &Converter{AssetsDir: t.TempDir()}, p)
+	want := "| Item | Amount |\n| --- | --- |\n| Rent | $ 1,234.00 |\n| Food | $ 98.10 |"
+	if !strings.Contains(md, want) {
+		t.Errorf("output lacks %q:\n%s", want, md)
+	}
+}
+
+func TestPreserveEmptyCells(t *testing.T) {
+	p := &testPDF{}
+	p.page(row(700, 72.0, "Name", 200.0, "Phone", 320.0, "City") +
+		row(682, 72.0, "Ana", 200.0, "555-0101", 320.0, "Porto") +
+		row(664, 72.0, "Bruno", 320.0, "Lisbon"))
+	tests := []struct {
+		preserve bool
+		want     string
+	}{
+		{false, "| Bruno | Lisbon |  |"},
+		{true, "| Bruno |  | Lisbon |"},
+	}
+	for _, tt := range tests {
+		md := convert(t, &Converter{AssetsDir: t.TempDir(), PreserveEmptyCells: tt.preserve}, p)
+		want := "| Name | Phone | City |\n| --- | --- | --- |\n| Ana | 555-0101 | Porto |\n" + tt.want
+		if !strings.Contains(md, want) {
+			t.Errorf("PreserveEmptyCells %v: output lacks %q:\n%s", tt.preserve, want, md)
+		}
+	}
+}
+
+func TestColumnBands(t *testing.T) {
+	rows := [][]tableCell{
+		{{X0: 72, X1: 100}, {X0: 200, X1: 250}},
+		{{X0: 72, X1: 130}, {X0: 220, X1: 280}, {X0: 320, X1: 340}},
+	}
+	bands := columnBands(rows)
+	if got, want := fmt.Sprint(bands), "[[72 130] [200 280] [320 340]]"; got != want {
+		t.Errorf("columnBands() = %s, want %s", got, want)
+	}
+	for x, want := range map[float64]int{80: 0, 150: 0, 260: 1, 330: 2, 400: 2, 10: 0} {
+		if got := bandOf(bands, x); got != want {
+			t.Errorf("bandOf(%g) = %d, want %d", x, got, want)
+		}
+	}
+}
+
+func TestFlattenTables(t *testing.T) {
+	want := "**Name:** Ana  \n**City:** Porto\n\n**Name:** Bruno  \n**City:** Lisbon"
+	borderless := row(700, 72.0, "Name", 200.0, "City") + row(682, 72.0, "Ana", 200.0, "Porto") + row(664, 72.0, "Bruno", 200.0, "Lisbon")
+	bordered := gridLines([]float64{66, 190, 300}, []float64{714, 696, 678, 660}) + borderless
+	for name, content := range map[string]string{"borderless": borderless, "bordered": bordered} {
+		p := &testPDF{}
+		p.page(content)
+		md := convert(t, &Converter{AssetsDir: t.TempDir(), FlattenTables: true}, p)
+		if !strings.Contains(md, want) || strings.Contains(md, "|") {
+			t.Errorf("%s: output = %q, want %q", name, md, want)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package pdf
+
+import "strings"
+
+// Checkbox glyphs that open a task list item
+var (
+	uncheckedBoxes = "☐❏❐❑❒"
+	checkedBoxes   = "☑☒✅"
+)
+
+// dingbatGlyphs maps the characters of symbol fonts that draw boxes and check
+// marks to the Unicode characters they show
+var dingbatGlyphs = map[string]map[rune]rune{
+	"zapfdingbats": {'3': '✓', '4': '✔', '7': '✗', '8': '✘', 'n': '■', 'o': '❏', 'p': '❐', 'q': '❑', 'r': '❒'},
+	"wingdings":    {'o': '☐', '¨': '☐', 'x': '☒', 'ý': '☒', 'þ': '☑', 'ü': '✓'},
+}
+
+// mapSymbolFont replaces the characters of a dingbat font with the symbols
+// they draw; text in other fonts is returned unchanged
+func mapSymbolFont(fontName, text string) string {
+	name := strings.ToLower(fontName)
+	for font, glyphs := range dingbatGlyphs {
+		if !strings.Contains(name, font) {
+			continue
+		}
+		return strings.Map(func(r rune) rune {
+			if mapped, ok := glyphs[r]; ok {
+				return mapped
+			}
+			return r
+		}, text)
+	}
+	return text
+}
@@ -0,0 +1,56 @@
+package pdf
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+)
+
+func TestCheckboxList(t *testing.T) {
+	p := &testPDF{}
+	widths := strings.TrimSpace(strings.Repeat("500 ", 256))
+	for i, font := range []string{"Wingdings-Regular", "ZapfDingbats"} {
+		id := p.add(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /Identity-H /FirstChar 0 /LastChar 255 /Widths [%s] >>", font, widths))
+		p.fonts += fmt.Sprintf(" /D%d %d 0 R", i+1, id)
+	}
+	item := func(y float64, box, font, text string) string {
+		return showText(72, y, box, font, 12) + showText(86, y, text, "F1", 12)
+	}
+	p.page(showText(72, 730, "Shopping:", "F1", 12) +
+		item(700, "x", "D1", "Buy milk") +
+		item(682, "o", "D1", "Buy bread") +
+		item(664, "x", "D1", "Skip cake") +
+		item(646, "o", "D2", "Call home"))
+
+	tests := []struct {
+		dialect markdown.Dialect
+		want    string
+	}{
+		{markdown.GFM, "- [x] Buy milk\n- [ ] Buy bread\n- [x] Skip cake\n- [ ] Call home\n"},
+		{markdown.CommonMark, "- ☑ Buy milk\n- ☐ Buy bread\n- ☑ Skip cake\n- ☐ Call home\n"},
+	}
+	for _, tt := range tests {
+		md := convert(t, &Converter{AssetsDir: t.TempDir(), Dialect: tt.dialect}, p)
+		if !strings.Contains(md, tt.want) {
+			t.Errorf("%s: output lacks %q:\n%s", tt.dialect, tt.want, md)
+		}
+	}
+}
+
+func TestMapSymbolFont(t *testing.T) {
+	tests := []struct {
+		font, text, want string
+	}{
+		{"ABCDEF+Wingdings-Regular", "þ", "☑"},
+		{"Wingdings", "o x", "☐ ☒"},
+		{"ZapfDingbats", "4q", "✔❑"},
+		{"Helvetica", "þo", "þo"},
+	}
+	for _, tt := range tests {
+		if got := mapSymbolFont(tt.font, tt.text); got != tt.want {
+			t.Errorf("mapSymbolFont(%q, %q) = %q, want %q", tt.font, tt.text, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package pdf
+
+import (
+	"math"
+	"unicode"
+	"unicode/utf8"
+)
+
+// attachChemicalSubscripts moves lowered, smaller digits that directly follow an
+// element symbol or closing parenthesis onto the baseline of that glyph, as in
+// H₂O or Ca(OH)₂, recording the drop in Rise. Digits elsewhere are untouched.
+func attachChemicalSubscripts(elements []TextElement) {
+	for i := range elements {
+		digit := &elements[i]
+		if r, n := utf8.DecodeRuneInString(digit.Text); n != len(digit.Text) || !unicode.IsDigit(r) || digit.Rise != 0 {
+			continue
+		}
+
+		for j, base := range elements {
+			if j == i || !isFormulaBase(base) {
+				continue
+			}
+			if digit.Size > base.Size*0.8 || digit.Y >= base.Y || base.Y-digit.Y > base.Size*0.5 {
+				continue
+			}
+			// The subscript starts where the preceding glyph ends
+			if math.Abs(base.X+base.Width-digit.X) > digit.Size*0.5 {
+				continue
+			}
+			digit.Rise = digit.Y - base.Y
+			digit.Y = base.Y
+			break
+		}
+	}
+
+	// Continue multi-digit subscripts such as C₁₂
+	for i := range elements {
+		digit := &elements[i]
+		if r, n := utf8.DecodeRuneInString(digit.Text); n != len(digit.Text) || !unicode.IsDigit(r) || digit.Rise != 0 {
+			continue
+		}
+		for _, prev := range elements {
+			if prev.Rise < 0 && prev.Y+prev.Rise == digit.Y && prev.Size == digit.Size &&
+				math.Abs(prev.X+prev.Width-digit.X) <= digit.Size*0.5 {
+				digit.Rise = prev.Rise
+				digit.Y = prev.Y
+				break
+			}
+		}
+	}
+}
+
+// isFormulaBase reports whether a glyph can carry a chemical subscript
+func isFormulaBase(e TextElement) bool {
+	r, n := utf8.DecodeRuneInString(e.Text)
+	return n == len(e.Text) && (unicode.IsLetter(r) || r == ')' || r == ']') && e.Rise == 0
+}
@@ -0,0 +1,47 @@
+package pdf
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+)
+
+// glyphs shows each character of s as a run of its own from x on, lowering
+// the characters marked by a _ in lowered
+func glyphs(x, y float64, s, lowered string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if i < len(lowered) && lowered[i] == '_' {
+			b.WriteString(showText(x, y-3, string(r), "F1", 8))
+			x += 4
+			continue
+		}
+		b.WriteString(showText(x, y, string(r), "F1", 12))
+		x += 6
+	}
+	return b.String()
+}
+
+func TestChemicalSubscripts(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect markdown.Dialect
+		formula string
+		lowered string
+		want    string
+	}{
+		{"water", markdown.GFM, "H2O", " _ ", "H<sub>2</sub>O"},
+		{"pandoc", markdown.Pandoc, "H2O", " _ ", "H~2~O"},
+		{"parenthesis and digits", markdown.GFM, "Ca(OH)2", "      _", "Ca(OH)<sub>2</sub>"},
+		{"multiple digits", markdown.GFM, "C12H22", " __ __", "C<sub>12</sub>H<sub>22</sub>"},
+		{"ordinary number", markdown.GFM, "Room 12", "", "Room 12"},
+	}
+	for _, tt := range tests {
+		p := &testPDF{}
+		p.page(glyphs(72, 700, tt.formula, tt.lowered))
+		if got := strings.TrimSpace(convert(t, &Converter{Dialect: tt.dialect}, p)); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package pdf
+
+import (
+	"strconv"
+	"strings"
+)
+
+// monospaceAdvance is the advance of a monospaced glyph relative to the font
+// size, used when a listing has no glyph to measure
+const monospaceAdvance = 0.6
+
+// listingLine is a line of a code listing with its printed line number, -1
+// for none
+type listingLine struct {
+	Number   int
+	Gutter   float64
+	Elements []TextElement
+}
+
+// codeListings finds the runs of consecutive lines set in a monospaced font
+// and returns the index past the last line of each run, by its first line.
+// Lines holding only a line number continue a run, as blank lines of code.
+func codeListings(lines []TextLine) map[int]int {
+	listings := make(map[int]int)
+	for i := 0; i < len(lines); {
+		end := i
+		for end < len(lines) && (isCodeLine(lines[end]) || end > i && isNumberLine(lines[end])) {
+			end++
+		}
+		if end-i >= 2 {
+			listings[i] = end
+		}
+		i = max(end, i+1)
+	}
+	return listings
+}
+
+// isCodeLine reports whether the text of a line is set in monospaced fonts,
+// apart from the digits of a line number opening it
+func isCodeLine(line TextLine) bool {
+	if line.Image != "" || line.Block != "" {
+		return false
+	}
+	code, gutter := false, true
+	for _, element := range line.Elements {
+		if element.Image || strings.TrimSpace(element.Text) == "" {
+			continue
+		}
+		if gutter && isDigits(element.Text) {
+			continue
+		}
+		gutter = false
+		if !isMonospaceFont(element.Font) {
+			return false
+		}
+		code = true
+	}
+	return code
+}
+
+// isNumberLine reports whether a line holds nothing but a number
+func isNumberLine(line TextLine) bool {
+	number := false
+	for _, element := range line.Elements {
+		if element.Image || strings.TrimSpace(element.Text) == "" {
+			continue
+		}
+		if !isDigits(element.Text) {
+			return false
+		}
+		number = true
+	}
+	return number
+}
+
+// renderListing renders a code listing as fenced code. When every line opens
+// with the next number of a column printed left of the code, that gutter is
+// left out.
+func renderListing(lines []TextLine) string {
+	listing := make([]listingLine, len(lines))
+	for i, line := range lines {
+		listing[i] = splitGutter(line.Elements)
+	}
+	if !hasGutter(listing) {
+		for i, line := range lines {
+			listing[i] = listingLine{Number: -1, Elements: line.Elements}
+		}
+	}
+	return fencedCode(listing)
+}
+
+// fencedCode renders the lines of a listing as fenced code, placing each
+// glyph at its column
+func fencedCode(listing []listingLine) string {
+	// Columns are counted from the leftmost glyph of the listing
+	left, advance, size := 0.0, 0.0, 0.0
+	for _, line := range listing {
+		for _, element := range line.Elements {
+			if element.Image || strings.TrimSpace(element.Text) == "" {
+				continue
+			}
+			if size == 0 || element.X < left {
+				left = element.X
+			}
+			if advance == 0 && element.Width > 0 && len([]rune(element.Text)) == 1 {
+				advance = element.Width
+			}
+			size = max(size, element.Size)
+		}
+	}
+	if advance == 0 {
+		advance = max(size, 1) * monospaceAdvance
+	}
+
+	var body []string
+	for _, line := range listing {
+		body = append(body, codeText(line.Elements, left, advance))
+	}
+	return fenceCode(strings.Join(body, "\n"))
+}
+
+// fenceCode fences code with a fence longer than any run of backticks in it
+func fenceCode(code string) string {
+	fence := "```"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	return fence + "\n" + code + "\n" + fence
+}
+
+// splitGutter separates the line number opening a line of a listing from its
+// code, returning the line with Number -1 when it opens with none
+func splitGutter(elements []TextElement) listingLine {
+	var digits strings.Builder
+	gutter := 0.0
+	i := 0
+	for ; i < len(elements); i++ {
+		element := elements[i]
+		if element.Image {
+			break
+		}
+		if strings.TrimSpace(element.Text) == "" {
+			if digits.Len() > 0 {
+				break
+			}
+			continue
+		}
+		if !isDigits(element.Text) {
+			break
+		}
+		// Digits run together only when set next to each other
+		if digits.Len() > 0 && element.X-gutter > element.Size*wordGapScale {
+			break
+		}
+		digits.WriteString(element.Text)
+		gutter = element.X + element.Width
+	}
+	n, err := strconv.Atoi(digits.String())
+	if err != nil {
+		return listingLine{Number: -1, Elements: elements}
+	}
+	return listingLine{Number: n, Gutter: gutter, Elements: elements[i:]}
+}
+
+// hasGutter reports whether the lines of a listing are numbered one after
+// another in a column that ends left of their code
+func hasGutter(listing []listingLine) bool {
+	gutter := 0.0
+	for i, line := range listing {
+		if line.Number < 0 || i > 0 && line.Number != listing[i-1].Number+1 {
+			return false
+		}
+		gutter = max(gutter, line.Gutter)
+	}
+	for _, line := range listing {
+		for _, element := range line.Elements {
+			if !element.Image && strings.TrimSpace(element.Text) != "" {
+				if element.X < gutter {
+					return false
+				}
+				break
+			}
+		}
+	}
+	return true
+}
+
+// codeText returns the text of a line of code, each glyph placed at the
+// column of its position so indentation and alignment survive
+func codeText(elements []TextElement, left, advance float64) string {
+	var b strings.Builder
+	column := 0
+	for _, element := range elements {
+		if element.Image || strings.TrimSpace(element.Text) == "" {
+			continue
+		}
+		for target := int((element.X-left)/advance + 0.5); column < target; column++ {
+			b.WriteByte(' ')
+		}
+		b.WriteString(element.Text)
+		column += len([]rune(element.Text))
+	}
+	return b.String()
+}
+
+// isDigits reports whether text is made of ASCII digits only
+func isDigits(text string) bool {
+	if text == "" {
+		return false
+	}
+	for _, r := range text {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,75 @@
+package pdf
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// listing shows lines of code in Courier 14 points apart, each opened by its
+// gutter number from first when first is not negative. Glyphs are 6 points
+// wide at 12 points, so the code starts 4 columns right of the numbers.
+func listing(first int, lines ...string) string {
+	var b strings.Builder
+	for i, line := range lines {
+		y := 700 - float64(i)*14
+		if first >= 0 {
+			b.WriteString(showText(72, y, fmt.Sprint(first+i), "F4", 12))
+		}
+		if line != "" {
+			b.WriteString(showText(96, y, line, "F4", 12))
+		}
+	}
+	return b.String()
+}
+
+func TestStripCodeLineNumbers(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"numbered", listing(1, "func main() {", "  run()", "}"), "```\nfunc main() {\n  run()\n}\n```"},
+		{"numbered from ten", listing(10, "a(1)", "b(2)"), "```\na(1)\nb(2)\n```"},
+		{"blank numbered line", listing(1, "if ok {", "", "}"), "```\nif ok {\n\n}\n```"},
+		{"numbers out of sequence kept", listing(1, "x", "y") + showText(72, 672, "7", "F4", 12) + showText(96, 672, "z", "F4", 12), "```\n1   x\n2   y\n7   z\n```"},
+		{"unnumbered", listing(-1, "a(1)", "  b(2)"), "```\na(1)\n  b(2)\n```"},
+		{"numbers only are not code", showText(72, 700, "10 20 30", "F4", 12) + showText(72, 686, "40 50 60", "F4", 12), "10 20 30\n\n40 50 60"},
+		{"proportional text", showText(72, 700, "1 First", "F1", 12) + showText(72, 686, "2 Second", "F1", 12), "1 First\n\n2 Second"},
+	}
+	for _, tt := range tests {
+		p := &testPDF{}
+		p.page(tt.content)
+		got := strings.TrimSpace(convert(t, &Converter{AssetsDir: t.TempDir(), StripCodeLineNumbers: true}, p))
+		if got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSplitGutter(t *testing.T) {
+	element := func(text string, x float64) TextElement {
+		return TextElement{Text: text, X: x, Width: 6 * float64(len(text)), Size: 12, Font: "Courier"}
+	}
+	tests := []struct {
+		name       string
+		elements   []TextElement
+		wantNumber int
+		wantCode   string
+	}{
+		{"digits together", []TextElement{element("1", 72), element("2", 78), element("x", 96)}, 12, "x"},
+		{"digits apart", []TextElement{element("1", 72), element("2", 96)}, 1, "2"},
+		{"no number", []TextElement{element("x", 72)}, -1, "x"},
+		{"space before the number", []TextElement{element(" ", 66), element("3", 72), element("y", 96)}, 3, "y"},
+	}
+	for _, tt := range tests {
+		got := splitGutter(tt.elements)
+		var code strings.Builder
+		for _, e := range got.Elements {
+			code.WriteString(e.Text)
+		}
+		if got.Number != tt.wantNumber || code.String() != tt.wantCode {
+			t.Errorf("%s: splitGutter() = %d, %q, want %d, %q", tt.name, got.Number, code.String(), tt.wantNumber, tt.wantCode)
+		}
+	}
+}
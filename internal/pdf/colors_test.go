@@ -0,0 +1,39 @@
+package pdf
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPreserveColors(t *testing.T) {
+	phrase := func(color string) string {
+		return showText(72, 700, "Keep this", "F1", 12) +
+			color + " " + showText(132, 700, "red phrase", "F1", 12) +
+			"0 g " + showText(198, 700, "here.", "F1", 12)
+	}
+	spaces := "/ColorSpace << /Profile [/ICCBased << /N 3 >>] /Palette [/Indexed /DeviceRGB 1 <000000ff0000>] /Spot [/Separation /Gold /DeviceCMYK << >>] >>"
+	tests := []struct {
+		name     string
+		content  string
+		preserve bool
+		want     string
+	}{
+		{"rgb", phrase("1 0 0 rg"), true, `Keep this <span style="color:#ff0000">red phrase</span> here.`},
+		{"cmyk", phrase("0 1 1 0 k"), true, `Keep this <span style="color:#ff0000">red phrase</span> here.`},
+		{"gray", phrase("0.5 g"), true, `Keep this <span style="color:#808080">red phrase</span> here.`},
+		{"black", phrase("0 0 0 rg"), true, "Keep this red phrase here."},
+		{"device space", phrase("/DeviceRGB cs 1 0 0 sc"), true, `Keep this <span style="color:#ff0000">red phrase</span> here.`},
+		{"ICC-based space", phrase("/Profile cs 1 0 0 scn"), true, `Keep this <span style="color:#ff0000">red phrase</span> here.`},
+		{"indexed space", phrase("/Palette cs 1 sc"), true, "Keep this red phrase here."},
+		{"separation space", phrase("/Spot cs 0.5 scn"), true, "Keep this red phrase here."},
+		{"off", phrase("1 0 0 rg"), false, "Keep this red phrase here."},
+	}
+	for _, tt := range tests {
+		p := &testPDF{}
+		p.pageWith(tt.content, "", spaces)
+		md := convert(t, &Converter{AssetsDir: t.TempDir(), PreserveColors: tt.preserve}, p)
+		if got := strings.TrimSpace(md); got != tt.want {
+			t.Errorf("%s: output = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package pdf
+
+import (
+	"math"
+	"sort"
+)
+
+const (
+	// minGutterScale is the narrowest gutter between two columns of text, in
+	// multiples of the body font size; word gaps are much narrower
+	minGutterScale = 1.5
+	// minColumnLines is the number of lines each column must hold
+	minColumnLines = 3
+	// columnFill is how much of its column the median line of each column
+	// spans. Prose fills its column, the cells of a borderless table do not.
+	columnFill = 0.6
+	// maxSpanningShare is the share of lines that may cross the gutter, such
+	// as a title set across both columns
+	maxSpanningShare = 0.25
+	// columnBalance is how wide the narrower column is at least, relative to
+	// the wider one. A label column beside its values is much narrower.
+	columnBalance = 2.0 / 3
+)
+
+// findGutter finds the vertical strip separating the two columns of a page
+// of text and returns its middle. The strip is the widest one in the middle
+// half of the text block that no glyph of all but a few spanning lines
+// covers, and each side of it must hold enough lines filling columns of
+// about the same width.
+func findGutter(lines []TextLine) (float64, bool) {
+	var elements []TextElement
+	minX, maxX := math.Inf(1), math.Inf(-1)
+	for _, line := range lines {
+		if len(line.Elements) == 0 {
+			continue
+		}
+		left, right := lineBounds(line)
+		minX, maxX = math.Min(minX, left), math.Max(maxX, right)
+		elements = append(elements, line.Elements...)
+	}
+	if len(elements) == 0 || maxX-minX < 1 {
+		return 0, false
+	}
+
+	// Count the lines with a glyph over each point of the text block
+	bins := int(math.Ceil(maxX - minX))
+	covered := make([]int, bins+1)
+	textLines := 0
+	for _, line := range lines {
+		if len(line.Elements) == 0 {
+			continue
+		}
+		textLines++
+		seen := make([]bool, bins+1)
+		for _, e := range line.Elements {
+			for x := int(e.X - minX); x <= int(math.Ceil(e.X+e.Width-minX)) && x <= bins; x++ {
+				if x >= 0 && !seen[x] {
+					seen[x] = true
+					covered[x]++
+				}
+			}
+		}
+	}
+
+	// Take the widest run of points in the middle half few lines cover
+	allowed := int(float64(textLines) * maxSpanningShare)
+	start, best, bestStart := -1, 0, 0
+	lo, hi := bins/4, bins-bins/4
+	for x := lo; x <= hi+1; x++ {
+		if x <= hi && covered[x] <= allowed {
+			if start < 0 {
+				start = x
+			}
+			continue
+		}
+		if start >= 0 && x-start > best {
+			best, bestStart = x-start, start
+		}
+		start = -1
+	}
+	if float64(best) < bodyFontSize(elements)*minGutterScale {
+		return 0, false
+	}
+	a, b := minX+float64(bestStart), minX+float64(bestStart+best)
+	gutter := (a + b) / 2
+
+	// Both sides must read as columns of prose
+	var lefts, rights []float64
+	for _, line := range splitColumns(lines, gutter) {
+		if len(line.Elements) == 0 {
+			continue
+		}
+		left, right := lineBounds(line)
+		switch lineColumn(line, gutter) {
+		case 1:
+			lefts = append(lefts, right-left)
+		case 2:
+			rights = append(rights, right-left)
+		}
+	}
+	if len(lefts) < minColumnLines || len(rights) < minColumnLines {
+		return 0, false
+	}
+	leftWidth, rightWidth := a-minX, maxX-b
+	if median(lefts) < leftWidth*columnFill || median(rights) < rightWidth*columnFill {
+		return 0, false
+	}
+	if math.Min(leftWidth, rightWidth) < math.Max(leftWidth, rightWidth)*columnBalance {
+		return 0, false
+	}
+	return gutter, true
+}
+
+// median returns the middle of a list of widths
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	return sorted[len(sorted)/2]
+}
+
+// splitColumns splits the lines set on both sides of a gutter into a line
+// for each column. Lines with a glyph over the gutter span both columns and
+// are kept whole.
+func splitColumns(lines []TextLine, gutter float64) []TextLine {
+	var split []TextLine
+	for _, line := range lines {
+		var left, right []TextElement
+		spanning := false
+		for _, e := range line.Elements {
+			switch {
+			case e.X+e.Width <= gutter:
+				left = append(left, e)
+			case e.X >= gutter:
+				right = append(right, e)
+			default:
+				spanning = true
+			}
+		}
+		if spanning || len(left) == 0 || len(right) == 0 {
+			split = append(split, line)
+			continue
+		}
+		for _, elements := range [][]TextElement{left, right} {
+			part := line
+			part.Elements = elements
+			part.FontSize = elements[0].Size
+			part.IsBold = isBoldFont(elements[0].Font)
+			part.MCID = firstMCID(elements)
+			split = append(split, part)
+		}
+	}
+	return split
+}
+
+// lineColumn returns the column a line lies in: 1 left of the gutter, 2
+// right of it and 0 when it spans both
+func lineColumn(line TextLine, gutter float64) int {
+	left, right := line.MinX, line.MaxX
+	if len(line.Elements) > 0 {
+		left, right = lineBounds(line)
+	}
+	switch {
+	case right <= gutter:
+		return 1
+	case left >= gutter:
+		return 2
+	}
+	return 0
+}
+
+// orderByColumns reorders lines sorted from the top of the page down so
+// two-column text reads column by column. Lines spanning both columns, such
+// as titles, keep their place and the columns between them are read left
+// column first.
+func orderByColumns(lines []TextLine, gutter float64) {
+	start := 0
+	flush := func(end int) {
+		section := append([]TextLine(nil), lines[start:end]...)
+		sort.SliceStable(section, func(i, j int) bool {
+			return lineColumn(section[i], gutter) < lineColumn(section[j], gutter)
+		})
+		copy(lines[start:end], section)
+	}
+	for i, line := range lines {
+		if lineColumn(line, gutter) == 0 {
+			flush(i)
+			start = i + 1
+		}
+	}
+	flush(len(lines))
+}
@@ -0,0 +1,100 @@
+package pdf
+
+import (
+	"strings"
+	"testing"
+)
+
+// columnsPDF sets the left and right lines side by side, 14 points apart,
+// under a title spanning the page
+func columnsPDF(title string, left, right []string, rightX float64) *testPDF {
+	var content strings.Builder
+	if title != "" {
+		content.WriteString(showText(72, 740, title, "F2", 18))
+	}
+	for i := range max(len(left), len(right)) {
+		y := 700 - float64(i)*14
+		if i < len(left) {
+			content.WriteString(showText(72, y, left[i], "F1", 10))
+		}
+		if i < len(right) {
+			content.WriteString(showText(rightX, y, right[i], "F1", 10))
+		}
+	}
+	p := &testPDF{}
+	p.page(content.String())
+	return p
+}
+
+func TestDetectColumns(t *testing.T) {
+	left := []string{"The left column opens the", "article and runs down the", "page for several lines."}
+	right := []string{"The right column follows", "the left one and is read", "after it, not beside it."}
+	tests := []struct {
+		name   string
+		p      *testPDF
+		detect bool
+		want   []string
+	}{
+		{
+			name:   "two columns read column by column",
+			p:      columnsPDF("Article", left, right, 320),
+			detect: true,
+			want:   append(append([]string{"Article"}, left...), right...),
+		},
+		{
+			name: "two columns without detection",
+			p:    columnsPDF("Article", left, right, 320),
+			want: []string{"Article", left[0], right[0], left[1], right[1]},
+		},
+		{
+			name:   "label column beside values",
+			p:      columnsPDF("", []string{"Invoice number:", "Date", "Customer"}, []string{"INV-2041", "14 May 2026", "Acme Corp"}, 300),
+			detect: true,
+			want:   []string{"Invoice number:", "INV-2041", "Date", "14 May 2026"},
+		},
+		{
+			name:   "single column",
+			p:      columnsPDF("", []string{"Only one column of text here,", "with lines that fill the page", "from its left margin onward."}, nil, 0),
+			detect: true,
+			want:   []string{"Only one column", "with lines", "from its left"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := convert(t, &Converter{DetectColumns: tt.detect}, tt.p)
+			last := -1
+			for _, text := range tt.want {
+				i := strings.Index(got, text)
+				if i < last {
+					t.Fatalf("%q out of order in:\n%s", text, got)
+				}
+				last = i
+			}
+		})
+	}
+}
+
+func TestFindGutter(t *testing.T) {
+	line := func(y float64, runs ...[2]float64) TextLine {
+		var elements []TextElement
+		for _, run := range runs {
+			elements = append(elements, TextElement{Text: "x", X: run[0], Width: run[1] - run[0], Y: y, Size: 10})
+		}
+		return TextLine{Elements: elements, Y: y, FontSize: 10}
+	}
+	columns := []TextLine{
+		line(700, [2]float64{72, 290}, [2]float64{320, 540}),
+		line(686, [2]float64{72, 288}, [2]float64{320, 538}),
+		line(672, [2]float64{72, 291}, [2]float64{320, 536}),
+	}
+	gutter, ok := findGutter(columns)
+	if !ok || gutter < 291 || gutter > 320 {
+		t.Errorf("findGutter(two columns) = %v, %v; want a gutter between 291 and 320", gutter, ok)
+	}
+	if _, ok := findGutter(append(columns, line(720, [2]float64{72, 540}), line(650, [2]float64{72, 540}))); ok {
+		t.Error("findGutter found a gutter crossed by two of five lines")
+	}
+	if _, ok := findGutter(columns[:2]); ok {
+		t.Error("findGutter found columns of two lines")
+	}
+}
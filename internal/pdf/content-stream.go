@@ -0,0 +1,458 @@
+package pdf
+
+import (
+	"math"
+
+	"github.com/rsc/pdf"
+)
+
+// noMCID marks a text element that is not part of a marked-content sequence
+const noMCID = -1
+
+// matrix is a PDF transformation matrix in row-major 3x3 form
+type matrix [3][3]float64
+
+var identity = matrix{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+
+func (x matrix) mul(y matrix) matrix {
+	var z matrix
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			for k := 0; k < 3; k++ {
+				z[i][j] += x[i][k] * y[k][j]
+			}
+		}
+	}
+	return z
+}
+
+// graphicsState is the subset of the PDF graphics and text state needed to position glyphs
+type graphicsState struct {
+	Tc    float64
+	Tw    float64
+	Th    float64
+	Tl    float64
+	Tfs   float64
+	Trise float64
+	Tm    matrix
+	Tlm   matrix
+	CTM   matrix
+	font  *fontInfo
+	// LineWidth is the stroke width in user space
+	LineWidth float64
+	// Fill is the nonstroking color as #rrggbb, empty for black
+	Fill string
+	// FillComponents is the number of components of the nonstroking color
+	// space, as colorComponents counts them
+	FillComponents int
+	// FillAlpha is the nonstroking opacity set by the ca entry of a graphics state
+	FillAlpha float64
+}
+
+// markedContent is an open BMC/BDC marked-content sequence
+type markedContent struct {
+	Tag  string
+	MCID int
+	// Hidden is set for optional content in a layer that is switched off
+	Hidden bool
+	// Watermark is set for an artifact marked as a watermark
+	Watermark bool
+}
+
+// pageContent is the text and imagery drawn on a page
+type pageContent struct {
+	Elements []TextElement
+	Images   []pageImage
+	// Rules are the thin horizontal lines painted on the page
+	Rules []rule
+	// Borders are the horizontal and vertical lines that may outline table cells
+	Borders []border
+	// Boxes are the shaded rectangles painted behind content, such as callouts
+	Boxes []rect
+	// Dots are the small filled circles painted on the page, such as vector bullets
+	Dots []rect
+}
+
+// readPageContent walks the page content stream and returns one element per drawn glyph.
+// It mirrors pdf.Page.Content but also records the marked-content sequence each glyph
+// belongs to and the placement of image XObjects. Content in hidden layers is skipped.
+func (c *Converter) readPageContent(doc *document, page pdf.Page) pageContent {
+	layers := doc.layers
+	var elements []TextElement
+	var images []pageImage
+	var rules []rule
+
+	g := graphicsState{Th: 1, Tm: identity, Tlm: identity, CTM: identity, LineWidth: 1, FillComponents: 1, FillAlpha: 1}
+
+	// The current path is kept as the rectangles and straight segments it is made of
+	var path []pathSegment
+	var borders []border
+	var boxes []rect
+	var dots []rect
+	// current is the current point and start the first point of its subpath
+	var current, start [2]float64
+	var gstack []graphicsState
+	var marked []markedContent
+	pageFonts := make(map[string]*fontInfo)
+
+	currentMCID := func() int {
+		for i := len(marked) - 1; i >= 0; i-- {
+			if marked[i].MCID != noMCID {
+				return marked[i].MCID
+			}
+		}
+		return noMCID
+	}
+
+	hidden := func() bool {
+		for _, mc := range marked {
+			if mc.Hidden || mc.Watermark && c.StripWatermarks {
+				return true
+			}
+		}
+		return false
+	}
+
+	showText := func(s string) {
+		if g.font == nil || hidden() {
+			return
+		}
+		n := 0
+		for _, ch := range g.font.enc.Decode(s) {
+			trm := matrix{{g.Tfs * g.Th, 0, 0}, {0, g.Tfs, 0}, {0, g.Trise, 1}}.mul(g.Tm).mul(g.CTM)
+			var w0 float64
+			if n < len(s) {
+				w0 = g.font.width(int(s[n]))
+			}
+			n++
+			if g.font.vertical {
+				// Vertical glyphs are set a full em apart, top to bottom
+				if ch != ' ' {
+					elements = append(elements, TextElement{
+						Text:     string(ch),
+						Font:     g.font.Name,
+						Size:     trm[0][0],
+						X:        trm[2][0],
+						Y:        trm[2][1],
+						Width:    trm[0][0],
+						MCID:     currentMCID(),
+						Color:    g.Fill,
+						Vertical: true,
+					})
+				}
+				ty := -(g.Tfs + g.Tc)
+				g.Tm = matrix{{1, 0, 0}, {0, 1, 0}, {0, ty, 1}}.mul(g.Tm)
+				continue
+			}
+			if ch != ' ' {
+				size := trm[0][0]
+				slanted := isSlanted(trm)
+				if slanted {
+					size = math.Hypot(trm[0][0], trm[0][1])
+				}
+				elements = append(elements, TextElement{
+					Text:      string(ch),
+					Font:      g.font.Name,
+					Size:      size,
+					X:         trm[2][0],
+					Y:         trm[2][1],
+					Width:     w0 / 1000 * size,
+					MCID:      currentMCID(),
+					Color:     g.Fill,
+					Watermark: slanted || g.FillAlpha < watermarkAlpha,
+				})
+			}
+			tx := w0/1000*g.Tfs + g.Tc
+			if ch == ' ' {
+				tx += g.Tw
+			}
+			tx *= g.Th
+			g.Tm = matrix{{1, 0, 0}, {0, 1, 0}, {tx, 0, 1}}.mul(g.Tm)
+		}
+	}
+
+	nextLine := func() {
+		g.Tlm = matrix{{1, 0, 0}, {0, 1, 0}, {0, -g.Tl, 1}}.mul(g.Tlm)
+		g.Tm = g.Tlm
+	}
+
+	interpret := func(stk *pdf.Stack, op string) {
+		n := stk.Len()
+		args := make([]pdf.Value, n)
+		for i := n - 1; i >= 0; i-- {
+			args[i] = stk.Pop()
+		}
+
+		switch op {
+		case "w": // set line width
+			if len(args) == 1 {
+				g.LineWidth = args[0].Float64()
+			}
+		case "g", "rg", "k": // set fill color space and color
+			g.FillComponents = map[string]int{"g": 1, "rg": 3, "k": 4}[op]
+			g.Fill = fillColor(args, g.FillComponents)
+		case "sc", "scn": // set fill color in the current color space
+			g.Fill = fillColor(args, g.FillComponents)
+		case "gs": // set parameters from a graphics state dictionary
+			if len(args) == 1 {
+				if ca := page.Resources().Key("ExtGState").Key(args[0].Name()).Key("ca"); ca.Kind() == pdf.Integer || ca.Kind() == pdf.Real {
+					g.FillAlpha = ca.Float64()
+				}
+			}
+		case "cs": // set fill color space, which resets the color to its initial black
+			g.Fill = ""
+			if len(args) == 1 {
+				g.FillComponents = colorComponents(page.Resources(), args[0].Name())
+			}
+		case "m": // begin subpath
+			if len(args) == 2 {
+				current = [2]float64{args[0].Float64(), args[1].Float64()}
+				start = current
+			}
+		case "l": // append straight line segment
+			if len(args) == 2 {
+				next := [2]float64{args[0].Float64(), args[1].Float64()}
+				path = append(path, pathSegment{From: current, To: next})
+				current = next
+			}
+		case "re": // append rectangle
+			if len(args) == 4 {
+				x, y, w, h := args[0].Float64(), args[1].Float64(), args[2].Float64(), args[3].Float64()
+				path = append(path, pathSegment{From: [2]float64{x, y}, To: [2]float64{x + w, y + h}, Rect: true})
+				current = [2]float64{x, y}
+				start = current
+			}
+		case "h": // close subpath with a line back to its start
+			if current != start {
+				path = append(path, pathSegment{From: current, To: start})
+				current = start
+			}
+		case "c", "v", "y": // curves make the path unusable as a rule, but may outline a dot
+			if n := len(args); n >= 4 {
+				next := [2]float64{args[n-2].Float64(), args[n-1].Float64()}
+				path = append(path, pathSegment{From: current, To: next, Curve: true})
+				current = next
+			}
+		case "S", "s", "f", "F", "f*", "B", "B*", "b", "b*": // paint path
+			if !hidden() {
+				rules = append(rules, g.pathRules(path, op == "S" || op == "s")...)
+				borders = append(borders, g.pathBorders(path, op != "f" && op != "F" && op != "f*")...)
+				if op != "S" && op != "s" {
+					boxes = append(boxes, g.pathBoxes(path)...)
+					if dot, ok := g.pathDot(path); ok {
+						dots = append(dots, dot)
+					}
+				}
+			}
+			path = nil
+		case "n": // end path without painting
+			path = nil
+		case "cm": // concatenate matrix to current transformation matrix
+			if len(args) == 6 {
+				g.CTM = toMatrix(args).mul(g.CTM)
+			}
+		case "q": // save graphics state
+			gstack = append(gstack, g)
+		case "Q": // restore graphics state
+			if len(gstack) > 0 {
+				g = gstack[len(gstack)-1]
+				gstack = gstack[:len(gstack)-1]
+			}
+		case "BT": // begin text object
+			g.Tm = identity
+			g.Tlm = identity
+		case "Tc": // set character spacing
+			if len(args) == 1 {
+				g.Tc = args[0].Float64()
+			}
+		case "Tw": // set word spacing
+			if len(args) == 1 {
+				g.Tw = args[0].Float64()
+			}
+		case "Tz": // set horizontal scaling
+			if len(args) == 1 {
+				g.Th = args[0].Float64() / 100
+			}
+		case "TL": // set leading
+			if len(args) == 1 {
+				g.Tl = args[0].Float64()
+			}
+		case "Ts": // set text rise
+			if len(args) == 1 {
+				g.Trise = args[0].Float64()
+			}
+		case "Tf": // set font and size
+			if len(args) == 2 {
+				name := args[0].Name()
+				if pageFonts[name] == nil {
+					pageFonts[name] = doc.fonts.lookup(page, name)
+				}
+				g.font = pageFonts[name]
+				g.Tfs = args[1].Float64()
+			}
+		case "TD", "Td": // move text position
+			if len(args) == 2 {
+				if op == "TD" {
+					g.Tl = -args[1].Float64()
+				}
+				g.Tlm = matrix{{1, 0, 0}, {0, 1, 0}, {args[0].Float64(), args[1].Float64(), 1}}.mul(g.Tlm)
+				g.Tm = g.Tlm
+			}
+		case "Tm": // set text matrix
+			if len(args) == 6 {
+				g.Tm = toMatrix(args)
+				g.Tlm = g.Tm
+			}
+		case "T*": // move to start of next line
+			nextLine()
+		case "Tj": // show text
+			if len(args) == 1 {
+				showText(args[0].RawString())
+			}
+		case "'": // move to next line and show text
+			if len(args) == 1 {
+				nextLine()
+				showText(args[0].RawString())
+			}
+		case "\"": // set spacing, move to next line and show text
+			if len(args) == 3 {
+				g.Tw = args[0].Float64()
+				g.Tc = args[1].Float64()
+				nextLine()
+				showText(args[2].RawString())
+			}
+		case "TJ": // show text with individual glyph positioning
+			if len(args) == 1 {
+				for i := 0; i < args[0].Len(); i++ {
+					x := args[0].Index(i)
+					if x.Kind() == pdf.String {
+						showText(x.RawString())
+					} else {
+						tx := -x.Float64() / 1000 * g.Tfs * g.Th
+						g.Tm = matrix{{1, 0, 0}, {0, 1, 0}, {tx, 0, 1}}.mul(g.Tm)
+					}
+				}
+			}
+		case "Do": // paint external object
+			if len(args) == 1 {
+				xobj := page.Resources().Key("XObject").Key(args[0].Name())
+				if xobj.Key("Subtype").Name() == "Image" && !hidden() && layers.isVisible(xobj.Key("OC")) {
+					// Images occupy the unit square mapped through the CTM
+					images = append(images, pageImage{
+						Stream: xobj,
+						X:      g.CTM[2][0],
+						Y:      g.CTM[2][1],
+						Width:  g.CTM[0][0],
+						Height: g.CTM[1][1],
+					})
+				}
+			}
+		case "BMC": // begin marked content
+			marked = append(marked, markedContent{Tag: tagName(args), MCID: noMCID})
+		case "BDC": // begin marked content with property list
+			props := markedContentProps(page, args)
+			mc := markedContent{Tag: tagName(args), MCID: markedContentID(props)}
+			if mc.Tag == "OC" {
+				mc.Hidden = !layers.isVisible(props)
+			}
+			if mc.Tag == "Artifact" && props.Key("Subtype").Name() == "Watermark" {
+				mc.Watermark = true
+			}
+			marked = append(marked, mc)
+		case "EMC": // end marked content
+			if len(marked) > 0 {
+				marked = marked[:len(marked)-1]
+			}
+		}
+	}
+
+	// Contents may be a single stream or an array of streams forming one program
+	contents := page.V.Key("Contents")
+	if contents.Kind() == pdf.Array {
+		for i := 0; i < contents.Len(); i++ {
+			pdf.Interpret(contents.Index(i), interpret)
+		}
+	} else {
+		pdf.Interpret(contents, interpret)
+	}
+
+	return pageContent{Elements: elements, Images: images, Rules: rules, Borders: borders, Boxes: boxes, Dots: dots}
+}
+
+// fillColor converts the operands of a fill color operator in a color space of
+// components components into a hex color, or an empty string for black and for
+// colors it cannot represent, such as those of Indexed or Separation spaces
+func fillColor(args []pdf.Value, components int) string {
+	if len(args) != components {
+		return ""
+	}
+	if color := deviceColor(args); color != "#000000" {
+		return color
+	}
+	return ""
+}
+
+// colorComponents returns the number of components of the color space a cs
+// operator selects by name, 1, 3 or 4 for spaces whose colors read as gray,
+// RGB or CMYK, and 0 for the others, such as Indexed, Separation and Pattern
+func colorComponents(resources pdf.Value, name string) int {
+	space := resources.Key("ColorSpace").Key(name)
+	switch space.Kind() {
+	case pdf.Name:
+		name = space.Name()
+	case pdf.Array:
+		name = space.Index(0).Name()
+	}
+	switch name {
+	case "DeviceGray", "CalGray", "G":
+		return 1
+	case "DeviceRGB", "CalRGB", "RGB":
+		return 3
+	case "DeviceCMYK", "CMYK":
+		return 4
+	case "ICCBased":
+		if n := int(space.Index(1).Key("N").Int64()); n == 1 || n == 3 || n == 4 {
+			return n
+		}
+	}
+	return 0
+}
+
+func toMatrix(args []pdf.Value) matrix {
+	var m matrix
+	for i := 0; i < 6; i++ {
+		m[i/2][i%2] = args[i].Float64()
+	}
+	m[2][2] = 1
+	return m
+}
+
+func tagName(args []pdf.Value) string {
+	if len(args) == 0 {
+		return ""
+	}
+	return args[0].Name()
+}
+
+// markedContentProps returns the property list of a BDC operator, which may be
+// inline or a named entry in the page's Properties resources
+func markedContentProps(page pdf.Page, args []pdf.Value) pdf.Value {
+	if len(args) < 2 {
+		return pdf.Value{}
+	}
+
+	props := args[1]
+	if props.Kind() == pdf.Name {
+		props = page.Resources().Key("Properties").Key(props.Name())
+	}
+	return props
+}
+
+// markedContentID returns the MCID of a BDC property list
+func markedContentID(props pdf.Value) int {
+	mcid := props.Key("MCID")
+	if mcid.Kind() != pdf.Integer {
+		return noMCID
+	}
+	return int(mcid.Int64())
+}
@@ -0,0 +1,99 @@
+package pdf
+
+import (
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/rsc/pdf"
+)
+
+const (
+	// coverTitleSize is the smallest font size of a cover page title
+	coverTitleSize = 18.0
+	// coverCenterTolerance is how far from the page center, relative to the page
+	// width, the middle of a centered line may be
+	coverCenterTolerance = 0.05
+	// coverMaxAuthorWords keeps sentences from being taken for an author line
+	coverMaxAuthorWords = 8
+)
+
+// coverDate matches the date line of a title block, such as "May 2026" or "2026-05-14"
+var coverDate = regexp.MustCompile(`(?i)^(?:\d{1,2}\s*)?(?:jan|feb|mar|apr|may|jun|jul|aug|sep|oct|nov|dec)[a-z]*\.?\s*(?:\d{1,2},?\s*)?\d{4}$|^\d{4}-\d{2}-\d{2}$|^\d{1,2}[./]\d{1,2}[./]\d{2,4}$`)
+
+// coverBlock is the title block of a document's first page
+type coverBlock struct {
+	Title    string
+	Subtitle string
+	Author   string
+	Date     string
+}
+
+// pageWidth returns the left edge and width of a page's media box, which may
+// be inherited from the page tree
+func pageWidth(page pdf.Page) (float64, float64) {
+	for v := page.V; v.Kind() == pdf.Dict; v = v.Key("Parent") {
+		if box := v.Key("MediaBox"); box.Len() == 4 {
+			return box.Index(0).Float64(), box.Index(2).Float64() - box.Index(0).Float64()
+		}
+	}
+	// US Letter
+	return 0, 612
+}
+
+// detectCover finds a title block at the top of the first page: a large
+// centered title followed by centered lines for the subtitle, author and date.
+// It returns the block and the lines that remain for the body.
+func (c *Converter) detectCover(page pdf.Page, lines []TextLine) (*coverBlock, []TextLine) {
+	left, width := pageWidth(page)
+	center := left + width/2
+	centered := func(line TextLine) bool {
+		if len(line.Elements) == 0 {
+			return false
+		}
+		x0, x1 := lineBounds(line)
+		return x1-x0 < width*0.8 && math.Abs((x0+x1)/2-center) < width*coverCenterTolerance
+	}
+
+	// The title is the first text on the page
+	start := -1
+	for i, line := range lines {
+		if len(line.Elements) > 0 {
+			start = i
+			break
+		}
+	}
+	if start < 0 || !centered(lines[start]) || lines[start].FontSize < coverTitleSize {
+		return nil, lines
+	}
+
+	cover := &coverBlock{}
+	titleSize := lines[start].FontSize
+	end := start
+	var title []string
+	for ; end < len(lines) && centered(lines[end]) && lines[end].FontSize == titleSize; end++ {
+		title = append(title, strings.TrimSpace(c.extractLineText(lines[end])))
+	}
+	cover.Title = strings.Join(title, " ")
+
+	// Centered lines directly below the title describe the document
+	for ; end < len(lines) && centered(lines[end]); end++ {
+		text := strings.TrimSpace(c.extractLineText(lines[end]))
+		switch {
+		case coverDate.MatchString(text) && cover.Date == "":
+			cover.Date = text
+		case lines[end].FontSize > lines[start].FontSize*0.6 && cover.Subtitle == "" && cover.Author == "":
+			cover.Subtitle = text
+		case cover.Author == "" && len(strings.Fields(text)) <= coverMaxAuthorWords:
+			author := text
+			if strings.HasPrefix(strings.ToLower(author), "by ") {
+				author = strings.TrimSpace(author[3:])
+			}
+			cover.Author = author
+		default:
+			// Anything else ends the title block
+			return cover, append(append([]TextLine{}, lines[:start]...), lines[end:]...)
+		}
+	}
+	return cover, append(append([]TextLine{}, lines[:start]...), lines[end:]...)
+}
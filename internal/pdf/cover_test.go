@@ -0,0 +1,71 @@
+package pdf
+
+import (
+	"strings"
+	"testing"
+)
+
+// centered shows s centered on a US Letter page
+func centered(y float64, s, font string, size float64) string {
+	return showText(306-float64(len([]rune(s)))*size/4, y, s, font, size)
+}
+
+func TestCover(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		// want is the whole output, empty when no cover should be found
+		want string
+	}{
+		{
+			name: "title block",
+			content: centered(700, "Annual Report", "F2", 24) + centered(670, "Results of the year", "F1", 16) +
+				centered(640, "By Jane Doe", "F1", 12) + centered(622, "May 2026", "F1", 12) +
+				showText(72, 560, "The year went well.", "F1", 12),
+			want: "---\ntitle: Annual Report\nsubtitle: Results of the year\nauthor: Jane Doe\ndate: May 2026\n---\n\n# Annual Report\n\nThe year went well.",
+		},
+		{
+			name:    "title on two lines",
+			content: centered(700, "Annual", "F2", 24) + centered(672, "Report", "F2", 24) + showText(72, 600, "The year went well.", "F1", 12),
+			want:    "---\ntitle: Annual Report\n---\n\n# Annual Report\n\nThe year went well.",
+		},
+		{
+			name:    "left aligned heading",
+			content: showText(72, 700, "Annual Report", "F2", 24) + showText(72, 660, "The year went well.", "F1", 12),
+		},
+		{
+			name:    "small centered text",
+			content: centered(700, "Annual Report", "F1", 12) + showText(72, 660, "The year went well.", "F1", 12),
+		},
+	}
+	for _, tt := range tests {
+		p := &testPDF{}
+		p.page(tt.content)
+		md := convert(t, &Converter{AssetsDir: t.TempDir()}, p)
+		if tt.want == "" {
+			if strings.HasPrefix(md, "---") || strings.Contains(md, "title:") {
+				t.Errorf("%s: cover detected in %q", tt.name, md)
+			}
+			continue
+		}
+		if got := strings.TrimSpace(md); got != tt.want {
+			t.Errorf("%s: output = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCoverDate(t *testing.T) {
+	for text, want := range map[string]bool{
+		"May 2026":          true,
+		"14 May 2026":       true,
+		"September 3, 2026": true,
+		"2026-05-14":        true,
+		"14.05.2026":        true,
+		"Maybe later":       false,
+		"2026":              false,
+	} {
+		if got := coverDate.MatchString(text); got != want {
+			t.Errorf("coverDate.MatchString(%q) = %v, want %v", text, got, want)
+		}
+	}
+}
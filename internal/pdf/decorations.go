@@ -0,0 +1,88 @@
+package pdf
+
+import (
+	"math"
+)
+
+const (
+	// maxRuleThickness is the thickest line, in points, treated as a text decoration
+	maxRuleThickness = 3.0
+	// underlineDepth is how far below the baseline an underline may sit, relative to the font size
+	underlineDepth = 0.3
+)
+
+// pathSegment is a straight line or rectangle of a path in user space
+type pathSegment struct {
+	From, To [2]float64
+	Rect     bool
+	Curve    bool
+}
+
+// rule is a thin horizontal line painted on a page, in device space
+type rule struct {
+	X0, X1    float64
+	Y         float64
+	Thickness float64
+}
+
+// pathRules returns the thin horizontal lines of a painted path. Stroked
+// segments take the line width as their thickness; filled rectangles their height.
+// Paths with curves or other straight segments are shapes, such as boxes, and have none.
+func (g graphicsState) pathRules(path []pathSegment, stroke bool) []rule {
+	var rules []rule
+	for _, seg := range path {
+		if seg.Curve || !seg.Rect && seg.From[1] != seg.To[1] {
+			return nil
+		}
+
+		x0, y0 := g.transform(seg.From)
+		x1, y1 := g.transform(seg.To)
+		if x1 < x0 {
+			x0, x1 = x1, x0
+		}
+
+		var thickness float64
+		switch {
+		case seg.Rect:
+			thickness = math.Abs(y1 - y0)
+			if stroke {
+				thickness += g.LineWidth * math.Abs(g.CTM[1][1])
+			}
+		case stroke && math.Abs(y1-y0) < 0.5:
+			thickness = g.LineWidth * math.Abs(g.CTM[1][1])
+		default:
+			continue
+		}
+
+		if thickness <= maxRuleThickness && x1-x0 > thickness*3 {
+			rules = append(rules, rule{X0: x0, X1: x1, Y: (y0 + y1) / 2, Thickness: thickness})
+		}
+	}
+	return rules
+}
+
+// transform maps a point from user space to device space
+func (g graphicsState) transform(p [2]float64) (float64, float64) {
+	m := g.CTM
+	return p[0]*m[0][0] + p[1]*m[1][0] + m[2][0], p[0]*m[0][1] + p[1]*m[1][1] + m[2][1]
+}
+
+// markUnderlines flags glyphs that have a rule running just below their baseline
+func markUnderlines(elements []TextElement, rules []rule) {
+	if len(rules) == 0 {
+		return
+	}
+	for i := range elements {
+		e := &elements[i]
+		center := e.X + e.Width/2
+		for _, r := range rules {
+			if center < r.X0 || center > r.X1 {
+				continue
+			}
+			if r.Y <= e.Y+e.Size*0.05 && r.Y >= e.Y-e.Size*underlineDepth {
+				e.Underline = true
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package pdf
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+)
+
+func TestUnderline(t *testing.T) {
+	// The rule runs under "this part", which spans 102 to 156
+	text := showText(72, 700, "Read ", "F1", 12) + showText(102, 700, "this part", "F1", 12) + showText(156, 700, " now", "F1", 12)
+	tests := []struct {
+		name    string
+		c       *Converter
+		content string
+		want    string
+	}{
+		{"html", &Converter{}, text + "0.5 w 102 698 m 156 698 l S\n", "Read <u>this part</u> now"},
+		{"pandoc", &Converter{Dialect: markdown.Pandoc}, text + "0.5 w 102 698 m 156 698 l S\n", "Read [this part]{.underline} now"},
+		{"emphasis", &Converter{UnderlineEmphasis: true}, text + "0.5 w 102 698 m 156 698 l S\n", "Read _this part_ now"},
+		{"rule too low", &Converter{}, text + "0.5 w 102 690 m 156 690 l S\n", "Read this part now"},
+		{"no rule", &Converter{}, text, "Read this part now"},
+	}
+	for _, tt := range tests {
+		p := &testPDF{}
+		p.page(tt.content)
+		if got := strings.TrimSpace(convert(t, tt.c, p)); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
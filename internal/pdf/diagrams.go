@@ -0,0 +1,85 @@
+package pdf
+
+import (
+	"math"
+	"strings"
+)
+
+// diagramShare is the share of the glyphs of a monospaced block that must
+// draw lines or boxes for it to be taken as a diagram rather than code
+const diagramShare = 0.3
+
+// diagramListings finds the runs of consecutive monospaced lines that draw a
+// diagram and returns the index past the last line of each run, by its first
+// line, so they can be rendered verbatim
+func diagramListings(lines []TextLine) map[int]int {
+	diagrams := make(map[int]int)
+	for i := 0; i < len(lines); {
+		end := i
+		for end < len(lines) && isCodeLine(lines[end]) {
+			end++
+		}
+		if end-i >= 2 && isDiagram(lines[i:end]) {
+			diagrams[i] = end
+		}
+		i = max(end, i+1)
+	}
+	return diagrams
+}
+
+// isDiagram reports whether monospaced lines are mostly drawing characters,
+// some of them aligned in a column over several lines as the sides of a box
+// or the stem of an arrow are
+func isDiagram(lines []TextLine) bool {
+	glyphs, drawing := 0, 0
+	columns := make(map[int]int)
+	for _, line := range lines {
+		seen := make(map[int]bool)
+		for _, element := range line.Elements {
+			if element.Image || strings.TrimSpace(element.Text) == "" {
+				continue
+			}
+			for _, r := range element.Text {
+				glyphs++
+				if !isDrawingRune(r) {
+					continue
+				}
+				drawing++
+				// Columns are compared at the size of a glyph
+				column := int(math.Round(element.X / max(element.Size*monospaceAdvance, 1)))
+				if !seen[column] {
+					seen[column] = true
+					columns[column]++
+				}
+			}
+		}
+	}
+	if glyphs == 0 || float64(drawing) < diagramShare*float64(glyphs) {
+		return false
+	}
+	for _, count := range columns {
+		if count >= 2 {
+			return true
+		}
+	}
+	return false
+}
+
+// isDrawingRune reports whether a character draws lines, boxes or arrows,
+// either from the box drawing and block ranges or with ASCII art
+func isDrawingRune(r rune) bool {
+	switch {
+	case r >= '─' && r <= '▟', r >= '←' && r <= '⇿':
+		return true
+	}
+	return strings.ContainsRune(`+-|/\_=*#<>^~`, r)
+}
+
+// renderDiagram renders the lines of a diagram verbatim as fenced code
+func renderDiagram(lines []TextLine) string {
+	listing := make([]listingLine, len(lines))
+	for i, line := range lines {
+		listing[i] = listingLine{Number: -1, Elements: line.Elements}
+	}
+	return fencedCode(listing)
+}
@@ -0,0 +1,51 @@
+package pdf
+
+import (
+	"strings"
+	"testing"
+)
+
+// monospaced shows lines in Courier 14 points apart
+func monospaced(lines ...string) string {
+	var b strings.Builder
+	for i, line := range lines {
+		b.WriteString(showText(72, 700-float64(i)*14, line, "F4", 12))
+	}
+	return b.String()
+}
+
+func TestDiagrams(t *testing.T) {
+	box := []string{
+		"+------+     +-----+",
+		"| User | --> | App |",
+		"+------+     +-----+",
+	}
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"box diagram", monospaced(box...), "```\n" + strings.Join(box, "\n") + "\n```"},
+		{"indented arrow", monospaced("+-------+", "| start |", "+---+---+", "    |", "    v"), "```\n+-------+\n| start |\n+---+---+\n    |\n    v\n```"},
+		{"few drawing characters", monospaced("  [ start ]", "      |", "  [ end ]"), "[ start ]\n\n      |\n\n  [ end ]"},
+		{"code", monospaced("func main() {", "  run()", "}"), "func main() {\n\n  run()\n\n}"},
+		{"single line", monospaced("+--+"), "+--+"},
+		{"drawing characters out of line", monospaced("a | b", "c  | d"), "a | b\n\nc  | d"},
+	}
+	for _, tt := range tests {
+		p := &testPDF{}
+		p.page(tt.content)
+		got := strings.TrimSpace(convert(t, &Converter{AssetsDir: t.TempDir()}, p))
+		if got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestIsDrawingRune(t *testing.T) {
+	for r, want := range map[rune]bool{'─': true, '┼': true, '█': true, '→': true, '+': true, '|': true, 'a': false, '1': false, ' ': false, '(': false} {
+		if got := isDrawingRune(r); got != want {
+			t.Errorf("isDrawingRune(%q) = %v, want %v", r, got, want)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package pdf
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// dropCapScale is how much larger than body text an initial must be to be a drop cap
+const dropCapScale = 2.0
+
+// mergeDropCaps finds decorative initials, a single large letter set beside the
+// first lines of a paragraph, and moves them onto the paragraph's first line at
+// body size so they read as its first character instead of a heading.
+func mergeDropCaps(elements []TextElement) {
+	body := bodyFontSize(elements)
+	if body == 0 {
+		return
+	}
+
+	for i := range elements {
+		dropCap := &elements[i]
+		if r, n := utf8.DecodeRuneInString(dropCap.Text); n != len(dropCap.Text) || !unicode.IsLetter(r) {
+			continue
+		}
+		if dropCap.Size < body*dropCapScale || hasLargeNeighbour(elements, i, body) {
+			continue
+		}
+
+		// The paragraph starts on the topmost body line immediately to its right
+		first := -1
+		for j, e := range elements {
+			if j == i || e.Size > body*1.2 || e.X <= dropCap.X || e.X > dropCap.X+dropCap.Width+dropCap.Size {
+				continue
+			}
+			if e.Y < dropCap.Y-dropCap.Size*0.2 || e.Y > dropCap.Y+dropCap.Size {
+				continue
+			}
+			if first < 0 || e.Y > elements[first].Y {
+				first = j
+			}
+		}
+		if first < 0 {
+			continue
+		}
+
+		// The initial is set apart from the text it begins, so it is moved up
+		// against it to keep the word whole
+		dropCap.Width *= elements[first].Size / dropCap.Size
+		dropCap.X = elements[first].X - dropCap.Width
+		dropCap.Y = elements[first].Y
+		dropCap.Size = elements[first].Size
+		dropCap.Font = elements[first].Font
+	}
+}
+
+// hasLargeNeighbour reports whether other oversized text shares the element's
+// baseline, as in a heading set in the same large font
+func hasLargeNeighbour(elements []TextElement, i int, body float64) bool {
+	for j, e := range elements {
+		if j != i && e.Y == elements[i].Y && e.Size > body*1.5 && e.Text != " " {
+			return true
+		}
+	}
+	return false
+}
+
+// bodyFontSize returns the most common font size among the elements
+func bodyFontSize(elements []TextElement) float64 {
+	counts := make(map[float64]int)
+	var best float64
+	for _, e := range elements {
+		counts[e.Size]++
+		if counts[e.Size] > counts[best] || (counts[e.Size] == counts[best] && e.Size < best) {
+			best = e.Size
+		}
+	}
+	return best
+}
@@ -0,0 +1,38 @@
+package pdf
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDropCaps(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name: "drop cap",
+			content: showText(72, 664, "T", "F1", 36) + showText(94, 700, "he quick brown fox", "F1", 12) +
+				showText(94, 682, "jumps over the lazy", "F1", 12) + showText(94, 664, "dog.", "F1", 12),
+			want: "The quick brown fox\n\njumps over the lazy\n\ndog.",
+		},
+		{
+			name:    "raised initial",
+			content: showText(72, 700, "O", "F1", 30) + showText(91, 700, "nce upon a time.", "F1", 12) + body("", "The end of the story."),
+			want:    "Once upon a time.\n\nThe end of the story.",
+		},
+		{
+			name:    "large heading kept",
+			content: showText(72, 720, "A", "F2", 24) + showText(90, 720, " Guide", "F2", 24) + body("Body text follows.", "More body text."),
+			want:    "# A Guide\nBody text follows.\n\nMore body text.",
+		},
+	}
+	for _, tt := range tests {
+		p := &testPDF{}
+		p.page(tt.content)
+		if got := strings.TrimSpace(convert(t, &Converter{}, p)); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
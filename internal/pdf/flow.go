@@ -0,0 +1,96 @@
+package pdf
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+)
+
+// joinPageFlow appends the Markdown of a page to the preceding text, continuing
+// a paragraph cut by the page break instead of starting a new block
+func joinPageFlow(prev, next string) string {
+	prev = strings.TrimRight(prev, " \n")
+	next = strings.TrimLeft(next, " \n")
+
+	lastStart := strings.LastIndex(prev, "\n") + 1
+	last := prev[lastStart:]
+	firstEnd := strings.Index(next, "\n")
+	if firstEnd < 0 {
+		firstEnd = len(next)
+	}
+	first := next[:firstEnd]
+
+	if !isFlowParagraph(last) || !isFlowParagraph(first) || endsSentence(last) {
+		return prev + "\n\n" + next
+	}
+
+	// A word hyphenated across the break is rejoined
+	if strings.HasSuffix(last, "-") {
+		if r, _ := utf8.DecodeRuneInString(first); unicode.IsLower(r) {
+			return strings.TrimSuffix(prev, "-") + next
+		}
+	}
+	return prev + " " + next
+}
+
+// isFlowParagraph reports whether a line is paragraph text rather than another block
+func isFlowParagraph(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" {
+		return false
+	}
+	for _, prefix := range []string{"#", "- ", "* ", "+ ", "|", "![", ">", "```", "---"} {
+		if strings.HasPrefix(trimmed, prefix) {
+			return false
+		}
+	}
+	return !orderedLabel.MatchString(strings.SplitN(trimmed, " ", 2)[0])
+}
+
+// endsSentence reports whether a paragraph line ends with closing punctuation
+func endsSentence(line string) bool {
+	r, _ := utf8.DecodeLastRuneInString(strings.TrimSpace(line))
+	return strings.ContainsRune(".!?:;\"'”’)", r)
+}
+
+// dehyphenate rejoins the words of a page hyphenated at the end of a line.
+// Every line of body text is a paragraph of its own, so the paragraph ending
+// in the first half of a word is joined with the one holding the rest.
+// Fenced code is left as it is.
+func dehyphenate(md string) string {
+	lines := strings.Split(md, "\n")
+	var out []string
+	var fence markdown.CodeFence
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if fence.Line(line) {
+			out = append(out, line)
+			continue
+		}
+		for hyphenated(line) && i+2 < len(lines) && lines[i+1] == "" && continuesWord(lines[i+2]) {
+			line = strings.TrimSuffix(line, "-") + strings.TrimLeft(lines[i+2], " ")
+			i += 2
+		}
+		out = append(out, line)
+	}
+	return strings.Join(out, "\n")
+}
+
+// hyphenated reports whether a paragraph line ends in a letter followed by a
+// hyphen, as the first half of a word broken at the line end does
+func hyphenated(line string) bool {
+	if !isFlowParagraph(line) || !strings.HasSuffix(line, "-") {
+		return false
+	}
+	r, _ := utf8.DecodeLastRuneInString(strings.TrimSuffix(line, "-"))
+	return unicode.IsLetter(r)
+}
+
+// continuesWord reports whether a paragraph line opens with a lowercase
+// letter, as the rest of a hyphenated word does
+func continuesWord(line string) bool {
+	r, _ := utf8.DecodeRuneInString(strings.TrimLeft(line, " "))
+	return isFlowParagraph(line) && unicode.IsLower(r)
+}
@@ -0,0 +1,102 @@
+package pdf
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestJoinPageFlow(t *testing.T) {
+	tests := []struct {
+		name       string
+		prev, next string
+		want       string
+	}{
+		{"continued paragraph", "The text runs", "on the next page.", "The text runs on the next page."},
+		{"hyphenated word", "The exam-", "ple continues.", "The example continues."},
+		{"finished sentence", "It ends here.", "A new one starts.", "It ends here.\n\nA new one starts."},
+		{"heading after", "The text runs", "## Next", "The text runs\n\n## Next"},
+		{"list after", "The text runs", "- item", "The text runs\n\n- item"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := joinPageFlow(tt.prev, tt.next); got != tt.want {
+				t.Errorf("joinPageFlow(%q, %q) = %q, want %q", tt.prev, tt.next, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDehyphenate(t *testing.T) {
+	tests := []struct {
+		name string
+		md   string
+		want string
+	}{
+		{
+			name: "word broken at the line end",
+			md:   "A hyphen-\n\nated word.\n\n",
+			want: "A hyphenated word.\n\n",
+		},
+		{
+			name: "word broken over three lines",
+			md:   "An extra-\n\nordinari-\n\nly long word.",
+			want: "An extraordinarily long word.",
+		},
+		{
+			name: "capitalized continuation",
+			md:   "A well-\n\nKnown name.",
+			want: "A well-\n\nKnown name.",
+		},
+		{
+			name: "dash after a space",
+			md:   "A pause -\n\nthen more.",
+			want: "A pause -\n\nthen more.",
+		},
+		{
+			name: "list items",
+			md:   "- first-\n\n- second",
+			want: "- first-\n\n- second",
+		},
+		{
+			name: "fenced code",
+			md:   "```\nx = a-\n\nb\n```",
+			want: "```\nx = a-\n\nb\n```",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dehyphenate(tt.md); got != tt.want {
+				t.Errorf("dehyphenate(%q) = %q, want %q", tt.md, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDehyphenateOption(t *testing.T) {
+	p := &testPDF{}
+	p.page(body("A hyphen-", "ated word is rejoined."))
+	for _, dehyphenate := range []bool{false, true} {
+		got := strings.TrimSpace(convert(t, &Converter{Dehyphenate: dehyphenate}, p))
+		want := "A hyphen-\n\nated word is rejoined."
+		if dehyphenate {
+			want = "A hyphenated word is rejoined."
+		}
+		if got != want {
+			t.Errorf("Dehyphenate %v: got %q, want %q", dehyphenate, got, want)
+		}
+	}
+}
+
+func TestNoPageBreaks(t *testing.T) {
+	p := &testPDF{}
+	p.page(body("A sentence that runs"))
+	p.page(body("on to the next page.", "", "Another paragraph."))
+
+	if got := convert(t, &Converter{}, p); !strings.Contains(got, "\n---\n") {
+		t.Errorf("page breaks: output lacks a separator:\n%s", got)
+	}
+	want := "A sentence that runs on to the next page.\n\nAnother paragraph."
+	if got := strings.TrimSpace(convert(t, &Converter{NoPageBreaks: true}, p)); got != want {
+		t.Errorf("continuous: got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,60 @@
+package pdf
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestKeepFontInfo(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "runs of a line",
+			content: showText(72, 700, "Plain ", "F1", 12) + showText(108, 700, "bold", "F2", 12) + showText(132, 700, " end.", "F1", 12),
+			want:    `<span data-font="Helvetica" data-size="12">Plain</span> <span data-font="Helvetica-Bold" data-size="12">bold</span> <span data-font="Helvetica" data-size="12">end.</span>`,
+		},
+		{
+			name:    "fractional size",
+			content: showText(72, 700, "Small print.", "F3", 9.5),
+			want:    `<span data-font="Helvetica-Oblique" data-size="9.5">Small print.</span>`,
+		},
+		{
+			name:    "heading",
+			content: showText(72, 700, "Big heading", "F1", 20) + showText(72, 660, "Body text.", "F1", 12) + showText(72, 642, "More body text.", "F1", 12),
+			want:    "# " + `<span data-font="Helvetica" data-size="20">Big heading</span>`,
+		},
+	}
+	for _, tt := range tests {
+		p := &testPDF{}
+		p.page(tt.content)
+		got := convert(t, &Converter{AssetsDir: t.TempDir(), KeepFontInfo: true}, p)
+		if !strings.Contains(got, tt.want) {
+			t.Errorf("%s: got %q, want it to contain %q", tt.name, got, tt.want)
+		}
+	}
+
+	p := &testPDF{}
+	p.page(body("No spans by default."))
+	if got := convert(t, &Converter{AssetsDir: t.TempDir()}, p); strings.Contains(got, "<span") {
+		t.Errorf("default: got %q, want no spans", got)
+	}
+}
+
+func TestFontSpan(t *testing.T) {
+	tests := []struct {
+		s, font string
+		size    float64
+		want    string
+	}{
+		{"text", "Times-Roman", 11, `<span data-font="Times-Roman" data-size="11">text</span>`},
+		{"text", `A"B&C`, 10.456, `<span data-font="A&#34;B&amp;C" data-size="10.46">text</span>`},
+	}
+	for _, tt := range tests {
+		if got := fontSpan(tt.s, tt.font, tt.size); got != tt.want {
+			t.Errorf("fontSpan(%q, %q, %g) = %q, want %q", tt.s, tt.font, tt.size, got, tt.want)
+		}
+	}
+}
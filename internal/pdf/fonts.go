@@ -0,0 +1,72 @@
+package pdf
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/rsc/pdf"
+)
+
+// fontInfo is a font with its encoding and metrics resolved once per document.
+// Resolving an encoding parses the font's ToUnicode CMap, and every width lookup
+// otherwise walks the font dictionary again.
+type fontInfo struct {
+	// Name is the base font name without any subset prefix
+	Name   string
+	enc    pdf.TextEncoding
+	first  int
+	widths []float64
+	// vertical is set for fonts in vertical writing mode, whose glyphs advance downwards
+	vertical bool
+}
+
+// width returns the advance width of a character code in glyph space units
+func (f *fontInfo) width(code int) float64 {
+	if i := code - f.first; i >= 0 && i < len(f.widths) {
+		return f.widths[i]
+	}
+	return 0
+}
+
+// fontCache holds the fonts of a document keyed by the font resources they are
+// named in, so fonts used under the same resource name on many pages are only
+// resolved once. The resources refer to font dictionaries as indirect objects,
+// which makes them cheap to write out as a key, while a font dictionary costs
+// as much to write out as to resolve. It is shared by pages extracted
+// concurrently.
+type fontCache struct {
+	mu    sync.Mutex
+	fonts map[string]*fontInfo
+}
+
+func newFontCache() *fontCache {
+	return &fontCache{fonts: make(map[string]*fontInfo)}
+}
+
+func (fc *fontCache) lookup(page pdf.Page, name string) *fontInfo {
+	key := page.Resources().Key("Font").String() + " " + name
+	fc.mu.Lock()
+	info, ok := fc.fonts[key]
+	fc.mu.Unlock()
+	if ok {
+		return info
+	}
+
+	font := page.Font(name)
+
+	baseFont := font.BaseFont()
+	if i := strings.Index(baseFont, "+"); i >= 0 {
+		baseFont = baseFont[i+1:]
+	}
+	info = &fontInfo{
+		Name:     baseFont,
+		enc:      font.Encoder(),
+		first:    font.FirstChar(),
+		widths:   font.Widths(),
+		vertical: isVerticalEncoding(font.V.Key("Encoding")),
+	}
+	fc.mu.Lock()
+	fc.fonts[key] = info
+	fc.mu.Unlock()
+	return info
+}
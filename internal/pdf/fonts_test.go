@@ -0,0 +1,99 @@
+package pdf
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fontPDF builds a document of pages that each show text in every test font
+// and in a font mapped to Unicode by a CMap, as embedded subsets are
+func fontPDF(numPages int) *testPDF {
+	p := &testPDF{}
+	var cmap strings.Builder
+	cmap.WriteString("begincmap\n/CMapName /Test def\n1 begincodespacerange <00> <FF> endcodespacerange\n")
+	for start := 0; start < 256; start += 100 {
+		end := min(start+100, 256)
+		fmt.Fprintf(&cmap, "%d beginbfchar\n", end-start)
+		for code := start; code < end; code++ {
+			fmt.Fprintf(&cmap, "<%02X> <%04X>\n", code, code+29)
+		}
+		cmap.WriteString("endbfchar\n")
+	}
+	cmap.WriteString("endcmap\n")
+	toUnicode := p.stream(cmap.String(), "")
+	widths := strings.TrimSpace(strings.Repeat("500 ", 256))
+	sans := p.add(fmt.Sprintf("<< /Type /Font /Subtype /TrueType /BaseFont /ABCDEF+Sans /FirstChar 0 /LastChar 255 /Widths [%s] /ToUnicode %d 0 R >>", widths, toUnicode))
+	p.fonts = fmt.Sprintf("/T1 %d 0 R", sans)
+
+	for i := 0; i < numPages; i++ {
+		var content string
+		for j, font := range append(testFonts, [2]string{"T1", "Sans"}) {
+			content += showText(72, 700-float64(j)*18, fmt.Sprintf("Page %d in %s.", i+1, font[1]), font[0], 12)
+		}
+		p.page(content)
+	}
+	return p
+}
+
+func TestFontCache(t *testing.T) {
+	reader := openTestPDF(t, fontPDF(2))
+	fc := newFontCache()
+	first := fc.lookup(reader.Page(1), "F2")
+	if first.Name != "Helvetica-Bold" || first.width('a') != 500 {
+		t.Errorf("lookup(F2) = %+v, want Helvetica-Bold with 500 unit glyphs", first)
+	}
+	// The font dictionary is shared by both pages
+	if again := fc.lookup(reader.Page(2), "F2"); again != first {
+		t.Errorf("lookup(F2) on page 2 resolved the font again")
+	}
+	if other := fc.lookup(reader.Page(1), "F1"); other == first || other.Name != "Helvetica" {
+		t.Errorf("lookup(F1) = %+v, want Helvetica", other)
+	}
+}
+
+func TestFontCacheConcurrent(t *testing.T) {
+	reader := openTestPDF(t, fontPDF(8))
+	fc := newFontCache()
+	var wg sync.WaitGroup
+	for i := 1; i <= reader.NumPage(); i++ {
+		wg.Add(1)
+		go func(page int) {
+			defer wg.Done()
+			for _, font := range testFonts {
+				if info := fc.lookup(reader.Page(page), font[0]); info.Name != font[1] {
+					t.Errorf("lookup(%s) on page %d = %q, want %q", font[0], page, info.Name, font[1])
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	if len(fc.fonts) != len(testFonts) {
+		t.Errorf("cache holds %d fonts, want %d", len(fc.fonts), len(testFonts))
+	}
+}
+
+func BenchmarkFontCache(b *testing.B) {
+	reader := openTestPDF(b, fontPDF(1))
+	page := reader.Page(1)
+	b.Run("cached", func(b *testing.B) {
+		fc := newFontCache()
+		for i := 0; i < b.N; i++ {
+			fc.lookup(page, "T1")
+		}
+	})
+	b.Run("uncached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			newFontCache().lookup(page, "T1")
+		}
+	})
+}
+
+func BenchmarkConvertFonts(b *testing.B) {
+	p := fontPDF(200)
+	c := &Converter{AssetsDir: b.TempDir()}
+	for i := 0; i < b.N; i++ {
+		convert(b, c, p)
+	}
+}
@@ -0,0 +1,25 @@
+package pdf
+
+import "sort"
+
+// lineSpacing returns the usual distance between the baselines of consecutive
+// lines of body text: the lower median, so that paragraph spacing and the
+// occasional wide gap do not count even when there are only two gaps. Pages
+// with too few lines to tell assume a leading of 120% of the body size.
+func lineSpacing(lines []TextLine, bodySize float64) float64 {
+	var gaps []float64
+	for i := 1; i < len(lines); i++ {
+		prev, line := lines[i-1], lines[i]
+		if len(prev.Elements) == 0 || len(line.Elements) == 0 || prev.FontSize > bodySize*1.2 || line.FontSize > bodySize*1.2 {
+			continue
+		}
+		if gap := prev.Y - line.Y; gap > 0 {
+			gaps = append(gaps, gap)
+		}
+	}
+	if len(gaps) < 2 {
+		return bodySize * 1.2
+	}
+	sort.Float64s(gaps)
+	return gaps[(len(gaps)-1)/2]
+}
@@ -0,0 +1,69 @@
+package pdf
+
+import (
+	"strings"
+	"testing"
+)
+
+// at shows lines of regular text at the given baselines
+func at(lines ...any) string {
+	var b strings.Builder
+	for i := 0; i < len(lines); i += 2 {
+		b.WriteString(showText(72, float64(lines[i].(int)), lines[i+1].(string), "F1", 12))
+	}
+	return b.String()
+}
+
+func TestGapBreaks(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		gaps    float64
+		want    string
+	}{
+		{"mid-page gap", at(700, "First line.", 686, "Second line.", 672, "Third line.", 400, "After the gap."), 3,
+			"First line.\n\nSecond line.\n\nThird line.\n\n---\n\nAfter the gap.\n\n"},
+		{"disabled", at(700, "First line.", 686, "Second line.", 400, "After the gap."), 0,
+			"First line.\n\nSecond line.\n\nAfter the gap.\n\n"},
+		{"paragraph spacing", at(700, "One.", 686, "Two.", 658, "Three.", 644, "Four."), 3,
+			"One.\n\nTwo.\n\nThree.\n\nFour.\n\n"},
+		{"two gaps", at(700, "Before.", 682, "Still before.", 574, "After."), 3,
+			"Before.\n\nStill before.\n\n---\n\nAfter.\n\n"},
+		{"higher threshold", at(700, "One.", 686, "Two.", 672, "Three.", 600, "Four."), 6,
+			"One.\n\nTwo.\n\nThree.\n\nFour.\n\n"},
+		{"below a heading", showText(72, 700, "Heading", "F2", 18) + at(600, "Body text.", 586, "More body text."), 3,
+			"## Heading\nBody text.\n\nMore body text.\n\n"},
+		{"ends a list", at(700, "- one", 686, "- two", 672, "- three", 500, "Closing words."), 3,
+			"\n- one\n- two\n- three\n\n---\n\nClosing words.\n\n"},
+	}
+	for _, tt := range tests {
+		p := &testPDF{}
+		p.page(tt.content)
+		got := convert(t, &Converter{AssetsDir: t.TempDir(), GapBreaks: tt.gaps}, p)
+		if strings.TrimRight(got, "\n") != strings.TrimRight(tt.want, "\n") {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLineSpacing(t *testing.T) {
+	bodySize := 12.0
+	line := func(y, size float64) TextLine {
+		return TextLine{Y: y, FontSize: size, Elements: []TextElement{{Text: "x", Size: size}}}
+	}
+	tests := []struct {
+		name  string
+		lines []TextLine
+		want  float64
+	}{
+		{"median", []TextLine{line(700, 12), line(686, 12), line(672, 12), line(400, 12)}, 14},
+		{"lower of two", []TextLine{line(700, 12), line(682, 12), line(574, 12)}, 18},
+		{"too few lines", []TextLine{line(700, 12), line(680, 12)}, bodySize * 1.2},
+		{"heading gaps left out", []TextLine{line(700, 24), line(600, 12), line(584, 12), line(568, 12)}, 16},
+	}
+	for _, tt := range tests {
+		if got := lineSpacing(tt.lines, bodySize); got != tt.want {
+			t.Errorf("%s: lineSpacing() = %g, want %g", tt.name, got, tt.want)
+		}
+	}
+}
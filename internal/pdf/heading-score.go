@@ -0,0 +1,99 @@
+package pdf
+
+import (
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Weights of the cues a heading score adds up. A line set at half again the
+// body size, or bold with one more cue, clears the default threshold of 0.5.
+const (
+	// sizeWeight is earned in full by text half again the body size
+	sizeWeight     = 0.5
+	boldWeight     = 0.3
+	centerWeight   = 0.15
+	capsWeight     = 0.15
+	isolatedWeight = 0.15
+	numberedWeight = 0.2
+	// longPenalty is taken from lines of more words than a heading runs to
+	longPenalty = 0.4
+	// sentencePenalty is taken from lines ending in a full stop
+	sentencePenalty = 0.15
+	// headingMaxWords separates headings from lines of body text
+	headingMaxWords = 12
+	// centerTolerance is how far, as a share of the text block width, the
+	// middle of a centered line may lie from the middle of the block
+	centerTolerance = 0.05
+)
+
+// numberedHeading matches the section number opening a heading, as in
+// "2.1 Scope" or "3. Results", capturing the number
+var numberedHeading = regexp.MustCompile(`^(\d+(?:\.\d+)*)\.?\s+\S`)
+
+// headingScore weighs the cues that set the line at index i apart as a heading
+// into one confidence: its size over the body text, boldness, centering, all
+// capitals, a paragraph gap on both sides and a section number, less penalties
+// for running as long as a sentence does.
+func headingScore(lines []TextLine, i int, lineText string, layout pageLayout, spacing float64) float64 {
+	line := lines[i]
+	text := strings.TrimSpace(plainHeading(lineText))
+	score := 0.0
+
+	if layout.BodySize > 0 {
+		score += sizeWeight * math.Min(math.Max((line.FontSize/layout.BodySize-1)/0.5, 0), 1)
+	}
+	if line.IsBold {
+		score += boldWeight
+	}
+	if len(line.Elements) > 0 && layout.Right > layout.Left {
+		left, right := lineBounds(line)
+		width := layout.Right - layout.Left
+		if left > layout.Left+1 && math.Abs((left+right)/2-(layout.Left+layout.Right)/2) < width*centerTolerance {
+			score += centerWeight
+		}
+	}
+	if isAllCaps(text) {
+		score += capsWeight
+	}
+	if separated(lines, i-1, i, spacing) && separated(lines, i, i+1, spacing) {
+		score += isolatedWeight
+	}
+	if numberedHeading.MatchString(text) {
+		score += numberedWeight
+	}
+
+	if len(strings.Fields(text)) > headingMaxWords {
+		score -= longPenalty
+	}
+	if strings.HasSuffix(text, ".") {
+		score -= sentencePenalty
+	}
+	return score
+}
+
+// numberedLevel returns the heading level of a heading opening with a section
+// number, one deeper than the parts of the number so that chapters sit under
+// the document title, or 0 for an unnumbered heading
+func numberedLevel(lineText string) int {
+	m := numberedHeading.FindStringSubmatch(strings.TrimSpace(plainHeading(lineText)))
+	if m == nil {
+		return 0
+	}
+	return min(strings.Count(m[1], ".")+2, 6)
+}
+
+// isAllCaps reports whether text has a few letters and all of them capitals
+func isAllCaps(text string) bool {
+	letters := 0
+	for _, r := range text {
+		if unicode.IsLetter(r) {
+			if !unicode.IsUpper(r) {
+				return false
+			}
+			letters++
+		}
+	}
+	return letters >= 3
+}
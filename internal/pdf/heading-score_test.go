@@ -0,0 +1,77 @@
+package pdf
+
+import (
+	"strings"
+	"testing"
+)
+
+// candidatePage shows a candidate line between two paragraphs of body text,
+// a paragraph gap above and below it. Glyphs are 6 points wide at 12 points,
+// so the body lines span 72 to 342.
+func candidatePage(candidate string) string {
+	return showText(72, 700, "Body text of the page runs the line out.", "F1", 12) +
+		showText(72, 682, "It continues for another line of text, too", "F1", 12) +
+		candidate +
+		showText(72, 610, "Body text after the candidate line follows", "F1", 12) +
+		showText(72, 592, "and more body text fills the next line too.", "F1", 12)
+}
+
+func TestHeadingScore(t *testing.T) {
+	tests := []struct {
+		name      string
+		candidate string
+		threshold float64
+		// want is the candidate as written, a heading or a paragraph
+		want string
+	}{
+		{"numbered bold line at body size", showText(72, 646, "2.1 Scope", "F2", 12), 0.5, "### 2.1 Scope"},
+		{"numbered bold line by size rules", showText(72, 646, "2.1 Scope", "F2", 12), 0, "2.1 Scope"},
+		{"numbered bold line below a higher threshold", showText(72, 646, "2.1 Scope", "F2", 12), 0.7, "2.1 Scope"},
+		{"centered capitals", showText(171, 646, "INTRODUCTION", "F2", 12), 0.5, "##### INTRODUCTION"},
+		{"large sentence", showText(72, 646, "This large line runs on like a sentence does, with many words in it.", "F1", 16), 0.5, "This large line runs on like a sentence does, with many words in it."},
+		{"large sentence by size rules", showText(72, 646, "This large line runs on like a sentence does, with many words in it.", "F1", 16), 0, "### This large line runs on like a sentence does, with many words in it."},
+		{"large short line", showText(72, 646, "Results", "F1", 18), 0.5, "## Results"},
+		{"plain line", showText(72, 646, "A plain line on its own", "F1", 12), 0.5, "A plain line on its own"},
+	}
+	for _, tt := range tests {
+		p := &testPDF{}
+		p.page(candidatePage(tt.candidate))
+		got := convert(t, &Converter{AssetsDir: t.TempDir(), HeadingThreshold: tt.threshold}, p)
+		var line string
+		for _, l := range strings.Split(got, "\n") {
+			if strings.Contains(l, strings.TrimLeft(tt.want, "# ")) {
+				line = l
+			}
+		}
+		if line != tt.want {
+			t.Errorf("%s: candidate written as %q, want %q in:\n%s", tt.name, line, tt.want, got)
+		}
+	}
+}
+
+func TestNumberedLevel(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"3. Results", 2},
+		{"2.1 Scope", 3},
+		{"1.2.3.4.5.6 Deep", 6},
+		{"<mark>4.2 Marked</mark>", 3},
+		{"Scope", 0},
+		{"2021 was a year", 2},
+	}
+	for _, tt := range tests {
+		if got := numberedLevel(tt.text); got != tt.want {
+			t.Errorf("numberedLevel(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestIsAllCaps(t *testing.T) {
+	for text, want := range map[string]bool{"INTRODUCTION": true, "PART 2: SCOPE": true, "OK": false, "Introduction": false, "123": false} {
+		if got := isAllCaps(text); got != want {
+			t.Errorf("isAllCaps(%q) = %v, want %v", text, got, want)
+		}
+	}
+}
@@ -0,0 +1,500 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/ascii85"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/errs"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/utils"
+	"github.com/rsc/pdf"
+)
+
+// Image formats accepted by Converter.ImageFormat
+const (
+	ImageFormatOriginal = "original"
+	ImageFormatPNG      = "png"
+	ImageFormatJPEG     = "jpeg"
+	// ImageFormatNone skips image extraction
+	ImageFormatNone = "none"
+)
+
+// jpegQuality is used whenever an image has to be re-encoded as JPEG
+const jpegQuality = 90
+
+// pageImage is an image XObject drawn on a page, positioned in page space
+type pageImage struct {
+	Stream pdf.Value
+	X      float64
+	Y      float64
+	Width  float64
+	Height float64
+}
+
+// extractImage writes an image into the assets directory and returns the
+// Markdown reference to it. Images of encrypted PDFs, whose streams are read
+// undecrypted, return errs.ErrEncrypted for the caller to skip them.
+func (c *Converter) extractImage(doc *document, img pageImage, pageNum, index int) (string, error) {
+	if isEncrypted(doc.reader) {
+		return "", fmt.Errorf("%w: images in encrypted PDFs are not supported", errs.ErrEncrypted)
+	}
+
+	raw, err := rawStreamData(doc.file, img.Stream)
+	if err != nil {
+		return "", err
+	}
+
+	data, encoding, err := decodeStreamFilters(raw, img.Stream)
+	if err != nil {
+		return "", err
+	}
+
+	data, ext, err := c.encodeImage(data, encoding, img.Stream)
+	if err != nil {
+		return "", err
+	}
+
+	doc.turns.wait(pageNum)
+	name := c.AssetNamer.Name(fmt.Sprintf("%s-page%d-img%d.%s", doc.name, pageNum, index, ext), data)
+	if err := os.WriteFile(filepath.Join(c.AssetsDir, name), data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write image: %v", err)
+	}
+
+	return "![](" + utils.AssetLink(c.AssetsLink, c.AssetsDir, name) + ")", nil
+}
+
+// encodeImage converts decoded stream data into an image file in the configured format.
+// encoding is the final filter of an image whose data is still encoded ("DCTDecode",
+// "JPXDecode") or empty when data holds raw samples.
+func (c *Converter) encodeImage(data []byte, encoding string, stream pdf.Value) ([]byte, string, error) {
+	format := c.ImageFormat
+	if format == "" {
+		format = ImageFormatOriginal
+	}
+
+	var img image.Image
+	switch encoding {
+	case "JPXDecode":
+		// JPEG 2000 cannot be decoded with the standard library, so it can only
+		// be kept as is
+		if format != ImageFormatOriginal {
+			return nil, "", fmt.Errorf("%w: JPEG 2000 images cannot be converted to %s; keep images in their original format", errs.ErrUnsupportedType, format)
+		}
+		return data, "jp2", nil
+	case "DCTDecode":
+		// Embedded JPEGs are copied unless they need transcoding or are CMYK
+		if format == ImageFormatOriginal || format == ImageFormatJPEG && !isCMYKJPEG(data) {
+			return data, "jpg", nil
+		}
+		decoded, err := jpeg.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to decode JPEG image: %v", err)
+		}
+		img = decoded
+	default:
+		decoded, err := sampledImage(data, stream)
+		if err != nil {
+			return nil, "", err
+		}
+		img = decoded
+	}
+
+	// CMYK output is poorly supported by viewers, so always convert to RGB
+	if _, ok := img.(*image.CMYK); ok {
+		img = toRGBA(img)
+	}
+
+	var buf bytes.Buffer
+	if format == ImageFormatJPEG {
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+			return nil, "", fmt.Errorf("failed to encode JPEG image: %v", err)
+		}
+		return buf.Bytes(), "jpg", nil
+	}
+
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, "", fmt.Errorf("failed to encode PNG image: %v", err)
+	}
+	return buf.Bytes(), "png", nil
+}
+
+func isCMYKJPEG(data []byte) bool {
+	config, err := jpeg.DecodeConfig(bytes.NewReader(data))
+	return err == nil && config.ColorModel == color.CMYKModel
+}
+
+func toRGBA(img image.Image) *image.RGBA {
+	rgba := image.NewRGBA(img.Bounds())
+	draw.Draw(rgba, rgba.Bounds(), img, img.Bounds().Min, draw.Src)
+	return rgba
+}
+
+// rawStreamData reads the undecoded bytes of a stream. pdf.Value.Reader always applies
+// the stream filters and does not support image codecs, so the stream is read from the
+// file directly using the offset reported by Value.String.
+func rawStreamData(file io.ReaderAt, stream pdf.Value) ([]byte, error) {
+	if stream.Kind() != pdf.Stream {
+		return nil, fmt.Errorf("object is not a stream")
+	}
+
+	desc := stream.String()
+	offset, err := strconv.ParseInt(desc[strings.LastIndex(desc, "@")+1:], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate stream: %v", err)
+	}
+
+	length := stream.Key("Length").Int64()
+	if length <= 0 {
+		return nil, fmt.Errorf("stream has no data")
+	}
+
+	data := make([]byte, length)
+	if _, err := file.ReadAt(data, offset); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to read stream: %v", err)
+	}
+
+	return data, nil
+}
+
+// decodeStreamFilters applies the general-purpose filters of a stream. Image codec
+// filters are left in place and reported so the caller can handle them.
+func decodeStreamFilters(data []byte, stream pdf.Value) ([]byte, string, error) {
+	filters := stream.Key("Filter")
+	params := stream.Key("DecodeParms")
+
+	var names []string
+	var parms []pdf.Value
+	switch filters.Kind() {
+	case pdf.Name:
+		names = []string{filters.Name()}
+		parms = []pdf.Value{params}
+	case pdf.Array:
+		for i := 0; i < filters.Len(); i++ {
+			names = append(names, filters.Index(i).Name())
+			parms = append(parms, params.Index(i))
+		}
+	}
+
+	for i, name := range names {
+		var err error
+		switch name {
+		case "FlateDecode", "Fl":
+			data, err = inflate(data, parms[i])
+		case "ASCIIHexDecode", "AHx":
+			data, err = decodeASCIIHex(data)
+		case "ASCII85Decode", "A85":
+			data, err = decodeASCII85(data)
+		case "DCTDecode", "DCT", "JPXDecode":
+			if i != len(names)-1 {
+				return nil, "", fmt.Errorf("unsupported filter chain %v", names)
+			}
+			if name == "DCT" {
+				name = "DCTDecode"
+			}
+			return data, name, nil
+		default:
+			return nil, "", fmt.Errorf("unsupported image filter %s", name)
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to apply %s: %v", name, err)
+		}
+	}
+
+	return data, "", nil
+}
+
+func inflate(data []byte, parms pdf.Value) ([]byte, error) {
+	zr, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	out, err := io.ReadAll(zr)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+
+	predictor := parms.Key("Predictor").Int64()
+	if predictor < 10 {
+		return out, nil
+	}
+
+	colors := intOr(parms.Key("Colors"), 1)
+	bpc := intOr(parms.Key("BitsPerComponent"), 8)
+	columns := intOr(parms.Key("Columns"), 1)
+	return unpredictPNG(out, (colors*bpc*columns+7)/8, (colors*bpc+7)/8)
+}
+
+// unpredictPNG reverses the PNG row predictors used by FlateDecode streams
+func unpredictPNG(data []byte, rowLen, bpp int) ([]byte, error) {
+	var out []byte
+	prev := make([]byte, rowLen)
+	for len(data) > 0 {
+		if len(data) < rowLen+1 {
+			break
+		}
+		filter, row := data[0], append([]byte(nil), data[1:rowLen+1]...)
+		data = data[rowLen+1:]
+
+		for i := range row {
+			var left, upLeft byte
+			if i >= bpp {
+				left = row[i-bpp]
+				upLeft = prev[i-bpp]
+			}
+			up := prev[i]
+			switch filter {
+			case 0:
+			case 1:
+				row[i] += left
+			case 2:
+				row[i] += up
+			case 3:
+				row[i] += byte((int(left) + int(up)) / 2)
+			case 4:
+				row[i] += paeth(left, up, upLeft)
+			default:
+				return nil, fmt.Errorf("unknown PNG predictor %d", filter)
+			}
+		}
+		out = append(out, row...)
+		prev = row
+	}
+	return out, nil
+}
+
+func paeth(a, b, c byte) byte {
+	p := int(a) + int(b) - int(c)
+	pa, pb, pc := abs(p-int(a)), abs(p-int(b)), abs(p-int(c))
+	switch {
+	case pa <= pb && pa <= pc:
+		return a
+	case pb <= pc:
+		return b
+	default:
+		return c
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func decodeASCIIHex(data []byte) ([]byte, error) {
+	var digits []byte
+	for _, b := range data {
+		if b == '>' {
+			break
+		}
+		if strings.IndexByte("0123456789abcdefABCDEF", b) >= 0 {
+			digits = append(digits, b)
+		}
+	}
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	out := make([]byte, len(digits)/2)
+	_, err := hex.Decode(out, digits)
+	return out, err
+}
+
+func decodeASCII85(data []byte) ([]byte, error) {
+	if i := bytes.Index(data, []byte("~>")); i >= 0 {
+		data = data[:i]
+	}
+	out := make([]byte, 4*len(data))
+	n, _, err := ascii85.Decode(out, data, true)
+	return out[:n], err
+}
+
+// sampledImage builds an image from raw samples described by the image dictionary
+func sampledImage(data []byte, stream pdf.Value) (image.Image, error) {
+	width := int(stream.Key("Width").Int64())
+	height := int(stream.Key("Height").Int64())
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("invalid image size %dx%d", width, height)
+	}
+
+	bpc := intOr(stream.Key("BitsPerComponent"), 8)
+	if stream.Key("ImageMask").Bool() {
+		bpc = 1
+	}
+
+	space, err := parseColorSpace(stream.Key("ColorSpace"), stream.Key("ImageMask").Bool())
+	if err != nil {
+		return nil, err
+	}
+
+	samples := newSampleReader(data, bpc, width*space.components)
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		samples.startRow(y)
+		for x := 0; x < width; x++ {
+			img.Set(x, y, space.color(samples, bpc))
+		}
+	}
+
+	return img, nil
+}
+
+// colorSpace converts image samples into colors
+type colorSpace struct {
+	components int
+	color      func(samples *sampleReader, bpc int) color.Color
+}
+
+func parseColorSpace(cs pdf.Value, mask bool) (colorSpace, error) {
+	if mask {
+		return colorSpace{1, func(s *sampleReader, bpc int) color.Color {
+			if s.next() == 0 {
+				return color.Black
+			}
+			return color.Transparent
+		}}, nil
+	}
+
+	name := cs.Name()
+	if cs.Kind() == pdf.Array {
+		name = cs.Index(0).Name()
+	}
+
+	switch name {
+	case "", "DeviceGray", "CalGray", "G":
+		return grayColorSpace(), nil
+	case "DeviceRGB", "CalRGB", "RGB":
+		return rgbColorSpace(), nil
+	case "DeviceCMYK", "CMYK":
+		return cmykColorSpace(), nil
+	case "ICCBased":
+		switch cs.Index(1).Key("N").Int64() {
+		case 1:
+			return grayColorSpace(), nil
+		case 4:
+			return cmykColorSpace(), nil
+		default:
+			return rgbColorSpace(), nil
+		}
+	case "Indexed", "I":
+		return parseIndexedColorSpace(cs)
+	}
+
+	return colorSpace{}, fmt.Errorf("unsupported color space %s", name)
+}
+
+func grayColorSpace() colorSpace {
+	return colorSpace{1, func(s *sampleReader, bpc int) color.Color {
+		return color.Gray{scaleSample(s.next(), bpc)}
+	}}
+}
+
+func rgbColorSpace() colorSpace {
+	return colorSpace{3, func(s *sampleReader, bpc int) color.Color {
+		return color.RGBA{scaleSample(s.next(), bpc), scaleSample(s.next(), bpc), scaleSample(s.next(), bpc), 0xff}
+	}}
+}
+
+func cmykColorSpace() colorSpace {
+	return colorSpace{4, func(s *sampleReader, bpc int) color.Color {
+		return color.CMYK{scaleSample(s.next(), bpc), scaleSample(s.next(), bpc), scaleSample(s.next(), bpc), scaleSample(s.next(), bpc)}
+	}}
+}
+
+func parseIndexedColorSpace(cs pdf.Value) (colorSpace, error) {
+	base, err := parseColorSpace(cs.Index(1), false)
+	if err != nil {
+		return colorSpace{}, err
+	}
+
+	lookup := cs.Index(3)
+	var table []byte
+	if lookup.Kind() == pdf.Stream {
+		table, err = io.ReadAll(lookup.Reader())
+		if err != nil {
+			return colorSpace{}, fmt.Errorf("failed to read color table: %v", err)
+		}
+	} else {
+		table = []byte(lookup.RawString())
+	}
+
+	hival := int(cs.Index(2).Int64())
+	palette := make([]color.Color, hival+1)
+	for i := range palette {
+		entry := make([]byte, base.components)
+		if start := i * base.components; start+base.components <= len(table) {
+			copy(entry, table[start:])
+		}
+		palette[i] = base.color(newSampleReader(entry, 8, base.components), 8)
+	}
+
+	return colorSpace{1, func(s *sampleReader, bpc int) color.Color {
+		index := int(s.next())
+		if index >= len(palette) {
+			return color.Black
+		}
+		return palette[index]
+	}}, nil
+}
+
+// sampleReader reads packed samples of 1 to 16 bits, with rows padded to whole bytes
+type sampleReader struct {
+	data    []byte
+	bpc     int
+	rowBits int
+	pos     int
+}
+
+func newSampleReader(data []byte, bpc, samplesPerRow int) *sampleReader {
+	rowBytes := (samplesPerRow*bpc + 7) / 8
+	return &sampleReader{data: data, bpc: bpc, rowBits: rowBytes * 8}
+}
+
+func (s *sampleReader) startRow(y int) {
+	s.pos = y * s.rowBits
+}
+
+func (s *sampleReader) next() uint16 {
+	var v uint16
+	for i := 0; i < s.bpc; i++ {
+		v <<= 1
+		if b := s.pos / 8; b < len(s.data) && s.data[b]&(0x80>>uint(s.pos%8)) != 0 {
+			v |= 1
+		}
+		s.pos++
+	}
+	return v
+}
+
+// scaleSample maps a sample of the given bit depth onto 0-255
+func scaleSample(v uint16, bpc int) uint8 {
+	switch bpc {
+	case 8:
+		return uint8(v)
+	case 16:
+		return uint8(v >> 8)
+	default:
+		return uint8(uint32(v) * 255 / (1<<uint(bpc) - 1))
+	}
+}
+
+func intOr(v pdf.Value, fallback int) int {
+	if v.Kind() != pdf.Integer {
+		return fallback
+	}
+	return int(v.Int64())
+}
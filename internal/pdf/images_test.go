@@ -0,0 +1,201 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/ascii85"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/errs"
+)
+
+// imageDocument builds a page drawing one image XObject with data and extra
+// entries of its dictionary
+func imageDocument(data, dict string) *testPDF {
+	p := &testPDF{}
+	img := p.stream(data, "/Type /XObject /Subtype /Image "+dict)
+	p.pageWith(showText(72, 700, "Figure", "F1", 12)+"q 200 0 0 100 72 400 cm /Im1 Do Q\n", "", fmt.Sprintf("/XObject << /Im1 %d 0 R >>", img))
+	return p
+}
+
+// extractedImages converts a document and returns the files written to the
+// assets directory
+func extractedImages(t *testing.T, c *Converter, p *testPDF) map[string][]byte {
+	t.Helper()
+	c.AssetsDir = t.TempDir()
+	convert(t, c, p)
+	entries, err := os.ReadDir(c.AssetsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string][]byte)
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(c.AssetsDir, entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[entry.Name()] = data
+	}
+	return files
+}
+
+func deflate(data []byte) string {
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	w.Write(data)
+	w.Close()
+	return buf.String()
+}
+
+func testJPEG(t *testing.T) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	for x := 0; x < 4; x++ {
+		img.Set(x, 0, color.RGBA{200, 10, 10, 255})
+		img.Set(x, 1, color.RGBA{10, 10, 200, 255})
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestImageFormat(t *testing.T) {
+	jpg := testJPEG(t)
+	rgb := string(bytes.Repeat([]byte{255, 0, 0}, 4*2))
+	tests := []struct {
+		name   string
+		format string
+		data   string
+		dict   string
+		want   string
+	}{
+		{"raw samples kept as png", ImageFormatOriginal, rgb, "/Width 4 /Height 2 /ColorSpace /DeviceRGB /BitsPerComponent 8", "png"},
+		{"flate samples to png", ImageFormatPNG, deflate([]byte(rgb)), "/Width 4 /Height 2 /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /FlateDecode", "png"},
+		{"raw samples to jpeg", ImageFormatJPEG, rgb, "/Width 4 /Height 2 /ColorSpace /DeviceRGB /BitsPerComponent 8", "jpg"},
+		{"jpeg kept", ImageFormatOriginal, string(jpg), "/Width 4 /Height 2 /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /DCTDecode", "jpg"},
+		{"jpeg to png", ImageFormatPNG, string(jpg), "/Width 4 /Height 2 /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /DCTDecode", "png"},
+		{"cmyk samples to png", ImageFormatPNG, string(bytes.Repeat([]byte{0, 255, 255, 0}, 4*2)), "/Width 4 /Height 2 /ColorSpace /DeviceCMYK /BitsPerComponent 8", "png"},
+		{"gray 1 bit", ImageFormatPNG, "\xa0\x50", "/Width 4 /Height 2 /ColorSpace /DeviceGray /BitsPerComponent 1", "png"},
+		{"ascii hex", ImageFormatPNG, "ff00ff00 ff00ff00>", "/Width 4 /Height 2 /ColorSpace /DeviceGray /BitsPerComponent 8 /Filter /ASCIIHexDecode", "png"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := extractedImages(t, &Converter{ImageFormat: tt.format}, imageDocument(tt.data, tt.dict))
+			if len(files) != 1 {
+				t.Fatalf("extracted %d images, want 1", len(files))
+			}
+			for name, data := range files {
+				if ext := strings.TrimPrefix(filepath.Ext(name), "."); ext != tt.want {
+					t.Errorf("extracted %s, want a .%s file", name, tt.want)
+				}
+				_, format, err := image.DecodeConfig(bytes.NewReader(data))
+				if err != nil {
+					t.Fatalf("cannot decode %s: %v", name, err)
+				}
+				if want := map[string]string{"png": "png", "jpg": "jpeg"}[tt.want]; format != want {
+					t.Errorf("%s holds %s data", name, format)
+				}
+			}
+		})
+	}
+}
+
+func TestImageFormatNone(t *testing.T) {
+	p := imageDocument("\x00", "/Width 1 /Height 1 /ColorSpace /DeviceGray /BitsPerComponent 8")
+	c := &Converter{ImageFormat: ImageFormatNone}
+	if files := extractedImages(t, c, p); len(files) != 0 {
+		t.Errorf("extracted %d images, want none", len(files))
+	}
+}
+
+func TestImageFormatJPXUnsupported(t *testing.T) {
+	p := imageDocument("\x00\x00\x00\x0cjP  ", "/Width 1 /Height 1 /Filter /JPXDecode")
+	data := p.bytes()
+	var out bytes.Buffer
+	err := (&Converter{AssetsDir: t.TempDir(), ImageFormat: ImageFormatPNG}).ToMarkdownFromReader(bytes.NewReader(data), int64(len(data)), "test.pdf", &out)
+	if !errors.Is(err, errs.ErrUnsupportedType) {
+		t.Errorf("error = %v, want ErrUnsupportedType", err)
+	}
+}
+
+func TestSampledImageColors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		dict string
+		want color.RGBA
+	}{
+		{"gray", "\x80", "/ColorSpace /DeviceGray /BitsPerComponent 8", color.RGBA{128, 128, 128, 255}},
+		{"rgb", "\x10\x20\x30", "/ColorSpace /DeviceRGB /BitsPerComponent 8", color.RGBA{16, 32, 48, 255}},
+		{"cmyk", "\x00\xff\xff\x00", "/ColorSpace /DeviceCMYK /BitsPerComponent 8", color.RGBA{255, 0, 0, 255}},
+		{"indexed", "\x01", "/ColorSpace [/Indexed /DeviceRGB 1 <000000ff8000>] /BitsPerComponent 8", color.RGBA{255, 128, 0, 255}},
+		{"4 bit gray", "\xf0", "/ColorSpace /DeviceGray /BitsPerComponent 4", color.RGBA{255, 255, 255, 255}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := extractedImages(t, &Converter{ImageFormat: ImageFormatPNG}, imageDocument(tt.data, "/Width 1 /Height 1 "+tt.dict))
+			for name, data := range files {
+				img, err := png.Decode(bytes.NewReader(data))
+				if err != nil {
+					t.Fatalf("cannot decode %s: %v", name, err)
+				}
+				if got := color.RGBAModel.Convert(img.At(0, 0)).(color.RGBA); got != tt.want {
+					t.Errorf("pixel = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestUnpredictPNG(t *testing.T) {
+	// Two rows of three one-byte pixels, each row prefixed by its filter type
+	tests := []struct {
+		name string
+		data []byte
+		want []byte
+	}{
+		{"none", []byte{0, 1, 2, 3, 0, 4, 5, 6}, []byte{1, 2, 3, 4, 5, 6}},
+		{"sub", []byte{1, 1, 1, 1, 1, 4, 1, 1}, []byte{1, 2, 3, 4, 5, 6}},
+		{"up", []byte{0, 1, 2, 3, 2, 3, 3, 3}, []byte{1, 2, 3, 4, 5, 6}},
+		{"average", []byte{0, 2, 4, 6, 3, 3, 1, 0}, []byte{2, 4, 6, 4, 5, 5}},
+		{"paeth", []byte{0, 1, 2, 3, 4, 3, 1, 1}, []byte{1, 2, 3, 4, 5, 6}},
+	}
+	for _, tt := range tests {
+		got, err := unpredictPNG(tt.data, 3, 1)
+		if err != nil || !bytes.Equal(got, tt.want) {
+			t.Errorf("%s: unpredictPNG() = %v, %v, want %v", tt.name, got, err, tt.want)
+		}
+	}
+	if _, err := unpredictPNG([]byte{9, 1, 2, 3}, 3, 1); err == nil {
+		t.Error("unpredictPNG() with filter type 9: error = nil")
+	}
+}
+
+func TestDecodeASCII(t *testing.T) {
+	got, err := decodeASCIIHex([]byte("48 65 6c\n6C 6f>"))
+	if err != nil || string(got) != "Hello" {
+		t.Errorf("decodeASCIIHex() = %q, %v", got, err)
+	}
+	got, err = decodeASCIIHex([]byte("4>"))
+	if err != nil || !bytes.Equal(got, []byte{0x40}) {
+		t.Errorf("decodeASCIIHex(odd) = %v, %v", got, err)
+	}
+
+	encoded := make([]byte, ascii85.MaxEncodedLen(5))
+	encoded = encoded[:ascii85.Encode(encoded, []byte("Hello"))]
+	got, err = decodeASCII85(append(encoded, "~>"...))
+	if err != nil || string(got) != "Hello" {
+		t.Errorf("decodeASCII85() = %q, %v", got, err)
+	}
+}
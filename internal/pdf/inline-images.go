@@ -0,0 +1,37 @@
+package pdf
+
+import "math"
+
+// inlineImageScale is how much taller than the surrounding text an image may be
+// to still be treated as part of the line, such as an icon or emoji
+const inlineImageScale = 2.0
+
+// inlineAnchor finds the text element an image sits beside on the same line.
+// Images that are small relative to that text and overlap its line box are
+// rendered inline instead of as a block of their own.
+func inlineAnchor(elements []TextElement, img pageImage) (TextElement, bool) {
+	best := -1
+	bestGap := math.Inf(1)
+	for i, e := range elements {
+		if img.Height > e.Size*inlineImageScale || img.Width > e.Size*inlineImageScale*2 {
+			continue
+		}
+		// The image must overlap the line box between the descender and the cap height
+		if img.Y >= e.Y+e.Size || img.Y+img.Height <= e.Y-e.Size*0.25 {
+			continue
+		}
+
+		gap := math.Max(e.X-(img.X+img.Width), img.X-(e.X+e.Width))
+		if gap > e.Size*2 {
+			continue
+		}
+		if gap < bestGap {
+			best = i
+			bestGap = gap
+		}
+	}
+	if best < 0 {
+		return TextElement{}, false
+	}
+	return elements[best], true
+}
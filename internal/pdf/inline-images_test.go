@@ -0,0 +1,54 @@
+package pdf
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestInlineImages(t *testing.T) {
+	p := &testPDF{}
+	img := p.stream("\x80", "/Type /XObject /Subtype /Image /Width 1 /Height 1 /ColorSpace /DeviceGray /BitsPerComponent 8")
+	content := showText(72, 700, "Press ", "F1", 12) + "q 12 0 0 12 108 698 cm /Im1 Do Q\n" + showText(122, 700, " to save.", "F1", 12) +
+		"q 200 0 0 100 72 500 cm /Im1 Do Q\n" + showText(72, 480, "Below the figure.", "F1", 12)
+	p.pageWith(content, "", fmt.Sprintf("/XObject << /Im1 %d 0 R >>", img))
+
+	got := convert(t, &Converter{AssetsDir: t.TempDir(), AssetsLink: "assets", ImageFormat: ImageFormatPNG}, p)
+	lines := strings.Split(strings.TrimSpace(got), "\n\n")
+	patterns := []string{
+		`^Press !\[\]\(assets/[^)]+\.png\) to save\.$`,
+		`^!\[\]\(assets/[^)]+\.png\)$`,
+		`^Below the figure\.$`,
+	}
+	if len(lines) != len(patterns) {
+		t.Fatalf("got %d blocks, want %d:\n%s", len(lines), len(patterns), got)
+	}
+	for i, pattern := range patterns {
+		if !regexp.MustCompile(pattern).MatchString(lines[i]) {
+			t.Errorf("block %d = %q, want a match of %s", i+1, lines[i], pattern)
+		}
+	}
+}
+
+func TestInlineAnchor(t *testing.T) {
+	word := TextElement{Text: "word", X: 100, Y: 700, Width: 24, Size: 12}
+	tests := []struct {
+		name string
+		img  pageImage
+		want bool
+	}{
+		{"icon after the word", pageImage{X: 126, Y: 698, Width: 12, Height: 12}, true},
+		{"icon before the word", pageImage{X: 86, Y: 699, Width: 12, Height: 10}, true},
+		{"too tall", pageImage{X: 126, Y: 690, Width: 12, Height: 30}, false},
+		{"too wide", pageImage{X: 126, Y: 698, Width: 60, Height: 12}, false},
+		{"below the line", pageImage{X: 126, Y: 670, Width: 12, Height: 12}, false},
+		{"far along the line", pageImage{X: 160, Y: 698, Width: 12, Height: 12}, false},
+	}
+	for _, tt := range tests {
+		anchor, ok := inlineAnchor([]TextElement{word}, tt.img)
+		if ok != tt.want || ok && anchor.Text != word.Text {
+			t.Errorf("%s: inlineAnchor() = %+v, %v, want %v", tt.name, anchor, ok, tt.want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package pdf
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// kvGapScale is the gap between label and value, relative to the font size,
+	// that separates the two columns of a fact sheet line
+	kvGapScale = 2.0
+	// kvMaxLabelLength keeps sentences that happen to be followed by a gap from being labels
+	kvMaxLabelLength = 40
+)
+
+// splitKeyValue splits a line into a label and a value when it consists of
+// exactly two columns separated by a wide gap, such as "Invoice date   12 May".
+// Lines with more columns are left to table detection.
+func splitKeyValue(line TextLine) (TextLine, TextLine, bool) {
+	split := -1
+	for i := 1; i < len(line.Elements); i++ {
+		prev := line.Elements[i-1]
+		gap := line.Elements[i].X - prev.X - prev.Width
+		if gap < line.FontSize*kvGapScale {
+			continue
+		}
+		if split >= 0 {
+			return TextLine{}, TextLine{}, false
+		}
+		split = i
+	}
+	if split < 0 {
+		return TextLine{}, TextLine{}, false
+	}
+
+	label, value := line, line
+	label.Elements = line.Elements[:split]
+	value.Elements = line.Elements[split:]
+
+	var text strings.Builder
+	for _, element := range label.Elements {
+		text.WriteString(element.Text)
+	}
+	labelText := strings.TrimSpace(text.String())
+	if labelText == "" || utf8.RuneCountInString(labelText) > kvMaxLabelLength || strings.ContainsAny(labelText[len(labelText)-1:], ".!?") {
+		return TextLine{}, TextLine{}, false
+	}
+	return label, value, true
+}
+
+// renderKeyValue renders a label/value pair as "**Label:** value"
+func (c *Converter) renderKeyValue(label, value TextLine) string {
+	labelText := strings.TrimSuffix(strings.TrimSpace(c.extractLineText(label)), ":")
+	return "**" + strings.TrimSpace(labelText) + ":** " + strings.TrimSpace(c.extractLineText(value))
+}
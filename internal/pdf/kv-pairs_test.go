@@ -0,0 +1,64 @@
+package pdf
+
+import (
+	"strings"
+	"testing"
+)
+
+// factSheet shows label and value columns, one pair per line
+func factSheet(pairs ...[2]string) string {
+	var b strings.Builder
+	for i, pair := range pairs {
+		y := 700 - float64(i)*18
+		b.WriteString(showText(72, y, pair[0], "F2", 12) + showText(250, y, pair[1], "F1", 12))
+	}
+	return b.String()
+}
+
+func TestKeyValuePairs(t *testing.T) {
+	sheet := factSheet([2]string{"Invoice number", "INV-0042"}, [2]string{"Invoice date:", "12 May 2024"}, [2]string{"Total", "120.00 EUR"})
+	tests := []struct {
+		name string
+		kv   bool
+		want string
+	}{
+		{"pairs", true, "**Invoice number:** INV-0042\n\n**Invoice date:** 12 May 2024\n\n**Total:** 120.00 EUR"},
+		{"table without the flag", false, "| Invoice number | INV-0042 |"},
+	}
+	for _, tt := range tests {
+		p := &testPDF{}
+		p.page(sheet)
+		got := strings.TrimSpace(convert(t, &Converter{KVPairs: tt.kv}, p))
+		if !strings.Contains(got, tt.want) {
+			t.Errorf("%s: output lacks %q:\n%s", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestSplitKeyValue(t *testing.T) {
+	// line sets texts 30 points wide with a gap between them
+	line := func(gap float64, texts ...string) TextLine {
+		var elements []TextElement
+		for i, text := range texts {
+			elements = append(elements, TextElement{Text: text, X: 72 + float64(i)*(30+gap), Width: 30, Size: 12})
+		}
+		return TextLine{Elements: elements, FontSize: 12}
+	}
+	tests := []struct {
+		name string
+		line TextLine
+		want bool
+	}{
+		{"two columns", line(150, "Total", "120.00"), true},
+		{"narrow gap", line(10, "Total", "120.00"), false},
+		{"three columns", line(150, "Total", "120.00", "EUR"), false},
+		{"sentence label", line(150, "It ended.", "Next"), false},
+		{"long label", line(150, strings.Repeat("x", 41), "Value"), false},
+		{"one column", line(150, "Total"), false},
+	}
+	for _, tt := range tests {
+		if _, _, ok := splitKeyValue(tt.line); ok != tt.want {
+			t.Errorf("%s: splitKeyValue() = %v, want %v", tt.name, ok, tt.want)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package pdf
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/errs"
+	"github.com/rsc/pdf"
+)
+
+// LayersAll selects every optional content group regardless of its default state
+const LayersAll = "all"
+
+// Layer is an optional content group of a PDF
+type Layer struct {
+	Name string
+	// Visible is the default visibility of the layer
+	Visible bool
+}
+
+// layerSet records which optional content groups are shown
+type layerSet struct {
+	visible map[string]bool
+}
+
+// readLayers returns the layers declared in the document catalog with their
+// default visibility
+func readLayers(reader *pdf.Reader) ([]Layer, map[string]bool) {
+	props := reader.Trailer().Key("Root").Key("OCProperties")
+	groups := props.Key("OCGs")
+	if groups.Kind() != pdf.Array {
+		return nil, nil
+	}
+
+	config := props.Key("D")
+	off := make(map[string]bool)
+	baseOn := config.Key("BaseState").Name() != "OFF"
+	if baseOn {
+		for _, ocg := range arrayValues(config.Key("OFF")) {
+			off[ocg.String()] = true
+		}
+	} else {
+		on := make(map[string]bool)
+		for _, ocg := range arrayValues(config.Key("ON")) {
+			on[ocg.String()] = true
+		}
+		for _, ocg := range arrayValues(groups) {
+			off[ocg.String()] = !on[ocg.String()]
+		}
+	}
+
+	var layers []Layer
+	visible := make(map[string]bool)
+	for _, ocg := range arrayValues(groups) {
+		v := !off[ocg.String()]
+		visible[ocg.String()] = v
+		layers = append(layers, Layer{Name: ocg.Key("Name").Text(), Visible: v})
+	}
+	return layers, visible
+}
+
+// newLayerSet resolves the visible layers of a document. With no selection the
+// document's default state applies; otherwise only the named layers are shown.
+func newLayerSet(reader *pdf.Reader, selected []string) *layerSet {
+	_, visible := readLayers(reader)
+	if visible == nil {
+		return nil
+	}
+
+	if len(selected) > 0 {
+		names := make(map[string]bool)
+		for _, name := range selected {
+			names[strings.ToLower(strings.TrimSpace(name))] = true
+		}
+		groups := reader.Trailer().Key("Root").Key("OCProperties").Key("OCGs")
+		for _, ocg := range arrayValues(groups) {
+			visible[ocg.String()] = names[LayersAll] || names[strings.ToLower(ocg.Key("Name").Text())]
+		}
+	}
+
+	return &layerSet{visible: visible}
+}
+
+// isVisible reports whether content governed by an optional content group or
+// membership dictionary is shown
+func (ls *layerSet) isVisible(oc pdf.Value) bool {
+	if ls == nil || oc.Kind() != pdf.Dict {
+		return true
+	}
+
+	if oc.Key("Type").Name() != "OCMD" {
+		v, ok := ls.visible[oc.String()]
+		return v || !ok
+	}
+
+	// Membership dictionaries combine several groups under a visibility policy
+	ocgs := arrayValues(oc.Key("OCGs"))
+	if oc.Key("OCGs").Kind() == pdf.Dict {
+		ocgs = []pdf.Value{oc.Key("OCGs")}
+	}
+	if len(ocgs) == 0 {
+		return true
+	}
+	on := 0
+	for _, ocg := range ocgs {
+		if ls.isVisible(ocg) {
+			on++
+		}
+	}
+	switch oc.Key("P").Name() {
+	case "AllOn":
+		return on == len(ocgs)
+	case "AnyOff":
+		return on < len(ocgs)
+	case "AllOff":
+		return on == 0
+	default: // AnyOn
+		return on > 0
+	}
+}
+
+// ListLayers returns the optional content groups of a PDF file
+func ListLayers(inputPath string) (layers []Layer, err error) {
+	// The PDF library panics on malformed objects
+	defer func() {
+		if r := recover(); r != nil {
+			layers, err = nil, fmt.Errorf("%w: %v", errs.ErrCorrupt, r)
+		}
+	}()
+
+	f, err := os.Open(inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PDF: %v", err)
+	}
+	defer f.Close()
+
+	reader, err := openReader(f)
+	if err != nil {
+		return nil, err
+	}
+
+	layers, _ = readLayers(reader)
+	return layers, nil
+}
+
+func arrayValues(v pdf.Value) []pdf.Value {
+	if v.Kind() != pdf.Array {
+		return nil
+	}
+	values := make([]pdf.Value, v.Len())
+	for i := range values {
+		values[i] = v.Index(i)
+	}
+	return values
+}
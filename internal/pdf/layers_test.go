@@ -0,0 +1,88 @@
+package pdf
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// layeredPDF builds a page with body text, a watermark in a layer that is
+// off by default and notes in a layer that is on
+func layeredPDF() *testPDF {
+	p := &testPDF{}
+	watermark := p.add("<< /Type /OCG /Name (Watermark) >>")
+	notes := p.add("<< /Type /OCG /Name (Notes) >>")
+	p.catalog = fmt.Sprintf("/OCProperties << /OCGs [%d 0 R %d 0 R] /D << /OFF [%d 0 R] >> >>", watermark, notes, watermark)
+	content := body("Body text.") +
+		"/OC /MC0 BDC\n" + showText(72, 600, "DRAFT", "F1", 12) + "EMC\n" +
+		"/OC /MC1 BDC\n" + showText(72, 560, "A note.", "F1", 12) + "EMC\n"
+	p.pageWith(content, "", fmt.Sprintf("/Properties << /MC0 %d 0 R /MC1 %d 0 R >>", watermark, notes))
+	return p
+}
+
+func TestLayers(t *testing.T) {
+	tests := []struct {
+		name   string
+		layers []string
+		want   []string
+		not    []string
+	}{
+		{"default state", nil, []string{"Body text.", "A note."}, []string{"DRAFT"}},
+		{"selected", []string{" watermark"}, []string{"Body text.", "DRAFT"}, []string{"A note."}},
+		{"all", []string{LayersAll}, []string{"Body text.", "DRAFT", "A note."}, nil},
+	}
+	for _, tt := range tests {
+		got := convert(t, &Converter{Layers: tt.layers}, layeredPDF())
+		for _, want := range tt.want {
+			if !strings.Contains(got, want) {
+				t.Errorf("%s: output lacks %q:\n%s", tt.name, want, got)
+			}
+		}
+		for _, not := range tt.not {
+			if strings.Contains(got, not) {
+				t.Errorf("%s: output holds %q:\n%s", tt.name, not, got)
+			}
+		}
+	}
+}
+
+func TestLayerMembership(t *testing.T) {
+	tests := []struct {
+		policy string
+		want   bool
+	}{
+		{"", true},
+		{"/P /AnyOn", true},
+		{"/P /AllOn", false},
+		{"/P /AnyOff", true},
+		{"/P /AllOff", false},
+	}
+	for _, tt := range tests {
+		p := layeredPDF()
+		md := p.add(fmt.Sprintf("<< /Type /OCMD /OCGs [1 0 R 2 0 R] %s >>", tt.policy))
+		p.pages[0].extra = fmt.Sprintf("/Membership %d 0 R", md)
+		reader := openTestPDF(t, p)
+		layers := newLayerSet(reader, nil)
+		if got := layers.isVisible(reader.Page(1).V.Key("Membership")); got != tt.want {
+			t.Errorf("isVisible(%q) = %v, want %v", tt.policy, got, tt.want)
+		}
+	}
+}
+
+func TestListLayers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "layers.pdf")
+	if err := os.WriteFile(path, layeredPDF().bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	layers, err := ListLayers(path)
+	if err != nil {
+		t.Fatalf("ListLayers() error = %v", err)
+	}
+	want := []Layer{{Name: "Watermark"}, {Name: "Notes", Visible: true}}
+	if !reflect.DeepEqual(layers, want) {
+		t.Errorf("ListLayers() = %+v, want %+v", layers, want)
+	}
+}
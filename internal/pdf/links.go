@@ -0,0 +1,239 @@
+package pdf
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+	"github.com/rsc/pdf"
+)
+
+// Internal links are written as placeholders while pages are extracted, since
+// the heading a link points to may be on a page that is not converted yet.
+// The placeholder is linkOpen, the link ID, linkMid, the text, then linkClose.
+const (
+	linkOpen  = "\uE000"
+	linkMid   = "\uE001"
+	linkClose = "\uE002"
+	// linkTolerance is how far, in points, a heading may reach above the position
+	// a link scrolls to and still be its target
+	linkTolerance = 4.0
+)
+
+var (
+	linkPlaceholder = regexp.MustCompile(`\x{E000}([^\x{E001}]*)\x{E001}(.*?)\x{E002}`)
+	// htmlTag strips inline HTML from heading text before it is turned into a slug
+	htmlTag = regexp.MustCompile(`<[^>]+>`)
+)
+
+// destination is the target of an internal link
+type destination struct {
+	// Page is the 1-based number of the target page
+	Page int
+	// Top is the vertical position scrolled to, if the destination sets one
+	Top    float64
+	HasTop bool
+}
+
+// pageLink is a GoTo link annotation of a page
+type pageLink struct {
+	Area   rect
+	Target destination
+}
+
+// pageHeading is a heading written on a page, recorded so links can point at it
+type pageHeading struct {
+	Top  float64
+	Text string
+	Slug string
+}
+
+// linkIndex holds the internal links and headings of a document. Pages write
+// only their own entries, so concurrent page extraction needs no locking.
+type linkIndex struct {
+	once sync.Once
+	// pages maps page objects to their page numbers
+	pages    map[string]int
+	links    [][]destination
+	headings [][]pageHeading
+}
+
+func newLinkIndex(numPages int) *linkIndex {
+	return &linkIndex{links: make([][]destination, numPages), headings: make([][]pageHeading, numPages)}
+}
+
+// pageNumber returns the number of a page object, scanning the page tree once
+func (li *linkIndex) pageNumber(reader *pdf.Reader, page pdf.Value) int {
+	li.once.Do(func() {
+		li.pages = make(map[string]int)
+		for i := 1; i <= reader.NumPage(); i++ {
+			li.pages[reader.Page(i).V.String()] = i
+		}
+	})
+	return li.pages[page.String()]
+}
+
+// readLinks returns the link annotations of a page that go to a destination
+// within the document, either directly or through a GoTo action
+func readLinks(doc *document, page pdf.Page) []pageLink {
+	var links []pageLink
+	annots := page.V.Key("Annots")
+	for i := 0; i < annots.Len(); i++ {
+		annot := annots.Index(i)
+		if annot.Key("Subtype").Name() != "Link" {
+			continue
+		}
+		dest := annot.Key("Dest")
+		if dest.IsNull() {
+			action := annot.Key("A")
+			if action.Key("S").Name() != "GoTo" {
+				continue
+			}
+			dest = action.Key("D")
+		}
+		area, ok := rectValue(annot.Key("Rect"))
+		if !ok {
+			continue
+		}
+		if target, ok := resolveDestination(doc, dest); ok {
+			links = append(links, pageLink{Area: area, Target: target})
+		}
+	}
+	return links
+}
+
+// resolveDestination finds the page and position of an explicit destination
+// array, or of a named destination looked up in the catalog
+func resolveDestination(doc *document, dest pdf.Value) (destination, bool) {
+	if dest.Kind() == pdf.String || dest.Kind() == pdf.Name {
+		name := dest.RawString()
+		if dest.Kind() == pdf.Name {
+			name = dest.Name()
+		}
+		root := doc.reader.Trailer().Key("Root")
+		dest = root.Key("Dests").Key(name)
+		if dest.IsNull() {
+			dest = lookupNameTree(root.Key("Names").Key("Dests"), name)
+		}
+	}
+	if dest.Kind() == pdf.Dict {
+		dest = dest.Key("D")
+	}
+	if dest.Kind() != pdf.Array || dest.Len() < 2 {
+		return destination{}, false
+	}
+
+	var target destination
+	if page := dest.Index(0); page.Kind() == pdf.Integer {
+		target.Page = int(page.Int64()) + 1
+	} else {
+		target.Page = doc.links.pageNumber(doc.reader, page)
+	}
+	if target.Page < 1 || target.Page > doc.reader.NumPage() {
+		return destination{}, false
+	}
+
+	top := pdf.Value{}
+	switch dest.Index(1).Name() {
+	case "XYZ":
+		top = dest.Index(3)
+	case "FitH", "FitBH":
+		top = dest.Index(2)
+	}
+	if top.Kind() == pdf.Integer || top.Kind() == pdf.Real {
+		target.Top, target.HasTop = top.Float64(), true
+	}
+	return target, true
+}
+
+// lookupNameTree finds a key in a name tree, descending through its kids
+func lookupNameTree(tree pdf.Value, name string) pdf.Value {
+	names := tree.Key("Names")
+	for i := 0; i+1 < names.Len(); i += 2 {
+		if names.Index(i).RawString() == name {
+			return names.Index(i + 1)
+		}
+	}
+	kids := tree.Key("Kids")
+	for i := 0; i < kids.Len(); i++ {
+		kid := kids.Index(i)
+		if limits := kid.Key("Limits"); limits.Len() == 2 {
+			if name < limits.Index(0).RawString() || name > limits.Index(1).RawString() {
+				continue
+			}
+		}
+		if v := lookupNameTree(kid, name); !v.IsNull() {
+			return v
+		}
+	}
+	return pdf.Value{}
+}
+
+// markLinks records the links of a page and tags every element whose glyph
+// center a link covers with that link's placeholder ID
+func markLinks(doc *document, pageNum int, elements []TextElement, links []pageLink) {
+	for i, link := range links {
+		doc.links.links[pageNum-1] = append(doc.links.links[pageNum-1], link.Target)
+		id := strconv.Itoa(pageNum) + ":" + strconv.Itoa(i)
+		for j := range elements {
+			e := &elements[j]
+			if link.Area.contains(e.X+e.Width/2, e.Y+e.Size/3) {
+				e.Link = id
+			}
+		}
+	}
+}
+
+// resolveLinks replaces link placeholders with links to the heading at the
+// destination: the first heading on the target page at or below the position
+// scrolled to. Links without such a heading are left as plain text.
+func resolveLinks(doc *document, body string) string {
+	// Slugs are made unique the way GitHub does, numbering repeats
+	seen := make(map[string]int)
+	for _, headings := range doc.links.headings {
+		for i := range headings {
+			slug := markdown.Slug(headings[i].Text)
+			if n := seen[slug]; n > 0 {
+				headings[i].Slug = slug + "-" + strconv.Itoa(n)
+			} else {
+				headings[i].Slug = slug
+			}
+			seen[slug]++
+		}
+	}
+
+	return linkPlaceholder.ReplaceAllStringFunc(body, func(match string) string {
+		m := linkPlaceholder.FindStringSubmatch(match)
+		text := m[2]
+		var page, index int
+		if parts := strings.SplitN(m[1], ":", 2); len(parts) == 2 {
+			page, _ = strconv.Atoi(parts[0])
+			index, _ = strconv.Atoi(parts[1])
+		}
+		if page < 1 || page > len(doc.links.links) || index >= len(doc.links.links[page-1]) {
+			return text
+		}
+
+		target := doc.links.links[page-1][index]
+		for _, h := range doc.links.headings[target.Page-1] {
+			if !target.HasTop || h.Top <= target.Top+linkTolerance {
+				return "[" + text + "](#" + h.Slug + ")"
+			}
+		}
+		return text
+	})
+}
+
+// linkText wraps the text of a linked run in a placeholder
+func linkText(text, id string) string {
+	return markdown.Wrap(text, linkOpen+id+linkMid, linkClose)
+}
+
+// plainHeading returns the text of a rendered heading without inline markup
+// or link placeholders, as the basis of its slug
+func plainHeading(text string) string {
+	text = linkPlaceholder.ReplaceAllString(text, "$2")
+	return strings.TrimSpace(htmlTag.ReplaceAllString(text, ""))
+}
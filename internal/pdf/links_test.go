@@ -0,0 +1,60 @@
+package pdf
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// linkedPDF builds a document whose first page links "Results" to a
+// destination, and whose second page has the Results heading at the top and
+// a Details heading lower down. catalog holds extra catalog entries.
+func linkedPDF(dest, catalog string) *testPDF {
+	p := &testPDF{catalog: catalog}
+	annot := p.add(fmt.Sprintf("<< /Type /Annot /Subtype /Link /Rect [118 696 164 712] %s >>", dest))
+	p.pageWith(body("See the Results section."), fmt.Sprintf("/Annots [%d 0 R]", annot), "")
+	p.page(showText(72, 700, "Results", "F2", 24) + showText(72, 660, "The numbers.", "F1", 12) +
+		showText(72, 400, "Details", "F2", 24) + showText(72, 360, "More numbers.", "F1", 12))
+	return p
+}
+
+func TestAnchorLinks(t *testing.T) {
+	tests := []struct {
+		name    string
+		dest    string
+		catalog string
+		anchors bool
+		want    string
+	}{
+		{"explicit destination", "/Dest [PAGE2 /XYZ 0 720 0]", "", true, "See the [Results](#results) section."},
+		{"goto action", "/A << /S /GoTo /D [PAGE2 /FitH 420] >>", "", true, "See the [Results](#details) section."},
+		{"page index", "/Dest [1 /Fit]", "", true, "See the [Results](#results) section."},
+		{"named destination", "/Dest /results", "/Dests << /results [PAGE2 /XYZ 0 720 0] >>", true, "See the [Results](#results) section."},
+		{"name tree", "/Dest (results)", "/Names << /Dests << /Names [(results) [PAGE2 /XYZ 0 720 0]] >> >>", true, "See the [Results](#results) section."},
+		{"below every heading", "/Dest [PAGE2 /XYZ 0 100 0]", "", true, "See the Results section."},
+		{"missing page", "/Dest [7 /Fit]", "", true, "See the Results section."},
+		{"unknown name", "/Dest /nowhere", "", true, "See the Results section."},
+		{"off", "/Dest [PAGE2 /XYZ 0 720 0]", "", false, "See the Results section."},
+	}
+	for _, tt := range tests {
+		md := convert(t, &Converter{AssetsDir: t.TempDir(), AnchorLinks: tt.anchors}, linkedPDF(tt.dest, tt.catalog))
+		if !strings.Contains(md, tt.want+"\n") {
+			t.Errorf("%s: output lacks %q:\n%s", tt.name, tt.want, md)
+		}
+		if strings.ContainsAny(md, linkOpen+linkMid+linkClose) {
+			t.Errorf("%s: placeholders left in %q", tt.name, md)
+		}
+	}
+}
+
+func TestAnchorLinksRepeatedHeadings(t *testing.T) {
+	p := &testPDF{}
+	annot := p.add("<< /Type /Annot /Subtype /Link /Rect [118 696 164 712] /Dest [PAGE3 /XYZ 0 720 0] >>")
+	p.pageWith(body("See the Summary section."), fmt.Sprintf("/Annots [%d 0 R]", annot), "")
+	p.page(showText(72, 700, "Summary", "F2", 24) + body("", "", "", "First part."))
+	p.page(showText(72, 700, "Summary", "F2", 24) + body("", "", "", "Second part."))
+	md := convert(t, &Converter{AssetsDir: t.TempDir(), AnchorLinks: true}, p)
+	if want := "See the [Summary](#summary-1) section."; !strings.Contains(md, want) {
+		t.Errorf("output lacks %q:\n%s", want, md)
+	}
+}
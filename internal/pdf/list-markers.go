@@ -0,0 +1,114 @@
+package pdf
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// List detection levels accepted by Converter.ListDetection
+const (
+	// ListDetectionLow takes only symbol bullets, "*", "+" and numbers as
+	// list markers, so prose opened by a dash or a letter stays prose
+	ListDetectionLow = "low"
+	// ListDetectionMedium takes any bullet or ordinal followed by a space
+	ListDetectionMedium = "medium"
+	// ListDetectionHigh also takes dashes and numbers set against their
+	// text, as in "-item" or "1.Item"
+	ListDetectionHigh = "high"
+)
+
+// Characters that open a bulleted list item. Symbol bullets may touch the
+// text; ASCII and dash markers must be followed by a space.
+var (
+	symbolBullets = "•◦▪▫‣⁃∙●○■□►▸➢➤✓✔𐀚"
+	textBullets   = "-*+–—"
+	// dashBullets are the text bullets that also open dialogue and asides
+	dashBullets = "-–—"
+)
+
+// ordinalPattern is the label of an ordered list item such as "12.", "b)",
+// "iv)" or "(3)": a number of up to nine digits (the CommonMark limit), a
+// letter or a roman numeral, followed by "." or ")" and optionally parenthesized
+const ordinalPattern = `\(?(?:[0-9]{1,9}|[a-zA-Z]|[ivxlcdm]{1,6}|[IVXLCDM]{1,6})[.)]`
+
+var (
+	// ordinalMarker matches an ordinal followed by the item text
+	ordinalMarker = regexp.MustCompile(`^(` + ordinalPattern + `)\s+(\S.*)$`)
+	// orderedLabel matches list labels that carry an explicit ordinal
+	orderedLabel = regexp.MustCompile(`^` + ordinalPattern + `$`)
+	// touchingOrdinal matches a number set against the capitalized item text
+	touchingOrdinal = regexp.MustCompile(`^(\(?[0-9]{1,9}[.)])(\p{Lu}.*)$`)
+)
+
+// listMarker is the marker found at the start of a list item
+type listMarker struct {
+	// Label is the ordinal of an ordered item, empty for bullets
+	Label string
+	// Text is the item text after the marker
+	Text string
+	// Task is set for items opened by a checkbox, Checked when it is ticked
+	Task    bool
+	Checked bool
+}
+
+// parseListMarker recognizes the bullet or ordinal that starts a list item,
+// taking more or fewer lines as items depending on the detection level
+func parseListMarker(lineText, detection string) (listMarker, bool) {
+	trimmed := strings.TrimSpace(lineText)
+	r, size := utf8.DecodeRuneInString(trimmed)
+	if r == utf8.RuneError {
+		return listMarker{}, false
+	}
+	rest := trimmed[size:]
+
+	switch {
+	case strings.ContainsRune(uncheckedBoxes, r) || strings.ContainsRune(checkedBoxes, r):
+		if text := strings.TrimSpace(rest); text != "" {
+			return listMarker{Text: text, Task: true, Checked: strings.ContainsRune(checkedBoxes, r)}, true
+		}
+	case strings.ContainsRune(symbolBullets, r):
+		if text := strings.TrimSpace(rest); text != "" {
+			return listMarker{Text: text}, true
+		}
+	case strings.ContainsRune(textBullets, r):
+		if detection == ListDetectionLow && strings.ContainsRune(dashBullets, r) {
+			break
+		}
+		spaced := strings.TrimLeft(rest, " \t") != rest
+		next, _ := utf8.DecodeRuneInString(rest)
+		if text := strings.TrimSpace(rest); text != "" && (spaced || detection == ListDetectionHigh && unicode.IsLetter(next)) {
+			return listMarker{Text: text}, true
+		}
+	}
+
+	m := ordinalMarker.FindStringSubmatch(trimmed)
+	if m == nil && detection == ListDetectionHigh {
+		m = touchingOrdinal.FindStringSubmatch(trimmed)
+	}
+	if m != nil && (detection != ListDetectionLow || strings.ContainsAny(m[1], "0123456789")) {
+		if label, ok := ordinal(m[1]); ok {
+			return listMarker{Label: label, Text: m[2]}, true
+		}
+	}
+	return listMarker{}, false
+}
+
+// escapeDashMarker escapes the dash opening a paragraph the low detection
+// level keeps as prose, which Markdown would otherwise read as a bullet
+func escapeDashMarker(lineText string) string {
+	if strings.HasPrefix(lineText, "- ") {
+		return `\` + lineText
+	}
+	return lineText
+}
+
+// ordinal checks the parentheses of a matched ordinal label and strips the
+// opening one, so "(3)" keeps its number when relabeled as "3."
+func ordinal(label string) (string, bool) {
+	if strings.HasPrefix(label, "(") {
+		return strings.TrimPrefix(label, "("), strings.HasSuffix(label, ")")
+	}
+	return label, true
+}
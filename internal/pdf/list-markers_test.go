@@ -0,0 +1,122 @@
+package pdf
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+)
+
+func TestOrderedListRendering(t *testing.T) {
+	tests := []struct {
+		name  string
+		style markdown.ListStyle
+		lines []string
+		want  string
+	}{
+		{
+			name:  "numbers keep their value",
+			lines: []string{"10. Tenth step", "11. Eleventh step"},
+			want:  "10. Tenth step\n11. Eleventh step",
+		},
+		{
+			name:  "parenthesized numbers",
+			lines: []string{"3) third", "(5) fifth"},
+			want:  "3. third\n5. fifth",
+		},
+		{
+			name:  "ordered delimiter",
+			style: markdown.ListStyle{Ordered: ")"},
+			lines: []string{"1. first", "2. second"},
+			want:  "1) first\n2) second",
+		},
+		{
+			name:  "bullet marker",
+			style: markdown.ListStyle{Bullet: "*"},
+			lines: []string{"- first", "· second"},
+			want:  "* first\n* second",
+		},
+		{
+			name:  "letters become bullets",
+			lines: []string{"a. apples", "b) bananas"},
+			want:  "- a. apples\n- b) bananas",
+		},
+		{
+			name:  "roman numerals become bullets",
+			style: markdown.ListStyle{Bullet: "*"},
+			lines: []string{"iv) fourth", "(IX) ninth"},
+			want:  "* iv) fourth\n* IX) ninth",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &testPDF{}
+			p.page(body(tt.lines...))
+			got := strings.TrimSpace(convert(t, &Converter{ListStyle: tt.style}, p))
+			if got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseListMarker(t *testing.T) {
+	tests := []struct {
+		line      string
+		detection string
+		want      listMarker
+		ok        bool
+	}{
+		{line: "• item", want: listMarker{Text: "item"}, ok: true},
+		{line: "•item", want: listMarker{Text: "item"}, ok: true},
+		{line: "- item", want: listMarker{Text: "item"}, ok: true},
+		{line: "-item", ok: false},
+		{line: "-item", detection: ListDetectionHigh, want: listMarker{Text: "item"}, ok: true},
+		{line: "- aside", detection: ListDetectionLow, ok: false},
+		{line: "– aside", detection: ListDetectionLow, ok: false},
+		{line: "* star", detection: ListDetectionLow, want: listMarker{Text: "star"}, ok: true},
+		{line: "• dot", detection: ListDetectionLow, want: listMarker{Text: "dot"}, ok: true},
+		{line: "1. one", detection: ListDetectionLow, want: listMarker{Label: "1.", Text: "one"}, ok: true},
+		{line: "-3 degrees", detection: ListDetectionHigh, ok: false},
+		{line: "12. twelve", want: listMarker{Label: "12.", Text: "twelve"}, ok: true},
+		{line: "(3) three", want: listMarker{Label: "3)", Text: "three"}, ok: true},
+		{line: "(3 three", ok: false},
+		{line: "b) bee", want: listMarker{Label: "b)", Text: "bee"}, ok: true},
+		{line: "b) bee", detection: ListDetectionLow, ok: false},
+		{line: "1.Item", ok: false},
+		{line: "1.Item", detection: ListDetectionHigh, want: listMarker{Label: "1.", Text: "Item"}, ok: true},
+		{line: "1.5 million", detection: ListDetectionHigh, ok: false},
+		{line: "☐ todo", want: listMarker{Text: "todo", Task: true}, ok: true},
+		{line: "☑ done", want: listMarker{Text: "done", Task: true, Checked: true}, ok: true},
+		{line: "☒  dropped", want: listMarker{Text: "dropped", Task: true, Checked: true}, ok: true},
+		{line: "☐", ok: false},
+		{line: "Plain prose", ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line+"/"+tt.detection, func(t *testing.T) {
+			got, ok := parseListMarker(tt.line, tt.detection)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("parseListMarker(%q, %q) = %+v, %v; want %+v, %v", tt.line, tt.detection, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestListDetection(t *testing.T) {
+	p := &testPDF{}
+	p.page(body("Intro paragraph.", "- and then, an aside", "Closing paragraph."))
+	tests := []struct {
+		detection string
+		want      string
+	}{
+		{"", "Intro paragraph.\n\n\n- and then, an aside\n\nClosing paragraph."},
+		{ListDetectionMedium, "Intro paragraph.\n\n\n- and then, an aside\n\nClosing paragraph."},
+		{ListDetectionLow, "Intro paragraph.\n\n\\- and then, an aside\n\nClosing paragraph."},
+	}
+	for _, tt := range tests {
+		got := strings.TrimSpace(convert(t, &Converter{AssetsDir: t.TempDir(), ListDetection: tt.detection}, p))
+		if got != tt.want {
+			t.Errorf("%q: got %q, want %q", tt.detection, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package pdf
+
+import (
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+)
+
+// mathFontPrefixes are the base font names used for math by TeX and common math typefaces
+var mathFontPrefixes = []string{"CMMI", "CMSY", "CMEX", "CMBSY", "MSAM", "MSBM", "EUFM", "RSFS", "LMMath", "STIXMath"}
+
+// equationNumber matches the number set at the margin of a displayed equation
+var equationNumber = regexp.MustCompile(`^\((\d+(?:\.\d+)*[a-z]?)\)$`)
+
+// isMathFont reports whether a font is typically used to typeset math
+func isMathFont(fontName string) bool {
+	for _, prefix := range mathFontPrefixes {
+		if strings.HasPrefix(fontName, prefix) {
+			return true
+		}
+	}
+	return strings.Contains(fontName, "Math")
+}
+
+// attachScripts moves raised and lowered math glyphs onto the baseline of the
+// line they belong to, recording their offset in Rise so they can be rendered
+// as superscripts and subscripts instead of lines of their own.
+func attachScripts(elements []TextElement) {
+	for i := range elements {
+		script := &elements[i]
+		if !isMathFont(script.Font) {
+			continue
+		}
+
+		best := -1
+		for j, base := range elements {
+			if j == i || base.Size <= script.Size || base.Y == script.Y {
+				continue
+			}
+			dy := math.Abs(script.Y - base.Y)
+			if dy > base.Size*0.6 {
+				continue
+			}
+			if best < 0 || dy < math.Abs(script.Y-elements[best].Y) {
+				best = j
+			}
+		}
+		if best >= 0 {
+			script.Rise = script.Y - elements[best].Y
+			script.Y = elements[best].Y
+		}
+	}
+}
+
+// renderMath renders a run of math glyphs as an inline LaTeX expression,
+// keeping surrounding spaces outside the delimiters
+func renderMath(run []TextElement) string {
+	var expr strings.Builder
+	script := 0
+	closeScript := func() {
+		if script != 0 {
+			expr.WriteString("}")
+			script = 0
+		}
+	}
+
+	for _, element := range run {
+		kind := 0
+		switch {
+		case element.Rise > element.Size*0.1:
+			kind = 1
+		case element.Rise < -element.Size*0.1:
+			kind = -1
+		}
+		if kind != script {
+			closeScript()
+			switch kind {
+			case 1:
+				expr.WriteString("^{")
+			case -1:
+				expr.WriteString("_{")
+			}
+			script = kind
+		}
+
+		expr.WriteString(markdown.LaTeXText(element.Text))
+	}
+	closeScript()
+
+	text := expr.String()
+	inner := strings.TrimSpace(text)
+	if inner == "" {
+		return text
+	}
+	start := strings.Index(text, inner)
+	return text[:start] + "$" + inner + "$" + text[start+len(inner):]
+}
+
+// displayMath recognizes a line holding only an equation centered in the text
+// block and returns it as a $$ display block. An equation number set apart at
+// the right becomes a \tag, and does not count towards the centering.
+func (l pageLayout) displayMath(line TextLine) (string, bool) {
+	elements := line.Elements
+	if len(elements) == 0 || l.Right <= l.Left {
+		return "", false
+	}
+
+	tag := ""
+	for k := len(elements) - 1; k > 0; k-- {
+		prev := elements[k-1]
+		if elements[k].X-(prev.X+prev.Width) > prev.Size*2 {
+			var number strings.Builder
+			for _, e := range elements[k:] {
+				number.WriteString(e.Text)
+			}
+			if m := equationNumber.FindStringSubmatch(strings.TrimSpace(number.String())); m != nil {
+				tag = ` \tag{` + m[1] + `}`
+				elements = elements[:k]
+			}
+			break
+		}
+	}
+
+	// Words in text fonts are prose, unless they are operator names, which
+	// become their LaTeX commands
+	elements = append([]TextElement(nil), elements...)
+	hasMath := false
+	start := -1
+	endWord := func(end int) bool {
+		if start < 0 {
+			return true
+		}
+		first := start
+		start = -1
+		var word strings.Builder
+		for _, e := range elements[first:end] {
+			word.WriteString(e.Text)
+		}
+		name := word.String()
+		if !markdown.IsLaTeXOperator(name) {
+			return len(name) < 3
+		}
+		elements[first].Text = `\` + name + " "
+		for i := first + 1; i < end; i++ {
+			elements[i].Text = ""
+		}
+		return true
+	}
+	for i, e := range elements {
+		letter := len(e.Text) > 0 && unicode.IsLetter([]rune(e.Text)[0])
+		gap := i > 0 && e.X-(elements[i-1].X+elements[i-1].Width) > e.Size*0.25
+		if isMathFont(e.Font) || !letter || gap {
+			if !endWord(i) {
+				return "", false
+			}
+		}
+		if isMathFont(e.Font) {
+			hasMath = true
+		} else if letter && start < 0 {
+			start = i
+		}
+	}
+	if !endWord(len(elements)) || !hasMath {
+		return "", false
+	}
+
+	left, right := lineBounds(TextLine{Elements: elements})
+	if left < l.Left+quoteIndent/2 {
+		return "", false
+	}
+	center := (l.Left + l.Right) / 2
+	if math.Abs((left+right)/2-center) > (l.Right-l.Left)*0.05 {
+		return "", false
+	}
+
+	expr := strings.Trim(strings.TrimSpace(renderMath(elements)), "$")
+	return "$$\n" + strings.TrimSpace(expr) + tag + "\n$$", true
+}
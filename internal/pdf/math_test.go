@@ -0,0 +1,140 @@
+package pdf
+
+import (
+	"strings"
+	"testing"
+)
+
+// mathPDF builds a page with math font runs inside prose: a superscript, a
+// subscript and an equation set on a line of its own with its number
+func mathPDF() *testPDF {
+	p := &testPDF{}
+	line := "Prose spread across the full width of the text block here."
+	p.page(body(line) +
+		showText(72, 682, "Let ", "F1", 12) + showText(96, 682, "x", "F5", 12) + showText(102, 687, "2", "F5", 8) + showText(106, 682, " be", "F1", 12) +
+		showText(72, 664, "then ", "F1", 12) + showText(102, 664, "a", "F5", 12) + showText(108, 660, "i", "F5", 8) + showText(112, 664, " holds.", "F1", 12) +
+		showText(243, 640, "y", "F5", 12) + showText(249, 640, "+", "F5", 12) + showText(255, 640, "1", "F5", 12) + showText(408, 640, "(1)", "F1", 12) +
+		showText(72, 616, line, "F1", 12))
+	return p
+}
+
+func TestMath(t *testing.T) {
+	tests := []struct {
+		name string
+		math bool
+		want []string
+		not  []string
+	}{
+		{
+			name: "math",
+			math: true,
+			want: []string{"Let $x^{2}$ be", "then $a_{i}$ holds.", "$$\ny+1 \\tag{1}\n$$"},
+		},
+		{
+			name: "off",
+			not:  []string{"$"},
+		},
+	}
+	for _, tt := range tests {
+		got := convert(t, &Converter{Math: tt.math}, mathPDF())
+		for _, want := range tt.want {
+			if !strings.Contains(got, want) {
+				t.Errorf("%s: output lacks %q:\n%s", tt.name, want, got)
+			}
+		}
+		for _, not := range tt.not {
+			if strings.Contains(got, not) {
+				t.Errorf("%s: output holds %q:\n%s", tt.name, not, got)
+			}
+		}
+	}
+}
+
+func TestIsMathFont(t *testing.T) {
+	tests := []struct {
+		font string
+		want bool
+	}{
+		{"CMMI10", true},
+		{"CMSY7", true},
+		{"CambriaMath", true},
+		{"STIXMathJax", true},
+		{"CMR10", false},
+		{"Helvetica", false},
+	}
+	for _, tt := range tests {
+		if got := isMathFont(tt.font); got != tt.want {
+			t.Errorf("isMathFont(%q) = %v, want %v", tt.font, got, tt.want)
+		}
+	}
+}
+
+func TestRenderMath(t *testing.T) {
+	glyph := func(text string, rise float64) TextElement {
+		return TextElement{Text: text, Size: 10, Rise: rise}
+	}
+	tests := []struct {
+		name string
+		run  []TextElement
+		want string
+	}{
+		{"plain", []TextElement{glyph("x", 0)}, "$x$"},
+		{"superscript", []TextElement{glyph("e", 0), glyph("i", 4), glyph("π", 4)}, "$e^{i\\pi }$"},
+		{"subscript then base", []TextElement{glyph("a", 0), glyph("n", -3), glyph("b", 0)}, "$a_{n}b$"},
+		{"spaces stay outside", []TextElement{glyph(" ", 0), glyph("x", 0), glyph(" ", 0)}, " $x$ "},
+		{"blank", []TextElement{glyph(" ", 0)}, " "},
+	}
+	for _, tt := range tests {
+		if got := renderMath(tt.run); got != tt.want {
+			t.Errorf("%s: renderMath() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDisplayMath(t *testing.T) {
+	line := "Prose spread across the full width of the text block here."
+	tests := []struct {
+		name     string
+		equation string
+		want     string
+		not      string
+	}{
+		{
+			name:     "centered",
+			equation: showText(234, 640, "E", "F5", 12) + showText(240, 640, "+", "F5", 12) + showText(246, 640, "m", "F5", 12) + showText(252, 640, "c", "F5", 12),
+			want:     "$$\nE+mc\n$$",
+		},
+		{
+			name:     "operator name",
+			equation: showText(231, 640, "sin", "F1", 12) + showText(252, 640, "x", "F5", 12),
+			want:     "$$\n\\sin x\n$$",
+		},
+		{
+			name:     "numbered",
+			equation: showText(240, 640, "a", "F5", 12) + showText(246, 640, "b", "F5", 12) + showText(396, 640, "(2.1)", "F1", 12),
+			want:     "$$\nab \\tag{2.1}\n$$",
+		},
+		{
+			name:     "at the margin",
+			equation: showText(72, 640, "E", "F5", 12) + showText(78, 640, "+", "F5", 12) + showText(84, 640, "m", "F5", 12),
+			not:      "$$",
+		},
+		{
+			name:     "prose around math",
+			equation: showText(186, 640, "where ", "F1", 12) + showText(222, 640, "x", "F5", 12) + showText(228, 640, " is positive", "F1", 12),
+			want:     "where $x$ is positive",
+			not:      "$$",
+		},
+	}
+	for _, tt := range tests {
+		p := &testPDF{}
+		p.page(body(line) + tt.equation + showText(72, 616, line, "F1", 12))
+		got := convert(t, &Converter{Math: true}, p)
+		if !strings.Contains(got, tt.want) {
+			t.Errorf("%s: output lacks %q:\n%s", tt.name, tt.want, got)
+		}
+		if tt.not != "" && strings.Contains(got, tt.not) {
+			t.Errorf("%s: output holds %q:\n%s", tt.name, tt.not, got)
+		}
+	}
+}
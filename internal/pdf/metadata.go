@@ -0,0 +1,85 @@
+package pdf
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/errs"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+)
+
+// ReadMetadata reads the document information dictionary and the catalog
+// language of a PDF without extracting its pages
+func ReadMetadata(inputPath string) (meta markdown.Metadata, err error) {
+	// The PDF library panics on malformed objects
+	defer func() {
+		if r := recover(); r != nil {
+			meta, err = markdown.Metadata{}, fmt.Errorf("%w: %v", errs.ErrCorrupt, r)
+		}
+	}()
+
+	f, err := os.Open(inputPath)
+	if err != nil {
+		return markdown.Metadata{}, fmt.Errorf("failed to open PDF: %v", err)
+	}
+	defer f.Close()
+
+	reader, err := openReader(f)
+	if err != nil {
+		return markdown.Metadata{}, err
+	}
+
+	info := reader.Trailer().Key("Info")
+	meta = markdown.Metadata{
+		Title:    strings.TrimSpace(info.Key("Title").Text()),
+		Author:   strings.TrimSpace(info.Key("Author").Text()),
+		Subject:  strings.TrimSpace(info.Key("Subject").Text()),
+		Keywords: markdown.ParseKeywords(info.Key("Keywords").Text()),
+		Pages:    reader.NumPage(),
+		Language: reader.Trailer().Key("Root").Key("Lang").Text(),
+	}
+	meta.Created, _ = parseDate(info.Key("CreationDate").Text())
+	meta.Modified, _ = parseDate(info.Key("ModDate").Text())
+	return meta, nil
+}
+
+// parseDate parses a PDF date string, D:YYYYMMDDHHmmSSOHH'mm', of which every
+// part after the year is optional
+func parseDate(s string) (time.Time, bool) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "D:")
+	fields := []int{0, 1, 1, 0, 0, 0}
+	widths := []int{4, 2, 2, 2, 2, 2}
+	for i, width := range widths {
+		if len(s) < width || s[0] < '0' || s[0] > '9' {
+			if i == 0 {
+				return time.Time{}, false
+			}
+			break
+		}
+		n, err := strconv.Atoi(s[:width])
+		if err != nil {
+			return time.Time{}, false
+		}
+		fields[i] = n
+		s = s[width:]
+	}
+
+	loc := time.UTC
+	if len(s) > 0 && (s[0] == '+' || s[0] == '-') {
+		parts := strings.Split(strings.TrimSuffix(s[1:], "'"), "'")
+		hours, _ := strconv.Atoi(parts[0])
+		minutes := 0
+		if len(parts) > 1 {
+			minutes, _ = strconv.Atoi(parts[1])
+		}
+		offset := hours*3600 + minutes*60
+		if s[0] == '-' {
+			offset = -offset
+		}
+		loc = time.FixedZone("", offset)
+	}
+	return time.Date(fields[0], time.Month(fields[1]), fields[2], fields[3], fields[4], fields[5], 0, loc), true
+}
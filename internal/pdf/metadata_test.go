@@ -0,0 +1,83 @@
+package pdf
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/errs"
+)
+
+// writeTestPDF writes a test PDF to a temporary file and returns its path
+func writeTestPDF(t *testing.T, p *testPDF) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "test.pdf")
+	if err := os.WriteFile(path, p.bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReadMetadata(t *testing.T) {
+	p := &testPDF{
+		info:    "<< /Title ( Annual Report ) /Author (Ana Lima) /Subject (Finance) /Keywords (budget; forecast, budget) /CreationDate (D:20240131093000+02'00') /ModDate (D:2024) >>",
+		catalog: "/Lang (pt-BR)",
+	}
+	p.page(body("First page."))
+	p.page(body("Second page."))
+	meta, err := ReadMetadata(writeTestPDF(t, p))
+	if err != nil {
+		t.Fatalf("ReadMetadata() error = %v", err)
+	}
+	if meta.Title != "Annual Report" || meta.Author != "Ana Lima" || meta.Subject != "Finance" {
+		t.Errorf("title, author, subject = %q, %q, %q", meta.Title, meta.Author, meta.Subject)
+	}
+	if got := strings.Join(meta.Keywords, ","); got != "budget,forecast" {
+		t.Errorf("Keywords = %v, want [budget forecast]", meta.Keywords)
+	}
+	if want := time.Date(2024, 1, 31, 7, 30, 0, 0, time.UTC); !meta.Created.Equal(want) {
+		t.Errorf("Created = %v, want %v", meta.Created, want)
+	}
+	if want := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC); !meta.Modified.Equal(want) {
+		t.Errorf("Modified = %v, want %v", meta.Modified, want)
+	}
+	if meta.Pages != 2 || meta.Language != "pt-BR" {
+		t.Errorf("Pages, Language = %d, %q, want 2, pt-BR", meta.Pages, meta.Language)
+	}
+
+	p = &testPDF{}
+	p.page(body("No information dictionary."))
+	if meta, err = ReadMetadata(writeTestPDF(t, p)); err != nil || meta.Title != "" || meta.Pages != 1 {
+		t.Errorf("ReadMetadata() without info = %+v, %v", meta, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "broken.pdf")
+	os.WriteFile(path, []byte("%PDF-1.7\nnot a document"), 0o644)
+	if _, err := ReadMetadata(path); !errors.Is(err, errs.ErrCorrupt) {
+		t.Errorf("ReadMetadata() of a broken PDF error = %v, want ErrCorrupt", err)
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Time
+		ok   bool
+	}{
+		{"D:20240131093000Z", time.Date(2024, 1, 31, 9, 30, 0, 0, time.UTC), true},
+		{"D:20240131093000-03'00'", time.Date(2024, 1, 31, 12, 30, 0, 0, time.UTC), true},
+		{"D:202401", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), true},
+		{"20240131", time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), true},
+		{"", time.Time{}, false},
+		{"D:yesterday", time.Time{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseDate(tt.in)
+		if ok != tt.ok || !got.Equal(tt.want) {
+			t.Errorf("parseDate(%q) = %v, %v, want %v, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package pdf
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rsc/pdf"
+)
+
+// note is a comment annotation collected for the notes appendix
+type note struct {
+	// ID names the anchor left in the body where the note is attached
+	ID     string
+	Page   int
+	Author string
+	Text   string
+	// Quote is the text a markup annotation such as a highlight covers
+	Quote string
+	// Placed is set once the anchor has been added to a line of the page
+	Placed bool
+	area   rect
+	quads  []rect
+}
+
+// readNotes returns the annotations of a page that carry a comment, in the
+// reading order of their position. Links, form fields and the pop-up windows
+// showing other annotations' comments are not notes.
+func readNotes(page pdf.Page, pageNum int) []note {
+	var notes []note
+	annots := page.V.Key("Annots")
+	for i := 0; i < annots.Len(); i++ {
+		annot := annots.Index(i)
+		switch annot.Key("Subtype").Name() {
+		case "Link", "Popup", "Widget":
+			continue
+		}
+		text := strings.Join(strings.Fields(annot.Key("Contents").Text()), " ")
+		area, ok := rectValue(annot.Key("Rect"))
+		if text == "" || !ok {
+			continue
+		}
+
+		n := note{Page: pageNum, Author: strings.TrimSpace(annot.Key("T").Text()), Text: text, area: area}
+		quads := annot.Key("QuadPoints")
+		for q := 0; q+8 <= quads.Len(); q += 8 {
+			r := rect{MinX: math.Inf(1), MinY: math.Inf(1), MaxX: math.Inf(-1), MaxY: math.Inf(-1)}
+			for p := q; p < q+8; p += 2 {
+				x, y := quads.Index(p).Float64(), quads.Index(p+1).Float64()
+				r = rect{MinX: min(r.MinX, x), MinY: min(r.MinY, y), MaxX: max(r.MaxX, x), MaxY: max(r.MaxY, y)}
+			}
+			n.quads = append(n.quads, r)
+		}
+		notes = append(notes, n)
+	}
+
+	sort.SliceStable(notes, func(i, j int) bool {
+		if notes[i].area.MaxY != notes[j].area.MaxY {
+			return notes[i].area.MaxY > notes[j].area.MaxY
+		}
+		return notes[i].area.MinX < notes[j].area.MinX
+	})
+	for i := range notes {
+		notes[i].ID = "note-" + strconv.Itoa(pageNum) + "-" + strconv.Itoa(i+1)
+	}
+	return notes
+}
+
+// placeNotes quotes the text covered by markup notes and adds the anchor of
+// every note at the end of the line it is attached to, the nearest line to
+// the middle of its area. The anchor is an inline element, so it stays with
+// the line wherever the line is rendered.
+func placeNotes(elements []TextElement, notes []note) []TextElement {
+	var anchors []TextElement
+	for i := range notes {
+		n := &notes[i]
+		var covered []TextElement
+		for _, e := range elements {
+			x, y := e.X+e.Width/2, e.Y+e.Size/3
+			for _, q := range n.quads {
+				if q.contains(x, y) {
+					covered = append(covered, e)
+					break
+				}
+			}
+		}
+		n.Quote = quoteElements(covered)
+
+		middle := (n.area.MinY + n.area.MaxY) / 2
+		best := -1
+		for j, e := range elements {
+			if e.Image {
+				continue
+			}
+			if best < 0 || math.Abs(e.Y+e.Size/3-middle) < math.Abs(elements[best].Y+elements[best].Size/3-middle) {
+				best = j
+			}
+		}
+		if best < 0 {
+			continue
+		}
+		end := elements[best]
+		for _, e := range elements {
+			if e.Y == end.Y && !e.Image && e.X+e.Width > end.X+end.Width {
+				end = e
+			}
+		}
+		anchors = append(anchors, TextElement{
+			Text:  `<a id="` + n.ID + `"></a>`,
+			Font:  end.Font,
+			Size:  end.Size,
+			X:     end.X + end.Width,
+			Y:     end.Y,
+			MCID:  end.MCID,
+			Image: true,
+		})
+		n.Placed = true
+	}
+	return append(elements, anchors...)
+}
+
+// quoteElements returns the text of glyphs in reading order, one space
+// between lines
+func quoteElements(elements []TextElement) string {
+	sort.SliceStable(elements, func(i, j int) bool {
+		if elements[i].Y != elements[j].Y {
+			return elements[i].Y > elements[j].Y
+		}
+		return elements[i].X < elements[j].X
+	})
+	var b strings.Builder
+	for i := 0; i < len(elements); {
+		j := i
+		for j < len(elements) && elements[j].Y == elements[i].Y {
+			j++
+		}
+		for _, e := range spaceElements(elements[i:j]) {
+			b.WriteString(e.Text)
+		}
+		b.WriteString(" ")
+		i = j
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// renderNotes renders the notes of every page as a numbered "Notes" section,
+// each linking back to its anchor in the body
+func (c *Converter) renderNotes(pages [][]note) string {
+	var result strings.Builder
+	count := 0
+	for _, notes := range pages {
+		for _, n := range notes {
+			if count == 0 {
+				result.WriteString("## Notes\n\n")
+			}
+			count++
+
+			entry := fmt.Sprintf("Page %d", n.Page)
+			if n.Author != "" {
+				entry += ", " + n.Author
+			}
+			if n.Quote != "" {
+				entry += `, on "` + n.Quote + `"`
+			}
+			entry += ": " + n.Text
+			if n.Placed {
+				entry += " [↩](#" + n.ID + ")"
+			}
+			result.WriteString(c.ListStyle.OrderedMarker(count) + " " + entry + "\n")
+		}
+	}
+	return result.String()
+}
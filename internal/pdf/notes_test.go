@@ -0,0 +1,84 @@
+package pdf
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// notesPDF builds a document whose pages each show two lines of text and
+// carry the annotations given for them
+func notesPDF(pages ...[]string) *testPDF {
+	p := &testPDF{}
+	for i, annots := range pages {
+		var refs []string
+		for _, annot := range annots {
+			refs = append(refs, fmt.Sprintf("%d 0 R", p.add(annot)))
+		}
+		p.pageWith(body(fmt.Sprintf("First line of page %d.", i+1), "Second line of the page."), "/Annots ["+strings.Join(refs, " ")+"]", "")
+	}
+	return p
+}
+
+func TestNotesAppendix(t *testing.T) {
+	// The first line has its baseline at 700 and the second at 682
+	comment := "<< /Type /Annot /Subtype /Text /Rect [300 680 320 696] /T (Bo) /Contents (Check   the dates) >>"
+	highlight := "<< /Type /Annot /Subtype /Highlight /Rect [70 698 150 712] /QuadPoints [71 712 149 712 71 698 149 698] /T (Ana) /Contents (Too vague) >>"
+	tests := []struct {
+		name  string
+		pages [][]string
+		off   bool
+		want  []string
+		none  []string
+	}{
+		{
+			name:  "comment",
+			pages: [][]string{{comment}},
+			want: []string{
+				`Second line of the page. <a id="note-1-1"></a>`,
+				"## Notes\n\n1. Page 1, Bo: Check the dates [↩](#note-1-1)\n",
+			},
+		},
+		{
+			name:  "highlight quotes the covered text",
+			pages: [][]string{{highlight}},
+			want: []string{
+				`<mark>First line of</mark> page 1. <a id="note-1-1"></a>`,
+				`1. Page 1, Ana, on "First line of": Too vague [↩](#note-1-1)`,
+			},
+		},
+		{
+			name:  "reading order across pages",
+			pages: [][]string{{comment, highlight}, {comment}},
+			want: []string{
+				"1. Page 1, Ana",
+				"2. Page 1, Bo: Check the dates [↩](#note-1-2)",
+				"3. Page 2, Bo: Check the dates [↩](#note-2-1)",
+			},
+		},
+		{
+			name:  "links, pop-ups and empty comments left out",
+			pages: [][]string{{"<< /Type /Annot /Subtype /Link /Rect [72 698 150 712] /Contents (Go) >>", "<< /Type /Annot /Subtype /Popup /Rect [300 680 320 696] /Contents (Pop) >>", "<< /Type /Annot /Subtype /Text /Rect [300 680 320 696] /Contents ( ) >>"}},
+			none:  []string{"## Notes", "<a id="},
+		},
+		{
+			name:  "disabled",
+			pages: [][]string{{comment}},
+			off:   true,
+			none:  []string{"## Notes", "<a id=", "Check the dates"},
+		},
+	}
+	for _, tt := range tests {
+		got := convert(t, &Converter{AssetsDir: t.TempDir(), NotesAppendix: !tt.off}, notesPDF(tt.pages...))
+		for _, want := range tt.want {
+			if !strings.Contains(got, want) {
+				t.Errorf("%s: got %q, want it to contain %q", tt.name, got, want)
+			}
+		}
+		for _, none := range tt.none {
+			if strings.Contains(got, none) {
+				t.Errorf("%s: got %q, want no %q", tt.name, got, none)
+			}
+		}
+	}
+}
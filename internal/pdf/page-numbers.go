@@ -0,0 +1,88 @@
+package pdf
+
+import (
+	"regexp"
+	"strings"
+)
+
+// pageNumberPattern matches running page numbers such as "12", "- 12 -",
+// "Page 12" and "12 of 30". Spaces are optional since they are not drawn as glyphs.
+var pageNumberPattern = regexp.MustCompile(`(?i)^(?:page\s*)?[-–—]?\s*(\d{1,4})\s*[-–—]?(?:\s*(?:of|/)\s*\d{1,4})?$`)
+
+// pageNumberGap is the distance to the nearest line, relative to the font size,
+// beyond which a line at the top or bottom of a page stands on its own
+const pageNumberGap = 2.0
+
+// stripPageNumbers removes a page number set on its own at the top or bottom of
+// the page, returning the remaining lines and the numbers found in each place
+func stripPageNumbers(lines []TextLine) ([]TextLine, string, string) {
+	top, bottom := -1, -1
+	for i, line := range lines {
+		if len(line.Elements) == 0 {
+			continue
+		}
+		if top < 0 || line.Y > lines[top].Y {
+			top = i
+		}
+		if bottom < 0 || line.Y < lines[bottom].Y {
+			bottom = i
+		}
+	}
+	if top < 0 {
+		return lines, "", ""
+	}
+
+	// Page numbers are not set larger than the body text, unlike headings such as a year
+	var elements []TextElement
+	for _, line := range lines {
+		elements = append(elements, line.Elements...)
+	}
+	body := bodyFontSize(elements)
+
+	header, footer := "", ""
+	if lines[top].FontSize <= body {
+		header = pageNumber(lines, top)
+	}
+	if bottom != top && lines[bottom].FontSize <= body {
+		footer = pageNumber(lines, bottom)
+	}
+	if header == "" && footer == "" {
+		return lines, "", ""
+	}
+
+	var kept []TextLine
+	for i, line := range lines {
+		if i == top && header != "" || i == bottom && footer != "" {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return kept, header, footer
+}
+
+// pageNumber returns the number shown by lines[i] if it is a page number
+// isolated from the rest of the text
+func pageNumber(lines []TextLine, i int) string {
+	var text strings.Builder
+	for _, element := range lines[i].Elements {
+		text.WriteString(element.Text)
+	}
+	m := pageNumberPattern.FindStringSubmatch(strings.TrimSpace(text.String()))
+	if m == nil {
+		return ""
+	}
+
+	for j, line := range lines {
+		if j == i || len(line.Elements) == 0 {
+			continue
+		}
+		gap := line.Y - lines[i].Y
+		if gap < 0 {
+			gap = -gap
+		}
+		if gap < lines[i].FontSize*pageNumberGap {
+			return ""
+		}
+	}
+	return m[1]
+}
@@ -0,0 +1,61 @@
+package pdf
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPageNumbers(t *testing.T) {
+	content := body("Body text of the page.", "Another line of body text.") + showText(300, 60, "12", "F1", 10)
+	tests := []struct {
+		name string
+		keep bool
+		want string
+	}{
+		{"dropped", false, "Body text of the page.\n\nAnother line of body text."},
+		{"kept as comment", true, "Body text of the page.\n\nAnother line of body text.\n\n<!-- page 12 -->"},
+	}
+	for _, tt := range tests {
+		p := &testPDF{}
+		p.page(content)
+		if got := strings.TrimSpace(convert(t, &Converter{KeepPageNumbers: tt.keep}, p)); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPageNumber(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"12", "12"},
+		{"- 12 -", "12"},
+		{"Page 3 of 10", "3"},
+		{"page 7", "7"},
+		{"4/9", "4"},
+		{"2024 was a good year", ""},
+		{"12345", ""},
+		{"Chapter 2", ""},
+	}
+	for _, tt := range tests {
+		lines := []TextLine{
+			{Elements: []TextElement{{Text: "Body text", Y: 700, Size: 12}}, Y: 700, FontSize: 12},
+			{Elements: []TextElement{{Text: tt.text, Y: 60, Size: 12}}, Y: 60, FontSize: 12},
+		}
+		if got := pageNumber(lines, 1); got != tt.want {
+			t.Errorf("pageNumber(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestPageNumberNearText(t *testing.T) {
+	// A number directly under the text continues it rather than numbering the page
+	lines := []TextLine{
+		{Elements: []TextElement{{Text: "Total", Y: 100, Size: 12}}, Y: 100, FontSize: 12},
+		{Elements: []TextElement{{Text: "12", Y: 86, Size: 12}}, Y: 86, FontSize: 12},
+	}
+	if got := pageNumber(lines, 1); got != "" {
+		t.Errorf("pageNumber() = %q, want none", got)
+	}
+}
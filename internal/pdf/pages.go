@@ -0,0 +1,107 @@
+package pdf
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/errs"
+)
+
+// pageTurns hands the naming of assets to the pages of a document in page
+// order, so names numbered in the order they are given follow the pages
+// whatever order the pages are extracted in. Only naming waits its turn: a
+// page decodes its images while the pages before it are still extracted.
+type pageTurns struct {
+	done []chan struct{}
+	once []sync.Once
+}
+
+func newPageTurns(numPages int) *pageTurns {
+	t := &pageTurns{done: make([]chan struct{}, numPages), once: make([]sync.Once, numPages)}
+	for i := range t.done {
+		t.done[i] = make(chan struct{})
+	}
+	return t
+}
+
+// wait blocks until every page before pageNum has named its assets
+func (t *pageTurns) wait(pageNum int) {
+	if t != nil && pageNum > 1 {
+		<-t.done[pageNum-2]
+	}
+}
+
+// finish marks pageNum as done naming assets once the pages before it are,
+// without waiting for them
+func (t *pageTurns) finish(pageNum int) {
+	if t == nil {
+		return
+	}
+	t.once[pageNum-1].Do(func() {
+		go func() {
+			t.wait(pageNum)
+			close(t.done[pageNum-1])
+		}()
+	})
+}
+
+// extractPages converts every page of a document to Markdown, in page order.
+// With more than one job, pages are extracted concurrently. The reader only
+// reads from the file after it is opened, so pages can share it; the font
+// cache is the only state written by page extraction, and assets are named
+// in page order through doc.turns.
+func (c *Converter) extractPages(doc *document, numPages int) ([]string, error) {
+	pages := make([]string, numPages)
+	failures := make([]error, numPages)
+	doc.turns = newPageTurns(numPages)
+
+	extract := func(i int) {
+		// Pages that fail or are skipped before naming their assets still
+		// pass their turn on
+		defer doc.turns.finish(i + 1)
+		// The PDF library panics on malformed objects, which must not escape a worker
+		defer func() {
+			if r := recover(); r != nil {
+				failures[i] = fmt.Errorf("%w: %v", errs.ErrCorrupt, r)
+			}
+		}()
+
+		page := doc.reader.Page(i + 1)
+		if page.V.IsNull() {
+			return // Skip empty pages
+		}
+		pages[i], failures[i] = c.extractStructuredText(doc, i+1, page)
+	}
+
+	if c.Jobs <= 1 {
+		for i := range pages {
+			if extract(i); failures[i] != nil {
+				break
+			}
+		}
+	} else {
+		next := make(chan int)
+		var wg sync.WaitGroup
+		for w := 0; w < c.Jobs && w < numPages; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range next {
+					extract(i)
+				}
+			}()
+		}
+		for i := range pages {
+			next <- i
+		}
+		close(next)
+		wg.Wait()
+	}
+
+	for i, err := range failures {
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract text from page %d: %w", i+1, err)
+		}
+	}
+	return pages, nil
+}
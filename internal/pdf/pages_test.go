@@ -0,0 +1,88 @@
+package pdf
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/utils"
+)
+
+// imagePDF builds a document whose pages each show a line of text and draw
+// a one-pixel gray image, every page's image of a different shade
+func imagePDF(numPages int) *testPDF {
+	p := &testPDF{}
+	for i := 0; i < numPages; i++ {
+		img := p.stream(string([]byte{byte(i * 10)}), "/Type /XObject /Subtype /Image /Width 1 /Height 1 /ColorSpace /DeviceGray /BitsPerComponent 8")
+		content := showText(72, 700, fmt.Sprintf("Page %d", i+1), "F1", 12) + "q 100 0 0 100 72 500 cm /Im1 Do Q\n"
+		p.pageWith(content, "", fmt.Sprintf("/XObject << /Im1 %d 0 R >>", img))
+	}
+	return p
+}
+
+var assetLink = regexp.MustCompile(`!\[\]\(assets/([^)]+)\)`)
+
+func TestExtractPagesAssetOrder(t *testing.T) {
+	const numPages = 12
+	for _, jobs := range []int{1, 2, 4, 16} {
+		t.Run(fmt.Sprintf("jobs=%d", jobs), func(t *testing.T) {
+			namer, err := utils.NewAssetNamer(utils.AssetNamingSequential)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c := &Converter{AssetsDir: t.TempDir(), AssetsLink: "assets", AssetNamer: namer, Jobs: jobs, ImageFormat: ImageFormatPNG}
+			md := convert(t, c, imagePDF(numPages))
+
+			var got []string
+			for _, m := range assetLink.FindAllStringSubmatch(md, -1) {
+				got = append(got, m[1])
+			}
+			var want []string
+			for i := 1; i <= numPages; i++ {
+				want = append(want, fmt.Sprintf("asset%d.png", i))
+			}
+			if strings.Join(got, " ") != strings.Join(want, " ") {
+				t.Errorf("assets = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestExtractPagesConcurrent(t *testing.T) {
+	p := &testPDF{}
+	var want []string
+	for i := 1; i <= 20; i++ {
+		p.page(body(fmt.Sprintf("Paragraph of page %d.", i)))
+		want = append(want, fmt.Sprintf("Paragraph of page %d.", i))
+	}
+	sequential := convert(t, &Converter{AssetsDir: t.TempDir()}, p)
+	for _, jobs := range []int{2, 8, 32} {
+		if got := convert(t, &Converter{AssetsDir: t.TempDir(), Jobs: jobs}, p); got != sequential {
+			t.Errorf("Jobs %d output = %q, want %q", jobs, got, sequential)
+		}
+	}
+	last := -1
+	for _, text := range want {
+		i := strings.Index(sequential, text)
+		if i <= last {
+			t.Fatalf("%q out of page order in %q", text, sequential)
+		}
+		last = i
+	}
+}
+
+func BenchmarkExtractPages(b *testing.B) {
+	p := &testPDF{}
+	for i := 1; i <= 50; i++ {
+		p.page(body(strings.Repeat(fmt.Sprintf("Text of page %d. ", i), 4), "Second line of the page.", "Third line of the page."))
+	}
+	for _, jobs := range []int{1, 4} {
+		b.Run(fmt.Sprintf("jobs=%d", jobs), func(b *testing.B) {
+			c := &Converter{AssetsDir: b.TempDir(), Jobs: jobs}
+			for i := 0; i < b.N; i++ {
+				convert(b, c, p)
+			}
+		})
+	}
+}
@@ -0,0 +1,335 @@
+package pdf
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/rc4"
+	"encoding/binary"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/rsc/pdf"
+)
+
+// testFonts are the fonts every page of a test PDF can show text with, by
+// resource name. Their glyphs are all 500 units wide.
+var testFonts = [][2]string{
+	{"F1", "Helvetica"},
+	{"F2", "Helvetica-Bold"},
+	{"F3", "Helvetica-Oblique"},
+	{"F4", "Courier"},
+	{"F5", "CMMI10"},
+}
+
+// testPDF builds minimal PDF documents in memory. Object bodies may refer to
+// the nth page as PAGEn, which is replaced by its reference when written.
+type testPDF struct {
+	objects []string
+	pages   []testPage
+	// catalog holds extra entries of the document catalog
+	catalog string
+	// info is the document information dictionary, omitted when empty
+	info string
+	// fonts holds extra entries of every page's font resources
+	fonts string
+	// encrypted encrypts the document with RC4 under an owner password
+	// alone, so readers open it without asking for one
+	encrypted bool
+}
+
+// testPage is the content stream of a page with extra entries of its
+// dictionary and of its resources
+type testPage struct {
+	content   string
+	extra     string
+	resources string
+}
+
+// add appends an object and returns its number
+func (p *testPDF) add(body string) int {
+	p.objects = append(p.objects, body)
+	return len(p.objects)
+}
+
+// stream appends a stream object with extra entries in its dictionary
+func (p *testPDF) stream(data, extra string) int {
+	return p.add(fmt.Sprintf("<< /Length %d %s >>\nstream\n%s\nendstream", len(data), extra, data))
+}
+
+// page appends a page drawn by content
+func (p *testPDF) page(content string) {
+	p.pageWith(content, "", "")
+}
+
+// pageWith appends a page with extra entries of its dictionary and resources
+func (p *testPDF) pageWith(content, extra, resources string) {
+	p.pages = append(p.pages, testPage{content: content, extra: extra, resources: resources})
+}
+
+var pagePlaceholder = regexp.MustCompile(`PAGE(\d+)`)
+
+// bytes writes the document with its cross-reference table
+func (p *testPDF) bytes() []byte {
+	objects := append([]string(nil), p.objects...)
+	add := func(body string) int {
+		objects = append(objects, body)
+		return len(objects)
+	}
+
+	widths := strings.TrimSpace(strings.Repeat("500 ", 256))
+	var fonts []string
+	for _, font := range testFonts {
+		id := add(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /Identity-H /FirstChar 0 /LastChar 255 /Widths [%s] >>", font[1], widths))
+		fonts = append(fonts, fmt.Sprintf("/%s %d 0 R", font[0], id))
+	}
+	pagesID := add("")
+	var kids []string
+	for _, page := range p.pages {
+		contentID := add(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(page.content), page.content))
+		id := add(fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 612 792] /Resources << /Font << %s %s >> %s >> /Contents %d 0 R %s >>",
+			pagesID, strings.Join(fonts, " "), p.fonts, page.resources, contentID, page.extra))
+		kids = append(kids, fmt.Sprintf("%d 0 R", id))
+	}
+	objects[pagesID-1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids))
+	catalogID := add(fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R %s >>", pagesID, p.catalog))
+	infoID := 0
+	if p.info != "" {
+		infoID = add(p.info)
+	}
+
+	resolve := func(body string) string {
+		return pagePlaceholder.ReplaceAllStringFunc(body, func(m string) string {
+			n, _ := strconv.Atoi(m[len("PAGE"):])
+			return kids[n-1]
+		})
+	}
+
+	for i, body := range objects {
+		objects[i] = resolve(body)
+	}
+	encryption := ""
+	if p.encrypted {
+		objects = encryptObjects(objects)
+		encryption = fmt.Sprintf(" /Encrypt %d 0 R /ID [<%x> <%x>]", len(objects), testFileID, testFileID)
+	}
+	trailer := fmt.Sprintf("<< /Size %d /Root %d 0 R", len(objects)+1, catalogID)
+	if infoID > 0 {
+		trailer += fmt.Sprintf(" /Info %d 0 R", infoID)
+	}
+	trailer += encryption
+
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.7\n")
+	offsets := make([]int, len(objects))
+	for i, body := range objects {
+		offsets[i] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, body)
+	}
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n%s >>\nstartxref\n%d\n%%%%EOF\n", trailer, xref)
+	return out.Bytes()
+}
+
+// testFileID is the file identifier of encrypted test PDFs, which their
+// encryption key is derived from
+var testFileID = []byte("doc2md-test-file")
+
+// passwordPad pads passwords to 32 bytes in the standard security handler
+var passwordPad = []byte("\x28\xbf\x4e\x5e\x4e\x75\x8a\x41\x64\x00\x4e\x56\xff\xfa\x01\x08\x2e\x2e\x00\xb6\xd0\x68\x3e\x80\x2f\x0c\xa9\xfe\x64\x53\x69\x7a")
+
+// encryptObjects encrypts the strings and streams of objects with the
+// 40-bit RC4 of the standard security handler, revision 2, under an empty
+// user password, and appends the encryption dictionary. Objects are keyed
+// by the whole digest of the file key and object number, as rsc/pdf reads
+// them. RC4 keeps the length of what it encrypts, so stream lengths hold.
+func encryptObjects(objects []string) []string {
+	ownerKey := md5.Sum([]byte("owner"))
+	owner := crypt(ownerKey[:5], passwordPad)
+	const permissions = int32(-44)
+	h := md5.New()
+	h.Write(passwordPad)
+	h.Write(owner)
+	binary.Write(h, binary.LittleEndian, permissions)
+	h.Write(testFileID)
+	key := h.Sum(nil)[:5]
+	user := crypt(key, passwordPad)
+
+	encrypted := make([]string, len(objects))
+	for i, body := range objects {
+		num := i + 1
+		objectKey := md5.Sum(append(append([]byte(nil), key...), byte(num), byte(num>>8), byte(num>>16), 0, 0))
+		encrypt := func(data string) string { return string(crypt(objectKey[:], []byte(data))) }
+
+		dict, stream := body, ""
+		if start := strings.Index(body, "\nstream\n"); start >= 0 {
+			end := strings.LastIndex(body, "\nendstream")
+			dict, stream = body[:start], "\nstream\n"+encrypt(body[start+len("\nstream\n"):end])+body[end:]
+		}
+		encrypted[i] = encryptStrings(dict, encrypt) + stream
+	}
+	return append(encrypted, fmt.Sprintf("<< /Filter /Standard /V 1 /R 2 /Length 40 /O <%x> /U <%x> /P %d >>", owner, user, permissions))
+}
+
+// crypt encrypts or decrypts data with RC4 under key
+func crypt(key, data []byte) []byte {
+	c, _ := rc4.NewCipher(key)
+	out := make([]byte, len(data))
+	c.XORKeyStream(out, data)
+	return out
+}
+
+// encryptStrings replaces the literal strings of an object with the hex
+// strings of their encrypted bytes
+func encryptStrings(body string, encrypt func(string) string) string {
+	var out strings.Builder
+	for i := 0; i < len(body); i++ {
+		if body[i] != '(' {
+			out.WriteByte(body[i])
+			continue
+		}
+		s, end := literalString(body, i)
+		fmt.Fprintf(&out, "<%x>", encrypt(s))
+		i = end
+	}
+	return out.String()
+}
+
+// literalString returns the bytes of the literal string starting at the
+// parenthesis at start, and the index of its closing parenthesis
+func literalString(body string, start int) (string, int) {
+	escapes := map[byte]byte{'n': '\n', 'r': '\r', 't': '\t', 'b': '\b', 'f': '\f'}
+	var s []byte
+	depth := 1
+	i := start + 1
+	for ; ; i++ {
+		c := body[i]
+		if c == '\\' {
+			i++
+			n := 0
+			for n < 3 && body[i+n] >= '0' && body[i+n] <= '7' {
+				n++
+			}
+			if n > 0 {
+				code, _ := strconv.ParseUint(body[i:i+n], 8, 8)
+				s = append(s, byte(code))
+				i += n - 1
+			} else if unescaped, ok := escapes[body[i]]; ok {
+				s = append(s, unescaped)
+			} else {
+				s = append(s, body[i])
+			}
+			continue
+		}
+		if c == '(' {
+			depth++
+		} else if c == ')' {
+			if depth--; depth == 0 {
+				return string(s), i
+			}
+		}
+		s = append(s, c)
+	}
+}
+
+// showText returns the operators drawing s at x, y in a font of the test PDF
+func showText(x, y float64, s, font string, size float64) string {
+	return fmt.Sprintf("BT /%s %g Tf %g %g Td %s Tj ET\n", font, size, x, y, encodeText(s))
+}
+
+// markContent wraps operators in a marked-content sequence of tag with an MCID
+func markContent(tag string, mcid int, content string) string {
+	return fmt.Sprintf("/%s << /MCID %d >> BDC\n%sEMC\n", tag, mcid, content)
+}
+
+// encodeText writes s as a PDF string in the glyph codes decodePDFText
+// reads, which are shifted 29 below the characters they show. Only
+// characters up to U+009C fit, since the reader takes codes past 0x7F for
+// invalid text.
+func encodeText(s string) string {
+	var b strings.Builder
+	b.WriteByte('(')
+	for _, r := range s {
+		code := r - 29
+		switch {
+		case code == '(' || code == ')' || code == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(code)
+		case code < 32 || code > 126:
+			fmt.Fprintf(&b, "\\%03o", code)
+		default:
+			b.WriteRune(code)
+		}
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+// body shows each line of text in the regular font, 18 points apart from
+// the top of the page down
+func body(lines ...string) string {
+	var b strings.Builder
+	for i, line := range lines {
+		b.WriteString(showText(72, 700-float64(i)*18, line, "F1", 12))
+	}
+	return b.String()
+}
+
+// convert converts a test PDF, failing the test on errors
+func convert(t testing.TB, c *Converter, p *testPDF) string {
+	t.Helper()
+	data := p.bytes()
+	var out bytes.Buffer
+	if err := c.ToMarkdownFromReader(bytes.NewReader(data), int64(len(data)), "test.pdf", &out); err != nil {
+		t.Fatalf("ToMarkdownFromReader() error = %v", err)
+	}
+	return out.String()
+}
+
+// taggedBuilder builds a tagged one-page document: text is drawn from the top
+// of the page down, each run in a marked-content sequence of its own, and
+// structure elements refer to the runs
+type taggedBuilder struct {
+	testPDF
+	content strings.Builder
+	mcid    int
+}
+
+// text draws s on the next line and returns its MCID as a structure kid
+func (b *taggedBuilder) text(s string) string {
+	y := 700 - float64(b.mcid)*18
+	b.content.WriteString(markContent("P", b.mcid, showText(72, y, s, "F1", 12)))
+	b.mcid++
+	return fmt.Sprint(b.mcid - 1)
+}
+
+// elem appends a structure element of a type and returns its reference
+func (b *taggedBuilder) elem(typ string, kids ...string) string {
+	return fmt.Sprintf("%d 0 R", b.add(fmt.Sprintf("<< /Type /StructElem /S /%s /Pg PAGE1 /K [%s] >>", typ, strings.Join(kids, " "))))
+}
+
+// build writes the structure tree holding kids, with extra entries of its
+// root such as a role map, and the page
+func (b *taggedBuilder) build(extra string, kids ...string) *testPDF {
+	root := b.add(fmt.Sprintf("<< /Type /StructTreeRoot /K [%s] %s >>", strings.Join(kids, " "), extra))
+	b.catalog = fmt.Sprintf("/StructTreeRoot %d 0 R /MarkInfo << /Marked true >>", root)
+	b.pageWith(b.content.String(), "/StructParents 0", "")
+	return &b.testPDF
+}
+
+// openTestPDF opens a test PDF with the PDF library, failing the test on errors
+func openTestPDF(t testing.TB, p *testPDF) *pdf.Reader {
+	t.Helper()
+	data := p.bytes()
+	reader, err := pdf.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+	return reader
+}
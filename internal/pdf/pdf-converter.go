@@ -1,17 +1,139 @@
 package pdf
 
 import (
+	"errors"
 	"fmt"
+	"html"
+	"io"
+	"math"
 	"os"
+	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"unicode"
 
+	"github.com/leandrowiemesfilho/markdown-converter/internal/errs"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/utils"
 	"github.com/rsc/pdf"
 )
 
+// autoTagCount is the number of tags derived when AutoTags is enabled
+const autoTagCount = 5
+
 type Converter struct {
 	AssetsDir string
+	// AssetsLink is the path prefix used to reference assets from the Markdown,
+	// defaulting to AssetsDir
+	AssetsLink string
+	// AssetNamer names extracted assets, keeping positional names when nil
+	AssetNamer  *utils.AssetNamer
+	AutoTags    bool
+	UseTags     bool
+	ImageFormat string
+	Dialect     markdown.Dialect
+	// PreserveColors keeps highlight and text colors as inline HTML styles
+	PreserveColors bool
+	// KeepFontInfo wraps each run of text in a span carrying its font name
+	// and size as data-font and data-size attributes, for inspecting the
+	// fonts detection worked from
+	KeepFontInfo bool
+	// SummaryOnly emits only the detected headings
+	SummaryOnly bool
+	// ListStyle selects the list item markers
+	ListStyle markdown.ListStyle
+	// Math renders runs in math fonts as inline LaTeX, and centered equations
+	// on lines of their own as display math
+	Math bool
+	// Layers names the optional content groups to show; when empty the
+	// document's default layer visibility applies
+	Layers []string
+	// NoPageBreaks omits page separators and continues paragraphs across pages
+	NoPageBreaks bool
+	// MergeTables joins a table continued at the top of a page to the table
+	// ending the page before it, dropping its repeated header
+	MergeTables bool
+	// UnderlineEmphasis renders underlined text as emphasis instead of HTML
+	UnderlineEmphasis bool
+	// ConvertAttachment, when set, converts supported embedded files
+	ConvertAttachment AttachmentConverter
+	// PortfolioConverter, when set, converts the documents of a PDF portfolio,
+	// which are then rendered as one section each instead of the portfolio pages
+	PortfolioConverter AttachmentConverter
+	// KVPairs renders label/value lines as "**Label:** value" instead of table rows
+	KVPairs bool
+	// KeepPageNumbers records running page numbers as HTML comments instead of dropping them
+	KeepPageNumbers bool
+	// DropSourceTOC leaves out the document's own table of contents entries
+	// instead of rendering them as a list of links
+	DropSourceTOC bool
+	// Jobs is the number of pages extracted concurrently; 0 or 1 extracts them in order
+	Jobs int
+	// PreserveEmptyCells aligns the cells of borderless tables to their columns,
+	// keeping empty cells in place
+	PreserveEmptyCells bool
+	// FlattenTables renders each table row as "**Header:** value" pairs instead of a pipe table
+	FlattenTables bool
+	// AnchorLinks turns internal links into links to the heading at their destination
+	AnchorLinks bool
+	// MaxHeadingLevel is the deepest heading level written, deeper headings
+	// being demoted to it. Zero leaves levels uncapped.
+	MaxHeadingLevel int
+	// StripWatermarks leaves out watermark artifacts and large slanted or
+	// translucent text drawn across the page
+	StripWatermarks bool
+	// GapBreaks, when positive, separates blocks of text further apart than
+	// this many times the page's line spacing with a thematic break
+	GapBreaks float64
+	// HeadingThreshold, when above zero, takes lines whose heading score
+	// reaches it as headings instead of applying the fixed size rules, and
+	// levels numbered headings by the depth of their number
+	HeadingThreshold float64
+	// NotesAppendix collects the comments of annotations into a "Notes"
+	// section at the end, each linking back to an anchor where it is attached
+	NotesAppendix bool
+	// StripCodeLineNumbers renders listings set in a monospaced font as fenced
+	// code, leaving out the line numbers printed in their gutter
+	StripCodeLineNumbers bool
+	// PreserveTabs renders runs of lines set at shared tab stops, outside
+	// bordered tables, as fenced code with a tab at every stop
+	PreserveTabs bool
+	// PreserveCellSpacing keeps the spacing of table cell text as it is set,
+	// measured from the positions of its glyphs, including the padding that
+	// opens or closes a cell, as non-breaking spaces
+	PreserveCellSpacing bool
+	// ListDetection is how readily lines are taken as list items: low,
+	// medium or high, defaulting to medium
+	ListDetection string
+	// Dehyphenate rejoins words hyphenated at the end of a line
+	Dehyphenate bool
+	// DetectColumns reads pages set in two columns of text column by column
+	// instead of across the gutter
+	DetectColumns bool
+	// Report, when set, receives the classification of the lines of every
+	// page once the document is converted
+	Report func(pages []PageReport)
+}
+
+// document holds the state of a single conversion
+type document struct {
+	reader *pdf.Reader
+	file   io.ReaderAt
+	name   string
+	tree   *structNode
+	layers *layerSet
+	fonts  *fontCache
+	// cover is the title block found on the first page, if any
+	cover *coverBlock
+	// links collects internal links and headings when AnchorLinks is set
+	links *linkIndex
+	// notes are the comment annotations of each page, collected for NotesAppendix
+	notes [][]note
+	// reports are the classified lines of each page, collected for Report
+	reports [][]LineReport
+	// turns orders the naming of the assets of concurrently extracted pages
+	turns *pageTurns
 }
 
 // TextElement represents a piece of text with its styling and position
@@ -23,6 +145,23 @@ type TextElement struct {
 	Y      float64
 	Width  float64
 	Height float64
+	MCID   int
+	// Highlight is the color of a highlight annotation covering the text
+	Highlight string
+	// Rise is the offset of a superscript or subscript from its line's baseline
+	Rise float64
+	// Underline is set when a rule is drawn just below the glyph
+	Underline bool
+	// Image marks an inline image, in which case Text holds its Markdown reference
+	Image bool
+	// Color is the fill color of the glyph as #rrggbb, empty for black
+	Color string
+	// Link is the placeholder ID of an internal link covering the glyph
+	Link string
+	// Vertical marks a glyph set in a vertical writing mode font
+	Vertical bool
+	// Watermark marks a glyph drawn the way watermarks are, at a slant or translucent
+	Watermark bool
 }
 
 // TextLine represents a line of text with its elements
@@ -31,9 +170,21 @@ type TextLine struct {
 	Y        float64
 	FontSize float64
 	IsBold   bool
+	MCID     int
+	// Image is the Markdown reference of an image placed at this position,
+	// in which case the line has no elements
+	Image string
+	// Block is Markdown rendered ahead of time, such as a bordered table,
+	// in which case the line has no elements
+	Block string
+	// Box is one more than the index of the shaded box the line lies in, 0 for none
+	Box int
+	// MinX and MaxX are the horizontal extent of an image or block line,
+	// which has no elements to measure
+	MinX, MaxX float64
 }
 
-func (c *Converter) ToMarkdown(inputPath, outputPath string) error {
+func (c *Converter) ToMarkdown(inputPath string, w io.Writer) error {
 	// Open the PDF file
 	f, err := os.Open(inputPath)
 	if err != nil {
@@ -41,107 +192,357 @@ func (c *Converter) ToMarkdown(inputPath, outputPath string) error {
 	}
 	defer f.Close()
 
-	// Get file size for PDF reader
 	fileInfo, err := f.Stat()
 	if err != nil {
 		return fmt.Errorf("failed to get file info: %v", err)
 	}
+	return c.ToMarkdownFromReader(f, fileInfo.Size(), inputPath, w)
+}
+
+// ToMarkdownFromReader converts a PDF of size bytes read from r, such as a
+// bytes.Reader holding a document that never touched the disk. The name
+// stands in for the file name where the output refers to its source.
+func (c *Converter) ToMarkdownFromReader(r io.ReaderAt, size int64, name string, w io.Writer) (err error) {
+	// The PDF library panics on malformed objects
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", errs.ErrCorrupt, r)
+		}
+	}()
 
-	// Create PDF reader with file size
-	reader, err := pdf.NewReader(f, fileInfo.Size())
+	// Each call reads through a reader of its own; only its pages share it
+	reader, err := newReader(r, size)
 	if err != nil {
-		return fmt.Errorf("failed to create PDF reader: %v", err)
+		return err
 	}
 
 	// Get number of pages
 	numPages := reader.NumPage()
 	if numPages == 0 {
-		return fmt.Errorf("PDF contains no pages")
+		return errs.ErrNoPages
 	}
 
-	// Create output file
-	outFile, err := os.Create(outputPath)
-	if err != nil {
-		return fmt.Errorf("failed to create output file: %v", err)
+	doc := &document{
+		reader: reader,
+		file:   r,
+		name:   strings.TrimSuffix(filepath.Base(name), filepath.Ext(name)),
+		layers: newLayerSet(reader, c.Layers),
+		fonts:  newFontCache(),
 	}
-	defer outFile.Close()
 
-	// Process each page
-	for pageNum := 1; pageNum <= numPages; pageNum++ {
-		page := reader.Page(pageNum)
-		if page.V.IsNull() {
-			continue // Skip empty pages
-		}
+	if c.AnchorLinks {
+		doc.links = newLinkIndex(numPages)
+	}
+	if c.NotesAppendix {
+		doc.notes = make([][]note, numPages)
+	}
+	if c.Report != nil {
+		doc.reports = make([][]LineReport, numPages)
+	}
 
-		// Extract structured text from the page
-		markdown, err := c.extractStructuredText(page)
+	// The documents of an encrypted portfolio cannot be extracted, so its
+	// cover page is converted as any other page, listing them as attachments
+	if c.PortfolioConverter != nil && isPortfolio(reader) && !isEncrypted(reader) {
+		text, err := c.renderPortfolio(doc)
 		if err != nil {
-			return fmt.Errorf("failed to extract text from page %d: %v", pageNum, err)
+			return err
 		}
+		frontMatter := c.buildFrontMatter(doc, text)
+		if _, err := io.WriteString(w, frontMatter.String()+strings.TrimSpace(text)+"\n"); err != nil {
+			return fmt.Errorf("failed to write markdown: %v", err)
+		}
+		return nil
+	}
 
+	// Prefer the structure tree of tagged PDFs when requested
+	if c.UseTags {
+		doc.tree = parseStructTree(reader)
+	}
+
+	// Extract structured text from each page
+	pages, err := c.extractPages(doc, numPages)
+	if err != nil {
+		return err
+	}
+
+	var body strings.Builder
+	for i, markdown := range pages {
+		pageNum := i + 1
 		if strings.TrimSpace(markdown) == "" {
 			continue
 		}
 
 		// Write the structured content
-		outFile.WriteString(markdown)
-		outFile.WriteString("\n\n")
+		if merged, ok := c.continueTable(body.String(), markdown); ok {
+			body.Reset()
+			body.WriteString(merged)
+		} else if c.NoPageBreaks && body.Len() > 0 {
+			joined := joinPageFlow(body.String(), markdown)
+			body.Reset()
+			body.WriteString(joined)
+		} else {
+			body.WriteString(markdown)
+		}
+		body.WriteString("\n\n")
 
 		// Add page separator (except for last page)
-		if pageNum < numPages {
-			outFile.WriteString("---\n\n")
+		if pageNum < numPages && !c.SummaryOnly && !c.NoPageBreaks {
+			body.WriteString("---\n\n")
+		}
+	}
+
+	if !c.SummaryOnly {
+		attachments, err := c.extractAttachments(doc)
+		if err != nil {
+			return err
+		}
+		body.WriteString(attachments)
+	}
+	if doc.notes != nil && !c.SummaryOnly {
+		if notes := c.renderNotes(doc.notes); notes != "" {
+			if !strings.HasSuffix(body.String(), "\n\n") {
+				body.WriteString("\n")
+			}
+			body.WriteString(notes)
+		}
+	}
+
+	text := body.String()
+	if doc.links != nil {
+		text = resolveLinks(doc, text)
+	}
+
+	frontMatter := c.buildFrontMatter(doc, text)
+	if _, err := io.WriteString(w, frontMatter.String()+text); err != nil {
+		return fmt.Errorf("failed to write markdown: %v", err)
+	}
+	if doc.reports != nil {
+		pages := make([]PageReport, numPages)
+		for i, lines := range doc.reports {
+			pages[i] = PageReport{Page: i + 1, Lines: lines}
 		}
+		c.Report(pages)
 	}
 
 	return nil
 }
 
-func (c *Converter) extractStructuredText(page pdf.Page) (string, error) {
-	content := page.Content()
+// openReader creates a PDF reader over an open file
+func openReader(f *os.File) (*pdf.Reader, error) {
+	// Get file size for PDF reader
+	fileInfo, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file info: %v", err)
+	}
+	return newReader(f, fileInfo.Size())
+}
+
+// newReader creates a PDF reader over size bytes of r, classifying failures
+// as encrypted or corrupt documents
+func newReader(r io.ReaderAt, size int64) (*pdf.Reader, error) {
+	reader, err := pdf.NewReader(r, size)
+	if err == pdf.ErrInvalidPassword || err != nil && strings.Contains(err.Error(), "encrypt") {
+		return nil, fmt.Errorf("%w: %v", errs.ErrEncrypted, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to create PDF reader: %v", errs.ErrCorrupt, err)
+	}
+	return reader, nil
+}
+
+// isEncrypted reports whether a document is encrypted. The PDF library
+// decrypts its text, but the streams read raw, such as those of images and
+// attachments, are left encrypted.
+func isEncrypted(reader *pdf.Reader) bool {
+	return !reader.Trailer().Key("Encrypt").IsNull()
+}
+
+// buildFrontMatter collects front matter fields from the document info dictionary,
+// falling back to derived tags when the document carries no keywords
+func (c *Converter) buildFrontMatter(doc *document, body string) markdown.FrontMatter {
+	var fm markdown.FrontMatter
+	if doc.cover != nil {
+		fm.Title = doc.cover.Title
+		fm.Subtitle = doc.cover.Subtitle
+		fm.Author = doc.cover.Author
+		fm.Date = doc.cover.Date
+	}
+
+	info := doc.reader.Trailer().Key("Info")
+	fm.Tags = markdown.ParseKeywords(info.Key("Keywords").Text())
+	if len(fm.Tags) == 0 && c.AutoTags {
+		fm.Tags = markdown.DeriveTags(body, autoTagCount)
+	}
+
+	return fm
+}
+
+func (c *Converter) extractStructuredText(doc *document, pageNum int, page pdf.Page) (string, error) {
+	content := c.readPageContent(doc, page)
 
 	// Extract all text elements with their properties
 	var elements []TextElement
-	for _, text := range content.Text {
-		decoded := decodePDFText(text.S)
-		if decoded == "" {
+	for _, element := range content.Elements {
+		element.Text = mapSymbolFont(element.Font, decodePDFText(element.Text))
+		if element.Text == "" {
 			continue
 		}
+		elements = append(elements, element)
+	}
+	if c.StripWatermarks {
+		elements = stripWatermarks(elements)
+	}
+	elements = vectorBullets(elements, content.Dots)
+	if doc.notes != nil {
+		notes := readNotes(page, pageNum)
+		elements = placeNotes(elements, notes)
+		doc.notes[pageNum-1] = notes
+	}
+	groups := groupBorders(content.Borders)
+	grids := findTableGrids(groups)
+	markHighlights(elements, readHighlights(page))
+	if doc.links != nil {
+		markLinks(doc, pageNum, elements, readLinks(doc, page))
+	}
+	markUnderlines(elements, excludeGridRules(content.Rules, grids))
+	mergeDropCaps(elements)
+	if c.Math {
+		attachScripts(elements)
+	}
+	attachChemicalSubscripts(elements)
+
+	// Tagged content maps directly to Markdown structure; images, bordered
+	// tables and sidebars are placed by the page layout as on untagged pages
+	tagged := doc.tree != nil && hasMarkedContent(elements)
+
+	// Extract images. Small images within a line of text join that line, the
+	// rest are placed among the lines by their top edge.
+	var blocks []TextLine
+	elements, vertical := c.extractVerticalText(elements)
+	if !c.SummaryOnly {
+		blocks = append(blocks, vertical...)
+		var tables []TextLine
+		elements, tables = c.extractBorderedTables(elements, grids)
+		blocks = append(blocks, tables...)
+		var sidebars []TextLine
+		elements, sidebars = c.extractSidebars(elements, findFrames(groups))
+		blocks = append(blocks, sidebars...)
+	}
+	var skipped []LineReport
+	if len(content.Images) > 0 && !c.SummaryOnly && c.ImageFormat != ImageFormatNone {
+		var inline []TextElement
+		for i, img := range content.Images {
+			ref, err := c.extractImage(doc, img, pageNum, i+1)
+			if errors.Is(err, errs.ErrEncrypted) {
+				// The text of the page decrypts, so only the image is left out
+				skipped = append(skipped, reportImage("skipped-image", img, err.Error()))
+				continue
+			}
+			if err != nil {
+				return "", fmt.Errorf("failed to extract image %d: %w", i+1, err)
+			}
+			// Tagged pages render their text from the structure tree, which
+			// inline images would not be part of
+			if anchor, ok := inlineAnchor(elements, img); ok && !tagged {
+				inline = append(inline, TextElement{
+					Text:  ref,
+					Font:  anchor.Font,
+					Size:  anchor.Size,
+					X:     img.X,
+					Y:     anchor.Y,
+					Width: img.Width,
+					MCID:  anchor.MCID,
+					Image: true,
+				})
+				continue
+			}
+			blocks = append(blocks, TextLine{Y: img.Y + img.Height, MCID: noMCID, Image: ref, MinX: img.X, MaxX: img.X + img.Width})
+		}
+		elements = append(elements, inline...)
+	}
+	doc.turns.finish(pageNum)
 
-		element := TextElement{
-			Text:  decoded,
-			Font:  text.Font,
-			Size:  text.FontSize,
-			X:     text.X,
-			Y:     text.Y,
-			Width: text.W,
+	// Group elements into lines, leaving out running page numbers
+	lines, header, footer := stripPageNumbers(c.groupElementsIntoLines(elements))
+	gutter, columns := 0.0, false
+	if c.DetectColumns && !tagged {
+		if gutter, columns = findGutter(lines); columns {
+			lines = splitColumns(lines, gutter)
 		}
-		elements = append(elements, element)
 	}
+	if tagged {
+		lines = c.convertTaggedPage(doc.tree, page, elements, c.SummaryOnly, c.ListStyle)
+	}
+	markBoxes(lines, content.Boxes)
 
-	if len(elements) == 0 {
-		return "", nil
+	// The title block of a cover page becomes the document heading and front matter
+	title := ""
+	if pageNum == 1 && !tagged {
+		if doc.cover, lines = c.detectCover(page, lines); doc.cover != nil {
+			title = "# " + doc.cover.Title + "\n\n"
+		}
 	}
 
-	// Group elements into lines
-	lines := c.groupElementsIntoLines(elements)
+	if len(blocks) > 0 {
+		lines = append(lines, blocks...)
+		sort.SliceStable(lines, func(i, j int) bool {
+			return lines[i].Y > lines[j].Y
+		})
+		orderByMarkedContent(lines)
+	}
+	if columns {
+		orderByColumns(lines, gutter)
+	}
+
+	if len(lines) == 0 {
+		if doc.reports != nil {
+			doc.reports[pageNum-1] = skipped
+		}
+		return title, nil
+	}
 
 	// Detect document structure and convert to Markdown
-	markdown := c.convertLinesToMarkdown(lines)
+	body, headings, report := c.convertLinesToMarkdown(lines)
+	if c.Dehyphenate {
+		body = dehyphenate(body)
+	}
+	if doc.reports != nil {
+		doc.reports[pageNum-1] = append(report, skipped...)
+	}
+	if doc.links != nil {
+		doc.links.headings[pageNum-1] = headings
+	}
+	markdown := title + body
+	if c.KeepPageNumbers {
+		if header != "" {
+			markdown = "<!-- page " + header + " -->\n\n" + markdown
+		}
+		if footer != "" {
+			markdown = strings.TrimRight(markdown, "\n") + "\n\n<!-- page " + footer + " -->\n"
+		}
+	}
 
 	return markdown, nil
 }
 
 func (c *Converter) groupElementsIntoLines(elements []TextElement) []TextLine {
-	// Group elements by Y coordinate (same line)
+	// Group elements by Y coordinate (same line), keeping the order in which
+	// lines first appear so the output does not depend on map iteration
 	lineMap := make(map[float64][]TextElement)
+	var ys []float64
 	for _, element := range elements {
+		if _, ok := lineMap[element.Y]; !ok {
+			ys = append(ys, element.Y)
+		}
 		lineMap[element.Y] = append(lineMap[element.Y], element)
 	}
 
-	// Sort elements within each line by X coordinate
+	// Sort elements within each line by X coordinate. Elements at the same
+	// position keep their content stream order.
 	var lines []TextLine
-	for y, lineElements := range lineMap {
-		sort.Slice(lineElements, func(i, j int) bool {
+	for _, y := range ys {
+		lineElements := lineMap[y]
+		sort.SliceStable(lineElements, func(i, j int) bool {
 			return lineElements[i].X < lineElements[j].X
 		})
 
@@ -151,77 +552,488 @@ func (c *Converter) groupElementsIntoLines(elements []TextElement) []TextLine {
 			Y:        y,
 			FontSize: lineElements[0].Size, // Use first element's size as reference
 			IsBold:   isBoldFont(lineElements[0].Font),
+			MCID:     firstMCID(lineElements),
 		}
 		lines = append(lines, line)
 	}
 
 	// Sort lines by Y coordinate (top to bottom)
-	sort.Slice(lines, func(i, j int) bool {
+	sort.SliceStable(lines, func(i, j int) bool {
 		return lines[i].Y > lines[j].Y // Higher Y means lower on page
 	})
 
+	orderByMarkedContent(lines)
+
 	return lines
 }
 
-func (c *Converter) convertLinesToMarkdown(lines []TextLine) string {
+// firstMCID returns the lowest marked-content ID among the elements of a line
+func firstMCID(elements []TextElement) int {
+	mcid := noMCID
+	for _, element := range elements {
+		if element.MCID != noMCID && (mcid == noMCID || element.MCID < mcid) {
+			mcid = element.MCID
+		}
+	}
+	return mcid
+}
+
+// orderByMarkedContent reorders lines that belong to marked-content sequences by
+// their MCID, which reflects the logical reading order of tagged PDFs. Untagged
+// lines such as artifacts keep their geometric position.
+func orderByMarkedContent(lines []TextLine) {
+	var slots []int
+	var marked []TextLine
+	for i, line := range lines {
+		if line.MCID != noMCID {
+			slots = append(slots, i)
+			marked = append(marked, line)
+		}
+	}
+
+	sort.SliceStable(marked, func(i, j int) bool {
+		return marked[i].MCID < marked[j].MCID
+	})
+
+	for i, slot := range slots {
+		lines[slot] = marked[i]
+	}
+}
+
+// convertLinesToMarkdown renders the lines of a page, also returning the
+// headings written so internal links can be pointed at them and, when Report
+// is set, the classification of each line
+func (c *Converter) convertLinesToMarkdown(lines []TextLine) (string, []pageHeading, []LineReport) {
 	var result strings.Builder
+	var headings []pageHeading
+	var report []LineReport
+	classify := func(kind string, line TextLine, text string) {
+		if c.Report != nil {
+			report = append(report, reportLine(kind, line, text))
+		}
+	}
 	var previousLine *TextLine
-	var inList bool
+	var inList, inQuote bool
+	var pullQuote []string
+	var attribution string
+	var table [][]tableCell
+	var note *callout
+
+	layout := measureLayout(lines)
+	quoted := layout.blockquoteLines(lines)
+	spacing := lineSpacing(lines, layout.BodySize)
+	for i, italic := range layout.italicQuoteLines(lines, spacing) {
+		quoted[i] = quoted[i] || italic
+	}
+	// above is the text line above, unset after an image or block whose own
+	// extent would count as a gap
+	var above *TextLine
+	// Code listings and diagrams are rendered as a whole, skipping their
+	// lines up to skip
+	var listings map[int]int
+	skip := 0
+	if c.StripCodeLineNumbers && !c.SummaryOnly {
+		listings = codeListings(lines)
+	} else if !c.SummaryOnly {
+		listings = diagramListings(lines)
+	}
+	var tabbed map[int]int
+	if c.PreserveTabs && !c.SummaryOnly {
+		tabbed = tabbedListings(lines, listings)
+		for start, end := range tabbed {
+			listings[start] = end
+		}
+	}
+
+	// Rows of a borderless table are collected and rendered together once the
+	// table ends, so they can be given the same columns
+	endTable := func() {
+		if len(table) > 0 {
+			result.WriteString("\n" + c.renderTableRows(table) + "\n\n")
+			table = nil
+		}
+	}
+
+	// Callouts continue over the following lines of their shaded box
+	endCallout := func() {
+		if note != nil {
+			result.WriteString(c.Dialect.Callout(note.Kind, note.Lines) + "\n\n")
+			note = nil
+		}
+	}
+
+	// Pull quotes are collected across lines and closed by the next block
+	closeQuotes := func() {
+		if len(pullQuote) > 0 {
+			quote := strings.Join(pullQuote, " ")
+			if attribution != "" {
+				quote += " <cite>" + attribution + "</cite>"
+			}
+			result.WriteString(`<blockquote class="pullquote">` + quote + "</blockquote>\n\n")
+			pullQuote = nil
+			attribution = ""
+		}
+		if inQuote {
+			result.WriteString("\n")
+			inQuote = false
+		}
+	}
 
 	for i, line := range lines {
+		if i < skip {
+			continue
+		}
+		if line.Image != "" || line.Block != "" {
+			endTable()
+			endCallout()
+			closeQuotes()
+			if inList {
+				result.WriteString("\n")
+				inList = false
+			}
+			result.WriteString(line.Image + line.Block + "\n\n")
+			if line.Image != "" {
+				classify("image", line, line.Image)
+			} else {
+				classify("block", line, strings.SplitN(line.Block, "\n", 2)[0])
+			}
+			above = nil
+			continue
+		}
+
 		lineText := c.extractLineText(line)
 		if strings.TrimSpace(lineText) == "" {
 			continue
 		}
+		// The space below a heading is its own and does not count as a gap
+		if c.GapBreaks > 0 && above != nil && above.FontSize <= layout.BodySize*1.2 &&
+			above.Y-line.Y > c.GapBreaks*spacing && !c.SummaryOnly {
+			endTable()
+			endCallout()
+			closeQuotes()
+			if inList {
+				result.WriteString("\n")
+				inList = false
+			}
+			result.WriteString("---\n\n")
+		}
+		above = &line
+		if end, ok := listings[i]; ok {
+			endTable()
+			endCallout()
+			closeQuotes()
+			if inList {
+				result.WriteString("\n")
+				inList = false
+			}
+			block, kind := renderListing(lines[i:end]), "code"
+			if _, ok := tabbed[i]; ok {
+				block, kind = renderTabbed(lines[i:end]), "tabbed"
+			} else if !c.StripCodeLineNumbers {
+				block, kind = renderDiagram(lines[i:end]), "diagram"
+			}
+			result.WriteString(block + "\n\n")
+			for _, listed := range lines[i:end] {
+				classify(kind, listed, c.extractLineText(listed))
+			}
+			skip = end
+			above = &lines[end-1]
+			previousLine = &lines[end-1]
+			continue
+		}
+		if note != nil {
+			if note.Box != 0 && line.Box == note.Box {
+				note.Lines = append(note.Lines, strings.TrimSpace(lineText))
+				classify("callout", line, lineText)
+				previousLine = &line
+				continue
+			}
+			endCallout()
+		}
+		cells := c.tableCells(line)
+		if len(cells) < 2 {
+			endTable()
+		}
+
+		// An attribution line closing a quote is kept inside it
+		if author, ok := parseAttribution(lineText); ok && !c.SummaryOnly && attribution == "" && (len(pullQuote) > 0 || inQuote) {
+			if len(pullQuote) > 0 {
+				attribution = author
+			} else {
+				result.WriteString(">\n> " + author + "\n")
+			}
+			classify("attribution", line, lineText)
+			previousLine = &line
+			continue
+		}
+
+		// Equations set on a line of their own
+		if c.Math && !c.SummaryOnly {
+			if block, ok := layout.displayMath(line); ok {
+				endTable()
+				closeQuotes()
+				if inList {
+					result.WriteString("\n")
+					inList = false
+				}
+				result.WriteString(block + "\n\n")
+				classify("math", line, lineText)
+				previousLine = &line
+				continue
+			}
+		}
+
+		if !c.SummaryOnly && layout.isPullQuote(line, lineText) {
+			if inQuote || inList {
+				closeQuotes()
+				if inList {
+					result.WriteString("\n")
+					inList = false
+				}
+			}
+			endTable()
+			pullQuote = append(pullQuote, strings.TrimSpace(lineText))
+			classify("pull-quote", line, lineText)
+			previousLine = &line
+			continue
+		}
+		if len(pullQuote) > 0 || (inQuote && !quoted[i]) {
+			closeQuotes()
+		}
+
+		// Entries of the source's table of contents link to the headings they name
+		if entry, ok := layout.parseTOCLine(line, lineText); ok && !c.SummaryOnly {
+			endTable()
+			if !c.DropSourceTOC {
+				if !inList {
+					result.WriteString("\n")
+				}
+				title := strings.TrimSpace(entry.Title)
+				result.WriteString(strings.Repeat("  ", entry.Level) + c.ListStyle.BulletMarker() + " [" + title + "](#" + markdown.Slug(title) + ")\n")
+				inList = true
+			}
+			classify("toc", line, lineText)
+			previousLine = &line
+			continue
+		}
+
+		// Note and warning boxes become alert blockquotes
+		if co, ok := parseCallout(line, lineText); ok && !c.SummaryOnly {
+			endTable()
+			if inList {
+				result.WriteString("\n")
+				inList = false
+			}
+			note = &co
+			classify("callout", line, lineText)
+			previousLine = &line
+			continue
+		}
 
 		// Detect heading based on font size and style
-		if c.isHeading(line, previousLine) {
+		heading := c.isHeading(line, previousLine)
+		if c.HeadingThreshold > 0 {
+			heading = headingScore(lines, i, lineText, layout, spacing) >= c.HeadingThreshold
+		}
+		// Ornaments set large between sections, and markup whose text was
+		// dropped, would leave a heading without a title
+		if heading && !hasHeadingText(lineText) {
+			endTable()
+			if inList {
+				result.WriteString("\n")
+				inList = false
+			}
+			if plainHeading(lineText) != "" && !c.SummaryOnly {
+				result.WriteString(strings.TrimSpace(lineText) + "\n\n")
+				classify("paragraph", line, lineText)
+			}
+			previousLine = &line
+			continue
+		}
+		if heading {
+			endTable()
 			level := c.getHeadingLevel(line)
+			if n := numberedLevel(lineText); n > 0 && c.HeadingThreshold > 0 {
+				level = capHeadingLevel(n, c.MaxHeadingLevel)
+			}
 			result.WriteString(strings.Repeat("#", level) + " " + lineText + "\n")
+			headings = append(headings, pageHeading{Top: line.Y + line.FontSize, Text: plainHeading(lineText)})
+			classify("heading", line, lineText)
 			inList = false
-		} else if c.isListItem(lineText) {
+		} else if c.SummaryOnly {
+			// Body text is dropped from summaries without further classification
+			classify("paragraph", line, lineText)
+		} else if item, ok := parseListMarker(lineText, c.ListDetection); ok {
 			// Detect list item
+			endTable()
 			if !inList {
 				result.WriteString("\n")
 			}
-			result.WriteString("- " + strings.TrimSpace(lineText[1:]) + "\n")
+			marker := c.ListStyle.BulletMarker()
+			if item.Label != "" {
+				marker = c.ListStyle.Relabel(item.Label)
+			}
+			if item.Task {
+				marker += " " + c.Dialect.TaskBox(item.Checked)
+			}
+			result.WriteString(marker + " " + item.Text + "\n")
+			classify("list", line, lineText)
 			inList = true
-		} else if c.isTableRow(line) {
+		} else if label, value, ok := splitKeyValue(line); ok && c.KVPairs {
+			// Fact sheet line with a label column and a value column
+			endTable()
+			if inList {
+				result.WriteString("\n")
+				inList = false
+			}
+			result.WriteString(c.renderKeyValue(label, value) + "\n\n")
+			classify("key-value", line, lineText)
+		} else if len(cells) >= 2 {
 			// Detect table row (based on alignment and multiple elements)
-			if i == 0 || !c.isTableRow(lines[i-1]) {
-				result.WriteString("\n") // Table separator before first row
+			if inList {
+				result.WriteString("\n")
+				inList = false
 			}
-			result.WriteString("| " + lineText + " |\n")
-			if i == 0 {
-				// Add header separator
-				separator := "|" + strings.Repeat(" --- |", len(line.Elements)) + "\n"
-				result.WriteString(separator)
+			table = append(table, cells)
+			classify("table", line, lineText)
+		} else if quoted[i] {
+			// Indented block of body text
+			if inList {
+				result.WriteString("\n")
+				inList = false
 			}
-			inList = false
+			result.WriteString("> " + strings.TrimSpace(lineText) + "\n")
+			classify("quote", line, lineText)
+			inQuote = true
 		} else {
 			// Regular paragraph
 			if inList {
 				result.WriteString("\n")
 				inList = false
 			}
+			if c.ListDetection == ListDetectionLow {
+				lineText = escapeDashMarker(lineText)
+			}
 
 			result.WriteString(lineText + "\n\n")
+			classify("paragraph", line, lineText)
 		}
 
 		previousLine = &line
 	}
+	endTable()
+	endCallout()
+	closeQuotes()
 
-	return result.String()
+	return result.String(), headings, report
+}
+
+// textRun identifies a sequence of elements rendered with the same inline markup
+type textRun struct {
+	color     string
+	math      bool
+	sub       bool
+	underline bool
+	image     bool
+	textColor string
+	link      string
+	font      string
+	size      float64
 }
 
 func (c *Converter) extractLineText(line TextLine) string {
 	var text strings.Builder
-	for _, element := range line.Elements {
-		text.WriteString(element.Text)
+	var run []TextElement
+	var current textRun
+	// spaceAfter is set when an inline image needs separating from the next run
+	spaceAfter := false
+
+	// Highlighted, math, subscript and underlined runs are wrapped as a whole
+	flush := func() {
+		if len(run) == 0 {
+			return
+		}
+		var s string
+		if current.image {
+			// Inline images are kept apart from the surrounding words
+			for _, element := range run {
+				if s != "" || text.Len() > 0 && !strings.HasSuffix(text.String(), " ") {
+					s += " "
+				}
+				s += element.Text
+			}
+			text.WriteString(s)
+			run = run[:0]
+			spaceAfter = true
+			return
+		}
+		if current.math {
+			s = renderMath(run)
+		} else {
+			for _, element := range run {
+				s += element.Text
+			}
+			if current.sub {
+				s = c.Dialect.Subscript(s)
+			}
+			if current.underline && c.UnderlineEmphasis {
+				s = markdown.Wrap(s, "_", "_")
+			} else if current.underline {
+				s = c.Dialect.Underline(s)
+			}
+		}
+		if current.textColor != "" {
+			s = markdown.Wrap(s, `<span style="color:`+current.textColor+`">`, "</span>")
+		}
+		if current.font != "" {
+			s = fontSpan(s, current.font, current.size)
+		}
+		if current.color != "" {
+			color := ""
+			if c.PreserveColors {
+				color = current.color
+			}
+			s = c.Dialect.Highlight(s, color)
+		}
+		if current.link != "" {
+			s = linkText(s, current.link)
+		}
+		if spaceAfter && !strings.HasPrefix(s, " ") {
+			s = " " + s
+		}
+		spaceAfter = false
+		text.WriteString(s)
+		run = run[:0]
+	}
+
+	for _, element := range spaceElements(line.Elements) {
+		key := textRun{color: element.Highlight, math: c.Math && isMathFont(element.Font), image: element.Image, link: element.Link}
+		if c.PreserveColors {
+			key.textColor = element.Color
+		}
+		if c.KeepFontInfo && !element.Image {
+			key.font, key.size = element.Font, element.Size
+		}
+		key.sub = !key.math && element.Rise < 0
+		key.underline = !key.math && element.Underline
+		if key != current {
+			flush()
+			current = key
+		}
+		run = append(run, element)
 	}
+	flush()
+
 	return text.String()
 }
 
+// fontSpan wraps a run of text in a span recording its font and size
+func fontSpan(s, font string, size float64) string {
+	open := fmt.Sprintf(`<span data-font="%s" data-size="%s">`,
+		html.EscapeString(font), strconv.FormatFloat(math.Round(size*100)/100, 'f', -1, 64))
+	return markdown.Wrap(s, open, "</span>")
+}
+
 func (c *Converter) isHeading(line TextLine, previousLine *TextLine) bool {
 	// Heading detection logic
 	if line.FontSize > 14 {
@@ -238,67 +1050,35 @@ func (c *Converter) isHeading(line TextLine, previousLine *TextLine) bool {
 
 func (c *Converter) getHeadingLevel(line TextLine) int {
 	// Simple heading level detection based on font size
+	level := 5
 	switch {
 	case line.FontSize >= 20:
-		return 1
+		level = 1
 	case line.FontSize >= 18:
-		return 2
+		level = 2
 	case line.FontSize >= 16:
-		return 3
+		level = 3
 	case line.FontSize >= 14:
-		return 4
-	default:
-		return 5
+		level = 4
 	}
+	return capHeadingLevel(level, c.MaxHeadingLevel)
 }
 
-func (c *Converter) isListItem(lineText string) bool {
-	// Detect list items (bullet points, numbers, etc.)
-	trimmed := strings.TrimSpace(lineText)
-	if len(trimmed) == 0 {
-		return false
-	}
-
-	// Check for bullet points
-	if strings.HasPrefix(trimmed, "•") || strings.HasPrefix(trimmed, "▪") ||
-		strings.HasPrefix(trimmed, "‣") || strings.HasPrefix(trimmed, "-") ||
-		strings.HasPrefix(trimmed, "𐀚") {
-		return true
-	}
-
-	// Check for numbered lists (1., 2., a., b., etc.)
-	if len(trimmed) > 2 && trimmed[1] == '.' {
-		firstChar := trimmed[0]
-		if (firstChar >= '0' && firstChar <= '9') ||
-			(firstChar >= 'a' && firstChar <= 'z') ||
-			(firstChar >= 'A' && firstChar <= 'Z') {
-			return true
-		}
-	}
-
-	return false
+// hasHeadingText reports whether the text of a heading has a letter or digit
+// to show once its markup is left out
+func hasHeadingText(lineText string) bool {
+	return strings.IndexFunc(plainHeading(lineText), func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsNumber(r)
+	}) >= 0
 }
 
-func (c *Converter) isTableRow(line TextLine) bool {
-	// Simple table detection - multiple elements with similar Y positions
-	if len(line.Elements) < 2 {
-		return false
-	}
-
-	// Check if elements are aligned in a table-like structure
-	for i := 1; i < len(line.Elements); i++ {
-		prev := line.Elements[i-1]
-		curr := line.Elements[i]
-
-		// If elements are too close together, probably not a table
-		if curr.X-prev.X-prev.Width < 5.0 {
-			return false
-		}
+// capHeadingLevel demotes a heading deeper than maxLevel to maxLevel, unless
+// maxLevel is zero
+func capHeadingLevel(level, maxLevel int) int {
+	if maxLevel > 0 && level > maxLevel {
+		return maxLevel
 	}
-
-	fmt.Println(line.Y, "is a table")
-
-	return true
+	return level
 }
 
 func isBoldFont(fontName string) bool {
@@ -309,6 +1089,14 @@ func isBoldFont(fontName string) bool {
 		strings.Contains(fontName, "heavy")
 }
 
+// isItalicFont reports whether a font name names an italic or oblique face
+func isItalicFont(fontName string) bool {
+	fontName = strings.ToLower(fontName)
+	return strings.Contains(fontName, "italic") ||
+		strings.Contains(fontName, "oblique") ||
+		strings.HasSuffix(fontName, "-it")
+}
+
 func decodePDFText(text string) string {
 	var result strings.Builder
 
@@ -0,0 +1,246 @@
+package pdf
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestFrontMatterTags(t *testing.T) {
+	text := body("Compilers translate source code.", "Compilers optimize source code.", "Linkers join object files.")
+	tests := []struct {
+		name     string
+		info     string
+		autoTags bool
+		want     string
+	}{
+		{"keywords", "<< /Keywords (go, pdf; markdown) >>", false, "tags:\n  - go\n  - pdf\n  - markdown\n"},
+		{"keywords win over auto tags", "<< /Keywords (go) >>", true, "tags:\n  - go\n"},
+		{"auto tags", "", true, "tags:\n  - code\n  - compilers\n  - source\n"},
+		{"no tags", "", false, ""},
+	}
+	for _, tt := range tests {
+		p := &testPDF{info: tt.info}
+		p.page(text)
+		md := convert(t, &Converter{AssetsDir: t.TempDir(), AutoTags: tt.autoTags}, p)
+		if tt.want == "" {
+			if strings.Contains(md, "tags:") {
+				t.Errorf("%s: unexpected tags in %q", tt.name, md)
+			}
+			continue
+		}
+		if !strings.HasPrefix(md, "---\n") || !strings.Contains(md, tt.want) {
+			t.Errorf("%s: front matter of %q lacks %q", tt.name, md, tt.want)
+		}
+	}
+}
+
+func TestOrderByMarkedContent(t *testing.T) {
+	line := func(text string, mcid int) TextLine {
+		return TextLine{Elements: []TextElement{{Text: text}}, MCID: mcid}
+	}
+	tests := []struct {
+		name  string
+		lines []TextLine
+		want  string
+	}{
+		{"untagged", []TextLine{line("a", noMCID), line("b", noMCID)}, "a b"},
+		{"by MCID", []TextLine{line("c", 2), line("a", 0), line("b", 1)}, "a b c"},
+		{"artifacts keep their slot", []TextLine{line("c", 5), line("header", noMCID), line("a", 1)}, "a header c"},
+		{"equal MCIDs keep their order", []TextLine{line("x", 3), line("y", 3), line("w", 1)}, "w x y"},
+	}
+	for _, tt := range tests {
+		orderByMarkedContent(tt.lines)
+		var got []string
+		for _, l := range tt.lines {
+			got = append(got, l.Elements[0].Text)
+		}
+		if strings.Join(got, " ") != tt.want {
+			t.Errorf("%s: order = %v, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFirstMCID(t *testing.T) {
+	tests := []struct {
+		mcids []int
+		want  int
+	}{
+		{nil, noMCID},
+		{[]int{noMCID, noMCID}, noMCID},
+		{[]int{4, 2, noMCID, 7}, 2},
+		{[]int{noMCID, 0}, 0},
+	}
+	for _, tt := range tests {
+		var elements []TextElement
+		for _, mcid := range tt.mcids {
+			elements = append(elements, TextElement{MCID: mcid})
+		}
+		if got := firstMCID(elements); got != tt.want {
+			t.Errorf("firstMCID(%v) = %d, want %d", tt.mcids, got, tt.want)
+		}
+	}
+}
+
+func TestMarkedContentReadingOrder(t *testing.T) {
+	// The second paragraph is drawn above the first, but marked as read after it
+	p := &testPDF{}
+	p.page(markContent("P", 1, showText(72, 700, "Read second.", "F1", 12)) +
+		markContent("P", 0, showText(72, 600, "Read first.", "F1", 12)) +
+		showText(72, 750, "Running header", "F1", 12))
+	got := strings.TrimSpace(convert(t, &Converter{}, p))
+	if want := "Running header\n\nRead first.\n\nRead second."; got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestSummaryOnly(t *testing.T) {
+	untagged := &testPDF{}
+	untagged.page(showText(72, 720, "Overview", "F2", 24) + body("Body text that is plain.", "More plain body text here.") +
+		showText(72, 640, "Details", "F2", 18) + showText(72, 620, "Closing body paragraph.", "F1", 12))
+	untagged.page(body("A second page of body text."))
+
+	b := &taggedBuilder{}
+	tagged := b.build("", b.elem("H1", b.text("Tagged title")), b.elem("P", b.text("Tagged body text.")), b.elem("H2", b.text("Tagged section")))
+
+	tests := []struct {
+		name string
+		c    *Converter
+		p    *testPDF
+		want string
+	}{
+		{"untagged", &Converter{SummaryOnly: true}, untagged, "# Overview\n## Details"},
+		{"tagged", &Converter{SummaryOnly: true, UseTags: true}, tagged, "# Tagged title\n\n## Tagged section"},
+	}
+	for _, tt := range tests {
+		if got := strings.TrimSpace(convert(t, tt.c, tt.p)); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMaxHeadingLevel(t *testing.T) {
+	untagged := &testPDF{}
+	untagged.page(showText(72, 720, "Overview", "F2", 24) + showText(72, 690, "Body text that is plain.", "F1", 12) +
+		showText(72, 660, "Minor", "F2", 14) + showText(72, 640, "More plain body text here.", "F1", 12) +
+		showText(72, 610, "Tiny", "F2", 13) + showText(72, 590, "Closing body paragraph.", "F1", 12))
+
+	b := &taggedBuilder{}
+	tagged := b.build("", b.elem("H1", b.text("Tagged title")), b.elem("H6", b.text("Deep section")), b.elem("P", b.text("Tagged body text.")))
+
+	tests := []struct {
+		name string
+		c    *Converter
+		p    *testPDF
+		want []string
+	}{
+		{"uncapped", &Converter{}, untagged, []string{"# Overview", "#### Minor", "##### Tiny"}},
+		{"capped", &Converter{MaxHeadingLevel: 4}, untagged, []string{"# Overview", "#### Minor", "#### Tiny"}},
+		{"capped to H1", &Converter{MaxHeadingLevel: 1}, untagged, []string{"# Overview", "# Minor", "# Tiny"}},
+		{"tagged", &Converter{MaxHeadingLevel: 4, UseTags: true}, tagged, []string{"# Tagged title", "#### Deep section"}},
+	}
+	for _, tt := range tests {
+		tt.c.AssetsDir = t.TempDir()
+		var headings []string
+		for _, line := range strings.Split(convert(t, tt.c, tt.p), "\n") {
+			if strings.HasPrefix(line, "#") {
+				headings = append(headings, line)
+			}
+		}
+		if strings.Join(headings, "\n") != strings.Join(tt.want, "\n") {
+			t.Errorf("%s: headings = %q, want %q", tt.name, headings, tt.want)
+		}
+	}
+}
+
+func TestEmptyHeadings(t *testing.T) {
+	tests := []struct {
+		name     string
+		ornament string
+		// want is the ornament as written, or empty when it is dropped
+		want string
+	}{
+		{"asterisms", "* * *", "* * *"},
+		{"tildes", "~~~", "~~~"},
+		{"spaces", "   ", ""},
+	}
+	for _, tt := range tests {
+		p := &testPDF{}
+		p.page(showText(72, 720, "Overview", "F2", 24) + showText(72, 690, "Body text before the ornament.", "F1", 12) +
+			showText(250, 650, tt.ornament, "F2", 24) + showText(72, 610, "Body text after the ornament.", "F1", 12))
+		got := convert(t, &Converter{AssetsDir: t.TempDir()}, p)
+		var headings []string
+		for _, line := range strings.Split(got, "\n") {
+			if strings.HasPrefix(line, "#") {
+				headings = append(headings, line)
+			}
+		}
+		if strings.Join(headings, "\n") != "# Overview" {
+			t.Errorf("%s: headings = %q, want [\"# Overview\"] in:\n%s", tt.name, headings, got)
+		}
+		if tt.want != "" && !strings.Contains(got, "\n"+tt.want+"\n") {
+			t.Errorf("%s: ornament %q missing from:\n%s", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestHasHeadingText(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"Overview", true},
+		{"2", true},
+		{"* * *", false},
+		{"<mark></mark>", false},
+		{"<mark>Marked</mark>", true},
+		{"  ", false},
+	}
+	for _, tt := range tests {
+		if got := hasHeadingText(tt.text); got != tt.want {
+			t.Errorf("hasHeadingText(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestDeterministicOutput(t *testing.T) {
+	p := &testPDF{}
+	for page := 0; page < 4; page++ {
+		var content strings.Builder
+		// Runs share baselines and some share positions, drawn out of order
+		for i := 20; i > 0; i-- {
+			y := 700 - float64(i%5)*18
+			content.WriteString(showText(72+float64(i%4)*90, y, fmt.Sprintf("w%d", i), "F1", 12))
+		}
+		p.page(content.String())
+	}
+	want := convert(t, &Converter{}, p)
+	for run := 0; run < 20; run++ {
+		c := &Converter{Jobs: 1 + run%4}
+		if got := convert(t, c, p); got != want {
+			t.Fatalf("run %d with %d jobs differs:\n%s\nwant:\n%s", run, c.Jobs, got, want)
+		}
+	}
+}
+
+func TestGroupElementsIntoLines(t *testing.T) {
+	elements := []TextElement{
+		{Text: "c", X: 200, Y: 700},
+		{Text: "d", X: 72, Y: 682},
+		{Text: "a", X: 72, Y: 700},
+		{Text: "b1", X: 130, Y: 700},
+		{Text: "b2", X: 130, Y: 700},
+	}
+	lines := (&Converter{}).groupElementsIntoLines(elements)
+	var got []string
+	for _, line := range lines {
+		var texts []string
+		for _, e := range line.Elements {
+			texts = append(texts, e.Text)
+		}
+		got = append(got, strings.Join(texts, " "))
+	}
+	if want := "a b1 b2 c|d"; strings.Join(got, "|") != want {
+		t.Errorf("lines = %q, want %s", got, want)
+	}
+}
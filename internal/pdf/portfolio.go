@@ -0,0 +1,51 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/transform"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/utils"
+	"github.com/rsc/pdf"
+)
+
+// isPortfolio reports whether a document is a PDF portfolio: a container whose
+// catalog presents its embedded files as a collection
+func isPortfolio(reader *pdf.Reader) bool {
+	return !reader.Trailer().Key("Root").Key("Collection").IsNull()
+}
+
+// renderPortfolio converts each document of a portfolio with
+// PortfolioConverter into a section headed by its file name, its own headings
+// moved one level down. Files that cannot be converted are linked instead.
+// The portfolio's pages are left out: they only hold the cover shown by
+// viewers without portfolio support.
+func (c *Converter) renderPortfolio(doc *document) (string, error) {
+	nested := transform.Nested(1)
+	var result strings.Builder
+	for i, file := range readEmbeddedFiles(doc.reader) {
+		base, fileName, err := c.saveEmbeddedFile(doc, file, i)
+		if err != nil {
+			return "", err
+		}
+
+		var converted bytes.Buffer
+		ok, err := c.PortfolioConverter(filepath.Join(c.AssetsDir, fileName), &converted)
+		if err != nil {
+			return "", fmt.Errorf("failed to convert portfolio document %s: %v", file.Name, err)
+		}
+
+		body, err := nested.Apply(converted.String(), transform.Metadata{InputPath: file.Name})
+		if err != nil {
+			return "", fmt.Errorf("failed to convert portfolio document %s: %v", file.Name, err)
+		}
+		result.WriteString("# " + base + "\n\n")
+		if body = strings.TrimSpace(body); !ok || body == "" {
+			body = "[" + base + "](" + utils.AssetLink(c.AssetsLink, c.AssetsDir, fileName) + ")"
+		}
+		result.WriteString(body + "\n\n")
+	}
+	return result.String(), nil
+}
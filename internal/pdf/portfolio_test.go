@@ -0,0 +1,99 @@
+package pdf
+
+import (
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// portfolioPDF builds a portfolio holding two documents and a data file,
+// whose page shows the cover of viewers without portfolio support
+func portfolioPDF() *testPDF {
+	report := &testPDF{}
+	report.page(showText(72, 700, "Quarterly Report", "F2", 24) + showText(72, 660, "Sales grew this quarter.", "F1", 12))
+	minutes := &testPDF{}
+	minutes.page(body("The board met on Monday."))
+
+	p := attachmentPDF(
+		[2]string{"report.pdf", string(report.bytes())},
+		[2]string{"minutes.pdf", string(minutes.bytes())},
+		[2]string{"data.bin", "\x00\x01"},
+	)
+	p.pages = nil
+	p.page(body("Open this portfolio in a viewer that supports it."))
+	p.catalog += " /Collection << /Type /Collection >>"
+	return p
+}
+
+func TestPortfolio(t *testing.T) {
+	convertPDF := func(path string, w io.Writer) (bool, error) {
+		if filepath.Ext(path) != ".pdf" {
+			return false, nil
+		}
+		return true, (&Converter{AssetsDir: filepath.Dir(path)}).ToMarkdown(path, w)
+	}
+	tests := []struct {
+		name    string
+		convert AttachmentConverter
+		want    []string
+		absent  []string
+	}{
+		{
+			name:    "merged",
+			convert: convertPDF,
+			want: []string{
+				"# report.pdf\n\n## Quarterly Report\nSales grew this quarter.\n\n# minutes.pdf",
+				"# minutes.pdf\n\nThe board met on Monday.\n\n# data.bin",
+				"# data.bin\n\n[data.bin](ASSETS/test-data.bin)",
+			},
+			absent: []string{"Open this portfolio"},
+		},
+		{
+			name:   "cover page",
+			want:   []string{"Open this portfolio in a viewer that supports it."},
+			absent: []string{"Quarterly Report"},
+		},
+	}
+	for _, tt := range tests {
+		dir := t.TempDir()
+		got := convert(t, &Converter{AssetsDir: dir, PortfolioConverter: tt.convert}, portfolioPDF())
+		for _, want := range tt.want {
+			if want = strings.ReplaceAll(want, "ASSETS", dir); !strings.Contains(got, want) {
+				t.Errorf("%s: output lacks %q:\n%s", tt.name, want, got)
+			}
+		}
+		for _, absent := range tt.absent {
+			if strings.Contains(got, absent) {
+				t.Errorf("%s: output holds %q:\n%s", tt.name, absent, got)
+			}
+		}
+	}
+}
+
+func TestPortfolioNotCollection(t *testing.T) {
+	called := false
+	c := &Converter{AssetsDir: t.TempDir(), PortfolioConverter: func(string, io.Writer) (bool, error) {
+		called = true
+		return true, nil
+	}}
+	got := convert(t, c, attachmentPDF([2]string{"notes.txt", "Plain notes"}))
+	if called || !strings.Contains(got, "Body text.") {
+		t.Errorf("document without a collection: converter called %v, output %q", called, got)
+	}
+}
+
+func TestPortfolioEncrypted(t *testing.T) {
+	c := &Converter{AssetsDir: t.TempDir(), PortfolioConverter: func(string, io.Writer) (bool, error) {
+		t.Error("document of an encrypted portfolio converted")
+		return true, nil
+	}}
+	p := portfolioPDF()
+	p.encrypted = true
+	got := convert(t, c, p)
+	for _, want := range []string{"Open this portfolio in a viewer that supports it.", "- report.pdf (not extracted from the encrypted PDF)\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output lacks %q:\n%s", want, got)
+		}
+	}
+}
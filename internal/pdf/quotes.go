@@ -0,0 +1,194 @@
+package pdf
+
+import (
+	"math"
+	"regexp"
+	"strings"
+)
+
+const (
+	// quoteIndent is the minimum indentation on both sides of a blockquote
+	quoteIndent = 24.0
+	// pullQuoteScale is how much larger than body text a pull quote is set
+	pullQuoteScale = 1.15
+	// pullQuoteMinWords separates pull quotes from short centered headings
+	pullQuoteMinWords = 5
+	// attributionMaxWords separates the attribution of a quote from a sentence
+	// that happens to open with a dash
+	attributionMaxWords = 12
+	// italicQuoteMinWords separates quotes set in italics from captions and
+	// short phrases emphasized on a line of their own
+	italicQuoteMinWords = 8
+	// paragraphGapScale is how much wider than the line spacing a gap must be
+	// to separate paragraphs
+	paragraphGapScale = 1.3
+)
+
+// attributionPattern matches a line opening with an em dash, horizontal bar,
+// en dash or double hyphen, as the author line under an epigraph does
+var attributionPattern = regexp.MustCompile(`^(?:—|―|–|--)\s*(\S.*)$`)
+
+// pageLayout describes the text block of a page
+type pageLayout struct {
+	Left     float64
+	Right    float64
+	BodySize float64
+}
+
+// measureLayout finds the body text size and the margins of the text block,
+// taking the most common line start as the left margin
+func measureLayout(lines []TextLine) pageLayout {
+	var elements []TextElement
+	for _, line := range lines {
+		elements = append(elements, line.Elements...)
+	}
+	layout := pageLayout{BodySize: bodyFontSize(elements)}
+
+	starts := make(map[float64]int)
+	for _, line := range lines {
+		if len(line.Elements) == 0 || line.FontSize > layout.BodySize*1.2 {
+			continue
+		}
+		left, right := lineBounds(line)
+		start := math.Round(left)
+		starts[start]++
+		if starts[start] > starts[layout.Left] || (starts[start] == starts[layout.Left] && start < layout.Left) {
+			layout.Left = start
+		}
+		layout.Right = math.Max(layout.Right, right)
+	}
+	return layout
+}
+
+// lineBounds returns the horizontal extent of a line's text
+func lineBounds(line TextLine) (float64, float64) {
+	first := line.Elements[0]
+	last := line.Elements[len(line.Elements)-1]
+	return first.X, last.X + last.Width
+}
+
+// isPullQuote reports whether a line is part of a pull quote: a sentence set
+// larger than body text and centered within the text block
+func (l pageLayout) isPullQuote(line TextLine, text string) bool {
+	if len(line.Elements) == 0 || l.Right <= l.Left || line.FontSize < l.BodySize*pullQuoteScale {
+		return false
+	}
+	if len(strings.Fields(text)) < pullQuoteMinWords {
+		return false
+	}
+
+	left, right := lineBounds(line)
+	if left < l.Left+quoteIndent/2 {
+		return false
+	}
+	center := (l.Left + l.Right) / 2
+	return math.Abs((left+right)/2-center) <= (l.Right-l.Left)*0.05
+}
+
+// blockquoteLines marks runs of at least two body lines that share a left edge
+// indented from both margins of the text block
+func (l pageLayout) blockquoteLines(lines []TextLine) []bool {
+	quoted := make([]bool, len(lines))
+	indented := func(line TextLine) (float64, bool) {
+		if len(line.Elements) == 0 || line.FontSize > l.BodySize*1.2 {
+			return 0, false
+		}
+		left, right := lineBounds(line)
+		return left, left >= l.Left+quoteIndent && right <= l.Right-quoteIndent/2
+	}
+
+	for i := 0; i < len(lines); {
+		left, ok := indented(lines[i])
+		if !ok {
+			i++
+			continue
+		}
+		j := i + 1
+		for j < len(lines) {
+			next, ok := indented(lines[j])
+			if !ok || math.Abs(next-left) > 1 {
+				break
+			}
+			j++
+		}
+		if j-i >= 2 {
+			for k := i; k < j; k++ {
+				quoted[k] = true
+			}
+		}
+		i = j
+	}
+	return quoted
+}
+
+// italicQuoteLines marks runs of body lines set wholly in italic fonts and
+// separated from the text around them by paragraph gaps, as quotes set in
+// italics instead of indented are. Italic words within roman lines are
+// emphasis and never start a run.
+func (l pageLayout) italicQuoteLines(lines []TextLine, spacing float64) []bool {
+	quoted := make([]bool, len(lines))
+	italic := func(line TextLine) bool {
+		if len(line.Elements) == 0 || line.FontSize > l.BodySize*1.2 {
+			return false
+		}
+		text := false
+		for _, element := range line.Elements {
+			if element.Image {
+				return false
+			}
+			if strings.TrimSpace(element.Text) == "" {
+				continue
+			}
+			if !isItalicFont(element.Font) {
+				return false
+			}
+			text = true
+		}
+		return text
+	}
+
+	for i := 0; i < len(lines); {
+		if !italic(lines[i]) {
+			i++
+			continue
+		}
+		j := i + 1
+		for j < len(lines) && italic(lines[j]) && !separated(lines, j-1, j, spacing) {
+			j++
+		}
+		words := 0
+		for k := i; k < j; k++ {
+			var text strings.Builder
+			for _, element := range spaceElements(lines[k].Elements) {
+				text.WriteString(element.Text)
+			}
+			words += len(strings.Fields(text.String()))
+		}
+		if separated(lines, i-1, i, spacing) && separated(lines, j-1, j, spacing) && words >= italicQuoteMinWords {
+			for k := i; k < j; k++ {
+				quoted[k] = true
+			}
+		}
+		i = j
+	}
+	return quoted
+}
+
+// parseAttribution recognizes the author line of a quote, such as
+// "— Author, Title", returning it with an em dash
+func parseAttribution(text string) (string, bool) {
+	m := attributionPattern.FindStringSubmatch(strings.TrimSpace(text))
+	if m == nil || len(strings.Fields(m[1])) > attributionMaxWords {
+		return "", false
+	}
+	return "— " + m[1], true
+}
+
+// separated reports whether a paragraph gap lies between two lines, as it
+// does at the edge of the page or next to an image or block
+func separated(lines []TextLine, above, below int, spacing float64) bool {
+	if above < 0 || below >= len(lines) || len(lines[above].Elements) == 0 || len(lines[below].Elements) == 0 {
+		return true
+	}
+	return lines[above].Y-lines[below].Y > spacing*paragraphGapScale
+}
@@ -0,0 +1,175 @@
+package pdf
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPullQuotes(t *testing.T) {
+	line := "Prose spread across the full width of the text block here."
+	p := &testPDF{}
+	p.page(body(line, line) +
+		showText(160, 640, "Design is how it works.", "F2", 16) +
+		showText(72, 610, line, "F1", 12) +
+		showText(108, 580, "An indented quotation that runs", "F1", 12) +
+		showText(108, 562, "over two lines of body text.", "F1", 12) +
+		showText(72, 532, line, "F1", 12))
+	got := convert(t, &Converter{}, p)
+	for _, want := range []string{
+		`<blockquote class="pullquote">Design is how it works.</blockquote>`,
+		"> An indented quotation that runs\n> over two lines of body text.",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output lacks %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestQuoteAttribution(t *testing.T) {
+	line := "Prose spread across the full width of the text block here."
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name: "block quote",
+			content: showText(108, 640, "The limits of my language mean", "F1", 12) +
+				showText(108, 622, "the limits of my world.", "F1", 12) +
+				showText(108, 604, "-- Ludwig Wittgenstein", "F1", 12),
+			want: "> The limits of my language mean\n> the limits of my world.\n>\n> — Ludwig Wittgenstein\n",
+		},
+		{
+			name: "pull quote",
+			content: showText(160, 640, "Design is how it works.", "F2", 16) +
+				showText(200, 618, "-- Steve Jobs", "F1", 12),
+			want: `<blockquote class="pullquote">Design is how it works. <cite>— Steve Jobs</cite></blockquote>`,
+		},
+		{
+			name:    "dash in prose",
+			content: showText(72, 640, "-- not an attribution without a quote", "F1", 12),
+			want:    "-- not an attribution without a quote",
+		},
+	}
+	for _, tt := range tests {
+		p := &testPDF{}
+		p.page(body(line, line) + tt.content + showText(72, 560, line, "F1", 12))
+		got := convert(t, &Converter{}, p)
+		if !strings.Contains(got, tt.want) {
+			t.Errorf("%s: output lacks %q:\n%s", tt.name, tt.want, got)
+
+		}
+	}
+}
+
+func TestIsPullQuote(t *testing.T) {
+	layout := pageLayout{Left: 72, Right: 432, BodySize: 12}
+	quote := func(x, size float64, text string) TextLine {
+		return TextLine{Elements: []TextElement{{X: x, Width: float64(len(text)) * size / 2, Size: size, Text: text}}, FontSize: size}
+	}
+	tests := []struct {
+		name string
+		line TextLine
+		want bool
+	}{
+		{"centered and large", quote(160, 16, "Design is how it works."), true},
+		{"body size", quote(183, 12, "Design is how it works."), false},
+		{"short heading", quote(220, 16, "Chapter One"), false},
+		{"at the margin", quote(72, 16, "Design is how it works today for everyone here."), false},
+		{"off center", quote(250, 16, "Design is how it works."), false},
+	}
+	for _, tt := range tests {
+		if got := layout.isPullQuote(tt.line, tt.line.Elements[0].Text); got != tt.want {
+			t.Errorf("%s: isPullQuote() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseAttribution(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+		ok   bool
+	}{
+		{"— Ada Lovelace", "— Ada Lovelace", true},
+		{"-- Ada Lovelace, Notes", "— Ada Lovelace, Notes", true},
+		{"– Someone", "— Someone", true},
+		{"Ada Lovelace", "", false},
+		{"— " + strings.Repeat("word ", 13), "", false},
+	}
+	for _, tt := range tests {
+		got, ok := parseAttribution(tt.text)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseAttribution(%q) = %q, %v, want %q, %v", tt.text, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+// styledLine is a line of text at a baseline in a font of the test PDF
+type styledLine struct {
+	y    float64
+	text string
+	font string
+}
+
+// showLines shows each line at the left margin
+func showLines(lines ...styledLine) string {
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(showText(72, line.y, line.text, line.font, 12))
+	}
+	return b.String()
+}
+
+func TestItalicQuotes(t *testing.T) {
+	opening := []styledLine{{700, "The author opens with a plain line.", "F1"}, {686, "Then a closing plain line follows.", "F1"}}
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name: "isolated italic paragraph",
+			content: showLines(append(opening, styledLine{650, "Every italic word of this long quote", "F3"}, styledLine{636, "runs on over a second line too.", "F3"},
+				styledLine{600, "Back to the plain body text.", "F1"})...),
+			want: "> Every italic word of this long quote\n> runs on over a second line too.\n",
+		},
+		{
+			name: "italic emphasis in prose",
+			content: showLines(styledLine{700, "Plain words open the line", "F1"}) + showText(228, 700, "then emphasis", "F3", 12) +
+				showLines(styledLine{686, "and the paragraph continues.", "F1"}),
+		},
+		{
+			name:    "short italic line",
+			content: showLines(append(opening, styledLine{650, "Figure 2: a caption", "F3"}, styledLine{614, "Back to the plain body text.", "F1"})...),
+		},
+		{
+			name: "italic lines within a paragraph",
+			content: showLines(append(opening, styledLine{672, "Every italic word of this long line", "F3"}, styledLine{658, "runs on over a second line too.", "F3"},
+				styledLine{644, "Back to the plain body text.", "F1"})...),
+		},
+	}
+	for _, tt := range tests {
+		p := &testPDF{}
+		p.page(tt.content)
+		got := convert(t, &Converter{AssetsDir: t.TempDir()}, p)
+		if tt.want == "" {
+			if strings.Contains(got, ">") {
+				t.Errorf("%s: got %q, want no blockquote", tt.name, got)
+			}
+		} else if !strings.Contains(got, tt.want) {
+			t.Errorf("%s: got %q, want it to contain %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestIsItalicFont(t *testing.T) {
+	for font, want := range map[string]bool{
+		"Times-Italic": true, "Helvetica-Oblique": true, "ABCDEF+Minion-It": true,
+		"Helvetica": false, "Times-Roman": false, "Italianno-Regular": false,
+	} {
+		if got := isItalicFont(font); got != want {
+			t.Errorf("isItalicFont(%q) = %v, want %v", font, got, want)
+		}
+	}
+}
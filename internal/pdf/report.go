@@ -0,0 +1,52 @@
+package pdf
+
+import (
+	"math"
+	"strings"
+)
+
+// LineReport records how a line of a page was classified, for debugging
+// detection. Kind is one of heading, list, table, paragraph, quote,
+// pull-quote, callout, attribution, math, toc, key-value, code, diagram,
+// tabbed, image and block, the last covering bordered tables, sidebars and
+// vertical text.
+type LineReport struct {
+	Kind     string  `json:"kind"`
+	Text     string  `json:"text"`
+	FontSize float64 `json:"font_size"`
+	X        float64 `json:"x"`
+	Y        float64 `json:"y"`
+}
+
+// PageReport lists the classified lines of a page in reading order. The
+// blocks of pages converted from their structure tree are reported as blocks.
+type PageReport struct {
+	Page  int          `json:"page"`
+	Lines []LineReport `json:"lines"`
+}
+
+// reportLine describes a line under its classification, rounding positions
+// to hundredths of a point
+func reportLine(kind string, line TextLine, text string) LineReport {
+	report := LineReport{
+		Kind:     kind,
+		Text:     strings.TrimSpace(text),
+		FontSize: math.Round(line.FontSize*100) / 100,
+		Y:        math.Round(line.Y*100) / 100,
+	}
+	if len(line.Elements) > 0 {
+		report.X = math.Round(line.Elements[0].X*100) / 100
+	}
+	return report
+}
+
+// reportImage describes an image under its classification, placed at its
+// top left corner
+func reportImage(kind string, img pageImage, text string) LineReport {
+	return LineReport{
+		Kind: kind,
+		Text: text,
+		X:    math.Round(img.X*100) / 100,
+		Y:    math.Round((img.Y+img.Height)*100) / 100,
+	}
+}
@@ -0,0 +1,42 @@
+package pdf
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestReport(t *testing.T) {
+	p := &testPDF{}
+	p.page(showText(72, 700, "Quarterly Report", "F2", 20) +
+		showText(72, 660, "The opening paragraph of the page.", "F1", 12) +
+		showText(72, 642, "- first item", "F1", 12) +
+		showText(72, 624, "- second item", "F1", 12))
+	p.page(showText(72, 700, "Second page text.", "F1", 12))
+
+	var pages []PageReport
+	c := &Converter{AssetsDir: t.TempDir(), Report: func(report []PageReport) { pages = report }}
+	convert(t, c, p)
+
+	want := [][]LineReport{
+		{
+			{Kind: "heading", Text: "Quarterly Report", FontSize: 20, X: 72, Y: 700},
+			{Kind: "paragraph", Text: "The opening paragraph of the page.", FontSize: 12, X: 72, Y: 660},
+			{Kind: "list", Text: "- first item", FontSize: 12, X: 72, Y: 642},
+			{Kind: "list", Text: "- second item", FontSize: 12, X: 72, Y: 624},
+		},
+		{
+			{Kind: "paragraph", Text: "Second page text.", FontSize: 12, X: 72, Y: 700},
+		},
+	}
+	if len(pages) != len(want) {
+		t.Fatalf("report of %d pages, want %d: %+v", len(pages), len(want), pages)
+	}
+	for i, page := range pages {
+		if page.Page != i+1 {
+			t.Errorf("page %d: numbered %d", i+1, page.Page)
+		}
+		if got, want := fmt.Sprintf("%+v", page.Lines), fmt.Sprintf("%+v", want[i]); got != want {
+			t.Errorf("page %d lines:\n got %s\nwant %s", i+1, got, want)
+		}
+	}
+}
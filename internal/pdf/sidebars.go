@@ -0,0 +1,87 @@
+package pdf
+
+import "strings"
+
+// minSidebarSize is the smallest width and height, in points, of a frame that
+// may hold a sidebar rather than outline a word or a figure
+const minSidebarSize = 36.0
+
+// findFrames returns the rectangles outlined by groups of borders that draw a
+// single cell, which frame boxed sidebars
+func findFrames(groups [][]border) []rect {
+	var frames []rect
+	for _, group := range groups {
+		xs, ys := boundaries(group)
+		if len(xs) != 2 || len(ys) != 2 {
+			continue
+		}
+		frame := rect{MinX: xs[0], MinY: ys[0], MaxX: xs[1], MaxY: ys[1]}
+		if frame.MaxX-frame.MinX >= minSidebarSize && frame.MaxY-frame.MinY >= minSidebarSize {
+			frames = append(frames, frame)
+		}
+	}
+	return frames
+}
+
+// extractSidebars takes the elements framed by a border out of the page flow
+// and renders each sidebar as a blockquote placed at the top of its frame, so
+// a box set beside the main column does not interleave with it. A frame around
+// all of the page's text is a page border and is left alone. The remaining
+// elements are returned for the line-based heuristics.
+func (c *Converter) extractSidebars(elements []TextElement, frames []rect) ([]TextElement, []TextLine) {
+	if len(frames) == 0 {
+		return elements, nil
+	}
+
+	framed := make([][]TextElement, len(frames))
+	var rest []TextElement
+	for _, e := range elements {
+		x, y := e.X+e.Width/2, e.Y+e.Size*0.3
+		// Elements belong to the smallest frame around them
+		best := -1
+		for i, frame := range frames {
+			if frame.contains(x, y) && (best < 0 || frame.area() < frames[best].area()) {
+				best = i
+			}
+		}
+		if best < 0 {
+			rest = append(rest, e)
+			continue
+		}
+		framed[best] = append(framed[best], e)
+	}
+
+	var blocks []TextLine
+	for i, frame := range frames {
+		if len(framed[i]) == 0 {
+			continue
+		}
+		if len(framed[i]) == len(elements) {
+			return elements, nil
+		}
+		body, _, _ := c.convertLinesToMarkdown(c.groupElementsIntoLines(framed[i]))
+		if quote := blockquote(body); quote != "" {
+			blocks = append(blocks, TextLine{Y: frame.MaxY, MCID: noMCID, Block: quote, MinX: frame.MinX, MaxX: frame.MaxX})
+		}
+	}
+	return rest, blocks
+}
+
+// blockquote quotes each line of rendered Markdown, keeping a blank line
+// between its blocks inside the quote
+func blockquote(md string) string {
+	md = strings.TrimSpace(md)
+	if md == "" {
+		return ""
+	}
+	var quoted []string
+	for _, line := range strings.Split(md, "\n") {
+		switch {
+		case line != "":
+			quoted = append(quoted, "> "+line)
+		case quoted[len(quoted)-1] != ">":
+			quoted = append(quoted, ">")
+		}
+	}
+	return strings.Join(quoted, "\n")
+}
@@ -0,0 +1,66 @@
+package pdf
+
+import (
+	"strings"
+	"testing"
+)
+
+// sidebarPage shows a main column of text at the left and a sidebar at the
+// right, framed by a border drawn with frame
+func sidebarPage(frame string) string {
+	var b strings.Builder
+	for i, line := range []string{"Main column text goes on.", "It continues here.", "And ends here."} {
+		b.WriteString(showText(72, 700-float64(i)*18, line, "F1", 12))
+	}
+	b.WriteString(showText(380, 700, "Did you know?", "F2", 16))
+	b.WriteString(showText(380, 680, "Sidebar fact.", "F1", 12))
+	b.WriteString(frame)
+	return b.String()
+}
+
+func TestSidebars(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"rectangle", sidebarPage("370 660 180 60 re S\n"),
+			"> ### Did you know?\n> Sidebar fact.\n\nMain column text goes on.\n\nIt continues here.\n\nAnd ends here."},
+		{"closed path", sidebarPage("370 660 m 550 660 l 550 720 l 370 720 l h S\n"),
+			"> ### Did you know?\n> Sidebar fact.\n\nMain column text goes on.\n\nIt continues here.\n\nAnd ends here."},
+		{"no frame", sidebarPage(""), ""},
+		{"frame too small", sidebarPage("370 695 20 20 re S\n"), ""},
+		{"page border", sidebarPage("36 36 540 720 re S\n"), ""},
+	}
+	unframed := strings.TrimSpace(convert(t, &Converter{AssetsDir: t.TempDir()}, &testPDF{pages: []testPage{{content: sidebarPage("")}}}))
+	if strings.Contains(unframed, "> ") {
+		t.Fatalf("unframed page: got %q, want no blockquote", unframed)
+	}
+	for _, tt := range tests {
+		p := &testPDF{}
+		p.page(tt.content)
+		got := strings.TrimSpace(convert(t, &Converter{AssetsDir: t.TempDir()}, p))
+		want := tt.want
+		if want == "" {
+			want = unframed
+		}
+		if got != want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, want)
+		}
+	}
+}
+
+func TestBlockquote(t *testing.T) {
+	tests := []struct {
+		md, want string
+	}{
+		{"", ""},
+		{"One line\n", "> One line"},
+		{"## Heading\nText\n\n\n\nMore\n\n", "> ## Heading\n> Text\n>\n> More"},
+	}
+	for _, tt := range tests {
+		if got := blockquote(tt.md); got != tt.want {
+			t.Errorf("blockquote(%q) = %q, want %q", tt.md, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package pdf
+
+import "strings"
+
+// wordGapScale is the gap between glyphs, relative to the font size, that
+// reads as a word space when no space glyph was drawn there
+const wordGapScale = 0.25
+
+// spaceElements normalizes the spacing between the glyphs of a line. A space is
+// added where a wide gap separates two words without a space glyph, and runs of
+// space glyphs collapse to one, so justified text with stretched word gaps reads
+// like any other line. Glyphs in monospaced fonts keep their spacing, since it
+// is significant in code.
+func spaceElements(elements []TextElement) []TextElement {
+	spaced := make([]TextElement, 0, len(elements))
+	for i, element := range elements {
+		if i > 0 && len(spaced) > 0 && !element.Image && !isMonospaceFont(element.Font) {
+			prev := spaced[len(spaced)-1]
+			afterSpace := !prev.Image && strings.HasSuffix(prev.Text, " ")
+			if strings.TrimSpace(element.Text) == "" && afterSpace {
+				continue
+			}
+			gap := element.X - elements[i-1].X - elements[i-1].Width
+			if !prev.Image && !afterSpace && !strings.HasPrefix(element.Text, " ") && gap > element.Size*wordGapScale {
+				element.Text = " " + element.Text
+			}
+		}
+		spaced = append(spaced, element)
+	}
+	return spaced
+}
+
+// isMonospaceFont reports whether a font sets every glyph at the same width
+func isMonospaceFont(fontName string) bool {
+	fontName = strings.ToLower(fontName)
+	for _, name := range []string{"courier", "mono", "consolas", "menlo", "typewriter"} {
+		if strings.Contains(fontName, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,29 @@
+package pdf
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestJustifiedSpacing(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"space glyph runs", body("Wide   spaced    words."), "Wide spaced words."},
+		{"word spacing", "BT /F1 12 Tf 20 Tw 72 700 Td " + encodeText("Wide spaced words.") + " Tj ET\n", "Wide spaced words."},
+		{"positioned words", "BT /F1 12 Tf 72 700 Td [" + encodeText("Wide") + " -1200 " + encodeText("spaced") + " -900 " + encodeText("words.") + "] TJ ET\n", "Wide spaced words."},
+		{"gap without a space glyph", showText(72, 700, "Wide", "F1", 12) + showText(110, 700, "words.", "F1", 12), "Wide words."},
+		{"tight glyphs", showText(72, 700, "Tight", "F1", 12) + showText(102, 700, "ly.", "F1", 12), "Tightly."},
+		{"monospace kept", showText(72, 700, "x   + 1", "F4", 12), "x   + 1"},
+	}
+	for _, tt := range tests {
+		p := &testPDF{}
+		p.page(tt.content)
+		md := convert(t, &Converter{AssetsDir: t.TempDir()}, p)
+		if !strings.Contains(md, tt.want) {
+			t.Errorf("%s: output = %q, want %q", tt.name, md, tt.want)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package pdf
+
+import "strings"
+
+// continueTable joins the Markdown of a page opening with a table to the
+// preceding text when that ends with a table of as many columns, which is
+// taken to be the same table cut by the page break. A header repeated at the
+// top of the page is dropped; otherwise the page's first row was read as a
+// header and becomes a row of the table.
+func (c *Converter) continueTable(prev, next string) (string, bool) {
+	if !c.MergeTables || c.SummaryOnly {
+		return "", false
+	}
+	prev = strings.TrimRight(prev, " \n")
+	if strings.HasSuffix(prev, "\n---") {
+		prev = strings.TrimRight(strings.TrimSuffix(prev, "---"), " \n")
+	}
+	next = strings.TrimLeft(next, " \n")
+
+	last := prev[strings.LastIndex(prev, "\n\n")+1:]
+	first, rest := next, ""
+	if i := strings.Index(next, "\n\n"); i >= 0 {
+		first, rest = next[:i], next[i:]
+	}
+	head, columns := tableHeader(last)
+	nextHead, nextColumns := tableHeader(first)
+	if columns == 0 || columns != nextColumns {
+		return "", false
+	}
+
+	rows := strings.Split(first, "\n")[2:]
+	if strings.Join(strings.Fields(nextHead), " ") != strings.Join(strings.Fields(head), " ") {
+		rows = append([]string{nextHead}, rows...)
+	}
+	if len(rows) == 0 {
+		return prev + rest, true
+	}
+	return prev + "\n" + strings.Join(rows, "\n") + rest, true
+}
+
+// tableHeader returns the header row and column count of a block that is a
+// Markdown table, or 0 columns when it is not one
+func tableHeader(block string) (string, int) {
+	lines := strings.Split(strings.TrimLeft(block, "\n"), "\n")
+	if len(lines) < 2 || !isTableSeparator(lines[1]) {
+		return "", 0
+	}
+	for _, line := range lines {
+		if !strings.HasPrefix(line, "|") {
+			return "", 0
+		}
+	}
+	return lines[0], strings.Count(lines[1], "-") / 3
+}
+
+// isTableSeparator reports whether a line is the delimiter row of a table
+func isTableSeparator(line string) bool {
+	return strings.HasPrefix(line, "|") && strings.Contains(line, "---") &&
+		strings.Trim(line, "|-: ") == ""
+}
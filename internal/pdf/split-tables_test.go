@@ -0,0 +1,83 @@
+package pdf
+
+import (
+	"strings"
+	"testing"
+)
+
+// tablePage draws a bordered table of rows of two cells, from the top of the
+// page or ending at its bottom
+func tablePage(top bool, rows ...[2]string) string {
+	y := 200.0
+	if top {
+		y = 740
+	}
+	ys := []float64{y}
+	var cells strings.Builder
+	for i, row := range rows {
+		baseline := y - float64(i)*20 - 14
+		cells.WriteString(showText(76, baseline, row[0], "F1", 12) + showText(184, baseline, row[1], "F1", 12))
+		ys = append(ys, y-float64(i+1)*20)
+	}
+	return gridLines([]float64{72, 180, 300}, ys) + cells.String()
+}
+
+func TestMergeTables(t *testing.T) {
+	header := [2]string{"Fruit", "Stock"}
+	first := showText(72, 700, "Stock levels follow.", "F1", 12) + tablePage(false, header, [2]string{"Apples", "12"}, [2]string{"Pears", "7"})
+	tests := []struct {
+		name   string
+		second string
+		merge  bool
+		// tables is the number of tables written
+		tables int
+		want   string
+	}{
+		{
+			name:   "repeated header",
+			second: tablePage(true, header, [2]string{"Plums", "3"}) + showText(72, 600, "After the table.", "F1", 12),
+			merge:  true,
+			tables: 1,
+			want:   "| Fruit | Stock |\n| --- | --- |\n| Apples | 12 |\n| Pears | 7 |\n| Plums | 3 |\n\nAfter the table.",
+		},
+		{
+			name:   "no repeated header",
+			second: tablePage(true, [2]string{"Plums", "3"}, [2]string{"Figs", "9"}),
+			merge:  true,
+			tables: 1,
+			want:   "| Fruit | Stock |\n| --- | --- |\n| Apples | 12 |\n| Pears | 7 |\n| Plums | 3 |\n| Figs | 9 |",
+		},
+		{
+			name:   "other columns",
+			second: gridLines([]float64{72, 150, 250, 350}, []float64{740, 720}) + showText(76, 726, "A", "F1", 12) + showText(154, 726, "B", "F1", 12) + showText(254, 726, "C", "F1", 12),
+			merge:  true,
+			tables: 2,
+			want:   "| Pears | 7 |\n\n",
+		},
+		{
+			name:   "text before the table",
+			second: showText(72, 760, "A new section.", "F1", 12) + tablePage(true, header, [2]string{"Plums", "3"}),
+			merge:  true,
+			tables: 2,
+			want:   "| Pears | 7 |\n\n",
+		},
+		{
+			name:   "disabled",
+			second: tablePage(true, header, [2]string{"Plums", "3"}),
+			tables: 2,
+			want:   "| Pears | 7 |\n\n",
+		},
+	}
+	for _, tt := range tests {
+		p := &testPDF{}
+		p.page(first)
+		p.page(tt.second)
+		got := convert(t, &Converter{AssetsDir: t.TempDir(), MergeTables: tt.merge}, p)
+		if !strings.Contains(got, tt.want) {
+			t.Errorf("%s: got %q, want it to contain %q", tt.name, got, tt.want)
+		}
+		if tables := strings.Count(got, "\n| --- "); tables != tt.tables {
+			t.Errorf("%s: got %d tables, want %d", tt.name, tables, tt.tables)
+		}
+	}
+}
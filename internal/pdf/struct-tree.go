@@ -0,0 +1,418 @@
+package pdf
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/errs"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+	"github.com/rsc/pdf"
+)
+
+// maxStructDepth guards against cyclic or absurdly deep structure trees
+const maxStructDepth = 64
+
+// structNode is a node of a tagged PDF's structure tree. Nodes with an empty Type
+// are marked-content references pointing at an MCID on a page.
+type structNode struct {
+	Type string
+	Page string
+	MCID int
+	Kids []*structNode
+}
+
+// parseStructTree returns the root of the document structure tree, or nil for untagged PDFs
+func parseStructTree(reader *pdf.Reader) *structNode {
+	root := reader.Trailer().Key("Root").Key("StructTreeRoot")
+	if root.Kind() != pdf.Dict {
+		return nil
+	}
+
+	roleMap := root.Key("RoleMap")
+	tree := &structNode{
+		Type: "Document",
+		Kids: parseStructKids(root.Key("K"), "", roleMap, make(map[string]bool), 0),
+	}
+	if len(tree.Kids) == 0 {
+		return nil
+	}
+
+	return tree
+}
+
+// parseStructKids parses the kids k of a structure element. Arrays and
+// elements holding themselves through their ancestors are left out, breaking
+// cycles that the depth limit alone would walk down every branch of.
+func parseStructKids(k pdf.Value, page string, roleMap pdf.Value, ancestors map[string]bool, depth int) []*structNode {
+	if depth > maxStructDepth {
+		return nil
+	}
+	if kind := k.Kind(); kind == pdf.Array || kind == pdf.Dict {
+		key := k.String()
+		if ancestors[key] {
+			return nil
+		}
+		ancestors[key] = true
+		defer delete(ancestors, key)
+	}
+
+	switch k.Kind() {
+	case pdf.Integer:
+		return []*structNode{{Page: page, MCID: int(k.Int64())}}
+	case pdf.Array:
+		var kids []*structNode
+		for i := 0; i < k.Len(); i++ {
+			kids = append(kids, parseStructKids(k.Index(i), page, roleMap, ancestors, depth+1)...)
+		}
+		return kids
+	case pdf.Dict:
+		if pg := k.Key("Pg"); pg.Kind() == pdf.Dict {
+			page = pg.String()
+		}
+		switch k.Key("Type").Name() {
+		case "MCR":
+			return []*structNode{{Page: page, MCID: int(k.Key("MCID").Int64())}}
+		case "OBJR":
+			return nil
+		}
+		return []*structNode{{
+			Type: standardStructType(k.Key("S").Name(), roleMap),
+			Kids: parseStructKids(k.Key("K"), page, roleMap, ancestors, depth+1),
+		}}
+	}
+
+	return nil
+}
+
+// standardStructType maps custom structure types onto standard ones through the role map
+func standardStructType(structType string, roleMap pdf.Value) string {
+	for i := 0; i < maxStructDepth; i++ {
+		mapped := roleMap.Key(structType).Name()
+		if mapped == "" || mapped == structType {
+			break
+		}
+		structType = mapped
+	}
+	return structType
+}
+
+// taggedPage renders the parts of a structure tree whose content lies on one page
+type taggedPage struct {
+	page        string
+	texts       map[int]string
+	summaryOnly bool
+	listStyle   markdown.ListStyle
+	// maxHeadingLevel caps the levels of Hn headings, as Converter.MaxHeadingLevel
+	maxHeadingLevel int
+	// tops is the baseline of the highest text of each marked-content sequence
+	tops map[int]float64
+	// blocks are the rendered blocks, in the order of the structure tree
+	blocks []TextLine
+}
+
+// convertTaggedPage renders the tagged content of a page using the document
+// structure tree instead of geometric heuristics. Each block is returned as a
+// line placed at its first content, carrying its position in the tree as its
+// MCID so that ordering by marked content keeps the tree's reading order.
+// With summaryOnly, only headings are rendered.
+func (c *Converter) convertTaggedPage(tree *structNode, page pdf.Page, elements []TextElement, summaryOnly bool, listStyle markdown.ListStyle) []TextLine {
+	tp := taggedPage{
+		page:            page.V.String(),
+		texts:           make(map[int]string),
+		summaryOnly:     summaryOnly,
+		listStyle:       listStyle,
+		maxHeadingLevel: c.MaxHeadingLevel,
+		tops:            make(map[int]float64),
+	}
+	for _, element := range elements {
+		if element.MCID != noMCID {
+			tp.texts[element.MCID] += element.Text
+			if top, ok := tp.tops[element.MCID]; !ok || element.Y > top {
+				tp.tops[element.MCID] = element.Y
+			}
+		}
+	}
+	tp.renderBlock(tree)
+	return tp.blocks
+}
+
+// hasMarkedContent reports whether any element belongs to a marked-content
+// sequence, which tagged pages map to the structure tree
+func hasMarkedContent(elements []TextElement) bool {
+	for _, element := range elements {
+		if element.MCID != noMCID {
+			return true
+		}
+	}
+	return false
+}
+
+// emit adds a rendered block placed where the first content of nodes on this
+// page lies
+func (tp *taggedPage) emit(block string, nodes ...*structNode) {
+	line := TextLine{MCID: len(tp.blocks), Block: strings.TrimRight(block, "\n")}
+	if mcid, ok := tp.firstMCID(nodes); ok {
+		line.Y = tp.tops[mcid]
+	}
+	tp.blocks = append(tp.blocks, line)
+}
+
+// firstMCID returns the first marked-content sequence under nodes with text
+// on this page
+func (tp *taggedPage) firstMCID(nodes []*structNode) (int, bool) {
+	for _, node := range nodes {
+		if node.Type == "" {
+			if _, ok := tp.tops[node.MCID]; ok && (node.Page == "" || node.Page == tp.page) {
+				return node.MCID, true
+			}
+			continue
+		}
+		if mcid, ok := tp.firstMCID(node.Kids); ok {
+			return mcid, true
+		}
+	}
+	return 0, false
+}
+
+func (tp *taggedPage) renderBlock(node *structNode) {
+	if level, ok := headingLevel(node.Type); ok {
+		if text := tp.text(node); plainHeading(text) != "" {
+			tp.emit(strings.Repeat("#", capHeadingLevel(level, tp.maxHeadingLevel))+" "+text, node)
+		}
+		return
+	}
+
+	if tp.summaryOnly {
+		for _, kid := range node.Kids {
+			if kid.Type != "" {
+				tp.renderBlock(kid)
+			}
+		}
+		return
+	}
+
+	switch node.Type {
+	case "P", "Caption", "BlockQuote", "Code", "Note", "TOCI":
+		if text := tp.text(node); text != "" {
+			if node.Type == "BlockQuote" {
+				text = "> " + text
+			}
+			tp.emit(text, node)
+		}
+	case "L":
+		var list strings.Builder
+		tp.renderList(&list, node, 0)
+		if list.Len() > 0 {
+			tp.emit(list.String(), node)
+		}
+	case "Table":
+		var table strings.Builder
+		tp.renderTable(&table, node)
+		if table.Len() > 0 {
+			tp.emit(table.String(), node)
+		}
+	case "Figure", "Formula", "Artifact":
+		// Non-textual content; images are placed by the page layout and any
+		// alternate text is not rendered
+	default:
+		// Grouping elements: render block children and gather loose inline content into paragraphs
+		var pending strings.Builder
+		var inline []*structNode
+		flush := func() {
+			if text := normalizeSpace(pending.String()); text != "" {
+				tp.emit(text, inline...)
+			}
+			pending.Reset()
+			inline = nil
+		}
+		for _, kid := range node.Kids {
+			if kid.Type == "" || isInlineStructType(kid.Type) {
+				inline = append(inline, kid)
+				pending.WriteString(tp.rawText(kid))
+				continue
+			}
+			flush()
+			tp.renderBlock(kid)
+		}
+		flush()
+	}
+}
+
+func (tp *taggedPage) renderList(result *strings.Builder, list *structNode, level int) {
+	indent := strings.Repeat("  ", level)
+	for _, item := range list.Kids {
+		if item.Type != "LI" {
+			continue
+		}
+
+		marker := tp.listStyle.BulletMarker()
+		var body strings.Builder
+		var nested []*structNode
+		for _, kid := range item.Kids {
+			switch kid.Type {
+			case "Lbl":
+				if label := tp.text(kid); orderedLabel.MatchString(label) {
+					if label, ok := ordinal(label); ok {
+						marker = tp.listStyle.Relabel(label)
+					}
+				}
+			case "L":
+				nested = append(nested, kid)
+			case "LBody":
+				for _, part := range kid.Kids {
+					if part.Type == "L" {
+						nested = append(nested, part)
+						continue
+					}
+					body.WriteString(tp.rawText(part) + " ")
+				}
+			default:
+				body.WriteString(tp.rawText(kid))
+			}
+		}
+
+		if text := normalizeSpace(body.String()); text != "" {
+			result.WriteString(indent + marker + " " + text + "\n")
+		}
+		for _, sub := range nested {
+			tp.renderList(result, sub, level+1)
+		}
+	}
+}
+
+func (tp *taggedPage) renderTable(result *strings.Builder, table *structNode) {
+	var rows [][]string
+	var collect func(node *structNode)
+	collect = func(node *structNode) {
+		for _, kid := range node.Kids {
+			switch kid.Type {
+			case "TR":
+				var cells []string
+				for _, cell := range kid.Kids {
+					if cell.Type == "TH" || cell.Type == "TD" {
+						cells = append(cells, strings.ReplaceAll(tp.text(cell), "|", "\\|"))
+					}
+				}
+				if len(cells) > 0 && strings.Join(cells, "") != "" {
+					rows = append(rows, cells)
+				}
+			case "THead", "TBody", "TFoot":
+				collect(kid)
+			}
+		}
+	}
+	collect(table)
+	if len(rows) == 0 {
+		return
+	}
+
+	columns := 0
+	for _, row := range rows {
+		if len(row) > columns {
+			columns = len(row)
+		}
+	}
+
+	for i, row := range rows {
+		for len(row) < columns {
+			row = append(row, "")
+		}
+		result.WriteString("| " + strings.Join(row, " | ") + " |\n")
+		if i == 0 {
+			result.WriteString("|" + strings.Repeat(" --- |", columns) + "\n")
+		}
+	}
+}
+
+// text returns the normalized text of all content under node that lies on this page
+func (tp *taggedPage) text(node *structNode) string {
+	return normalizeSpace(tp.rawText(node))
+}
+
+func (tp *taggedPage) rawText(node *structNode) string {
+	if node.Type == "" {
+		if node.Page != "" && node.Page != tp.page {
+			return ""
+		}
+		return tp.texts[node.MCID]
+	}
+
+	var text strings.Builder
+	for _, kid := range node.Kids {
+		text.WriteString(tp.rawText(kid))
+	}
+	return text.String()
+}
+
+func isInlineStructType(structType string) bool {
+	switch structType {
+	case "Span", "Quote", "Link", "Reference", "BibEntry", "Lbl", "Annot", "Ruby", "Warichu":
+		return true
+	}
+	return false
+}
+
+func normalizeSpace(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// headingLevel parses the level of an H or Hn structure type
+func headingLevel(structType string) (int, bool) {
+	if structType == "H" {
+		return 1, true
+	}
+	if !strings.HasPrefix(structType, "H") {
+		return 0, false
+	}
+	level, err := strconv.Atoi(structType[1:])
+	if err != nil || level < 1 || level > 6 {
+		return 0, false
+	}
+	return level, true
+}
+
+// ReadStructure counts the headings, list items and tables declared in the
+// structure tree of a tagged PDF. It returns false for untagged documents.
+func ReadStructure(inputPath string) (s markdown.Structure, tagged bool, err error) {
+	// The PDF library panics on malformed objects
+	defer func() {
+		if r := recover(); r != nil {
+			s, tagged, err = markdown.Structure{}, false, fmt.Errorf("%w: %v", errs.ErrCorrupt, r)
+		}
+	}()
+
+	f, err := os.Open(inputPath)
+	if err != nil {
+		return markdown.Structure{}, false, fmt.Errorf("failed to open PDF: %v", err)
+	}
+	defer f.Close()
+
+	reader, err := openReader(f)
+	if err != nil {
+		return markdown.Structure{}, false, err
+	}
+	tree := parseStructTree(reader)
+	if tree == nil {
+		return markdown.Structure{}, false, nil
+	}
+
+	var walk func(node *structNode)
+	walk = func(node *structNode) {
+		if _, ok := headingLevel(node.Type); ok {
+			s.Headings++
+		}
+		switch node.Type {
+		case "LI":
+			s.ListItems++
+		case "Table":
+			s.Tables++
+		}
+		for _, kid := range node.Kids {
+			walk(kid)
+		}
+	}
+	walk(tree)
+	return s, true, nil
+}
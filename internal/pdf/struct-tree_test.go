@@ -0,0 +1,195 @@
+package pdf
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+)
+
+// taggedListPDF builds a tagged page holding a list whose items are labeled
+// with labels
+func taggedListPDF(labels ...string) *testPDF {
+	p := &testPDF{}
+	var content strings.Builder
+	var items []string
+	for i, label := range labels {
+		y := 700 - float64(i)*18
+		content.WriteString(markContent("Lbl", 2*i, showText(72, y, label, "F1", 12)))
+		content.WriteString(markContent("LBody", 2*i+1, showText(100, y, fmt.Sprintf("item %d", i+1), "F1", 12)))
+		lbl := p.add(fmt.Sprintf("<< /Type /StructElem /S /Lbl /Pg PAGE1 /K %d >>", 2*i))
+		lbody := p.add(fmt.Sprintf("<< /Type /StructElem /S /LBody /Pg PAGE1 /K %d >>", 2*i+1))
+		items = append(items, fmt.Sprintf("%d 0 R", p.add(fmt.Sprintf("<< /Type /StructElem /S /LI /K [%d 0 R %d 0 R] >>", lbl, lbody))))
+	}
+	list := p.add(fmt.Sprintf("<< /Type /StructElem /S /L /K [%s] >>", strings.Join(items, " ")))
+	root := p.add(fmt.Sprintf("<< /Type /StructTreeRoot /K %d 0 R >>", list))
+	p.catalog = fmt.Sprintf("/StructTreeRoot %d 0 R /MarkInfo << /Marked true >>", root)
+	p.pageWith(content.String(), "/StructParents 0", "")
+	return p
+}
+
+func TestTaggedListLabels(t *testing.T) {
+	tests := []struct {
+		name   string
+		style  markdown.ListStyle
+		labels []string
+		want   string
+	}{
+		{
+			name:   "numbers",
+			labels: []string{"1.", "2."},
+			want:   "1. item 1\n2. item 2",
+		},
+		{
+			name:   "numbers under the ordered delimiter",
+			style:  markdown.ListStyle{Ordered: ")"},
+			labels: []string{"(1)", "(2)"},
+			want:   "1) item 1\n2) item 2",
+		},
+		{
+			name:   "letters",
+			labels: []string{"a.", "b."},
+			want:   "- a. item 1\n- b. item 2",
+		},
+		{
+			name:   "roman numerals",
+			labels: []string{"iv)", "v)"},
+			want:   "- iv) item 1\n- v) item 2",
+		},
+		{
+			name:   "dash labels",
+			labels: []string{"-", "-"},
+			want:   "- item 1\n- item 2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := strings.TrimSpace(convert(t, &Converter{ListStyle: tt.style}, taggedListPDF(tt.labels...)))
+			if got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaggedStructure(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func(b *taggedBuilder) *testPDF
+		want  string
+	}{
+		{
+			name: "headings and paragraphs",
+			build: func(b *taggedBuilder) *testPDF {
+				return b.build("", b.elem("H1", b.text("Title")), b.elem("P", b.text("Body text.")), b.elem("H2", b.text("Part")))
+			},
+			want: "# Title\n\nBody text.\n\n## Part",
+		},
+		{
+			name: "tree order over drawing order",
+			build: func(b *taggedBuilder) *testPDF {
+				first, second := b.text("Drawn first."), b.text("Drawn second.")
+				return b.build("", b.elem("P", second), b.elem("P", first))
+			},
+			want: "Drawn second.\n\nDrawn first.",
+		},
+		{
+			name: "role map",
+			build: func(b *taggedBuilder) *testPDF {
+				return b.build("/RoleMap << /Title /Heading /Heading /H2 >>", b.elem("Title", b.text("Mapped")))
+			},
+			want: "## Mapped",
+		},
+		{
+			name: "block quote",
+			build: func(b *taggedBuilder) *testPDF {
+				return b.build("", b.elem("BlockQuote", b.text("Quoted words.")))
+			},
+			want: "> Quoted words.",
+		},
+		{
+			name: "table",
+			build: func(b *taggedBuilder) *testPDF {
+				head := b.elem("TR", b.elem("TH", b.text("Name")), b.elem("TH", b.text("Qty")))
+				row := b.elem("TR", b.elem("TD", b.text("Apples")), b.elem("TD", b.text("3|4")))
+				return b.build("", b.elem("Table", head, row))
+			},
+			want: "| Name | Qty |\n| --- | --- |\n| Apples | 3\\|4 |",
+		},
+		{
+			name: "spans gathered into a paragraph",
+			build: func(b *taggedBuilder) *testPDF {
+				return b.build("", b.elem("Sect", b.elem("Span", b.text("Loose ")), b.elem("Span", b.text("words"))))
+			},
+			want: "Loose words",
+		},
+		{
+			name: "figures left out",
+			build: func(b *taggedBuilder) *testPDF {
+				return b.build("", b.elem("Figure", b.text("Chart label")), b.elem("P", b.text("After.")))
+			},
+			want: "After.",
+		},
+		{
+			name: "cycles left out",
+			build: func(b *taggedBuilder) *testPDF {
+				loop := len(b.objects) + 1
+				b.add(fmt.Sprintf("<< /Type /StructElem /S /P /Pg PAGE1 /K [%s %d 0 R] >>", b.text("Looped."), loop))
+				kids := len(b.objects) + 1
+				b.add(fmt.Sprintf("[%s %d 0 R %d 0 R]", b.text("Listed."), kids, kids))
+				return b.build("", fmt.Sprintf("%d 0 R", loop), b.elem("Sect", fmt.Sprintf("%d 0 R", kids)))
+			},
+			want: "Looped.\n\nListed.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := strings.TrimSpace(convert(t, &Converter{UseTags: true}, tt.build(&taggedBuilder{})))
+			if got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStandardStructType(t *testing.T) {
+	p := &testPDF{}
+	p.catalog = "/RoleMap << /Title /H1 /Chapter /Sect /Loop /Loop /A /B /B /A >>"
+	p.page("")
+	reader := openTestPDF(t, p)
+	roleMap := reader.Trailer().Key("Root").Key("RoleMap")
+	tests := []struct {
+		in, want string
+	}{
+		{"Title", "H1"},
+		{"Chapter", "Sect"},
+		{"P", "P"},
+		{"Loop", "Loop"},
+	}
+	for _, tt := range tests {
+		if got := standardStructType(tt.in, roleMap); got != tt.want {
+			t.Errorf("standardStructType(%s) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+	// Cycles end after maxStructDepth steps
+	if got := standardStructType("A", roleMap); got != "A" && got != "B" {
+		t.Errorf("standardStructType(A) = %s", got)
+	}
+}
+
+func TestHeadingLevel(t *testing.T) {
+	tests := []struct {
+		in    string
+		level int
+		ok    bool
+	}{
+		{"H", 1, true}, {"H1", 1, true}, {"H6", 6, true}, {"H7", 0, false},
+		{"H0", 0, false}, {"Hx", 0, false}, {"P", 0, false},
+	}
+	for _, tt := range tests {
+		if level, ok := headingLevel(tt.in); level != tt.level || ok != tt.ok {
+			t.Errorf("headingLevel(%s) = %d, %v, want %d, %v", tt.in, level, ok, tt.level, tt.ok)
+		}
+	}
+}
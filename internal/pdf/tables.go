@@ -0,0 +1,293 @@
+package pdf
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+)
+
+// borderTolerance is how far apart, in points, border lines may be and still meet
+const borderTolerance = 2.0
+
+// border is a horizontal or vertical line painted on a page, in device space
+type border struct {
+	X0, Y0, X1, Y1 float64
+}
+
+func (b border) horizontal() bool {
+	return math.Abs(b.Y1-b.Y0) < math.Abs(b.X1-b.X0)
+}
+
+// tableGrid is the cell structure of a table drawn with borders. Xs are the
+// column boundaries from left to right, Ys the row boundaries from top to bottom.
+type tableGrid struct {
+	Xs []float64
+	Ys []float64
+}
+
+// pathBorders returns the horizontal and vertical lines of a painted path that
+// may outline table cells. Stroked rectangles contribute their four edges and
+// thin filled rectangles their center line; wide fills are cell shading.
+func (g graphicsState) pathBorders(path []pathSegment, stroke bool) []border {
+	var borders []border
+	add := func(from, to [2]float64) {
+		x0, y0 := g.transform(from)
+		x1, y1 := g.transform(to)
+		if math.Abs(y1-y0) > 0.5 && math.Abs(x1-x0) > 0.5 {
+			return // Diagonal
+		}
+		borders = append(borders, border{
+			X0: math.Min(x0, x1), Y0: math.Min(y0, y1),
+			X1: math.Max(x0, x1), Y1: math.Max(y0, y1),
+		})
+	}
+
+	for _, seg := range path {
+		switch {
+		case seg.Curve:
+			continue
+		case seg.Rect && stroke:
+			x0, y0, x1, y1 := seg.From[0], seg.From[1], seg.To[0], seg.To[1]
+			add([2]float64{x0, y0}, [2]float64{x1, y0})
+			add([2]float64{x0, y1}, [2]float64{x1, y1})
+			add([2]float64{x0, y0}, [2]float64{x0, y1})
+			add([2]float64{x1, y0}, [2]float64{x1, y1})
+		case seg.Rect:
+			w, h := math.Abs(seg.To[0]-seg.From[0]), math.Abs(seg.To[1]-seg.From[1])
+			midX, midY := (seg.From[0]+seg.To[0])/2, (seg.From[1]+seg.To[1])/2
+			if h <= maxRuleThickness && w > h {
+				add([2]float64{seg.From[0], midY}, [2]float64{seg.To[0], midY})
+			} else if w <= maxRuleThickness && h > w {
+				add([2]float64{midX, seg.From[1]}, [2]float64{midX, seg.To[1]})
+			}
+		case stroke:
+			add(seg.From, seg.To)
+		}
+	}
+	return borders
+}
+
+// findTableGrids derives the rows and columns of the tables drawn by groups of
+// borders. Groups with fewer than two columns are frames or rules rather than
+// tables.
+func findTableGrids(groups [][]border) []tableGrid {
+	var grids []tableGrid
+	for _, group := range groups {
+		xs, ys := boundaries(group)
+		if len(xs) < 3 || len(ys) < 2 {
+			continue
+		}
+		// Rows run from the top of the page down
+		for i, j := 0, len(ys)-1; i < j; i, j = i+1, j-1 {
+			ys[i], ys[j] = ys[j], ys[i]
+		}
+		grids = append(grids, tableGrid{Xs: xs, Ys: ys})
+	}
+	return grids
+}
+
+// groupBorders unions the borders that meet into groups, each drawing one
+// table or frame
+func groupBorders(borders []border) [][]border {
+	parent := make([]int, len(borders))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	for i := range borders {
+		for j := i + 1; j < len(borders); j++ {
+			if bordersMeet(borders[i], borders[j]) {
+				parent[find(i)] = find(j)
+			}
+		}
+	}
+
+	index := make(map[int]int)
+	var groups [][]border
+	for i, b := range borders {
+		root := find(i)
+		if _, ok := index[root]; !ok {
+			index[root] = len(groups)
+			groups = append(groups, nil)
+		}
+		groups[index[root]] = append(groups[index[root]], b)
+	}
+	return groups
+}
+
+// boundaries returns the distinct positions of the vertical and of the
+// horizontal borders of a group, in increasing order
+func boundaries(group []border) (xs, ys []float64) {
+	for _, b := range group {
+		if b.horizontal() {
+			ys = append(ys, (b.Y0+b.Y1)/2)
+		} else {
+			xs = append(xs, (b.X0+b.X1)/2)
+		}
+	}
+	return mergeBoundaries(xs), mergeBoundaries(ys)
+}
+
+// bordersMeet reports whether two borders touch or cross
+func bordersMeet(a, b border) bool {
+	return a.X0 <= b.X1+borderTolerance && b.X0 <= a.X1+borderTolerance &&
+		a.Y0 <= b.Y1+borderTolerance && b.Y0 <= a.Y1+borderTolerance
+}
+
+// mergeBoundaries sorts positions and merges those closer than borderTolerance
+func mergeBoundaries(values []float64) []float64 {
+	sort.Float64s(values)
+	var merged []float64
+	for _, v := range values {
+		if n := len(merged); n > 0 && v-merged[n-1] < borderTolerance {
+			continue
+		}
+		merged = append(merged, v)
+	}
+	return merged
+}
+
+// contains reports whether a point lies within the outer border of the grid
+func (t tableGrid) contains(x, y float64) bool {
+	return x >= t.Xs[0] && x <= t.Xs[len(t.Xs)-1] && y <= t.Ys[0] && y >= t.Ys[len(t.Ys)-1]
+}
+
+// cell returns the row and column of a point inside the grid
+func (t tableGrid) cell(x, y float64) (int, int) {
+	row, col := len(t.Ys)-2, len(t.Xs)-2
+	for i := 1; i < len(t.Ys); i++ {
+		if y >= t.Ys[i] {
+			row = i - 1
+			break
+		}
+	}
+	for i := 1; i < len(t.Xs); i++ {
+		if x < t.Xs[i] {
+			col = i - 1
+			break
+		}
+	}
+	return row, col
+}
+
+// excludeGridRules drops the rules that are row borders of a table, so text
+// sitting on them is not mistaken for underlined text
+func excludeGridRules(rules []rule, grids []tableGrid) []rule {
+	if len(grids) == 0 {
+		return rules
+	}
+	var kept []rule
+	for _, r := range rules {
+		inGrid := false
+		for _, grid := range grids {
+			if grid.contains((r.X0+r.X1)/2, r.Y) {
+				inGrid = true
+				break
+			}
+		}
+		if !inGrid {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+// extractBorderedTables assigns the elements lying inside drawn table grids to
+// their cells by position and renders each table as a block. The remaining
+// elements are returned for the line-based heuristics.
+func (c *Converter) extractBorderedTables(elements []TextElement, grids []tableGrid) ([]TextElement, []TextLine) {
+	if len(grids) == 0 {
+		return elements, nil
+	}
+
+	cells := make([][][][]TextElement, len(grids))
+	for i, grid := range grids {
+		cells[i] = make([][][]TextElement, len(grid.Ys)-1)
+		for row := range cells[i] {
+			cells[i][row] = make([][]TextElement, len(grid.Xs)-1)
+		}
+	}
+
+	var rest []TextElement
+	for _, e := range elements {
+		x, y := e.X+e.Width/2, e.Y+e.Size*0.3
+		placed := false
+		for i, grid := range grids {
+			if grid.contains(x, y) {
+				row, col := grid.cell(x, y)
+				cells[i][row][col] = append(cells[i][row][col], e)
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			rest = append(rest, e)
+		}
+	}
+
+	var blocks []TextLine
+	for i, grid := range grids {
+		if table := c.renderGrid(cells[i]); table != "" {
+			blocks = append(blocks, TextLine{Y: grid.Ys[0], MCID: noMCID, Block: table, MinX: grid.Xs[0], MaxX: grid.Xs[len(grid.Xs)-1]})
+		}
+	}
+	return rest, blocks
+}
+
+// renderGrid renders the cells of a bordered table as a Markdown table, the
+// first row being the header. Rows without text are left out.
+func (c *Converter) renderGrid(cells [][][]TextElement) string {
+	var lefts []float64
+	space := 0.0
+	if c.PreserveCellSpacing {
+		lefts, space = columnLefts(cells)
+	}
+	var rows [][]string
+	for _, row := range cells {
+		var texts []string
+		empty := true
+		for col, cell := range row {
+			var parts []string
+			for _, line := range c.groupElementsIntoLines(cell) {
+				text := strings.TrimSpace(c.extractLineText(line))
+				if text != "" && c.PreserveCellSpacing {
+					text = keepSpaces(spacedText(line.Elements, lefts[col], space))
+				}
+				if text != "" {
+					parts = append(parts, text)
+				}
+			}
+			text := strings.ReplaceAll(strings.Join(parts, " "), "|", "\\|")
+			if text != "" {
+				empty = false
+			}
+			texts = append(texts, text)
+		}
+		if !empty {
+			rows = append(rows, texts)
+		}
+	}
+	if len(rows) == 0 {
+		return ""
+	}
+	if c.FlattenTables {
+		return markdown.FlattenTable(rows)
+	}
+
+	var b strings.Builder
+	for i, row := range rows {
+		b.WriteString("| " + strings.Join(row, " | ") + " |\n")
+		if i == 0 {
+			b.WriteString("|" + strings.Repeat(" --- |", len(row)) + "\n")
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
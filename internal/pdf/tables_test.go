@@ -0,0 +1,81 @@
+package pdf
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// gridLines strokes the vertical borders at xs and the horizontal ones at ys
+func gridLines(xs, ys []float64) string {
+	var b strings.Builder
+	b.WriteString("0.5 w\n")
+	for _, x := range xs {
+		fmt.Fprintf(&b, "%g %g m %g %g l S\n", x, ys[0], x, ys[len(ys)-1])
+	}
+	for _, y := range ys {
+		fmt.Fprintf(&b, "%g %g m %g %g l S\n", xs[0], y, xs[len(xs)-1], y)
+	}
+	return b.String()
+}
+
+func TestBorderedTables(t *testing.T) {
+	xs := []float64{72, 180, 300}
+	ys := []float64{700, 680, 660, 640}
+	cells := showText(76, 686, "Fruit", "F1", 12) + showText(184, 686, "Stock", "F1", 12) +
+		showText(76, 666, "Apples", "F1", 12) + showText(184, 666, "12", "F1", 12) +
+		showText(184, 646, "7", "F1", 12)
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "lines",
+			content: gridLines(xs, ys) + cells,
+			want:    "| Fruit | Stock |\n| --- | --- |\n| Apples | 12 |\n|  | 7 |",
+		},
+		{
+			name:    "stroked rectangles",
+			content: "0.5 w 72 680 108 20 re 180 680 120 20 re 72 660 108 20 re 180 660 120 20 re 72 640 108 20 re 180 640 120 20 re S\n" + cells,
+			want:    "| Fruit | Stock |\n| --- | --- |\n| Apples | 12 |\n|  | 7 |",
+		},
+		{
+			name: "wrapped cell",
+			content: gridLines(xs, []float64{700, 680, 640}) + showText(76, 686, "Fruit", "F1", 12) + showText(184, 686, "Notes", "F1", 12) +
+				showText(76, 666, "Pears", "F1", 12) + showText(184, 666, "Ripe in", "F1", 12) + showText(184, 650, "autumn", "F1", 12),
+			want: "| Fruit | Notes |\n| --- | --- |\n| Pears | Ripe in autumn |",
+		},
+	}
+	for _, tt := range tests {
+		p := &testPDF{}
+		p.page(tt.content + showText(72, 600, "After the table.", "F1", 12))
+		md := convert(t, &Converter{AssetsDir: t.TempDir()}, p)
+		if !strings.Contains(md, tt.want) {
+			t.Errorf("%s: output lacks %q:\n%s", tt.name, tt.want, md)
+		}
+		if !strings.Contains(md, "After the table.") {
+			t.Errorf("%s: text outside the grid is lost:\n%s", tt.name, md)
+		}
+	}
+}
+
+func TestFindTableGrids(t *testing.T) {
+	frame := []border{{72, 600, 300, 600}, {72, 700, 300, 700}, {72, 600, 72, 700}, {300, 600, 300, 700}}
+	grid := append(frame[:4:4], border{180, 600, 180, 700}, border{72, 650, 300, 650})
+	tests := []struct {
+		name    string
+		borders []border
+		want    string
+	}{
+		{"frame", frame, "[]"},
+		{"grid", grid, "[{[72 180 300] [700 650 600]}]"},
+		{"rule", []border{{72, 500, 300, 500}}, "[]"},
+		{"near borders merge", append(grid, border{181, 600, 181, 700}), "[{[72 180 300] [700 650 600]}]"},
+	}
+	for _, tt := range tests {
+		if got := fmt.Sprint(findTableGrids(groupBorders(tt.borders))); got != tt.want {
+			t.Errorf("%s: findTableGrids() = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package pdf
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// tabStopTolerance is how far apart, in points, the starts of text may be and
+// still share a tab stop
+const tabStopTolerance = 3.0
+
+// tabSegment is the text of a line between two tab stops and where it starts
+type tabSegment struct {
+	X    float64
+	Text string
+}
+
+// tabbedListings finds the runs of consecutive lines whose text is split by
+// gaps as wide as separate table cells, at stops shared by the lines, and
+// returns the index past the last line of each run, by its first line. Lines
+// within the listings found before are left to them.
+func tabbedListings(lines []TextLine, listings map[int]int) map[int]int {
+	taken := make(map[int]bool)
+	for start, end := range listings {
+		for i := start; i < end; i++ {
+			taken[i] = true
+		}
+	}
+
+	tabbed := make(map[int]int)
+	for i := 0; i < len(lines); {
+		end := i
+		for end < len(lines) && !taken[end] && len(tabSegments(lines[end])) >= 2 {
+			end++
+		}
+		if end-i >= 2 && sharesTabStops(lines[i:end]) {
+			tabbed[i] = end
+		}
+		i = max(end, i+1)
+	}
+	return tabbed
+}
+
+// tabSegments splits a line of text at gaps as wide as separate table cells
+func tabSegments(line TextLine) []tabSegment {
+	if line.Image != "" || line.Block != "" || len(line.Elements) == 0 {
+		return nil
+	}
+	var segments []tabSegment
+	start := 0
+	for i := 1; i <= len(line.Elements); i++ {
+		if i < len(line.Elements) {
+			prev := line.Elements[i-1]
+			if line.Elements[i].X-prev.X-prev.Width < line.FontSize*cellGapScale {
+				continue
+			}
+		}
+		var text strings.Builder
+		for _, element := range spaceElements(line.Elements[start:i]) {
+			if !element.Image {
+				text.WriteString(element.Text)
+			}
+		}
+		if s := strings.TrimSpace(text.String()); s != "" {
+			segments = append(segments, tabSegment{X: line.Elements[start].X, Text: s})
+		}
+		start = i
+	}
+	return segments
+}
+
+// sharesTabStops reports whether every segment after the first of each line
+// starts at a stop where a segment of another line starts too
+func sharesTabStops(lines []TextLine) bool {
+	var starts [][]float64
+	for _, line := range lines {
+		var xs []float64
+		for _, segment := range tabSegments(line)[1:] {
+			xs = append(xs, segment.X)
+		}
+		starts = append(starts, xs)
+	}
+	for i, xs := range starts {
+		for _, x := range xs {
+			shared := false
+			for j, others := range starts {
+				if j != i && nearStop(others, x) {
+					shared = true
+					break
+				}
+			}
+			if !shared {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// nearStop reports whether x lies within tabStopTolerance of one of stops
+func nearStop(stops []float64, x float64) bool {
+	for _, stop := range stops {
+		if math.Abs(stop-x) <= tabStopTolerance {
+			return true
+		}
+	}
+	return false
+}
+
+// renderTabbed renders lines set at tab stops as fenced code, separating their
+// segments with a tab for every stop passed, so a line that leaves a column
+// empty keeps the columns after it aligned
+func renderTabbed(lines []TextLine) string {
+	var stops []float64
+	for _, line := range lines {
+		for _, segment := range tabSegments(line) {
+			if !nearStop(stops, segment.X) {
+				stops = append(stops, segment.X)
+			}
+		}
+	}
+	sort.Float64s(stops)
+	column := func(x float64) int {
+		nearest := 0
+		for i, stop := range stops {
+			if math.Abs(stop-x) < math.Abs(stops[nearest]-x) {
+				nearest = i
+			}
+		}
+		return nearest
+	}
+
+	var body []string
+	for _, line := range lines {
+		var b strings.Builder
+		at := 0
+		for i, segment := range tabSegments(line) {
+			col := column(segment.X)
+			tabs := col - at
+			if i > 0 {
+				tabs = max(tabs, 1)
+			}
+			b.WriteString(strings.Repeat("\t", tabs) + segment.Text)
+			at = col
+		}
+		body = append(body, b.String())
+	}
+	return fenceCode(strings.Join(body, "\n"))
+}
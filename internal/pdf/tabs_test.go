@@ -0,0 +1,114 @@
+package pdf
+
+import (
+	"strings"
+	"testing"
+)
+
+// tabbedRows shows rows of text whose cells start at the given stops, 18
+// points apart from the top of the page down; empty cells are left out
+func tabbedRows(stops []float64, rows ...[]string) string {
+	var b strings.Builder
+	for i, row := range rows {
+		for j, cell := range row {
+			if cell != "" {
+				b.WriteString(showText(stops[j], 700-float64(i)*18, cell, "F1", 12))
+			}
+		}
+	}
+	return b.String()
+}
+
+func TestPreserveTabs(t *testing.T) {
+	stops := []float64{72, 200, 300}
+	tests := []struct {
+		name         string
+		content      string
+		preserveTabs bool
+		// want is the fenced code the rows are written as, or empty when
+		// they are not fenced
+		want string
+	}{
+		{
+			name:         "tab-aligned columns",
+			content:      tabbedRows(stops, []string{"Name", "Size", "Owner"}, []string{"main.go", "12", "ana"}, []string{"go.mod", "3", "root"}),
+			preserveTabs: true,
+			want:         "```\nName\tSize\tOwner\nmain.go\t12\tana\ngo.mod\t3\troot\n```",
+		},
+		{
+			name:         "empty column kept aligned",
+			content:      tabbedRows(stops, []string{"PID", "TTY", "CMD"}, []string{"41", "", "bash"}, []string{"57", "pts/0", "top"}),
+			preserveTabs: true,
+			want:         "```\nPID\tTTY\tCMD\n41\t\tbash\n57\tpts/0\ttop\n```",
+		},
+		{
+			name:         "stops not shared",
+			content:      showText(72, 700, "Left", "F1", 12) + showText(200, 700, "Right", "F1", 12) + showText(72, 682, "Other", "F1", 12) + showText(330, 682, "Far", "F1", 12),
+			preserveTabs: true,
+		},
+		{
+			name:    "without preserve tabs",
+			content: tabbedRows(stops, []string{"Name", "Size", "Owner"}, []string{"main.go", "12", "ana"}),
+		},
+	}
+	for _, tt := range tests {
+		p := &testPDF{}
+		p.page(tt.content)
+		got := convert(t, &Converter{AssetsDir: t.TempDir(), PreserveTabs: tt.preserveTabs}, p)
+		if tt.want == "" {
+			if strings.Contains(got, "```") || strings.Contains(got, "\t") {
+				t.Errorf("%s: rows fenced with tabs in:\n%s", tt.name, got)
+			}
+		} else if !strings.Contains(got, tt.want) {
+			t.Errorf("%s: got:\n%s\nwant:\n%s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTabSegments(t *testing.T) {
+	tests := []struct {
+		name     string
+		elements []TextElement
+		want     []tabSegment
+	}{
+		{
+			name:     "cells",
+			elements: []TextElement{{Text: "main", X: 72, Width: 24, Size: 12}, {Text: ".go", X: 96, Width: 18, Size: 12}, {Text: "12", X: 200, Width: 12, Size: 12}},
+			want:     []tabSegment{{72, "main.go"}, {200, "12"}},
+		},
+		{
+			name:     "words of a sentence",
+			elements: []TextElement{{Text: "Words", X: 72, Width: 30, Size: 12}, {Text: "set", X: 108, Width: 18, Size: 12}, {Text: "apart", X: 132, Width: 30, Size: 12}},
+			want:     []tabSegment{{72, "Words set apart"}},
+		},
+		{
+			name:     "blank cell left out",
+			elements: []TextElement{{Text: "PID", X: 72, Width: 18, Size: 12}, {Text: " ", X: 200, Width: 6, Size: 12}, {Text: "bash", X: 300, Width: 24, Size: 12}},
+			want:     []tabSegment{{72, "PID"}, {300, "bash"}},
+		},
+	}
+	for _, tt := range tests {
+		got := tabSegments(TextLine{Y: 700, FontSize: 12, Elements: tt.elements})
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: tabSegments() = %+v, want %+v", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: segment %d = %+v, want %+v", tt.name, i, got[i], tt.want[i])
+			}
+		}
+	}
+	if got := tabSegments(TextLine{Image: "assets/figure.png"}); got != nil {
+		t.Errorf("tabSegments(image line) = %+v, want nil", got)
+	}
+}
+
+func TestNearStop(t *testing.T) {
+	stops := []float64{72, 200}
+	for x, want := range map[float64]bool{72: true, 202.5: true, 197: true, 204: false, 100: false} {
+		if got := nearStop(stops, x); got != want {
+			t.Errorf("nearStop(%v, %g) = %v, want %v", stops, x, got, want)
+		}
+	}
+}
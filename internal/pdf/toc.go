@@ -0,0 +1,37 @@
+package pdf
+
+import (
+	"math"
+	"regexp"
+	"strings"
+)
+
+// tocLeader matches a table of contents entry: a title, a leader of dots and
+// a trailing page number such as "Chapter 1 ........ 5"
+var tocLeader = regexp.MustCompile(`^(.*?\S)\s*(?:\.\s*){3,}\s*(\d+|[ivxlcdm]+)$`)
+
+// tocIndent is the indentation that separates levels of a table of contents
+const tocIndent = 12.0
+
+// tocEntry is a line of a table of contents found in the source document
+type tocEntry struct {
+	Title string
+	Page  string
+	Level int
+}
+
+// parseTOCLine recognizes a table of contents entry, using the indentation of
+// the line within the text block as its nesting level
+func (l pageLayout) parseTOCLine(line TextLine, text string) (tocEntry, bool) {
+	m := tocLeader.FindStringSubmatch(strings.TrimSpace(text))
+	if m == nil || len(line.Elements) == 0 {
+		return tocEntry{}, false
+	}
+
+	left, _ := lineBounds(line)
+	level := int(math.Max(0, math.Round((left-l.Left)/tocIndent)))
+	if level > 5 {
+		level = 5
+	}
+	return tocEntry{Title: strings.TrimSpace(m[1]), Page: m[2], Level: level}, true
+}
@@ -0,0 +1,82 @@
+package pdf
+
+import (
+	"strings"
+	"testing"
+)
+
+// tocPDF builds a contents page whose entries are indented by level, followed
+// by a page of text
+func tocPDF() *testPDF {
+	p := &testPDF{}
+	p.page(showText(72, 700, "Contents", "F1", 12) +
+		showText(72, 682, "Introduction ........ 1", "F1", 12) +
+		showText(84, 664, "Getting started . . . . 3", "F1", 12) +
+		showText(72, 646, "Appendix ........ iv", "F1", 12))
+	p.page(body("The body of the document."))
+	return p
+}
+
+func TestSourceTOC(t *testing.T) {
+	tests := []struct {
+		name string
+		c    Converter
+		want []string
+		not  []string
+	}{
+		{
+			name: "entries become links",
+			want: []string{
+				"- [Introduction](#introduction)\n  - [Getting started](#getting-started)\n- [Appendix](#appendix)\n",
+				"The body of the document.",
+			},
+			not: []string{"...."},
+		},
+		{
+			name: "dropped",
+			c:    Converter{DropSourceTOC: true},
+			want: []string{"Contents", "The body of the document."},
+			not:  []string{"Introduction", "Appendix", "...."},
+		},
+	}
+	for _, tt := range tests {
+		c := tt.c
+		c.AssetsDir = t.TempDir()
+		md := convert(t, &c, tocPDF())
+		for _, s := range tt.want {
+			if !strings.Contains(md, s) {
+				t.Errorf("%s: output lacks %q:\n%s", tt.name, s, md)
+			}
+		}
+		for _, s := range tt.not {
+			if strings.Contains(md, s) {
+				t.Errorf("%s: output has %q:\n%s", tt.name, s, md)
+			}
+		}
+	}
+}
+
+func TestTOCLeader(t *testing.T) {
+	tests := []struct {
+		text  string
+		title string
+		page  string
+	}{
+		{"Chapter 1 ........ 5", "Chapter 1", "5"},
+		{"Preface . . . . . xii", "Preface", "xii"},
+		{"Results.....42", "Results", "42"},
+		{"Wait.. 3", "", ""},
+		{"Chapter 1 5", "", ""},
+		{"Loading ........", "", ""},
+	}
+	for _, tt := range tests {
+		m := tocLeader.FindStringSubmatch(tt.text)
+		var title, page string
+		if m != nil {
+			title, page = m[1], m[2]
+		}
+		if title != tt.title || page != tt.page {
+			t.Errorf("%q: got (%q, %q), want (%q, %q)", tt.text, title, page, tt.title, tt.page)
+		}
+	}
+}
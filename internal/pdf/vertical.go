@@ -0,0 +1,174 @@
+package pdf
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/rsc/pdf"
+)
+
+const (
+	// verticalMinGlyphs is the shortest column of stacked CJK glyphs taken as
+	// vertical text when the font does not declare a vertical writing mode
+	verticalMinGlyphs = 3
+	// verticalColumnGap is the widest gap between columns of one vertical
+	// paragraph, relative to the font size
+	verticalColumnGap = 2.5
+)
+
+// isVerticalEncoding reports whether a font encoding selects vertical writing
+// mode, as the Identity-V CMap and other CMaps named with a -V suffix do
+func isVerticalEncoding(enc pdf.Value) bool {
+	name := enc.Name()
+	if enc.Kind() == pdf.Stream || enc.Kind() == pdf.Dict {
+		if enc.Key("WMode").Int64() == 1 {
+			return true
+		}
+		name = enc.Key("CMapName").Name()
+	}
+	return strings.HasSuffix(name, "-V")
+}
+
+// isCJK reports whether a glyph belongs to a script commonly set vertically
+func isCJK(text string) bool {
+	for _, r := range text {
+		if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) {
+			return true
+		}
+	}
+	return false
+}
+
+// extractVerticalText removes the glyphs of vertical text from the elements
+// and reassembles them into paragraphs, read top to bottom within a column
+// and right to left across columns. Glyphs are vertical when their font says
+// so, or when CJK glyphs are stacked a line apart with nothing beside them.
+func (c *Converter) extractVerticalText(elements []TextElement) ([]TextElement, []TextLine) {
+	vertical := make([]bool, len(elements))
+	found := false
+	for i, e := range elements {
+		vertical[i] = e.Vertical
+		found = found || e.Vertical
+	}
+	if markStackedGlyphs(elements, vertical) {
+		found = true
+	}
+	if !found {
+		return elements, nil
+	}
+
+	var rest, glyphs []TextElement
+	for i, e := range elements {
+		if vertical[i] {
+			glyphs = append(glyphs, e)
+		} else {
+			rest = append(rest, e)
+		}
+	}
+
+	var blocks []TextLine
+	for _, paragraph := range verticalParagraphs(glyphs) {
+		var text strings.Builder
+		top := math.Inf(-1)
+		minX, maxX := math.Inf(1), math.Inf(-1)
+		for _, column := range paragraph {
+			text.WriteString(strings.TrimSpace(c.extractLineText(TextLine{Elements: column, FontSize: column[0].Size})))
+			top = math.Max(top, column[0].Y+column[0].Size)
+			for _, e := range column {
+				minX, maxX = math.Min(minX, e.X), math.Max(maxX, e.X+e.Width)
+			}
+		}
+		if text.Len() > 0 {
+			blocks = append(blocks, TextLine{Y: top, MCID: noMCID, Block: text.String(), MinX: minX, MaxX: maxX})
+		}
+	}
+	return rest, blocks
+}
+
+// markStackedGlyphs marks columns of CJK glyphs set one above the other, each
+// alone on its line, as vertical. It reports whether any were found.
+func markStackedGlyphs(elements []TextElement, vertical []bool) bool {
+	isolated := func(i int) bool {
+		e := elements[i]
+		for j, other := range elements {
+			if j != i && math.Abs(other.Y-e.Y) < e.Size*0.3 && math.Abs(other.X-e.X) < e.Size*1.5 {
+				return false
+			}
+		}
+		return true
+	}
+
+	var candidates []int
+	for i, e := range elements {
+		if !vertical[i] && isCJK(e.Text) && isolated(i) {
+			candidates = append(candidates, i)
+		}
+	}
+	sort.SliceStable(candidates, func(a, b int) bool {
+		ea, eb := elements[candidates[a]], elements[candidates[b]]
+		if math.Abs(ea.X-eb.X) > ea.Size*0.3 {
+			return ea.X < eb.X
+		}
+		return ea.Y > eb.Y
+	})
+
+	found := false
+	for start := 0; start < len(candidates); {
+		end := start + 1
+		for end < len(candidates) {
+			prev, next := elements[candidates[end-1]], elements[candidates[end]]
+			step := prev.Y - next.Y
+			if math.Abs(prev.X-next.X) > prev.Size*0.3 || step < prev.Size*0.8 || step > prev.Size*1.6 {
+				break
+			}
+			end++
+		}
+		if end-start >= verticalMinGlyphs {
+			for _, i := range candidates[start:end] {
+				vertical[i] = true
+			}
+			found = true
+		}
+		start = end
+	}
+	return found
+}
+
+// verticalParagraphs groups vertical glyphs into columns, ordered right to
+// left with their glyphs top to bottom, and joins neighbouring columns that
+// overlap vertically into paragraphs
+func verticalParagraphs(glyphs []TextElement) [][][]TextElement {
+	sort.SliceStable(glyphs, func(i, j int) bool {
+		if math.Abs(glyphs[i].X-glyphs[j].X) > glyphs[i].Size*0.5 {
+			return glyphs[i].X > glyphs[j].X
+		}
+		return glyphs[i].Y > glyphs[j].Y
+	})
+
+	var columns [][]TextElement
+	for _, g := range glyphs {
+		if n := len(columns); n > 0 && math.Abs(columns[n-1][0].X-g.X) <= g.Size*0.5 {
+			columns[n-1] = append(columns[n-1], g)
+			continue
+		}
+		columns = append(columns, []TextElement{g})
+	}
+
+	var paragraphs [][][]TextElement
+	for _, column := range columns {
+		if n := len(paragraphs); n > 0 {
+			prev := paragraphs[n-1][len(paragraphs[n-1])-1]
+			top, bottom := column[0].Y, column[len(column)-1].Y
+			prevTop, prevBottom := prev[0].Y, prev[len(prev)-1].Y
+			size := column[0].Size
+			if prev[0].X-column[0].X <= size*verticalColumnGap && top >= prevBottom-size && bottom <= prevTop+size {
+				paragraphs[n-1] = append(paragraphs[n-1], column)
+				continue
+			}
+		}
+		paragraphs = append(paragraphs, [][]TextElement{column})
+	}
+	return paragraphs
+}
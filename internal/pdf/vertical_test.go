@@ -0,0 +1,58 @@
+package pdf
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// glyphColumn returns the elements of s stacked one glyph per line at x,
+// from y downwards
+func glyphColumn(x, y float64, s string) []TextElement {
+	var column []TextElement
+	for i, r := range []rune(s) {
+		column = append(column, TextElement{Text: string(r), Font: "MSMincho", Size: 12, X: x, Y: y - 14*float64(i), Width: 12})
+	}
+	return column
+}
+
+func TestVerticalText(t *testing.T) {
+	latin := TextElement{Text: "A", Font: "Helvetica", Size: 12, X: 72, Y: 500, Width: 6}
+	tests := []struct {
+		name     string
+		elements []TextElement
+		want     []string
+		rest     int
+	}{
+		{"columns right to left", append(glyphColumn(280, 700, "文章です"), glyphColumn(300, 700, "日本語の")...), []string{"日本語の文章です"}, 0},
+		{"separate paragraphs", append(glyphColumn(300, 700, "日本語"), glyphColumn(200, 700, "文章です")...), []string{"日本語", "文章です"}, 0},
+		{"latin text kept", append(glyphColumn(300, 700, "日本語の"), latin), []string{"日本語の"}, 1},
+		{"too few stacked", glyphColumn(300, 700, "日本"), nil, 2},
+		{"latin column", glyphColumn(300, 700, "ABCD"), nil, 4},
+	}
+	for _, tt := range tests {
+		rest, blocks := (&Converter{}).extractVerticalText(tt.elements)
+		var got []string
+		for _, block := range blocks {
+			got = append(got, block.Block)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) || len(rest) != tt.rest {
+			t.Errorf("%s: blocks = %q with %d left, want %q with %d", tt.name, got, len(rest), tt.want, tt.rest)
+		}
+	}
+}
+
+func TestVerticalWritingMode(t *testing.T) {
+	// A font whose encoding declares vertical writing mode advances its glyphs
+	// downwards; columns are read right to left
+	p := &testPDF{}
+	widths := strings.TrimSpace(strings.Repeat("500 ", 256))
+	font := p.add(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /Mincho /Encoding << /WMode 1 >> /FirstChar 0 /LastChar 255 /Widths [%s] >>", widths))
+	p.fonts = fmt.Sprintf("/V1 %d 0 R", font)
+	p.page("BT /V1 12 Tf 300 700 Td " + encodeText("Read") + " Tj ET\nBT /V1 12 Tf 284 700 Td " + encodeText("Down") + " Tj ET\n" +
+		showText(72, 500, "After.", "F1", 12))
+	md := convert(t, &Converter{AssetsDir: t.TempDir()}, p)
+	if want := "ReadDown"; !strings.Contains(md, want) {
+		t.Errorf("output lacks %q:\n%s", want, md)
+	}
+}
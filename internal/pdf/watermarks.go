@@ -0,0 +1,64 @@
+package pdf
+
+import (
+	"math"
+	"unicode"
+)
+
+const (
+	// watermarkAlpha is the fill opacity below which text reads as a watermark
+	watermarkAlpha = 0.6
+	// watermarkScale is how much larger than the body text a watermark is set
+	watermarkScale = 1.5
+	// watermarkMinLetters keeps short marks such as a slanted initial
+	watermarkMinLetters = 3
+)
+
+// isSlanted reports whether a text rendering matrix sets glyphs at a diagonal,
+// rather than along or across the page
+func isSlanted(trm matrix) bool {
+	angle := math.Abs(math.Mod(math.Atan2(trm[0][1], trm[0][0])*180/math.Pi, 90))
+	return angle > 10 && angle < 80
+}
+
+// stripWatermarks removes watermark text: runs of slanted or translucent
+// glyphs, drawn together, that spell at least a word and are set much larger
+// than the body text. Slanted or faded labels at the body size are kept, and
+// so is repeated text drawn like the rest of the page.
+func stripWatermarks(elements []TextElement) []TextElement {
+	var body []TextElement
+	for _, e := range elements {
+		if !e.Watermark {
+			body = append(body, e)
+		}
+	}
+	if len(body) == 0 {
+		body = elements
+	}
+	bodySize := bodyFontSize(body)
+
+	kept := elements[:0:0]
+	for i := 0; i < len(elements); {
+		if !elements[i].Watermark {
+			kept = append(kept, elements[i])
+			i++
+			continue
+		}
+		end := i
+		letters := 0
+		size := 0.0
+		for ; end < len(elements) && elements[end].Watermark; end++ {
+			for _, r := range elements[end].Text {
+				if unicode.IsLetter(r) {
+					letters++
+				}
+			}
+			size = math.Max(size, elements[end].Size)
+		}
+		if letters < watermarkMinLetters || size < bodySize*watermarkScale {
+			kept = append(kept, elements[i:end]...)
+		}
+		i = end
+	}
+	return kept
+}
@@ -0,0 +1,74 @@
+package pdf
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// slantedText returns the operators drawing s at 45 degrees from x, y
+func slantedText(x, y float64, s string, size float64) string {
+	return fmt.Sprintf("BT /F1 %g Tf 0.7071 0.7071 -0.7071 0.7071 %g %g Tm %s Tj ET\n", size, x, y, encodeText(s))
+}
+
+func TestStripWatermarks(t *testing.T) {
+	text := body("Quarterly figures are final.", "Revenue grew in every region.")
+	tests := []struct {
+		name      string
+		content   string
+		resources string
+		want      string
+		not       string
+		// flat watermarks read as text when they are kept
+		flat bool
+	}{
+		{
+			name:    "slanted",
+			content: slantedText(150, 250, "DRAFT", 72) + text,
+			not:     "DRAFT",
+		},
+		{
+			name:      "translucent",
+			content:   "q /GS1 gs " + showText(100, 400, "CONFIDENTIAL", "F2", 48) + "Q\n" + text,
+			resources: "/ExtGState << /GS1 << /ca 0.2 >> >>",
+			not:       "CONFIDENTIAL",
+			flat:      true,
+		},
+		{
+			name:    "watermark artifact",
+			content: "/Artifact << /Type /Pagination /Subtype /Watermark >> BDC\n" + showText(72, 100, "Internal use", "F1", 12) + "EMC\n" + text,
+			not:     "Internal use",
+			flat:    true,
+		},
+		{
+			name:      "faded label at body size",
+			content:   text + "q /GS1 gs " + showText(72, 600, "Approved by the board", "F1", 12) + "Q\n",
+			resources: "/ExtGState << /GS1 << /ca 0.5 >> >>",
+			want:      "Approved by the board",
+		},
+		{
+			name:    "slanted initial",
+			content: slantedText(150, 250, "A", 72) + text,
+			want:    "A",
+		},
+	}
+	for _, tt := range tests {
+		for _, strip := range []bool{false, true} {
+			p := &testPDF{}
+			p.pageWith(tt.content, "", tt.resources)
+			got := convert(t, &Converter{StripWatermarks: strip}, p)
+			if !strings.Contains(got, "Quarterly figures are final.") {
+				t.Errorf("%s, strip %v: output lacks the body:\n%s", tt.name, strip, got)
+			}
+			if tt.want != "" && !strings.Contains(got, tt.want) {
+				t.Errorf("%s, strip %v: output lacks %q:\n%s", tt.name, strip, tt.want, got)
+			}
+			if strip && tt.not != "" && strings.Contains(got, tt.not) {
+				t.Errorf("%s: output holds %q:\n%s", tt.name, tt.not, got)
+			}
+			if !strip && tt.flat && !strings.Contains(got, tt.not) {
+				t.Errorf("%s, kept: output lacks %q:\n%s", tt.name, tt.not, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,170 @@
+// Package plaintext converts plain text files to Markdown. The lines of a
+// paragraph are joined as the soft break mode selects; blocks that express an
+// outline through indentation alone become nested bullet lists.
+package plaintext
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+)
+
+// Converter converts plain text (.txt) files to Markdown
+type Converter struct {
+	// ListStyle selects the list item markers
+	ListStyle markdown.ListStyle
+	// SoftBreak selects how the line breaks inside a paragraph are rendered
+	SoftBreak markdown.SoftBreak
+}
+
+var (
+	// bulletLine matches lines that already carry a list marker
+	bulletLine = regexp.MustCompile(`^([-*+•]|\d+[.)])\s`)
+	// codeLine matches lines that read as source code rather than prose
+	codeLine = regexp.MustCompile(`[;{}]\s*$|^\s*(#include|import|package|def|func|return|class|var|let|const)\b|:=|==|!=|=>|->|\)\s*\{|^\s*(//|/\*|#!)`)
+)
+
+func (c *Converter) ToMarkdown(inputPath string, w io.Writer) error {
+	data, err := os.ReadFile(inputPath)
+	if err != nil {
+		return fmt.Errorf("failed to open text file: %v", err)
+	}
+
+	text := strings.TrimPrefix(string(data), "\uFEFF")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	var blocks []string
+	for _, block := range splitBlocks(text) {
+		if levels, ok := outlineLevels(block); ok {
+			blocks = append(blocks, c.renderOutline(block, levels))
+			continue
+		}
+		blocks = append(blocks, c.renderParagraph(block))
+	}
+
+	if _, err := io.WriteString(w, strings.Join(blocks, "\n\n")+"\n"); err != nil {
+		return fmt.Errorf("failed to write markdown: %v", err)
+	}
+	return nil
+}
+
+// splitBlocks splits text at blank lines, dropping trailing whitespace
+func splitBlocks(text string) [][]string {
+	var blocks [][]string
+	var block []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, " \t")
+		if line == "" {
+			if len(block) > 0 {
+				blocks = append(blocks, block)
+				block = nil
+			}
+			continue
+		}
+		block = append(block, line)
+	}
+	if len(block) > 0 {
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+// outlineLevels reports whether a block is an outline: prose lines without
+// list markers whose indentation grows one consistent step at a time, starting
+// at the margin and returning to a shallower level at least once below it.
+// The return separates an outline from a paragraph with a hanging indent.
+// It returns the nesting level of each line.
+func outlineLevels(block []string) ([]int, bool) {
+	if len(block) < 2 {
+		return nil, false
+	}
+
+	indents := make([]int, len(block))
+	tabs := false
+	step := 0
+	for i, line := range block {
+		text := strings.TrimLeft(line, " \t")
+		if bulletLine.MatchString(text) || codeLine.MatchString(text) || !isProse(text) {
+			return nil, false
+		}
+		prefix := line[:len(line)-len(text)]
+		if strings.Contains(prefix, "\t") {
+			if strings.Trim(prefix, "\t") != "" {
+				return nil, false // Mixed tabs and spaces
+			}
+			tabs = true
+		}
+		indents[i] = len(prefix)
+		if !strings.Contains(prefix, "\t") && indents[i] > 0 && (step == 0 || indents[i] < step) {
+			step = indents[i]
+		}
+	}
+	if tabs && step > 0 {
+		return nil, false
+	}
+	if tabs {
+		step = 1
+	}
+	if step == 0 || indents[0] != 0 {
+		return nil, false
+	}
+
+	levels := make([]int, len(block))
+	returned := false
+	for i, indent := range indents {
+		if indent%step != 0 {
+			return nil, false
+		}
+		levels[i] = indent / step
+		if i > 0 {
+			if levels[i] > levels[i-1]+1 {
+				return nil, false
+			}
+			if levels[i] < levels[i-1] {
+				returned = true
+			}
+		}
+	}
+	return levels, returned
+}
+
+// isProse reports whether a line is mostly letters and spaces
+func isProse(text string) bool {
+	letters, others := 0, 0
+	for _, r := range text {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsSpace(r):
+			letters++
+		case !unicode.IsDigit(r) && !strings.ContainsRune(`.,;:!?'"()-–—&/`, r):
+			others++
+		}
+	}
+	return letters > 0 && others*10 <= letters
+}
+
+// renderOutline renders the lines of an outline as a nested bullet list
+func (c *Converter) renderOutline(block []string, levels []int) string {
+	lines := make([]string, len(block))
+	for i, line := range block {
+		lines[i] = strings.Repeat("  ", levels[i]) + c.ListStyle.BulletMarker() + " " + strings.TrimLeft(line, " \t")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderParagraph joins the lines of a block of prose. Blocks holding code,
+// list items or indented lines are kept as written, since joining them would
+// change what they show.
+func (c *Converter) renderParagraph(block []string) string {
+	for _, line := range block {
+		text := strings.TrimLeft(line, " \t")
+		if text != line || bulletLine.MatchString(text) || codeLine.MatchString(text) {
+			return strings.Join(block, "\n")
+		}
+	}
+	return c.SoftBreak.Join(block)
+}
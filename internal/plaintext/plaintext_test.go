@@ -0,0 +1,88 @@
+package plaintext
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+)
+
+func TestOutlineListStyle(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outline.txt")
+	if err := os.WriteFile(path, []byte("Fruit\n  Apples\n    Green ones\nBread\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		style markdown.ListStyle
+		want  string
+	}{
+		{markdown.ListStyle{}, "- Fruit\n  - Apples\n    - Green ones\n- Bread\n"},
+		{markdown.ListStyle{Bullet: "+"}, "+ Fruit\n  + Apples\n    + Green ones\n+ Bread\n"},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		if err := (&Converter{ListStyle: tt.style}).ToMarkdown(path, &out); err != nil {
+			t.Fatalf("ToMarkdown() error = %v", err)
+		}
+		if out.String() != tt.want {
+			t.Errorf("%+v: got %q, want %q", tt.style, out.String(), tt.want)
+		}
+	}
+}
+
+// convertText converts plain text, failing the test on errors
+func convertText(t *testing.T, c *Converter, text string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "test.txt")
+	if err := os.WriteFile(path, []byte(text), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := c.ToMarkdown(path, &out); err != nil {
+		t.Fatalf("ToMarkdown() error = %v", err)
+	}
+	return out.String()
+}
+
+func TestOutline(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"tabs", "Planning\n\tBudget\n\t\tTravel costs\n\tSchedule\nDelivery\n", "- Planning\n  - Budget\n    - Travel costs\n  - Schedule\n- Delivery\n"},
+		{"spaces", "Planning\n    Budget\n        Travel\nDelivery\n", "- Planning\n  - Budget\n    - Travel\n- Delivery\n"},
+		{"windows line endings", "\uFEFFPlanning\r\n\tBudget\r\nDelivery\r\n", "- Planning\n  - Budget\n- Delivery\n"},
+		{"hanging indent", "A paragraph whose\n  lines hang below\n  the first one\n", "A paragraph whose\n  lines hang below\n  the first one\n"},
+		{"code", "func main() {\n\tfmt.Println(x)\n}\n", "func main() {\n\tfmt.Println(x)\n}\n"},
+		{"skipped level", "Planning\n\t\tTravel\nDelivery\n", "Planning\n\t\tTravel\nDelivery\n"},
+		{"mixed tabs and spaces", "Planning\n\tBudget\n  Travel\nDelivery\n", "Planning\n\tBudget\n  Travel\nDelivery\n"},
+		{"bullets", "- Planning\n  - Budget\n- Delivery\n", "- Planning\n  - Budget\n- Delivery\n"},
+		{"paragraphs", "First line\nsecond line\n\nNext paragraph\n", "First line second line\n\nNext paragraph\n"},
+	}
+	for _, tt := range tests {
+		if got := convertText(t, &Converter{}, tt.text); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSoftBreak(t *testing.T) {
+	text := "221B Baker Street\nLondon\nNW1 6XE\n"
+	tests := []struct {
+		mode markdown.SoftBreak
+		want string
+	}{
+		{"", "221B Baker Street London NW1 6XE\n"},
+		{markdown.SoftBreakSpace, "221B Baker Street London NW1 6XE\n"},
+		{markdown.SoftBreakHard, "221B Baker Street  \nLondon  \nNW1 6XE\n"},
+		{markdown.SoftBreakNewline, "221B Baker Street\nLondon\nNW1 6XE\n"},
+	}
+	for _, tt := range tests {
+		if got := convertText(t, &Converter{SoftBreak: tt.mode}, text); got != tt.want {
+			t.Errorf("%q: got %q, want %q", tt.mode, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package pptx
+
+import (
+	"archive/zip"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/errs"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+)
+
+// ReadMetadata reads the core properties of a presentation and counts its
+// slides
+func ReadMetadata(inputPath string) (markdown.Metadata, error) {
+	z, err := zip.OpenReader(inputPath)
+	if err != nil {
+		return markdown.Metadata{}, fmt.Errorf("%w: failed to open PPTX: %v", errs.ErrCorrupt, err)
+	}
+	defer z.Close()
+
+	p := &pkg{zip: z}
+	var meta markdown.Metadata
+	// The core properties are optional
+	if core, err := p.parse("docProps/core.xml"); err == nil {
+		if props := core.child("coreProperties"); props != nil {
+			for _, child := range props.Children {
+				text := strings.TrimSpace(child.text())
+				switch child.Name {
+				case "title":
+					meta.Title = text
+				case "creator":
+					meta.Author = text
+				case "subject":
+					meta.Subject = text
+				case "keywords":
+					meta.Keywords = markdown.ParseKeywords(text)
+				case "created":
+					meta.Created, _ = time.Parse(time.RFC3339, text)
+				case "modified":
+					meta.Modified, _ = time.Parse(time.RFC3339, text)
+				case "language":
+					meta.Language = text
+				}
+			}
+		}
+	}
+
+	slides, err := p.slidePaths()
+	if err != nil {
+		return markdown.Metadata{}, err
+	}
+	meta.Pages = len(slides)
+	return meta, nil
+}
@@ -0,0 +1,28 @@
+package pptx
+
+import (
+	"testing"
+)
+
+func TestReadMetadata(t *testing.T) {
+	core := `<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties"` +
+		` xmlns:dc="http://purl.org/dc/elements/1.1/"><dc:title> Roadmap </dc:title><dc:creator>Bo</dc:creator>` +
+		`<dc:language>de-DE</dc:language></cp:coreProperties>`
+	tests := []struct {
+		name  string
+		parts map[string]string
+		want  string
+	}{
+		{"core properties", map[string]string{"docProps/core.xml": core}, "Roadmap"},
+		{"no core properties", nil, ""},
+	}
+	for _, tt := range tests {
+		meta, err := ReadMetadata(writePptx(t, "", tt.parts))
+		if err != nil {
+			t.Fatalf("%s: ReadMetadata() error = %v", tt.name, err)
+		}
+		if meta.Title != tt.want || meta.Pages != 1 {
+			t.Errorf("%s: Title, Pages = %q, %d, want %q, 1", tt.name, meta.Title, meta.Pages, tt.want)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+// Package pptx converts PowerPoint presentations (.pptx) to Markdown, one
+// section per slide. Text frames become paragraphs and lists, tables become
+// GFM tables, charts the table of their data and SmartArt diagrams a nested
+// list of their text.
+package pptx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/errs"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/utils"
+)
+
+// Converter converts PowerPoint presentations to Markdown
+type Converter struct {
+	AssetsDir string
+	// AssetsLink is the path prefix used to reference assets from the Markdown,
+	// defaulting to AssetsDir
+	AssetsLink string
+	// AssetNamer names extracted assets, keeping positional names when nil
+	AssetNamer *utils.AssetNamer
+	// ListStyle selects the list item markers
+	ListStyle markdown.ListStyle
+	// OCR recognizes the text of pictures without a description and uses it as their alt text
+	OCR bool
+}
+
+// node is an element of a package part, or a piece of text when Name is empty
+type node struct {
+	Name     string
+	Attr     []xml.Attr
+	Text     string
+	Children []*node
+}
+
+// pkg is an open presentation package
+type pkg struct {
+	zip  *zip.ReadCloser
+	name string
+}
+
+func (c *Converter) ToMarkdown(inputPath string, w io.Writer) error {
+	z, err := zip.OpenReader(inputPath)
+	if err != nil {
+		return fmt.Errorf("%w: failed to open PPTX: %v", errs.ErrCorrupt, err)
+	}
+	defer z.Close()
+
+	p := &pkg{zip: z, name: strings.TrimSuffix(filepath.Base(inputPath), filepath.Ext(inputPath))}
+	slides, err := p.slidePaths()
+	if err != nil {
+		return err
+	}
+
+	var result strings.Builder
+	for i, slidePath := range slides {
+		section, err := c.renderSlide(p, slidePath, i+1)
+		if err != nil {
+			return err
+		}
+		result.WriteString(section)
+	}
+
+	if _, err := io.WriteString(w, strings.TrimSpace(result.String())+"\n"); err != nil {
+		return fmt.Errorf("failed to write markdown: %v", err)
+	}
+	return nil
+}
+
+// slidePaths returns the slide parts in presentation order
+func (p *pkg) slidePaths() ([]string, error) {
+	presentation, err := p.parse("ppt/presentation.xml")
+	if err != nil {
+		return nil, err
+	}
+	rels, err := p.relationships("ppt/presentation.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	var slides []string
+	if list := presentation.find("sldIdLst"); list != nil {
+		for _, id := range list.Children {
+			if target, ok := rels[id.relationship("id")]; ok && id.Name == "sldId" {
+				slides = append(slides, target)
+			}
+		}
+	}
+	return slides, nil
+}
+
+// relationships maps the relationship IDs of a part to their targets, which
+// are package paths for internal targets
+func (p *pkg) relationships(part string) (map[string]string, error) {
+	relsPath := path.Join(path.Dir(part), "_rels", path.Base(part)+".rels")
+	root, err := p.parse(relsPath)
+	if err != nil {
+		// Parts without relationships have no rels part
+		return map[string]string{}, nil
+	}
+
+	rels := make(map[string]string)
+	for _, rel := range root.findAll("Relationship") {
+		target := rel.attr("Target")
+		if rel.attr("TargetMode") != "External" {
+			if strings.HasPrefix(target, "/") {
+				target = strings.TrimPrefix(target, "/")
+			} else {
+				target = path.Join(path.Dir(part), target)
+			}
+		}
+		rels[rel.attr("Id")] = target
+	}
+	return rels, nil
+}
+
+// parse reads a package part into a tree of nodes
+func (p *pkg) parse(name string) (*node, error) {
+	f, err := p.open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	root := &node{}
+	stack := []*node{root}
+	d := xml.NewDecoder(f)
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to parse %s: %v", errs.ErrCorrupt, name, err)
+		}
+
+		parent := stack[len(stack)-1]
+		switch t := tok.(type) {
+		case xml.StartElement:
+			n := &node{Name: t.Name.Local, Attr: t.Attr}
+			parent.Children = append(parent.Children, n)
+			stack = append(stack, n)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			parent.Children = append(parent.Children, &node{Text: string(t)})
+		}
+	}
+	return root, nil
+}
+
+// open returns a reader for a package part
+func (p *pkg) open(name string) (io.ReadCloser, error) {
+	for _, f := range p.zip.File {
+		if f.Name == name {
+			r, err := f.Open()
+			if err != nil {
+				return nil, fmt.Errorf("%w: failed to open %s: %v", errs.ErrCorrupt, name, err)
+			}
+			return r, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: missing part %s", errs.ErrCorrupt, name)
+}
+
+// attr returns the value of an attribute by its local name
+func (n *node) attr(name string) string {
+	for _, a := range n.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// relationshipsNamespace is the namespace of attributes naming a relationship
+const relationshipsNamespace = "http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+
+// relationship returns the value of an attribute in the relationships
+// namespace, such as r:id, which elements may have next to a plain id
+func (n *node) relationship(name string) string {
+	for _, a := range n.Attr {
+		if a.Name.Local == name && a.Name.Space == relationshipsNamespace {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// child returns the first child with the given local name
+func (n *node) child(name string) *node {
+	for _, child := range n.Children {
+		if child.Name == name {
+			return child
+		}
+	}
+	return nil
+}
+
+// find returns the first descendant with the given local name
+func (n *node) find(name string) *node {
+	for _, child := range n.Children {
+		if child.Name == name {
+			return child
+		}
+		if found := child.find(name); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// findAll returns the descendants with the given local name, in document order
+func (n *node) findAll(name string) []*node {
+	var found []*node
+	for _, child := range n.Children {
+		if child.Name == name {
+			found = append(found, child)
+		}
+		found = append(found, child.findAll(name)...)
+	}
+	return found
+}
+
+// text returns the character data of a node and its descendants
+func (n *node) text() string {
+	if n.Name == "" {
+		return n.Text
+	}
+	var b strings.Builder
+	for _, child := range n.Children {
+		b.WriteString(child.text())
+	}
+	return b.String()
+}
@@ -0,0 +1,99 @@
+package pptx
+
+import (
+	"archive/zip"
+	"bytes"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const relsNS = `xmlns="http://schemas.openxmlformats.org/package/2006/relationships"`
+
+// writePptx writes a presentation of one slide holding shapes, given as the
+// elements inside <p:spTree>, and other parts by name
+func writePptx(t *testing.T, shapes string, parts map[string]string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	z := zip.NewWriter(&buf)
+	files := map[string]string{
+		"ppt/presentation.xml": `<?xml version="1.0" encoding="UTF-8"?>` +
+			`<p:presentation xmlns:p="http://schemas.openxmlformats.org/presentationml/2006/main"` +
+			` xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<p:sldIdLst><p:sldId id="256" r:id="rId1"/></p:sldIdLst></p:presentation>`,
+		"ppt/_rels/presentation.xml.rels": `<?xml version="1.0" encoding="UTF-8"?><Relationships ` + relsNS + `>` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/slide" Target="slides/slide1.xml"/></Relationships>`,
+		"ppt/slides/slide1.xml": `<?xml version="1.0" encoding="UTF-8"?>` +
+			`<p:sld xmlns:p="http://schemas.openxmlformats.org/presentationml/2006/main"` +
+			` xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main"` +
+			` xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<p:cSld><p:spTree>` + shapes + `</p:spTree></p:cSld></p:sld>`,
+	}
+	for name, data := range parts {
+		files[name] = data
+	}
+	for name, data := range files {
+		f, err := z.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(data))
+	}
+	if err := z.Close(); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "test.pptx")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// picture returns a picture shape with a name and description and the parts
+// holding its image
+func picture(t *testing.T, name, descr string) (string, map[string]string) {
+	t.Helper()
+	var img bytes.Buffer
+	if err := png.Encode(&img, image.NewGray(image.Rect(0, 0, 1, 1))); err != nil {
+		t.Fatal(err)
+	}
+	shape := `<p:pic><p:nvPicPr><p:cNvPr id="2" name="` + name + `" descr="` + descr + `"/></p:nvPicPr>` +
+		`<p:blipFill><a:blip r:embed="rIdImg"/></p:blipFill></p:pic>`
+	parts := map[string]string{
+		"ppt/slides/_rels/slide1.xml.rels": `<?xml version="1.0" encoding="UTF-8"?><Relationships ` + relsNS + `>` +
+			`<Relationship Id="rIdImg" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/image" Target="../media/image1.png"/></Relationships>`,
+		"ppt/media/image1.png": img.String(),
+	}
+	return shape, parts
+}
+
+func TestPictureAltText(t *testing.T) {
+	tests := []struct {
+		name      string
+		shapeName string
+		descr     string
+		ocr       bool
+		want      string
+	}{
+		{"description", "Picture 1", "A chart", false, "![A chart]("},
+		{"shape name", "Picture 1", "", false, "![Picture 1]("},
+		{"description over OCR", "Picture 1", "A chart", true, "![A chart]("},
+		{"OCR without tesseract", "Picture 1", "", true, "![Picture 1]("},
+	}
+	// Without tesseract on the path recognition finds no text
+	t.Setenv("PATH", "")
+	for _, tt := range tests {
+		shape, parts := picture(t, tt.shapeName, tt.descr)
+		c := &Converter{AssetsDir: t.TempDir(), OCR: tt.ocr}
+		var out bytes.Buffer
+		if err := c.ToMarkdown(writePptx(t, shape, parts), &out); err != nil {
+			t.Fatalf("%s: ToMarkdown() error = %v", tt.name, err)
+		}
+		if !strings.Contains(out.String(), tt.want) {
+			t.Errorf("%s: output = %q, want %q", tt.name, out.String(), tt.want)
+		}
+	}
+}
@@ -0,0 +1,457 @@
+package pptx
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/errs"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/ocr"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/smartart"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/utils"
+)
+
+// cellEscaper keeps cell text on one table row
+var cellEscaper = strings.NewReplacer("|", `\|`, "  \n", "<br>")
+
+// altEscaper escapes the brackets of recognized text used as image alt text
+var altEscaper = strings.NewReplacer("[", "\\[", "]", "\\]")
+
+// slide is the slide being rendered with the targets of its relationships
+type slide struct {
+	c    *Converter
+	p    *pkg
+	rels map[string]string
+}
+
+// renderSlide renders a slide as a section headed by its title placeholder,
+// or by its slide number when it has none. Speaker notes are left out.
+func (c *Converter) renderSlide(p *pkg, slidePath string, number int) (string, error) {
+	root, err := p.parse(slidePath)
+	if err != nil {
+		return "", err
+	}
+	rels, err := p.relationships(slidePath)
+	if err != nil {
+		return "", err
+	}
+
+	s := &slide{c: c, p: p, rels: rels}
+	title := ""
+	var blocks []string
+	if tree := root.find("spTree"); tree != nil {
+		if blocks, err = s.renderShapes(tree, &title); err != nil {
+			return "", err
+		}
+	}
+
+	if title == "" {
+		title = "Slide " + strconv.Itoa(number)
+	}
+	var result strings.Builder
+	result.WriteString("## " + title + "\n\n")
+	for _, block := range blocks {
+		result.WriteString(block + "\n\n")
+	}
+	return result.String(), nil
+}
+
+// renderShapes renders the shapes of a shape tree or group in their z-order,
+// taking the first title placeholder as the slide title
+func (s *slide) renderShapes(tree *node, title *string) ([]string, error) {
+	var blocks []string
+	for _, shape := range tree.Children {
+		switch shape.Name {
+		case "sp":
+			placeholder := ""
+			isPlaceholder := false
+			if nv := shape.find("nvPr"); nv != nil {
+				if ph := nv.child("ph"); ph != nil {
+					placeholder, isPlaceholder = ph.attr("type"), true
+				}
+			}
+			body := shape.child("txBody")
+			if body == nil {
+				continue
+			}
+			if (placeholder == "title" || placeholder == "ctrTitle") && *title == "" {
+				*title = strings.TrimSpace(strings.ReplaceAll(s.plainText(body), "\n", " "))
+				continue
+			}
+			// Body placeholders show bullets unless a paragraph turns them off
+			bullets := isPlaceholder && (placeholder == "" || placeholder == "body" || placeholder == "obj")
+			blocks = append(blocks, s.renderTextBody(body, bullets)...)
+		case "grpSp":
+			group, err := s.renderShapes(shape, title)
+			if err != nil {
+				return nil, err
+			}
+			blocks = append(blocks, group...)
+		case "graphicFrame":
+			data := shape.find("graphicData")
+			if data == nil {
+				continue
+			}
+			if table := data.child("tbl"); table != nil {
+				if text := s.renderTable(table); text != "" {
+					blocks = append(blocks, text)
+				}
+			} else if chart := data.find("chart"); chart != nil {
+				text, err := s.renderChart(chart.relationship("id"))
+				if err != nil {
+					return nil, err
+				}
+				if text != "" {
+					blocks = append(blocks, text)
+				}
+			} else if ids := data.find("relIds"); ids != nil {
+				if text := s.renderDiagram(ids.relationship("dm")); text != "" {
+					blocks = append(blocks, text)
+				}
+			}
+		case "pic":
+			ref, err := s.savePicture(shape)
+			if err != nil {
+				return nil, err
+			}
+			if ref != "" {
+				blocks = append(blocks, ref)
+			}
+		}
+	}
+	return blocks, nil
+}
+
+// renderTextBody renders the paragraphs of a text body. Paragraphs with a
+// bullet become list items nested by their level, and consecutive items are
+// returned as one block so the list stays together.
+func (s *slide) renderTextBody(body *node, bullets bool) []string {
+	var blocks, items []string
+	var counters [9]int
+	flush := func() {
+		if len(items) > 0 {
+			blocks = append(blocks, strings.Join(items, "\n"))
+			items = nil
+		}
+		counters = [9]int{}
+	}
+
+	for _, para := range body.Children {
+		if para.Name != "p" {
+			continue
+		}
+		text := strings.TrimSpace(s.inline(para))
+		if text == "" {
+			continue
+		}
+
+		level, bullet, ordered := 0, bullets, false
+		if props := para.child("pPr"); props != nil {
+			if lvl, err := strconv.Atoi(props.attr("lvl")); err == nil {
+				level = min(max(lvl, 0), len(counters)-1)
+			}
+			switch {
+			case props.child("buNone") != nil:
+				bullet = false
+			case props.child("buAutoNum") != nil:
+				bullet, ordered = true, true
+			case props.child("buChar") != nil:
+				bullet = true
+			}
+		}
+		if !bullet {
+			flush()
+			blocks = append(blocks, text)
+			continue
+		}
+
+		marker := s.c.ListStyle.BulletMarker()
+		if ordered {
+			counters[level]++
+			marker = s.c.ListStyle.OrderedMarker(counters[level])
+		}
+		for i := level + 1; i < len(counters); i++ {
+			counters[i] = 0
+		}
+		items = append(items, strings.Repeat("  ", level)+marker+" "+text)
+	}
+	flush()
+	return blocks
+}
+
+// inline renders the runs of a paragraph with their emphasis and links
+func (s *slide) inline(para *node) string {
+	var b strings.Builder
+	for _, child := range para.Children {
+		switch child.Name {
+		case "r", "fld":
+			t := child.child("t")
+			if t == nil {
+				continue
+			}
+			text := t.text()
+			if props := child.child("rPr"); props != nil {
+				if props.attr("i") == "1" {
+					text = markdown.Wrap(text, "*", "*")
+				}
+				if props.attr("b") == "1" {
+					text = markdown.Wrap(text, "**", "**")
+				}
+				if link := props.child("hlinkClick"); link != nil {
+					if target, ok := s.rels[link.relationship("id")]; ok && strings.Contains(target, "://") && strings.TrimSpace(text) != "" {
+						text = markdown.Wrap(text, "[", "]("+target+")")
+					}
+				}
+			}
+			b.WriteString(text)
+		case "br":
+			b.WriteString("  \n")
+		}
+	}
+	return b.String()
+}
+
+// plainText returns the text of a text body, one line per paragraph
+func (s *slide) plainText(body *node) string {
+	var lines []string
+	for _, para := range body.Children {
+		if para.Name != "p" {
+			continue
+		}
+		var line strings.Builder
+		for _, t := range para.findAll("t") {
+			line.WriteString(t.text())
+		}
+		lines = append(lines, line.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderTable renders an a:tbl as a GFM table whose first row is the header.
+// Cells covered by a merged cell are kept empty so the columns stay aligned.
+func (s *slide) renderTable(table *node) string {
+	var rows [][]string
+	for _, tr := range table.Children {
+		if tr.Name != "tr" {
+			continue
+		}
+		var row []string
+		for _, tc := range tr.Children {
+			if tc.Name != "tc" {
+				continue
+			}
+			text := ""
+			if body := tc.child("txBody"); body != nil && tc.attr("hMerge") != "1" && tc.attr("vMerge") != "1" {
+				var parts []string
+				for _, para := range body.Children {
+					if para.Name == "p" {
+						if t := strings.TrimSpace(s.inline(para)); t != "" {
+							parts = append(parts, t)
+						}
+					}
+				}
+				text = cellEscaper.Replace(strings.Join(parts, "<br>"))
+			}
+			row = append(row, text)
+		}
+		rows = append(rows, row)
+	}
+	return renderRows(rows)
+}
+
+// renderChart renders the data of a chart part as a table with a row per
+// category and a column per series, below the chart title
+func (s *slide) renderChart(id string) (string, error) {
+	target, ok := s.rels[id]
+	if !ok {
+		return "", nil
+	}
+	chart, err := s.p.parse(target)
+	if err != nil {
+		return "", nil // A missing chart part leaves nothing to render
+	}
+
+	header := []string{""}
+	var columns [][]string
+	var categories []string
+	for _, ser := range chart.findAll("ser") {
+		name := ""
+		if tx := ser.child("tx"); tx != nil {
+			name = strings.Join(values(tx), " ")
+		}
+		if name == "" {
+			name = "Series " + strconv.Itoa(len(columns)+1)
+		}
+		header = append(header, cellEscaper.Replace(name))
+
+		cat, val := ser.child("cat"), ser.child("val")
+		if cat == nil {
+			cat = ser.child("xVal")
+		}
+		if val == nil {
+			val = ser.child("yVal")
+		}
+		if cat != nil && len(categories) == 0 {
+			categories = points(cat)
+		}
+		if val != nil {
+			columns = append(columns, points(val))
+		} else {
+			columns = append(columns, nil)
+		}
+	}
+	if len(columns) == 0 {
+		return "", nil
+	}
+
+	count := len(categories)
+	for _, column := range columns {
+		count = max(count, len(column))
+	}
+	rows := [][]string{header}
+	for i := 0; i < count; i++ {
+		label := strconv.Itoa(i + 1)
+		if i < len(categories) {
+			label = categories[i]
+		}
+		row := []string{cellEscaper.Replace(label)}
+		for _, column := range columns {
+			value := ""
+			if i < len(column) {
+				value = column[i]
+			}
+			row = append(row, value)
+		}
+		rows = append(rows, row)
+	}
+
+	table := renderRows(rows)
+	if title := chart.find("title"); title != nil {
+		var text strings.Builder
+		for _, t := range title.findAll("t") {
+			text.WriteString(t.text())
+		}
+		if heading := strings.TrimSpace(text.String()); heading != "" {
+			table = "**" + heading + "**\n\n" + table
+		}
+	}
+	return table, nil
+}
+
+// renderDiagram renders the text of a SmartArt data part as a nested list
+func (s *slide) renderDiagram(id string) string {
+	target, ok := s.rels[id]
+	if !ok {
+		return ""
+	}
+	r, err := s.p.open(target)
+	if err != nil {
+		return ""
+	}
+	defer r.Close()
+
+	nodes, err := smartart.Parse(r)
+	if err != nil {
+		return "" // A diagram that cannot be read leaves nothing to render
+	}
+	return smartart.Render(nodes, s.c.ListStyle)
+}
+
+// points returns the cached values of a chart data reference by their index
+func points(ref *node) []string {
+	var values []string
+	for _, pt := range ref.findAll("pt") {
+		i, err := strconv.Atoi(pt.attr("idx"))
+		if err != nil || i < 0 || i > 10000 {
+			continue
+		}
+		for len(values) <= i {
+			values = append(values, "")
+		}
+		if v := pt.child("v"); v != nil {
+			values[i] = strings.TrimSpace(v.text())
+		}
+	}
+	return values
+}
+
+// values returns the cached or literal values of a chart text reference
+func values(ref *node) []string {
+	var texts []string
+	for _, v := range ref.findAll("v") {
+		if text := strings.TrimSpace(v.text()); text != "" {
+			texts = append(texts, text)
+		}
+	}
+	return texts
+}
+
+// renderRows renders rows as a GFM table padded to the widest row
+func renderRows(rows [][]string) string {
+	columns := 0
+	for _, row := range rows {
+		columns = max(columns, len(row))
+	}
+	if columns == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	for i, row := range rows {
+		for len(row) < columns {
+			row = append(row, "")
+		}
+		b.WriteString("| " + strings.Join(row, " | ") + " |\n")
+		if i == 0 {
+			b.WriteString("|" + strings.Repeat(" --- |", columns) + "\n")
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// savePicture copies the image of a picture shape into the assets directory
+// and returns its Markdown reference
+func (s *slide) savePicture(pic *node) (string, error) {
+	blip := pic.find("blip")
+	if blip == nil {
+		return "", nil
+	}
+	target, ok := s.rels[blip.relationship("embed")]
+	if !ok || strings.Contains(target, "://") {
+		return "", nil
+	}
+	r, err := s.p.open(target)
+	if err != nil {
+		return "", nil
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("%w: failed to read %s: %v", errs.ErrCorrupt, target, err)
+	}
+	c := s.c
+	name := c.AssetNamer.Name(fmt.Sprintf("%s-%s", s.p.name, path.Base(target)), data)
+	imagePath := filepath.Join(c.AssetsDir, name)
+	if err := os.WriteFile(imagePath, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write image: %v", err)
+	}
+
+	alt := ""
+	if props := pic.find("cNvPr"); props != nil {
+		alt = props.attr("descr")
+		// Scanned figures carry their text only as pixels
+		if alt == "" && c.OCR {
+			alt = altEscaper.Replace(ocr.Recognize(imagePath))
+		}
+		if alt == "" {
+			alt = props.attr("name")
+		}
+	}
+	return "![" + alt + "](" + utils.AssetLink(c.AssetsLink, c.AssetsDir, name) + ")", nil
+}
@@ -0,0 +1,159 @@
+package pptx
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// slideRels returns the relationships part of the test slide
+func slideRels(targets map[string]string) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?><Relationships ` + relsNS + `>`)
+	for id, target := range targets {
+		b.WriteString(`<Relationship Id="` + id + `" Target="` + target + `"/>`)
+	}
+	return b.String() + `</Relationships>`
+}
+
+// convertSlide converts a presentation of one slide, failing the test on errors
+func convertSlide(t *testing.T, shapes string, parts map[string]string) string {
+	t.Helper()
+	c := &Converter{AssetsDir: t.TempDir()}
+	var out bytes.Buffer
+	if err := c.ToMarkdown(writePptx(t, shapes, parts), &out); err != nil {
+		t.Fatalf("ToMarkdown() error = %v", err)
+	}
+	return out.String()
+}
+
+func TestTable(t *testing.T) {
+	cell := func(attrs string, paras ...string) string {
+		var b strings.Builder
+		for _, p := range paras {
+			b.WriteString(`<a:p><a:r><a:t>` + p + `</a:t></a:r></a:p>`)
+		}
+		return `<a:tc` + attrs + `><a:txBody>` + b.String() + `</a:txBody></a:tc>`
+	}
+	table := `<p:graphicFrame><a:graphic><a:graphicData><a:tbl>` +
+		`<a:tr>` + cell(` gridSpan="2"`, "Region") + cell(` hMerge="1"`, "hidden") + cell("", "Sales") + `</a:tr>` +
+		`<a:tr>` + cell("", "North") + cell("", "a|b") + cell("", "10", "rising") + `</a:tr>` +
+		`<a:tr>` + cell("", "South") + `</a:tr>` +
+		`</a:tbl></a:graphicData></a:graphic></p:graphicFrame>`
+	want := "## Slide 1\n\n| Region |  | Sales |\n| --- | --- | --- |\n| North | a\\|b | 10<br>rising |\n| South |  |  |\n"
+	if got := convertSlide(t, table, nil); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestChart(t *testing.T) {
+	frame := `<p:graphicFrame><a:graphic><a:graphicData><c:chart xmlns:c="http://schemas.openxmlformats.org/drawingml/2006/chart" r:id="rIdChart"/></a:graphicData></a:graphic></p:graphicFrame>`
+	series := func(name, values string) string {
+		return `<c:ser><c:tx><c:strRef><c:strCache><c:pt idx="0"><c:v>` + name + `</c:v></c:pt></c:strCache></c:strRef></c:tx>` +
+			`<c:cat><c:strRef><c:strCache><c:pt idx="0"><c:v>Q1</c:v></c:pt><c:pt idx="1"><c:v>Q2</c:v></c:pt></c:strCache></c:strRef></c:cat>` +
+			`<c:val><c:numRef><c:numCache>` + values + `</c:numCache></c:numRef></c:val></c:ser>`
+	}
+	chart := func(title, body string) string {
+		return `<c:chartSpace xmlns:c="http://schemas.openxmlformats.org/drawingml/2006/chart" xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main"><c:chart>` +
+			title + `<c:plotArea><c:barChart>` + body + `</c:barChart></c:plotArea></c:chart></c:chartSpace>`
+	}
+	rels := slideRels(map[string]string{"rIdChart": "../charts/chart1.xml"})
+	tests := []struct {
+		name  string
+		chart string
+		want  string
+	}{
+		{
+			name: "two series",
+			chart: chart(`<c:title><c:tx><c:rich><a:p><a:r><a:t>Revenue</a:t></a:r></a:p></c:rich></c:tx></c:title>`,
+				series("2023", `<c:pt idx="0"><c:v>5</c:v></c:pt><c:pt idx="1"><c:v>7</c:v></c:pt>`)+
+					series("2024", `<c:pt idx="1"><c:v>9</c:v></c:pt>`)),
+			want: "## Slide 1\n\n**Revenue**\n\n|  | 2023 | 2024 |\n| --- | --- | --- |\n| Q1 | 5 |  |\n| Q2 | 7 | 9 |\n",
+		},
+		{
+			name:  "unnamed series without categories",
+			chart: chart("", `<c:ser><c:val><c:numRef><c:numCache><c:pt idx="0"><c:v>3</c:v></c:pt></c:numCache></c:numRef></c:val></c:ser>`),
+			want:  "## Slide 1\n\n|  | Series 1 |\n| --- | --- |\n| 1 | 3 |\n",
+		},
+		{
+			name:  "no series",
+			chart: chart("", ""),
+			want:  "## Slide 1\n",
+		},
+		{
+			name: "missing chart part",
+			want: "## Slide 1\n",
+		},
+	}
+	for _, tt := range tests {
+		parts := map[string]string{"ppt/slides/_rels/slide1.xml.rels": rels}
+		if tt.chart != "" {
+			parts["ppt/charts/chart1.xml"] = tt.chart
+		}
+		if got := convertSlide(t, frame, parts); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSlideText(t *testing.T) {
+	para := func(props, runs string) string { return `<a:p>` + props + runs + `</a:p>` }
+	run := func(props, text string) string { return `<a:r>` + props + `<a:t>` + text + `</a:t></a:r>` }
+	shape := func(placeholder string, paras ...string) string {
+		return `<p:sp><p:nvSpPr><p:nvPr>` + placeholder + `</p:nvPr></p:nvSpPr><p:txBody>` + strings.Join(paras, "") + `</p:txBody></p:sp>`
+	}
+	shapes := shape(`<p:ph type="title"/>`, para("", run("", "Agenda"))) +
+		`<p:grpSp>` + shape(`<p:ph idx="1"/>`,
+		para("", run(`<a:rPr b="1"/>`, "Goals")),
+		para(`<a:pPr lvl="1"/>`, run(`<a:rPr i="1"/>`, "Growth")),
+		para(`<a:pPr lvl="1"><a:buAutoNum type="arabicPeriod"/></a:pPr>`, run("", "First")),
+		para(`<a:pPr lvl="1"><a:buAutoNum type="arabicPeriod"/></a:pPr>`, run("", "Second")),
+		para(`<a:pPr><a:buNone/></a:pPr>`, run(`<a:rPr><a:hlinkClick r:id="rIdLink"/></a:rPr>`, "Details")),
+	) + `</p:grpSp>` +
+		shape("", para("", run("", "Footnote"))+para("", ""))
+	parts := map[string]string{
+		"ppt/slides/_rels/slide1.xml.rels": `<?xml version="1.0" encoding="UTF-8"?><Relationships ` + relsNS + `>` +
+			`<Relationship Id="rIdLink" Target="https://example.com/plan" TargetMode="External"/></Relationships>`,
+	}
+	want := "## Agenda\n\n- **Goals**\n  - *Growth*\n  1. First\n  2. Second\n\n[Details](https://example.com/plan)\n\nFootnote\n"
+	if got := convertSlide(t, shapes, parts); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestSmartArt(t *testing.T) {
+	frame := `<p:graphicFrame><a:graphic><a:graphicData>` +
+		`<dgm:relIds xmlns:dgm="http://schemas.openxmlformats.org/drawingml/2006/diagram" r:dm="rIdDm"/>` +
+		`</a:graphicData></a:graphic></p:graphicFrame>`
+	data := `<dgm:dataModel xmlns:dgm="http://schemas.openxmlformats.org/drawingml/2006/diagram" xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main"><dgm:ptLst>` +
+		`<dgm:pt modelId="0" type="doc"/>` +
+		`<dgm:pt modelId="1"><dgm:t><a:p><a:r><a:t>Research</a:t></a:r></a:p></dgm:t></dgm:pt>` +
+		`<dgm:pt modelId="2"><dgm:t><a:p><a:r><a:t>Interviews</a:t></a:r></a:p></dgm:t></dgm:pt>` +
+		`<dgm:pt modelId="3"><dgm:t><a:p><a:r><a:t>Design</a:t></a:r></a:p></dgm:t></dgm:pt>` +
+		`</dgm:ptLst><dgm:cxnLst><dgm:cxn srcId="0" destId="1" srcOrd="0"/><dgm:cxn srcId="0" destId="3" srcOrd="1"/>` +
+		`<dgm:cxn srcId="1" destId="2" srcOrd="0"/></dgm:cxnLst></dgm:dataModel>`
+	tests := []struct {
+		name  string
+		parts map[string]string
+		want  string
+	}{
+		{
+			name: "list",
+			parts: map[string]string{
+				"ppt/slides/_rels/slide1.xml.rels": slideRels(map[string]string{"rIdDm": "../diagrams/data1.xml"}),
+				"ppt/diagrams/data1.xml":           data,
+			},
+			want: "## Slide 1\n\n- Research\n  - Interviews\n- Design\n",
+		},
+		{
+			name:  "missing data part",
+			parts: map[string]string{"ppt/slides/_rels/slide1.xml.rels": slideRels(map[string]string{"rIdDm": "../diagrams/data1.xml"})},
+			want:  "## Slide 1\n",
+		},
+	}
+	for _, tt := range tests {
+		if got := convertSlide(t, frame, tt.parts); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// Package preview converts zip containers that bundle a PDF rendering of
+// their document, such as the QuickLook preview of iWork packages, by
+// converting that PDF. A format whose own content is not read is supported
+// by naming the entries its preview may be stored in.
+package preview
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/errs"
+)
+
+// IWork names the entries holding the preview of Pages, Keynote and Numbers
+// packages
+var IWork = []string{"QuickLook/Preview.pdf", "preview.pdf"}
+
+// PDFConverter converts a PDF held in memory
+type PDFConverter interface {
+	ToMarkdownFromReader(r io.ReaderAt, size int64, name string, w io.Writer) error
+}
+
+// Converter converts a container by the PDF preview it bundles
+type Converter struct {
+	// Entries names the entries that may hold the preview, tried in order and
+	// matched regardless of case
+	Entries []string
+	// PDF converts the preview
+	PDF PDFConverter
+}
+
+// New returns a converter for containers keeping their preview in one of
+// entries, converted by conv
+func New(conv PDFConverter, entries ...string) *Converter {
+	return &Converter{Entries: entries, PDF: conv}
+}
+
+func (c *Converter) ToMarkdown(inputPath string, w io.Writer) error {
+	f, err := os.Open(inputPath)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %v", err)
+	}
+	defer f.Close()
+
+	fileInfo, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to get file info: %v", err)
+	}
+	return c.ToMarkdownFromReader(f, fileInfo.Size(), inputPath, w)
+}
+
+// ToMarkdownFromReader converts a container of size bytes read from r. The
+// name stands in for the file name where the output refers to its source.
+func (c *Converter) ToMarkdownFromReader(r io.ReaderAt, size int64, name string, w io.Writer) error {
+	z, err := zip.NewReader(r, size)
+	if err != nil {
+		return fmt.Errorf("%w: failed to open package: %v", errs.ErrCorrupt, err)
+	}
+	data, err := Extract(z, c.Entries)
+	if err != nil {
+		return err
+	}
+	return c.PDF.ToMarkdownFromReader(bytes.NewReader(data), int64(len(data)), name, w)
+}
+
+// Extract reads the first of entries a zip archive holds, failing with
+// errs.ErrUnsupportedType when it holds none of them
+func Extract(z *zip.Reader, entries []string) ([]byte, error) {
+	for _, entry := range entries {
+		for _, f := range z.File {
+			if !strings.EqualFold(f.Name, entry) {
+				continue
+			}
+			r, err := f.Open()
+			if err != nil {
+				return nil, fmt.Errorf("%w: failed to open %s: %v", errs.ErrCorrupt, f.Name, err)
+			}
+			data, err := io.ReadAll(r)
+			r.Close()
+			if err != nil {
+				return nil, fmt.Errorf("%w: failed to read %s: %v", errs.ErrCorrupt, f.Name, err)
+			}
+			return data, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: package has no PDF preview", errs.ErrUnsupportedType)
+}
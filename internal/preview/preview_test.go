@@ -0,0 +1,86 @@
+package preview
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/errs"
+)
+
+// fakePDF writes the size and start of the PDF it is given and the name
+type fakePDF struct{}
+
+func (fakePDF) ToMarkdownFromReader(r io.ReaderAt, size int64, name string, w io.Writer) error {
+	data := make([]byte, size)
+	if _, err := r.ReadAt(data, 0); err != nil && err != io.EOF {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "%s from %s", data, filepath.Base(name))
+	return err
+}
+
+// writeZip writes a zip archive of entries to a file of a name
+func writeZip(t *testing.T, name string, entries map[string]string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	z := zip.NewWriter(&buf)
+	for entry, data := range entries {
+		f, err := z.Create(entry)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(data))
+	}
+	if err := z.Close(); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestToMarkdown(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		entries []string
+		zip     map[string]string
+		want    string
+		wantErr error
+	}{
+		{"pages package", "report.pages", IWork, map[string]string{"Index/Document.iwa": "...", "QuickLook/Preview.pdf": "%PDF pages"}, "%PDF pages from report.pages", nil},
+		{"older keynote package", "talk.key", IWork, map[string]string{"index.apxl": "...", "preview.pdf": "%PDF keynote"}, "%PDF keynote from talk.key", nil},
+		{"entry case", "sheet.numbers", IWork, map[string]string{"quicklook/preview.PDF": "%PDF numbers"}, "%PDF numbers from sheet.numbers", nil},
+		{"entries in order", "report.pages", IWork, map[string]string{"preview.pdf": "%PDF second", "QuickLook/Preview.pdf": "%PDF first"}, "%PDF first from report.pages", nil},
+		{"other container", "drawing.vnd", []string{"Previews/render.pdf"}, map[string]string{"content.xml": "<x/>", "Previews/render.pdf": "%PDF drawing"}, "%PDF drawing from drawing.vnd", nil},
+		{"no preview", "report.pages", IWork, map[string]string{"Index/Document.iwa": "..."}, "", errs.ErrUnsupportedType},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		err := New(fakePDF{}, tt.entries...).ToMarkdown(writeZip(t, tt.file, tt.zip), &out)
+		if !errors.Is(err, tt.wantErr) || out.String() != tt.want {
+			t.Errorf("%s: got %q, %v, want %q, %v", tt.name, out.String(), err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestToMarkdownNotZip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.pages")
+	if err := os.WriteFile(path, []byte("not a zip"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := New(fakePDF{}, IWork...).ToMarkdown(path, io.Discard); !errors.Is(err, errs.ErrCorrupt) {
+		t.Errorf("ToMarkdown() error = %v, want %v", err, errs.ErrCorrupt)
+	}
+	if err := New(fakePDF{}, IWork...).ToMarkdown(filepath.Join(t.TempDir(), "missing.pages"), io.Discard); err == nil {
+		t.Error("ToMarkdown(missing.pages) error = nil, want an error")
+	}
+}
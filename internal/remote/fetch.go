@@ -0,0 +1,185 @@
+package remote
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/email"
+)
+
+const (
+	// DefaultUserAgent identifies the converter to remote servers
+	DefaultUserAgent = "doc2md"
+	defaultTimeout   = 60 * time.Second
+	defaultMaxSize   = 100 << 20
+	defaultRetries   = 3
+	retryDelay       = time.Second
+)
+
+// contentTypes maps the media types of supported documents to file extensions
+var contentTypes = map[string]string{
+	"application/pdf": ".pdf",
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document":   ".docx",
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet":         ".xlsx",
+	"application/vnd.openxmlformats-officedocument.presentationml.presentation": ".pptx",
+	"message/rfc822":             ".eml",
+	"application/vnd.ms-outlook": ".msg",
+}
+
+// Fetcher downloads remote inputs to temporary files
+type Fetcher struct {
+	UserAgent string
+	// Timeout bounds a single download attempt, including redirects
+	Timeout time.Duration
+	// MaxSize is the largest accepted response body in bytes
+	MaxSize int64
+	// Retries is the number of attempts made for transient failures
+	Retries int
+	Client  *http.Client
+}
+
+// IsURL reports whether an input refers to an http(s) resource
+func IsURL(input string) bool {
+	return strings.HasPrefix(input, "http://") || strings.HasPrefix(input, "https://")
+}
+
+// FileName returns the name of the document a URL refers to, without any query
+func FileName(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || path.Base(u.Path) == "/" || path.Base(u.Path) == "." {
+		return "download"
+	}
+	return path.Base(u.Path)
+}
+
+// Fetch downloads a URL into a temporary directory and returns the local path,
+// whose extension reflects the detected document type. cleanup removes the
+// downloaded file and must be called once the file is no longer needed.
+func (f *Fetcher) Fetch(rawURL string) (localPath string, cleanup func(), err error) {
+	retries := f.Retries
+	if retries <= 0 {
+		retries = defaultRetries
+	}
+
+	var data []byte
+	var contentType string
+	for attempt := 1; ; attempt++ {
+		var retryable bool
+		data, contentType, retryable, err = f.get(rawURL)
+		if err == nil || !retryable || attempt >= retries {
+			break
+		}
+		time.Sleep(retryDelay * time.Duration(attempt))
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "doc2md-")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temporary directory: %v", err)
+	}
+	cleanup = func() { os.RemoveAll(dir) }
+
+	name := FileName(rawURL)
+	if ext := detectExtension(data, contentType); ext != "" && !strings.EqualFold(filepath.Ext(name), ext) {
+		name = strings.TrimSuffix(name, filepath.Ext(name)) + ext
+	}
+
+	localPath = filepath.Join(dir, name)
+	if err := os.WriteFile(localPath, data, 0644); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("failed to save download: %v", err)
+	}
+
+	return localPath, cleanup, nil
+}
+
+// get performs a single download attempt. Network errors and server errors are
+// reported as retryable.
+func (f *Fetcher) get(rawURL string) ([]byte, string, bool, error) {
+	client := f.Client
+	if client == nil {
+		timeout := f.Timeout
+		if timeout <= 0 {
+			timeout = defaultTimeout
+		}
+		client = &http.Client{Timeout: timeout}
+	}
+
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, "", false, fmt.Errorf("invalid URL %s: %v", rawURL, err)
+	}
+	userAgent := f.UserAgent
+	if userAgent == "" {
+		userAgent = DefaultUserAgent
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", true, fmt.Errorf("failed to download %s: %v", rawURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		retryable := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+		return nil, "", retryable, fmt.Errorf("failed to download %s: %s", rawURL, resp.Status)
+	}
+
+	maxSize := f.MaxSize
+	if maxSize <= 0 {
+		maxSize = defaultMaxSize
+	}
+	if resp.ContentLength > maxSize {
+		return nil, "", false, fmt.Errorf("download %s exceeds the %d byte limit", rawURL, maxSize)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, "", true, fmt.Errorf("failed to download %s: %v", rawURL, err)
+	}
+	if int64(len(data)) > maxSize {
+		return nil, "", false, fmt.Errorf("download %s exceeds the %d byte limit", rawURL, maxSize)
+	}
+
+	return data, resp.Header.Get("Content-Type"), false, nil
+}
+
+// detectExtension identifies a document by its leading bytes, falling back to
+// the Content-Type reported by the server
+func detectExtension(data []byte, contentType string) string {
+	switch {
+	case bytes.HasPrefix(data, []byte("%PDF-")):
+		return ".pdf"
+	case email.IsMSG(data):
+		// Compound files are also .doc, .xls and .ppt documents, which the
+		// URL or the Content-Type tell apart
+		return ".msg"
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")):
+		// Office Open XML packages are told apart by their main part
+		switch {
+		case bytes.Contains(data, []byte("word/")):
+			return ".docx"
+		case bytes.Contains(data, []byte("xl/")):
+			return ".xlsx"
+		case bytes.Contains(data, []byte("ppt/")):
+			return ".pptx"
+		}
+	}
+
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		return contentTypes[mediaType]
+	}
+	return ""
+}
@@ -0,0 +1,214 @@
+package remote
+
+import (
+	"encoding/binary"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"unicode/utf16"
+)
+
+// compoundFile builds a compound file of 512-byte sectors whose root storage
+// holds empty streams of the given names: the header, a FAT sector and a
+// directory sector
+func compoundFile(streams ...string) []byte {
+	const (
+		endOfChain = 0xfffffffe
+		freeSector = 0xffffffff
+		fatSector  = 0xfffffffd
+		noStream   = 0xffffffff
+	)
+	data := make([]byte, 3*512)
+	le := binary.LittleEndian
+	copy(data, "\xd0\xcf\x11\xe0\xa1\xb1\x1a\xe1")
+	le.PutUint16(data[30:], 9)
+	le.PutUint32(data[48:], 1)
+	le.PutUint32(data[56:], 4096)
+	le.PutUint32(data[60:], endOfChain)
+	le.PutUint32(data[68:], endOfChain)
+	for i := 0; i < 109; i++ {
+		le.PutUint32(data[76+4*i:], freeSector)
+	}
+	le.PutUint32(data[76:], 0)
+
+	fat := data[512:1024]
+	for i := 0; i < 128; i++ {
+		le.PutUint32(fat[4*i:], freeSector)
+	}
+	le.PutUint32(fat[0:], fatSector)
+	le.PutUint32(fat[4:], endOfChain)
+
+	entry := func(i int, name string, typ byte, right, child uint32) {
+		b := data[1024+128*i : 1024+128*(i+1)]
+		units := utf16.Encode([]rune(name))
+		for j, u := range units {
+			le.PutUint16(b[2*j:], u)
+		}
+		le.PutUint16(b[64:], uint16(2*len(units)+2))
+		b[66] = typ
+		le.PutUint32(b[68:], noStream)
+		le.PutUint32(b[72:], right)
+		le.PutUint32(b[76:], child)
+		le.PutUint32(b[116:], endOfChain)
+	}
+	child := uint32(noStream)
+	if len(streams) > 0 {
+		child = 1
+	}
+	entry(0, "Root Entry", 5, noStream, child)
+	// Chain the streams through their right siblings
+	for i, name := range streams {
+		right := uint32(noStream)
+		if i+1 < len(streams) {
+			right = uint32(i + 2)
+		}
+		entry(i+1, name, 2, right, noStream)
+	}
+	return data
+}
+
+func TestDetectExtension(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        []byte
+		contentType string
+		want        string
+	}{
+		{"pdf", []byte("%PDF-1.7\n"), "", ".pdf"},
+		{"docx", []byte("PK\x03\x04....word/document.xml"), "", ".docx"},
+		{"xlsx", []byte("PK\x03\x04....xl/workbook.xml"), "", ".xlsx"},
+		{"pptx", []byte("PK\x03\x04....ppt/presentation.xml"), "", ".pptx"},
+		{"msg", compoundFile("__properties_version1.0", "__substg1.0_0037001F"), "", ".msg"},
+		{"msg substg only", compoundFile("__substg1.0_1000001F"), "application/octet-stream", ".msg"},
+		{"doc", compoundFile("WordDocument", "1Table"), "", ""},
+		{"xls by content type", compoundFile("Workbook"), "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", ".xlsx"},
+		{"outlook content type", []byte("unknown"), "application/vnd.ms-outlook", ".msg"},
+		{"truncated compound file", []byte("\xd0\xcf\x11\xe0\xa1\xb1\x1a\xe1"), "", ""},
+		{"content type parameters", []byte("From: a@example.com"), "message/rfc822; charset=utf-8", ".eml"},
+		{"unknown", []byte("hello"), "text/plain", ""},
+	}
+	for _, tt := range tests {
+		if got := detectExtension(tt.data, tt.contentType); got != tt.want {
+			t.Errorf("%s: detectExtension() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFetchKeepsExtension(t *testing.T) {
+	tests := []struct {
+		path string
+		data []byte
+		want string
+	}{
+		{"/report.doc", compoundFile("WordDocument"), "report.doc"},
+		{"/mail", compoundFile("__substg1.0_0037001F"), "mail.msg"},
+		{"/mail.doc", compoundFile("__substg1.0_0037001F"), "mail.msg"},
+		{"/paper", []byte("%PDF-1.7\n"), "paper.pdf"},
+	}
+	for _, tt := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.Write(tt.data)
+		}))
+		f := &Fetcher{}
+		localPath, cleanup, err := f.Fetch(server.URL + tt.path)
+		server.Close()
+		if err != nil {
+			t.Fatalf("Fetch(%s) error = %v", tt.path, err)
+		}
+		cleanup()
+		if got := filepath.Base(localPath); got != tt.want {
+			t.Errorf("Fetch(%s) saved %s, want %s", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestFetch(t *testing.T) {
+	var attempts int
+	var userAgent string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/paper.pdf", func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.UserAgent()
+		w.Write([]byte("%PDF-1.7\n"))
+	})
+	mux.HandleFunc("/moved", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/paper.pdf", http.StatusFound)
+	})
+	mux.HandleFunc("/flaky.pdf", func(w http.ResponseWriter, _ *http.Request) {
+		if attempts++; attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("%PDF-1.7\n"))
+	})
+	mux.HandleFunc("/missing.pdf", func(w http.ResponseWriter, _ *http.Request) {
+		attempts++
+		http.NotFound(w, nil)
+	})
+	mux.HandleFunc("/big.pdf", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write(make([]byte, 64))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	tests := []struct {
+		path         string
+		fetcher      Fetcher
+		wantName     string
+		wantAttempts int
+		wantErr      bool
+	}{
+		{path: "/paper.pdf", wantName: "paper.pdf"},
+		{path: "/moved", wantName: "moved.pdf"},
+		{path: "/flaky.pdf", fetcher: Fetcher{Retries: 2}, wantName: "flaky.pdf", wantAttempts: 2},
+		{path: "/missing.pdf", wantAttempts: 1, wantErr: true},
+		{path: "/big.pdf", fetcher: Fetcher{MaxSize: 32}, wantErr: true},
+	}
+	for _, tt := range tests {
+		attempts = 0
+		localPath, cleanup, err := tt.fetcher.Fetch(server.URL + tt.path)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Fetch(%s) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+		}
+		if err == nil {
+			cleanup()
+			if got := filepath.Base(localPath); got != tt.wantName {
+				t.Errorf("Fetch(%s) saved %s, want %s", tt.path, got, tt.wantName)
+			}
+			if _, err := os.Stat(localPath); !os.IsNotExist(err) {
+				t.Errorf("Fetch(%s): cleanup left %s", tt.path, localPath)
+			}
+		}
+		if tt.wantAttempts > 0 && attempts != tt.wantAttempts {
+			t.Errorf("Fetch(%s) made %d attempts, want %d", tt.path, attempts, tt.wantAttempts)
+		}
+	}
+
+	f := &Fetcher{UserAgent: "test-agent"}
+	_, cleanup, err := f.Fetch(server.URL + "/paper.pdf")
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	cleanup()
+	if userAgent != "test-agent" {
+		t.Errorf("User-Agent = %q, want test-agent", userAgent)
+	}
+}
+
+func TestFileName(t *testing.T) {
+	tests := []struct {
+		url, want string
+	}{
+		{"https://example.com/docs/report.pdf?version=2", "report.pdf"},
+		{"https://example.com/", "download"},
+		{"https://example.com", "download"},
+		{"http://example.com/a/b", "b"},
+	}
+	for _, tt := range tests {
+		if got := FileName(tt.url); got != tt.want {
+			t.Errorf("FileName(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+// Package smartart reads the text of SmartArt diagrams, which Office packages
+// keep in a diagramData part apart from the drawing that shows them.
+package smartart
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/errs"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+)
+
+// Node is a point of a diagram with the points below it in the hierarchy
+type Node struct {
+	Text     string
+	Children []*Node
+}
+
+// dataModel is the dgm:dataModel root of a diagramData part
+type dataModel struct {
+	Points []struct {
+		ID         string `xml:"modelId,attr"`
+		Type       string `xml:"type,attr"`
+		Paragraphs []struct {
+			Runs []string `xml:"r>t"`
+		} `xml:"t>p"`
+	} `xml:"ptLst>pt"`
+	Connections []struct {
+		Type  string `xml:"type,attr"`
+		Src   string `xml:"srcId,attr"`
+		Dest  string `xml:"destId,attr"`
+		Order int    `xml:"srcOrd,attr"`
+	} `xml:"cxnLst>cxn"`
+}
+
+// Parse reads a diagramData part and returns the top-level points of the
+// diagram. Points are linked to their parent by parOf connections, in the
+// order of the connection; transitions and presentation points are left out.
+func Parse(r io.Reader) ([]*Node, error) {
+	var model dataModel
+	if err := xml.NewDecoder(r).Decode(&model); err != nil {
+		return nil, fmt.Errorf("%w: failed to parse SmartArt data: %v", errs.ErrCorrupt, err)
+	}
+
+	nodes := make(map[string]*Node)
+	root := ""
+	for _, pt := range model.Points {
+		switch pt.Type {
+		case "", "node", "asst":
+		case "doc":
+			root = pt.ID
+		default:
+			continue
+		}
+		var lines []string
+		for _, para := range pt.Paragraphs {
+			if text := strings.TrimSpace(strings.Join(para.Runs, "")); text != "" {
+				lines = append(lines, text)
+			}
+		}
+		nodes[pt.ID] = &Node{Text: strings.Join(lines, " ")}
+	}
+
+	connections := model.Connections
+	sort.SliceStable(connections, func(i, j int) bool { return connections[i].Order < connections[j].Order })
+	hasParent := make(map[string]bool)
+	for _, cxn := range connections {
+		if cxn.Type != "" && cxn.Type != "parOf" {
+			continue
+		}
+		parent, child := nodes[cxn.Src], nodes[cxn.Dest]
+		if parent == nil || child == nil || hasParent[cxn.Dest] || cxn.Dest == root {
+			continue
+		}
+		hasParent[cxn.Dest] = true
+		parent.Children = append(parent.Children, child)
+	}
+
+	if doc, ok := nodes[root]; ok {
+		return doc.Children, nil
+	}
+	// Without a document point the diagram is a forest of its parentless points
+	var top []*Node
+	for _, pt := range model.Points {
+		if n, ok := nodes[pt.ID]; ok && !hasParent[pt.ID] {
+			top = append(top, n)
+		}
+	}
+	return top, nil
+}
+
+// Render renders diagram points as a bullet list nested by their depth.
+// Points without text are left out and their children lifted to their level.
+func Render(nodes []*Node, style markdown.ListStyle) string {
+	var lines []string
+	seen := make(map[*Node]bool)
+	var walk func(nodes []*Node, depth int)
+	walk = func(nodes []*Node, depth int) {
+		for _, n := range nodes {
+			if seen[n] {
+				continue
+			}
+			seen[n] = true
+			if n.Text == "" {
+				walk(n.Children, depth)
+				continue
+			}
+			lines = append(lines, strings.Repeat("  ", depth)+style.BulletMarker()+" "+n.Text)
+			walk(n.Children, depth+1)
+		}
+	}
+	walk(nodes, 0)
+	return strings.Join(lines, "\n")
+}
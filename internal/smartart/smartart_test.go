@@ -0,0 +1,88 @@
+package smartart
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/errs"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+)
+
+// point returns a diagram point of a type with text
+func point(id, typ, text string) string {
+	return `<dgm:pt modelId="` + id + `" type="` + typ + `"><dgm:t><a:p><a:r><a:t>` + text + `</a:t></a:r></a:p></dgm:t></dgm:pt>`
+}
+
+// connection returns a connection of a type from src to dest
+func connection(typ, src, dest, order string) string {
+	return `<dgm:cxn modelId="c` + dest + `" type="` + typ + `" srcId="` + src + `" destId="` + dest + `" srcOrd="` + order + `"/>`
+}
+
+// dataPart returns a diagramData part holding points and connections
+func dataPart(points, connections string) string {
+	return `<dgm:dataModel xmlns:dgm="http://schemas.openxmlformats.org/drawingml/2006/diagram" xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main">` +
+		`<dgm:ptLst>` + points + `</dgm:ptLst><dgm:cxnLst>` + connections + `</dgm:cxnLst></dgm:dataModel>`
+}
+
+func TestParseRender(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  string
+		style markdown.ListStyle
+		want  string
+	}{
+		{
+			name: "hierarchy in connection order",
+			data: dataPart(
+				point("0", "doc", "")+point("1", "node", "Plan")+point("2", "node", "Build")+point("3", "node", "Scope")+
+					point("4", "node", "Budget")+point("5", "parTrans", "ignored")+point("6", "pres", "ignored"),
+				connection("parOf", "0", "2", "1")+connection("", "0", "1", "0")+connection("parOf", "1", "4", "1")+
+					connection("parOf", "1", "3", "0")+connection("presOf", "1", "6", "0"),
+			),
+			want: "- Plan\n  - Scope\n  - Budget\n- Build",
+		},
+		{
+			name:  "list style",
+			data:  dataPart(point("0", "doc", "")+point("1", "node", "Plan"), connection("parOf", "0", "1", "0")),
+			style: markdown.ListStyle{Bullet: "*"},
+			want:  "* Plan",
+		},
+		{
+			name: "points without text lifted",
+			data: dataPart(
+				point("0", "doc", "")+point("1", "node", "")+point("2", "asst", "Helper"),
+				connection("parOf", "0", "1", "0")+connection("parOf", "1", "2", "0"),
+			),
+			want: "- Helper",
+		},
+		{
+			name: "without a document point",
+			data: dataPart(point("1", "", "One")+point("2", "", "Two")+point("3", "", "Child"), connection("parOf", "1", "3", "0")),
+			want: "- One\n  - Child\n- Two",
+		},
+		{
+			name: "second parent ignored",
+			data: dataPart(
+				point("0", "doc", "")+point("1", "node", "A")+point("2", "node", "B")+point("3", "node", "Shared"),
+				connection("parOf", "0", "1", "0")+connection("parOf", "0", "2", "1")+connection("parOf", "1", "3", "0")+connection("parOf", "2", "3", "0"),
+			),
+			want: "- A\n  - Shared\n- B",
+		},
+	}
+	for _, tt := range tests {
+		nodes, err := Parse(strings.NewReader(tt.data))
+		if err != nil {
+			t.Fatalf("%s: Parse() error = %v", tt.name, err)
+		}
+		if got := Render(nodes, tt.style); got != tt.want {
+			t.Errorf("%s: Render() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseCorrupt(t *testing.T) {
+	if _, err := Parse(strings.NewReader("<dgm:dataModel>")); !errors.Is(err, errs.ErrCorrupt) {
+		t.Errorf("Parse() error = %v, want ErrCorrupt", err)
+	}
+}
@@ -0,0 +1,96 @@
+package transform
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Ways of handling characters ASCII has no transliteration for
+const (
+	// UnmappedReplace writes a question mark in their place
+	UnmappedReplace = "replace"
+	// UnmappedDrop leaves them out
+	UnmappedDrop = "drop"
+)
+
+// asciiSymbols transliterates the characters that do not decompose into ASCII
+// letters, such as ligatures written as one letter, punctuation and symbols
+var asciiSymbols = map[rune]string{
+	'“': `"`, '”': `"`, '„': `"`, '‟': `"`, '«': `"`, '»': `"`,
+	'‘': "'", '’': "'", '‚': "'", '‛': "'", '‹': "'", '›': "'", '′': "'", '″': `"`,
+	'—': "--", '–': "-", '‐': "-", '‑': "-", '‒': "-", '−': "-", '⁄': "/",
+	'•': "*", '·': "*", '…': "...", '¡': "!", '¿': "?",
+	'ß': "ss", 'ẞ': "SS", 'æ': "ae", 'Æ': "AE", 'œ': "oe", 'Œ': "OE", 'ø': "o", 'Ø': "O",
+	'đ': "d", 'Đ': "D", 'ð': "d", 'Ð': "D", 'ł': "l", 'Ł': "L", 'þ': "th", 'Þ': "Th", 'ı': "i",
+	'©': "(c)", '®': "(R)", '™': "(TM)", '×': "x", '÷': "/", '±': "+/-",
+	'≤': "<=", '≥': ">=", '≠': "!=", '→': "->", '←': "<-", '⇒': "=>",
+	'€': "EUR", '£': "GBP", '¥': "JPY", '¢': "c",
+}
+
+// ParseUnmapped validates the handling of untransliterable characters. An
+// empty name selects UnmappedReplace.
+func ParseUnmapped(name string) (string, error) {
+	switch name {
+	case "", UnmappedReplace:
+		return UnmappedReplace, nil
+	case UnmappedDrop:
+		return UnmappedDrop, nil
+	}
+	return "", fmt.Errorf("unsupported unmapped character handling: %s (expected replace or drop)", name)
+}
+
+// ASCII returns a pass transliterating the Markdown to ASCII. Accented letters
+// lose their accents, as in "café" to "cafe", compatibility forms such as
+// ligatures and superscripts decompose, and typographic punctuation becomes
+// its plain form. Characters with no equivalent are replaced with "?" or
+// dropped, as unmapped selects.
+func ASCII(unmapped string) func(md string) string {
+	fallback := "?"
+	if unmapped == UnmappedDrop {
+		fallback = ""
+	}
+	return func(md string) string {
+		var b strings.Builder
+		for _, r := range md {
+			if r <= unicode.MaxASCII {
+				b.WriteRune(r)
+				continue
+			}
+			b.WriteString(transliterate(r, fallback))
+		}
+		return b.String()
+	}
+}
+
+// transliterate returns the ASCII form of a non-ASCII character
+func transliterate(r rune, fallback string) string {
+	if s, ok := asciiSymbols[r]; ok {
+		return s
+	}
+	if unicode.IsSpace(r) {
+		return " "
+	}
+
+	var b strings.Builder
+	for _, d := range norm.NFKD.String(string(r)) {
+		switch {
+		case d <= unicode.MaxASCII:
+			b.WriteRune(d)
+		case unicode.Is(unicode.Mn, d):
+			// Accents and other combining marks
+		case asciiSymbols[d] != "":
+			b.WriteString(asciiSymbols[d])
+		case unicode.IsSpace(d):
+			b.WriteString(" ")
+		default:
+			return fallback
+		}
+	}
+	if b.Len() == 0 && !unicode.Is(unicode.Mn, r) {
+		return fallback
+	}
+	return b.String()
+}
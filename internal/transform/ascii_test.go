@@ -0,0 +1,47 @@
+package transform
+
+import "testing"
+
+func TestASCII(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       string
+		unmapped string
+		want     string
+	}{
+		{"accents", "Café crème, Ångström, naïve", UnmappedReplace, "Cafe creme, Angstrom, naive"},
+		{"curly quotes", "“Quoted” and ‘single’ it’s", UnmappedReplace, `"Quoted" and 'single' it's`},
+		{"dashes and ellipsis", "2020–2021 — done…", UnmappedReplace, "2020-2021 -- done..."},
+		{"letters without decomposition", "Straße, Œuvre, Łódź, smørrebrød", UnmappedReplace, "Strasse, OEuvre, Lodz, smorrebrod"},
+		{"compatibility forms", "ﬁne x² ½", UnmappedReplace, "fine x2 1/2"},
+		{"symbols", "© 2024 ™ 5 × 3 ≤ 20 € → next", UnmappedReplace, "(c) 2024 (TM) 5 x 3 <= 20 EUR -> next"},
+		{"spaces", "a b c", UnmappedReplace, "a b c"},
+		{"unmapped replaced", "Emoji 😀 and 漢字", UnmappedReplace, "Emoji ? and ??"},
+		{"unmapped dropped", "Emoji 😀 and 漢字", UnmappedDrop, "Emoji  and "},
+		{"Markdown kept", "# Título\n\n- [é](https://example.com/é)\n", UnmappedReplace, "# Titulo\n\n- [e](https://example.com/e)\n"},
+	}
+	for _, tt := range tests {
+		if got := ASCII(tt.unmapped)(tt.in); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseUnmapped(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{"", UnmappedReplace, false},
+		{"replace", UnmappedReplace, false},
+		{"drop", UnmappedDrop, false},
+		{"keep", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseUnmapped(tt.name)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("ParseUnmapped(%q) = %q, %v, want %q, wantErr %v", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
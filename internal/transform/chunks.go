@@ -0,0 +1,74 @@
+package transform
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+)
+
+// charsPerToken approximates the length of a token for English prose
+const charsPerToken = 4
+
+// Chunk units accepted by Chunk
+const (
+	ChunkChars  = "chars"
+	ChunkTokens = "tokens"
+)
+
+// Chunk splits Markdown into pieces of at most size characters, or estimated
+// tokens when unit is ChunkTokens. Splits only happen between blocks, so a
+// paragraph, table or fenced code block is never cut; a single block longer
+// than size becomes a chunk of its own.
+func Chunk(md string, size int, unit string) []string {
+	if size <= 0 {
+		return []string{md}
+	}
+	length := func(s string) int {
+		n := utf8.RuneCountInString(s)
+		if unit == ChunkTokens {
+			return (n + charsPerToken - 1) / charsPerToken
+		}
+		return n
+	}
+
+	var chunks []string
+	var current strings.Builder
+	for _, block := range splitBlocks(md) {
+		if current.Len() > 0 && length(current.String()+"\n\n"+block+"\n") > size {
+			chunks = append(chunks, current.String()+"\n")
+			current.Reset()
+		}
+		if current.Len() > 0 {
+			current.WriteString("\n\n")
+		}
+		current.WriteString(block)
+	}
+	if current.Len() > 0 {
+		chunks = append(chunks, current.String()+"\n")
+	}
+	return chunks
+}
+
+// splitBlocks splits Markdown at blank lines outside fenced code blocks
+func splitBlocks(md string) []string {
+	var blocks []string
+	var block []string
+	var fence markdown.CodeFence
+	flush := func() {
+		if len(block) > 0 {
+			blocks = append(blocks, strings.Join(block, "\n"))
+			block = nil
+		}
+	}
+
+	for _, line := range strings.Split(md, "\n") {
+		if !fence.Line(line) && strings.TrimSpace(line) == "" {
+			flush()
+			continue
+		}
+		block = append(block, line)
+	}
+	flush()
+	return blocks
+}
@@ -0,0 +1,49 @@
+package transform
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestChunk(t *testing.T) {
+	tests := []struct {
+		name string
+		md   string
+		size int
+		unit string
+		want []string
+	}{
+		{"no limit", "# A\n\nText", 0, ChunkChars, []string{"# A\n\nText"}},
+		{"fits", "# A\n\nText\n", 20, ChunkChars, []string{"# A\n\nText\n"}},
+		{"paragraphs", "First one.\n\nSecond one.\n\nThird one.\n", 24, ChunkChars, []string{"First one.\n\nSecond one.\n", "Third one.\n"}},
+		{"final newline counts", "First one.\n\nSecond one.\n\nThird one.\n", 23, ChunkChars, []string{"First one.\n", "Second one.\n", "Third one.\n"}},
+		{"long block alone", "Short.\n\n" + strings.Repeat("x", 30) + "\n\nEnd.", 10, ChunkChars, []string{"Short.\n", strings.Repeat("x", 30) + "\n", "End.\n"}},
+		{"code block kept whole", "Intro.\n\n```\na\n\nb\n```\n\nAfter.", 12, ChunkChars, []string{"Intro.\n", "```\na\n\nb\n```\n", "After.\n"}},
+		{"table kept whole", "| A |\n| --- |\n| 1 |\n\nNext.", 10, ChunkChars, []string{"| A |\n| --- |\n| 1 |\n", "Next.\n"}},
+		{"tokens", "First one.\n\nSecond one.\n\nThird one.\n", 6, ChunkTokens, []string{"First one.\n\nSecond one.\n", "Third one.\n"}},
+		{"runes", "ééééé\n\nééééé", 13, ChunkChars, []string{"ééééé\n\nééééé\n"}},
+	}
+	for _, tt := range tests {
+		got := Chunk(tt.md, tt.size, tt.unit)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Chunk() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestChunkSizeBound(t *testing.T) {
+	var blocks []string
+	for i := 1; i <= 40; i++ {
+		blocks = append(blocks, strings.Repeat("word ", i%7+1))
+	}
+	md := strings.Join(blocks, "\n\n")
+	for _, size := range []int{8, 20, 50, 100} {
+		for _, chunk := range Chunk(md, size, ChunkChars) {
+			if n := utf8.RuneCountInString(chunk); n > size && strings.Contains(strings.TrimSpace(chunk), "\n\n") {
+				t.Errorf("size %d: chunk of %d characters holds several blocks: %q", size, n, chunk)
+			}
+		}
+	}
+}
@@ -0,0 +1,345 @@
+package transform
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+)
+
+// maxHeadingLevel is the deepest ATX heading Markdown supports
+const maxHeadingLevel = 6
+
+var atxHeading = regexp.MustCompile(`^(#{1,6})([ \t].*)?$`)
+
+// headingNumber matches the number a heading starts with: a number followed
+// by a dot, such as "2.", a multi-level one, such as "1.3" or "1.3.2.", or a
+// roman numeral of two letters or more followed by a dot, such as "IV.". A
+// bare number, as in "2024 Results", and a lone letter, as in "C. Smith", are
+// title text.
+var headingNumber = regexp.MustCompile(`^(?:\d+(?:\.\d+)+\.?|\d+\.|([IVXLCDM]{2,})\.)\s+`)
+
+// romanNumeral matches a well-formed roman numeral
+var romanNumeral = regexp.MustCompile(`^M{0,3}(?:CM|CD|D?C{0,3})(?:XC|XL|L?X{0,3})(?:IX|IV|V?I{0,3})$`)
+
+// stripHeadingNumber removes the number a heading's text starts with
+func stripHeadingNumber(text string) string {
+	m := headingNumber.FindStringSubmatch(text)
+	if m == nil || m[1] != "" && !romanNumeral.MatchString(m[1]) {
+		return text
+	}
+	return text[len(m[0]):]
+}
+
+// ShiftHeadings moves every ATX heading down by offset levels, so that an H1
+// becomes an H(1+offset). Levels are clamped to the H1–H6 range and headings
+// inside fenced code blocks are left untouched.
+func ShiftHeadings(md string, offset int) string {
+	if offset == 0 {
+		return md
+	}
+
+	lines := strings.Split(md, "\n")
+	var fence markdown.CodeFence
+	for i, line := range lines {
+		if fence.Line(line) {
+			continue
+		}
+
+		m := atxHeading.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		level := len(m[1]) + offset
+		if level < 1 {
+			level = 1
+		}
+		if level > maxHeadingLevel {
+			level = maxHeadingLevel
+		}
+		lines[i] = strings.Repeat("#", level) + m[2]
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// FirstHeadingAsTitle makes the first ATX heading the only H1 and moves the
+// headings after it so that the shallowest of them is an H2, keeping their
+// levels relative to each other; none goes past H6. Front matter without a
+// title takes the heading's text as its title. Fenced code blocks are left
+// untouched.
+func FirstHeadingAsTitle(md string) string {
+	fm, content := markdown.ParseFrontMatter(md)
+	frontMatter := strings.TrimSuffix(md, content)
+
+	lines := strings.Split(content, "\n")
+	var headings []int
+	var fence markdown.CodeFence
+	for i, line := range lines {
+		if fence.Line(line) {
+			continue
+		}
+		if atxHeading.MatchString(line) {
+			headings = append(headings, i)
+		}
+	}
+	if len(headings) == 0 {
+		return md
+	}
+
+	shallowest := maxHeadingLevel
+	for _, i := range headings[1:] {
+		shallowest = min(shallowest, len(atxHeading.FindStringSubmatch(lines[i])[1]))
+	}
+	title := ""
+	for n, i := range headings {
+		m := atxHeading.FindStringSubmatch(lines[i])
+		level := 1
+		if n > 0 {
+			level = min(len(m[1])-shallowest+2, maxHeadingLevel)
+		} else {
+			// A closing sequence of #s is not part of the heading text
+			title = strings.TrimSpace(strings.TrimRight(strings.TrimSpace(m[2]), "#"))
+		}
+		lines[i] = strings.Repeat("#", level) + m[2]
+	}
+
+	if frontMatter != "" && fm.Title == "" && title != "" {
+		fm.Title = title
+		frontMatter = fm.String()
+	}
+	return frontMatter + strings.Join(lines, "\n")
+}
+
+// Outline keeps only the ATX headings of md, outside fenced code, one block
+// each, as the outline of the document. Front matter is left in place.
+func Outline(md string) string {
+	content := StripFrontMatter(md)
+	frontMatter := strings.TrimSuffix(md, content)
+
+	var headings []string
+	var fence markdown.CodeFence
+	for _, line := range strings.Split(content, "\n") {
+		if !fence.Line(line) && atxHeading.MatchString(line) {
+			headings = append(headings, strings.TrimRight(line, " \t"))
+		}
+	}
+	if len(headings) == 0 {
+		return frontMatter
+	}
+	return frontMatter + strings.Join(headings, "\n\n") + "\n"
+}
+
+// FlattenHeadings renders every ATX heading deeper than keep as a bold
+// paragraph, so keep 0 flattens all headings. A heading has no blank lines
+// around it to separate it from the text before and after, which a bold
+// paragraph needs, so they are added. Fenced code blocks are left untouched.
+func FlattenHeadings(md string, keep int) string {
+	lines := strings.Split(md, "\n")
+	out := make([]string, 0, len(lines))
+	var fence markdown.CodeFence
+	for i, line := range lines {
+		if fence.Line(line) {
+			out = append(out, line)
+			continue
+		}
+
+		m := atxHeading.FindStringSubmatch(line)
+		if m == nil || len(m[1]) <= keep {
+			out = append(out, line)
+			continue
+		}
+		// A closing sequence of #s is not part of the heading text
+		text := strings.TrimSpace(strings.TrimRight(strings.TrimSpace(m[2]), "#"))
+		if text == "" {
+			out = append(out, "")
+			continue
+		}
+		if n := len(out); n > 0 && strings.TrimSpace(out[n-1]) != "" {
+			out = append(out, "")
+		}
+		out = append(out, "**"+text+"**")
+		if i+1 < len(lines) && strings.TrimSpace(lines[i+1]) != "" {
+			out = append(out, "")
+		}
+	}
+
+	return strings.Join(out, "\n")
+}
+
+// NumberHeadings numbers the ATX headings by their place in the hierarchy, as
+// in "## 1. Intro" and "### 1.1 Scope", replacing the numbers they were written
+// with. A lone heading at the top level is the document title and stays
+// unnumbered, numbering starting at the level below it. A skipped level counts
+// as its first section. Fenced code blocks are left untouched.
+func NumberHeadings(md string) string {
+	lines := strings.Split(md, "\n")
+	headings := make(map[int]int)
+	var fence markdown.CodeFence
+	for i, line := range lines {
+		if fence.Line(line) {
+			continue
+		}
+		if m := atxHeading.FindStringSubmatch(line); m != nil && strings.TrimSpace(m[2]) != "" {
+			headings[i] = len(m[1])
+		}
+	}
+	if len(headings) == 0 {
+		return md
+	}
+
+	top, atTop := maxHeadingLevel, 0
+	for _, level := range headings {
+		if level < top {
+			top, atTop = level, 0
+		}
+		if level == top {
+			atTop++
+		}
+	}
+	if atTop == 1 {
+		top++
+	}
+
+	var counters [maxHeadingLevel]int
+	for i, line := range lines {
+		level, ok := headings[i]
+		if !ok || level < top {
+			continue
+		}
+		depth := level - top
+		for j := 0; j < depth; j++ {
+			if counters[j] == 0 {
+				counters[j] = 1
+			}
+		}
+		counters[depth]++
+		for j := depth + 1; j < len(counters); j++ {
+			counters[j] = 0
+		}
+
+		parts := make([]string, depth+1)
+		for j := range parts {
+			parts[j] = strconv.Itoa(counters[j])
+		}
+		number := strings.Join(parts, ".")
+		if depth == 0 {
+			number += "."
+		}
+		text := stripHeadingNumber(strings.TrimSpace(line[level:]))
+		lines[i] = line[:level] + " " + number + " " + text
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// titleStopwords are the short function words Title Case keeps lowercase
+// unless they start or end the heading
+var titleStopwords = map[string]bool{
+	"a": true, "an": true, "the": true, "and": true, "but": true, "or": true, "nor": true,
+	"for": true, "so": true, "yet": true, "as": true, "at": true, "by": true, "in": true,
+	"of": true, "off": true, "on": true, "per": true, "to": true, "up": true, "via": true,
+	"vs": true, "from": true, "into": true, "onto": true, "with": true,
+}
+
+// TitleCaseHeadings capitalizes the words of every ATX heading except the
+// stopwords within it, as in "## The Art of War". Fenced code blocks are left
+// untouched.
+func TitleCaseHeadings(md string) string {
+	return caseHeadings(md, true)
+}
+
+// SentenceCaseHeadings lowercases the words of every ATX heading but the
+// first, as in "## The art of war". Fenced code blocks are left untouched.
+func SentenceCaseHeadings(md string) string {
+	return caseHeadings(md, false)
+}
+
+// caseHeadings recases the heading text word by word. Words with a capital
+// past their first letter, such as acronyms and names like "iPhone", keep
+// their case, as do code spans and link targets.
+func caseHeadings(md string, title bool) string {
+	lines := strings.Split(md, "\n")
+	var fence markdown.CodeFence
+	for i, line := range lines {
+		if fence.Line(line) {
+			continue
+		}
+
+		m := atxHeading.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		words := strings.Split(m[2], " ")
+		first, last := -1, -1
+		for j, word := range words {
+			if strings.IndexFunc(word, unicode.IsLetter) >= 0 {
+				if first < 0 {
+					first = j
+				}
+				last = j
+			}
+		}
+		for j, word := range words {
+			if j < first || j > last {
+				continue
+			}
+			upper := j == first
+			if title {
+				core := strings.ToLower(strings.TrimFunc(word, func(r rune) bool { return !unicode.IsLetter(r) }))
+				afterColon := j > first && strings.HasSuffix(words[j-1], ":")
+				upper = upper || j == last || afterColon || !titleStopwords[core]
+			}
+			parts := strings.Split(word, "-")
+			for k, part := range parts {
+				parts[k] = caseWord(part, upper && (k == 0 || title))
+			}
+			words[j] = strings.Join(parts, "-")
+		}
+		lines[i] = m[1] + strings.Join(words, " ")
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// caseWord sets the case of the first letter of a word, which is otherwise lowercase
+func caseWord(word string, upper bool) string {
+	if strings.Contains(word, "`") || strings.Contains(word, "](") || strings.Contains(word, "://") {
+		return word
+	}
+	start := strings.IndexFunc(word, unicode.IsLetter)
+	if start < 0 {
+		return word
+	}
+	first, size := utf8.DecodeRuneInString(word[start:])
+	rest := word[start+size:]
+	// Acronyms and mixed-case names keep their case, like the pronoun I
+	if strings.IndexFunc(rest, unicode.IsUpper) >= 0 {
+		return word
+	}
+	if first == 'I' && strings.IndexFunc(rest, unicode.IsLetter) != 0 {
+		return word
+	}
+	if upper {
+		first = unicode.ToUpper(first)
+	} else {
+		first = unicode.ToLower(first)
+	}
+	return word[:start] + string(first) + rest
+}
+
+// StripFrontMatter removes a leading YAML front matter block and the blank
+// lines that follow it
+func StripFrontMatter(md string) string {
+	if !strings.HasPrefix(md, "---\n") {
+		return md
+	}
+	end := strings.Index(md[4:], "\n---\n")
+	if end < 0 {
+		return md
+	}
+	return strings.TrimLeft(md[4+end+5:], "\n")
+}
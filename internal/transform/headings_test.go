@@ -0,0 +1,146 @@
+package transform
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+)
+
+func TestFirstHeadingAsTitle(t *testing.T) {
+	tests := []struct {
+		name string
+		md   string
+		want string
+		// h1s is the number of H1s the output must have
+		h1s int
+	}{
+		{"first heading promoted", "## Intro\n\nText\n\n### Detail\n\n## Usage\n", "# Intro\n\nText\n\n### Detail\n\n## Usage\n", 1},
+		{"other H1s demoted", "# Guide\n\n# Setup\n\n## Flags\n", "# Guide\n\n## Setup\n\n### Flags\n", 1},
+		{"levels capped at H6", "# Guide\n\n# Setup\n\n###### Deep\n", "# Guide\n\n## Setup\n\n###### Deep\n", 1},
+		{"front matter title from the heading", "---\nauthor: Ana\n---\n\n## Report ##\n\nText\n", "---\ntitle: Report\nauthor: Ana\n---\n\n# Report ##\n\nText\n", 1},
+		{"front matter title kept", "---\ntitle: Given\n---\n\n## Other\n", "---\ntitle: Given\n---\n\n# Other\n", 1},
+		{"fenced code", "```\n# comment\n```\n\n## Real\n", "```\n# comment\n```\n\n# Real\n", 1},
+		{"no headings", "Text\n", "Text\n", 0},
+	}
+	for _, tt := range tests {
+		got := FirstHeadingAsTitle(tt.md)
+		if got != tt.want {
+			t.Errorf("%s: FirstHeadingAsTitle(%q) = %q, want %q", tt.name, tt.md, got, tt.want)
+		}
+		var fence markdown.CodeFence
+		h1s := 0
+		for _, line := range strings.Split(got, "\n") {
+			if !fence.Line(line) && (line == "#" || strings.HasPrefix(line, "# ")) {
+				h1s++
+			}
+		}
+		if h1s != tt.h1s {
+			t.Errorf("%s: %d H1s in %q, want %d", tt.name, h1s, got, tt.h1s)
+		}
+	}
+}
+
+func TestOutline(t *testing.T) {
+	tests := []struct {
+		name string
+		md   string
+		want string
+	}{
+		{"headings kept", "# Title\n\nText\n\n## Part  \n- item\n\n### Detail\nMore\n", "# Title\n\n## Part\n\n### Detail\n"},
+		{"fenced code", "# Title\n\n```sh\n# comment\n```\n\n~~~\n## not a heading\n~~~\n", "# Title\n"},
+		{"front matter", "---\ntitle: Report\n---\n\n# Report\n\nText\n", "---\ntitle: Report\n---\n\n# Report\n"},
+		{"not headings", "#hashtag\n    # indented code\n", ""},
+		{"already an outline", "# Title\n\n## Part\n", "# Title\n\n## Part\n"},
+	}
+	for _, tt := range tests {
+		if got := Outline(tt.md); got != tt.want {
+			t.Errorf("%s: Outline(%q) = %q, want %q", tt.name, tt.md, got, tt.want)
+		}
+	}
+}
+
+func TestFlattenHeadings(t *testing.T) {
+	tests := []struct {
+		name string
+		md   string
+		keep int
+		want string
+	}{
+		{"all", "# Title\n\nText\n\n## Part\n", 0, "**Title**\n\nText\n\n**Part**\n"},
+		{"deeper than keep", "# Title\n\n## Part\n\n### Detail\n", 1, "# Title\n\n**Part**\n\n**Detail**\n"},
+		{"blank lines added", "Intro\n## Part\nText", 0, "Intro\n\n**Part**\n\nText"},
+		{"closing hashes", "## Part ##\n", 0, "**Part**\n"},
+		{"empty heading", "Text\n\n##\n\nMore", 0, "Text\n\n\n\nMore"},
+		{"fenced code", "```\n# comment\n```\n", 0, "```\n# comment\n```\n"},
+		{"not a heading", "#hashtag\n", 0, "#hashtag\n"},
+	}
+	for _, tt := range tests {
+		if got := FlattenHeadings(tt.md, tt.keep); got != tt.want {
+			t.Errorf("%s: FlattenHeadings(%q, %d) = %q, want %q", tt.name, tt.md, tt.keep, got, tt.want)
+		}
+	}
+}
+
+func TestNumberHeadings(t *testing.T) {
+	tests := []struct {
+		name string
+		md   string
+		want string
+	}{
+		{
+			"three levels",
+			"# Guide\n\n## Intro\n\n### Scope\n\n#### Limits\n\n### Terms\n\n## Usage\n",
+			"# Guide\n\n## 1. Intro\n\n### 1.1 Scope\n\n#### 1.1.1 Limits\n\n### 1.2 Terms\n\n## 2. Usage\n",
+		},
+		{
+			"source numbering replaced",
+			"## 3. Intro\n\n### 2.7 Scope\n\n## IV. Usage\n\n### 4.1. Setup\n",
+			"## 1. Intro\n\n### 1.1 Scope\n\n## 2. Usage\n\n### 2.1 Setup\n",
+		},
+		{
+			"title text kept",
+			"## 2024 Results\n\n## 3 Ways to Win\n\n## C. Smith Biography\n\n## DIM. Lights\n\n## XII. Annex\n",
+			"## 1. 2024 Results\n\n## 2. 3 Ways to Win\n\n## 3. C. Smith Biography\n\n## 4. DIM. Lights\n\n## 5. Annex\n",
+		},
+		{"several at the top", "# One\n\n# Two\n", "# 1. One\n\n# 2. Two\n"},
+		{"skipped level", "# Title\n\n### Detail\n\n## Usage\n", "# Title\n\n### 1.1 Detail\n\n## 2. Usage\n"},
+		{"lone title", "# Title\n\nText\n", "# Title\n\nText\n"},
+		{"fenced code", "## Intro\n\n```\n## not a heading\n```\n\n## Usage\n", "## 1. Intro\n\n```\n## not a heading\n```\n\n## 2. Usage\n"},
+		{"no headings", "Just text\n", "Just text\n"},
+	}
+	for _, tt := range tests {
+		if got := NumberHeadings(tt.md); got != tt.want {
+			t.Errorf("%s: NumberHeadings() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCaseHeadings(t *testing.T) {
+	tests := []struct {
+		name     string
+		md       string
+		title    string
+		sentence string
+	}{
+		{"stopwords", "## the art of war", "## The Art of War", "## The art of war"},
+		{"acronym", "# using the NASA API in practice", "# Using the NASA API in Practice", "# Using the NASA API in practice"},
+		{"mixed case name", "## Why iPhone Sales Fell", "## Why iPhone Sales Fell", "## Why iPhone sales fell"},
+		{"stopword at the end", "### What It Is Made OF", "### What It Is Made OF", "### What it is made OF"},
+		{"after a colon", "## Dune: a novel of the desert", "## Dune: A Novel of the Desert", "## Dune: a novel of the desert"},
+		{"hyphenated", "## state-of-the-art tools", "## State-Of-The-Art Tools", "## State-of-the-art tools"},
+		{"pronoun I", "## What I Learned", "## What I Learned", "## What I learned"},
+		{"code span and link", "## Calling `fetchAll` With [Docs](https://example.com/Docs)", "## Calling `fetchAll` with [Docs](https://example.com/Docs)", "## Calling `fetchAll` with [Docs](https://example.com/Docs)"},
+		{"leading number", "## 2. getting started", "## 2. Getting Started", "## 2. Getting started"},
+		{"body text", "Some Body Text In Title Case", "Some Body Text In Title Case", "Some Body Text In Title Case"},
+		{"fenced code", "```\n# a shell comment\n```", "```\n# a shell comment\n```", "```\n# a shell comment\n```"},
+	}
+	for _, tt := range tests {
+		if got := TitleCaseHeadings(tt.md); got != tt.title {
+			t.Errorf("%s: TitleCaseHeadings() = %q, want %q", tt.name, got, tt.title)
+		}
+		if got := SentenceCaseHeadings(tt.md); got != tt.sentence {
+			t.Errorf("%s: SentenceCaseHeadings() = %q, want %q", tt.name, got, tt.sentence)
+		}
+	}
+}
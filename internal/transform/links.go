@@ -0,0 +1,90 @@
+package transform
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+)
+
+var (
+	// inlineLink matches [text](url) and [text](url "title"), allowing one level
+	// of parentheses in the URL. Images are told apart by the preceding "!".
+	inlineLink = regexp.MustCompile(`\[([^\[\]]*)\]\(((?:[^()\s]|\([^()\s]*\))+)(?:\s+"([^"]*)")?\)`)
+	// referenceDefinition matches a numbered link reference definition
+	referenceDefinition = regexp.MustCompile(`(?m)^\[(\d+)\]:`)
+)
+
+// ReferenceLinks rewrites inline links as numbered reference links, [text][1],
+// and collects their definitions at the end of the document. Links to the same
+// URL share one definition. Images, fenced code blocks and code spans are left
+// untouched, and numbering continues after any numbered definitions already
+// in the Markdown.
+func ReferenceLinks(md string) string {
+	next := 1
+	for _, m := range referenceDefinition.FindAllStringSubmatch(md, -1) {
+		if n, err := strconv.Atoi(m[1]); err == nil && n >= next {
+			next = n + 1
+		}
+	}
+
+	refs := make(map[string]int)
+	var definitions []string
+	reference := func(url, title string) int {
+		if n, ok := refs[url]; ok {
+			return n
+		}
+		n := next
+		next++
+		refs[url] = n
+		definition := "[" + strconv.Itoa(n) + "]: " + url
+		if title != "" {
+			definition += ` "` + title + `"`
+		}
+		definitions = append(definitions, definition)
+		return n
+	}
+
+	lines := strings.Split(md, "\n")
+	var fence markdown.CodeFence
+	for i, line := range lines {
+		if fence.Line(line) {
+			continue
+		}
+
+		// Odd segments between backticks are code spans
+		segments := strings.Split(line, "`")
+		for j := 0; j < len(segments); j += 2 {
+			segments[j] = referenceLinksInText(segments[j], reference)
+		}
+		lines[i] = strings.Join(segments, "`")
+	}
+	if len(definitions) == 0 {
+		return md
+	}
+
+	result := strings.TrimRight(strings.Join(lines, "\n"), "\n")
+	return result + "\n\n" + strings.Join(definitions, "\n") + "\n"
+}
+
+// referenceLinksInText rewrites the inline links of a piece of text outside code
+func referenceLinksInText(text string, reference func(url, title string) int) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range inlineLink.FindAllStringSubmatchIndex(text, -1) {
+		if m[0] > 0 && text[m[0]-1] == '!' {
+			continue
+		}
+		title := ""
+		if m[6] >= 0 {
+			title = text[m[6]:m[7]]
+		}
+		n := reference(text[m[4]:m[5]], title)
+		b.WriteString(text[last:m[0]])
+		b.WriteString("[" + text[m[2]:m[3]] + "][" + strconv.Itoa(n) + "]")
+		last = m[1]
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
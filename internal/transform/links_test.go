@@ -0,0 +1,24 @@
+package transform
+
+import "testing"
+
+func TestReferenceLinks(t *testing.T) {
+	tests := []struct {
+		name, md, want string
+	}{
+		{"none", "Plain text\n", "Plain text\n"},
+		{"collected", "See [Go](https://go.dev) and [docs](https://go.dev/doc).\n", "See [Go][1] and [docs][2].\n\n[1]: https://go.dev\n[2]: https://go.dev/doc\n"},
+		{"deduplicated", "[a](https://x.test) then [b](https://x.test)", "[a][1] then [b][1]\n\n[1]: https://x.test\n"},
+		{"title", `[a](https://x.test "X site")`, "[a][1]\n\n[1]: https://x.test \"X site\"\n"},
+		{"parentheses in url", "[wiki](https://en.wikipedia.org/wiki/Go_(language))", "[wiki][1]\n\n[1]: https://en.wikipedia.org/wiki/Go_(language)\n"},
+		{"images kept", "![logo](logo.png) [home](/)", "![logo](logo.png) [home][1]\n\n[1]: /\n"},
+		{"code span", "Use `[a](b)` or [c](d)", "Use `[a](b)` or [c][1]\n\n[1]: d\n"},
+		{"fenced code", "```\n[a](b)\n```\n", "```\n[a](b)\n```\n"},
+		{"numbering continues", "[a](x)\n\n[4]: y\n", "[a][5]\n\n[4]: y\n\n[5]: x\n"},
+	}
+	for _, tt := range tests {
+		if got := ReferenceLinks(tt.md); got != tt.want {
+			t.Errorf("%s: ReferenceLinks(%q) = %q, want %q", tt.name, tt.md, got, tt.want)
+		}
+	}
+}
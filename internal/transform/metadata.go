@@ -0,0 +1,89 @@
+package transform
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+)
+
+// Places of the metadata table in the output
+const (
+	// MetadataTop puts the table before the content, below any front matter
+	MetadataTop = "top"
+	// MetadataBottom puts the table after the content
+	MetadataBottom = "bottom"
+)
+
+// MetadataReader reads the metadata a document declares, as
+// converter.ExtractMetadata does
+type MetadataReader func(inputPath string) (markdown.Metadata, error)
+
+// metadataEscaper keeps a metadata value on its table row
+var metadataEscaper = strings.NewReplacer("|", `\|`, "\n", " ")
+
+// ParseMetadataPosition validates the place of the metadata table. An empty
+// name selects MetadataTop.
+func ParseMetadataPosition(name string) (string, error) {
+	switch name {
+	case "", MetadataTop:
+		return MetadataTop, nil
+	case MetadataBottom:
+		return MetadataBottom, nil
+	}
+	return "", fmt.Errorf("unsupported metadata table position: %s (expected top or bottom)", name)
+}
+
+// MetadataTable returns a transform adding a table of the title, author, page
+// count and creation date of the converted document, at the top or bottom of
+// the Markdown as position selects. The metadata is Metadata.Document when
+// set and read from the input with read otherwise. Merged outputs and
+// documents without metadata that can be read are left unchanged.
+func MetadataTable(position string, read MetadataReader) Transform {
+	return Func(func(md string, meta Metadata) (string, error) {
+		info := meta.Document
+		if info == nil && meta.InputPath != "" {
+			if declared, err := read(meta.InputPath); err == nil {
+				info = &declared
+			}
+		}
+		if info == nil {
+			return md, nil
+		}
+		table := renderMetadata(*info)
+		if table == "" {
+			return md, nil
+		}
+
+		if position == MetadataBottom {
+			return strings.TrimRight(md, "\n") + "\n\n" + table + "\n", nil
+		}
+		content := StripFrontMatter(md)
+		frontMatter := strings.TrimSuffix(md, content)
+		return frontMatter + table + "\n\n" + content, nil
+	})
+}
+
+// renderMetadata renders the metadata a document declares as a table; empty
+// values are left out, as is the table when they all are
+func renderMetadata(info markdown.Metadata) string {
+	var rows []string
+	add := func(field, value string) {
+		if value = strings.TrimSpace(value); value != "" {
+			rows = append(rows, "| "+field+" | "+metadataEscaper.Replace(value)+" |")
+		}
+	}
+	add("Title", info.Title)
+	add("Author", info.Author)
+	if info.Pages > 0 {
+		add("Pages", strconv.Itoa(info.Pages))
+	}
+	if !info.Created.IsZero() {
+		add("Created", info.Created.Format("2006-01-02"))
+	}
+	if len(rows) == 0 {
+		return ""
+	}
+	return "| Field | Value |\n| --- | --- |\n" + strings.Join(rows, "\n")
+}
@@ -0,0 +1,82 @@
+package transform
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+)
+
+func TestMetadataTable(t *testing.T) {
+	report := markdown.Metadata{Title: "Annual | Report", Author: "Ana\nLima", Pages: 3, Created: time.Date(2024, 1, 15, 9, 30, 0, 0, time.UTC)}
+	const table = "| Field | Value |\n| --- | --- |\n| Title | Annual \\| Report |\n| Author | Ana Lima |\n| Pages | 3 |\n| Created | 2024-01-15 |"
+	tests := []struct {
+		name     string
+		position string
+		md       string
+		path     string
+		info     markdown.Metadata
+		err      error
+		want     string
+	}{
+		{"top", MetadataTop, "# Report\n\nText\n", "report.pdf", report, nil, table + "\n\n# Report\n\nText\n"},
+		{"bottom", MetadataBottom, "# Report\n\nText\n\n", "report.pdf", report, nil, "# Report\n\nText\n\n" + table + "\n"},
+		{"below front matter", MetadataTop, "---\ntitle: Report\n---\n\nText\n", "report.pdf", report, nil, "---\ntitle: Report\n---\n\n" + table + "\n\nText\n"},
+		{"empty values left out", MetadataTop, "Text\n", "notes.pdf", markdown.Metadata{Title: " ", Pages: 1}, nil, "| Field | Value |\n| --- | --- |\n| Pages | 1 |\n\nText\n"},
+		{"no metadata", MetadataTop, "Text\n", "notes.pdf", markdown.Metadata{}, nil, "Text\n"},
+		{"unreadable metadata", MetadataTop, "Text\n", "notes.txt", report, errors.New("no metadata reader"), "Text\n"},
+		{"merged output", MetadataTop, "Text\n", "", report, nil, "Text\n"},
+	}
+	for _, tt := range tests {
+		var read string
+		reader := func(inputPath string) (markdown.Metadata, error) {
+			read = inputPath
+			return tt.info, tt.err
+		}
+		got, err := MetadataTable(tt.position, reader).Apply(tt.md, Metadata{InputPath: tt.path})
+		if err != nil {
+			t.Fatalf("%s: Apply() error = %v", tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+		if read != tt.path {
+			t.Errorf("%s: read metadata of %q, want %q", tt.name, read, tt.path)
+		}
+	}
+}
+
+func TestMetadataTableDocument(t *testing.T) {
+	reader := func(inputPath string) (markdown.Metadata, error) {
+		t.Errorf("metadata of %s read, want the document's", inputPath)
+		return markdown.Metadata{}, nil
+	}
+	meta := Metadata{InputPath: "https://example.com/report.pdf", Document: &markdown.Metadata{Title: "Report"}}
+	got, err := MetadataTable(MetadataTop, reader).Apply("Text\n", meta)
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if want := "| Field | Value |\n| --- | --- |\n| Title | Report |\n\nText\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestParseMetadataPosition(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{"", MetadataTop, false},
+		{"top", MetadataTop, false},
+		{"bottom", MetadataBottom, false},
+		{"middle", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseMetadataPosition(tt.name)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseMetadataPosition(%q) = %q, %v, want %q, wantErr %v", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
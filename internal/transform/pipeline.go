@@ -0,0 +1,80 @@
+package transform
+
+import "github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+
+// Metadata describes the document a transform is applied to
+type Metadata struct {
+	// InputPath is the converted document, empty when several inputs are merged
+	InputPath string
+	// OutputPath is the Markdown file being written
+	OutputPath string
+	// Document is the metadata the input declares when it was read during
+	// the conversion, as for downloads removed before the passes run; nil
+	// leaves MetadataTable reading InputPath
+	Document *markdown.Metadata
+}
+
+// Transform is a post-processing pass applied to converted Markdown before it is written
+type Transform interface {
+	Apply(md string, meta Metadata) (string, error)
+}
+
+// Func adapts a function to the Transform interface
+type Func func(md string, meta Metadata) (string, error)
+
+func (f Func) Apply(md string, meta Metadata) (string, error) {
+	return f(md, meta)
+}
+
+// Text adapts a pass that only rewrites the Markdown, such as NormalizeQuotes
+func Text(pass func(md string) string) Transform {
+	return Func(func(md string, _ Metadata) (string, error) {
+		return pass(md), nil
+	})
+}
+
+// Replace returns a transform applying regex substitutions in order
+func Replace(rules []Replacement) Transform {
+	return Text(func(md string) string {
+		return ApplyReplacements(md, rules)
+	})
+}
+
+// HeadingOffset returns a transform shifting every heading by offset levels
+func HeadingOffset(offset int) Transform {
+	return Text(func(md string) string {
+		return ShiftHeadings(md, offset)
+	})
+}
+
+// Nested returns the pipeline preparing a converted document to become a
+// section of another, as merged inputs and portfolio documents do: its front
+// matter is dropped and its headings move offset levels down
+func Nested(offset int) Pipeline {
+	var p Pipeline
+	p.Register(Text(StripFrontMatter))
+	p.Register(HeadingOffset(offset))
+	return p
+}
+
+// Pipeline applies transforms in the order they were registered. The zero
+// value is an empty pipeline, which leaves the Markdown unchanged.
+type Pipeline struct {
+	transforms []Transform
+}
+
+// Register appends a transform to the pipeline
+func (p *Pipeline) Register(t Transform) {
+	p.transforms = append(p.transforms, t)
+}
+
+// Apply runs every transform on the output of the previous one, stopping at the first error
+func (p *Pipeline) Apply(md string, meta Metadata) (string, error) {
+	for _, t := range p.transforms {
+		var err error
+		if md, err = t.Apply(md, meta); err != nil {
+			return "", err
+		}
+	}
+	return md, nil
+}
@@ -0,0 +1,57 @@
+package transform
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPipeline(t *testing.T) {
+	appendText := func(s string) Transform {
+		return Text(func(md string) string { return md + s })
+	}
+	failed := errors.New("failed")
+
+	tests := []struct {
+		name       string
+		transforms []Transform
+		want       string
+		wantErr    error
+	}{
+		{"empty", nil, "md", nil},
+		{"in order", []Transform{appendText(" a"), appendText(" b")}, "md a b", nil},
+		{"metadata", []Transform{Func(func(md string, meta Metadata) (string, error) {
+			return md + " " + meta.InputPath, nil
+		})}, "md in.pdf", nil},
+		{"stops at error", []Transform{
+			Func(func(string, Metadata) (string, error) { return "", failed }),
+			appendText(" b"),
+		}, "", failed},
+	}
+	for _, tt := range tests {
+		var p Pipeline
+		for _, transform := range tt.transforms {
+			p.Register(transform)
+		}
+		got, err := p.Apply("md", Metadata{InputPath: "in.pdf"})
+		if !errors.Is(err, tt.wantErr) || got != tt.want {
+			t.Errorf("%s: Apply() = %q, %v, want %q, %v", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestNested(t *testing.T) {
+	tests := []struct {
+		name, md, want string
+	}{
+		{"headings", "# Title\n\ntext\n\n## Part\n", "## Title\n\ntext\n\n### Part\n"},
+		{"front matter", "---\ntitle: Doc\n---\n\n# Title\n", "## Title\n"},
+		{"code", "```\n# comment\n```\n", "```\n# comment\n```\n"},
+		{"deepest", "###### Deep\n", "###### Deep\n"},
+	}
+	for _, tt := range tests {
+		p := Nested(1)
+		if got, err := p.Apply(tt.md, Metadata{}); err != nil || got != tt.want {
+			t.Errorf("%s: Nested(1).Apply(%q) = %q, %v, want %q", tt.name, tt.md, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package transform
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+)
+
+// asciiPunctuation maps typographic quotes and dashes to their ASCII forms
+var asciiPunctuation = strings.NewReplacer(
+	"“", `"`, "”", `"`, "„", `"`, "‟", `"`,
+	"‘", "'", "’", "'", "‚", "'", "‛", "'",
+	"—", "--", "–", "-",
+)
+
+// NormalizeQuotes replaces curly quotes with straight quotes, em dashes with
+// "--" and en dashes with "-"
+func NormalizeQuotes(md string) string {
+	return asciiPunctuation.Replace(md)
+}
+
+// SmartQuotes is the inverse of NormalizeQuotes: straight quotes become curly
+// quotes, "--" becomes an em dash and a hyphen between spaces an en dash.
+// Front matter, fenced code blocks, code spans, HTML tags and comments and
+// link destinations are left untouched so the Markdown keeps its meaning.
+func SmartQuotes(md string) string {
+	_, content := markdown.ParseFrontMatter(md)
+	frontMatter := strings.TrimSuffix(md, content)
+
+	lines := strings.Split(content, "\n")
+	var fence markdown.CodeFence
+	comment := false
+	for i, line := range lines {
+		if !comment && fence.Line(line) {
+			continue
+		}
+		lines[i] = smartenLine(line, &comment)
+	}
+	return frontMatter + strings.Join(lines, "\n")
+}
+
+// smartenLine applies SmartQuotes to a single line outside code blocks.
+// comment is set while an HTML comment is open, which may span lines.
+func smartenLine(line string, comment *bool) string {
+	runes := []rune(line)
+	var b strings.Builder
+	var skipUntil rune
+	for i, r := range runes {
+		if *comment {
+			b.WriteRune(r)
+			if r == '>' && i >= 2 && runes[i-1] == '-' && runes[i-2] == '-' {
+				*comment = false
+			}
+			continue
+		}
+		if skipUntil != 0 {
+			b.WriteRune(r)
+			if r == skipUntil {
+				skipUntil = 0
+			}
+			continue
+		}
+
+		var prev, next rune
+		if i > 0 {
+			prev = runes[i-1]
+		}
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+
+		switch {
+		case r == '`':
+			skipUntil = '`'
+		case r == '<' && strings.HasPrefix(string(runes[i:]), "<!--"):
+			*comment = true
+		case r == '<' && (unicode.IsLetter(next) || next == '/'):
+			skipUntil = '>'
+		case r == '(' && prev == ']':
+			skipUntil = ')'
+		case r == '"':
+			if opensQuote(prev) {
+				b.WriteRune('“')
+			} else {
+				b.WriteRune('”')
+			}
+			continue
+		case r == '\'':
+			if opensQuote(prev) {
+				b.WriteRune('‘')
+			} else {
+				// Closing quotes and apostrophes share a glyph
+				b.WriteRune('’')
+			}
+			continue
+		case r == '-' && next == '-' && prev != '-' && (i+2 >= len(runes) || runes[i+2] != '-'):
+			b.WriteRune('—')
+			runes[i+1] = 0
+			continue
+		case r == '-' && prev == ' ' && next == ' ' && strings.TrimSpace(string(runes[:i])) != "":
+			// Spaced hyphens inside text are dashes; at the start of a line they are list markers
+			b.WriteRune('–')
+			continue
+		case r == 0:
+			// Second hyphen of an em dash
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// opensQuote reports whether a quote following prev starts a quotation
+func opensQuote(prev rune) bool {
+	return prev == 0 || unicode.IsSpace(prev) || strings.ContainsRune("([{<>*_~-—–“‘", prev)
+}
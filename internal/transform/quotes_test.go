@@ -0,0 +1,49 @@
+package transform
+
+import "testing"
+
+func TestNormalizeQuotes(t *testing.T) {
+	tests := []struct {
+		md, want string
+	}{
+		{"“Quoted” and ‘single’", `"Quoted" and 'single'`},
+		{"It’s „low“ ‚too‘", `It's "low" 'too'`},
+		{"Wait—now – then", "Wait--now - then"},
+		{"Plain text", "Plain text"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeQuotes(tt.md); got != tt.want {
+			t.Errorf("NormalizeQuotes(%q) = %q, want %q", tt.md, got, tt.want)
+		}
+	}
+}
+
+func TestSmartQuotes(t *testing.T) {
+	tests := []struct {
+		name, md, want string
+	}{
+		{"double", `He said "hi" (and "bye")`, "He said “hi” (and “bye”)"},
+		{"single and apostrophe", "It's 'fine'", "It’s ‘fine’"},
+		{"dashes", "Wait--now - then", "Wait—now – then"},
+		{"list marker kept", "- item - part", "- item – part"},
+		{"rule kept", "---", "---"},
+		{"code span", "Use `\"x\"` here", "Use `\"x\"` here"},
+		{"fence", "```\nx = \"y\"\n```\n\"z\"", "```\nx = \"y\"\n```\n“z”"},
+		{"link destination", `[a "b"](http://x/"c") "d"`, "[a “b”](http://x/\"c\") “d”"},
+		{"html", `<a title="t">"x"</a>`, `<a title="t">“x”</a>`},
+		{"comment", "<!-- \"a\"\n\"b\" -->\n\"c\"", "<!-- \"a\"\n\"b\" -->\n“c”"},
+		{"front matter", "---\ntitle: \"A\"\n---\n\"B\"", "---\ntitle: \"A\"\n---\n“B”"},
+	}
+	for _, tt := range tests {
+		if got := SmartQuotes(tt.md); got != tt.want {
+			t.Errorf("%s: SmartQuotes(%q) = %q, want %q", tt.name, tt.md, got, tt.want)
+		}
+	}
+}
+
+func TestQuotesRoundTrip(t *testing.T) {
+	md := "“Quoted” text—with ‘dashes’ – and it’s fine"
+	if got := SmartQuotes(NormalizeQuotes(md)); got != md {
+		t.Errorf("SmartQuotes(NormalizeQuotes(%q)) = %q", md, got)
+	}
+}
@@ -0,0 +1,71 @@
+package transform
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Replacement is a regex substitution applied to the final Markdown
+type Replacement struct {
+	Pattern     *regexp.Regexp
+	Replacement string
+}
+
+// ParseReplacement parses a sed-style "/pattern/replacement/" rule. The first
+// character is used as the delimiter and may be escaped with a backslash;
+// a backslash ending the pattern is written as \\ to leave the delimiter
+// unescaped. The replacement may reference capture groups as $1 or ${name}.
+func ParseReplacement(rule string) (Replacement, error) {
+	if len(rule) < 3 {
+		return Replacement{}, fmt.Errorf("invalid replace rule %q: expected /pattern/replacement/", rule)
+	}
+
+	delim := rule[0]
+	parts := splitUnescaped(rule[1:], delim)
+	if len(parts) != 3 || parts[2] != "" {
+		return Replacement{}, fmt.Errorf("invalid replace rule %q: expected /pattern/replacement/", rule)
+	}
+	if parts[0] == "" {
+		return Replacement{}, fmt.Errorf("invalid replace rule %q: empty pattern", rule)
+	}
+
+	pattern, err := regexp.Compile(parts[0])
+	if err != nil {
+		return Replacement{}, fmt.Errorf("invalid replace pattern %q: %v", parts[0], err)
+	}
+
+	return Replacement{Pattern: pattern, Replacement: parts[1]}, nil
+}
+
+// ApplyReplacements runs each rule over the Markdown in order
+func ApplyReplacements(md string, rules []Replacement) string {
+	for _, rule := range rules {
+		md = rule.Pattern.ReplaceAllString(md, rule.Replacement)
+	}
+	return md
+}
+
+// splitUnescaped splits s on every delim not escaped by a backslash,
+// removing the backslash from escaped delimiters. Escaped backslashes are
+// kept as they are, for the pattern to read them.
+func splitUnescaped(s string, delim byte) []string {
+	var parts []string
+	var current strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s) && s[i+1] == '\\':
+			current.WriteString(`\\`)
+			i++
+		case s[i] == '\\' && i+1 < len(s) && s[i+1] == delim:
+			current.WriteByte(delim)
+			i++
+		case s[i] == delim:
+			parts = append(parts, current.String())
+			current.Reset()
+		default:
+			current.WriteByte(s[i])
+		}
+	}
+	return append(parts, current.String())
+}
@@ -0,0 +1,53 @@
+package transform
+
+import "testing"
+
+func TestParseReplacement(t *testing.T) {
+	tests := []struct {
+		rule        string
+		pattern     string
+		replacement string
+		wantErr     bool
+	}{
+		{rule: "/foo/bar/", pattern: "foo", replacement: "bar"},
+		{rule: "/a/$1/", pattern: "a", replacement: "$1"},
+		{rule: `/a\/b/c/`, pattern: "a/b", replacement: "c"},
+		{rule: `/a\\/b/`, pattern: `a\\`, replacement: "b"},
+		{rule: `/a\\\/b/c/`, pattern: `a\\/b`, replacement: "c"},
+		{rule: `/\d\\w/x/`, pattern: `\d\\w`, replacement: "x"},
+		{rule: "|x|y|", pattern: "x", replacement: "y"},
+		{rule: "/gone//", pattern: "gone", replacement: ""},
+		{rule: "/a,b/c/", pattern: "a,b", replacement: "c"},
+		{rule: "/a/b", wantErr: true},
+		{rule: "/a/b/c/", wantErr: true},
+		{rule: "//b/", wantErr: true},
+		{rule: "/(/b/", wantErr: true},
+		{rule: "ab", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseReplacement(tt.rule)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseReplacement(%q) error = %v, wantErr %v", tt.rule, err, tt.wantErr)
+			continue
+		}
+		if err == nil && (got.Pattern.String() != tt.pattern || got.Replacement != tt.replacement) {
+			t.Errorf("ParseReplacement(%q) = %q, %q; want %q, %q", tt.rule, got.Pattern, got.Replacement, tt.pattern, tt.replacement)
+		}
+	}
+}
+
+func TestApplyReplacements(t *testing.T) {
+	var rules []Replacement
+	for _, rule := range []string{`/(\w+)@example\.com/$1@redacted/`, "/recieve/receive/"} {
+		r, err := ParseReplacement(rule)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rules = append(rules, r)
+	}
+	md := "# Contact\n\nWrite to ann@example.com to recieve it.\n"
+	want := "# Contact\n\nWrite to ann@redacted to receive it.\n"
+	if got := ApplyReplacements(md, rules); got != want {
+		t.Errorf("ApplyReplacements() = %q, want %q", got, want)
+	}
+}
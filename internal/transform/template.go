@@ -0,0 +1,83 @@
+package transform
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+)
+
+// TemplateData is what a document template is executed with
+type TemplateData struct {
+	// Content is the converted Markdown, without its front matter
+	Content  string
+	Title    string
+	Subtitle string
+	Author   string
+	Date     string
+	Tags     []string
+	// Source is the file name of the converted document, empty for merged output
+	Source string
+}
+
+// ParseTemplate reads a document template, a Markdown file with text/template
+// placeholders such as {{ .Content }} and {{ .Title }}
+func ParseTemplate(path string) (*template.Template, error) {
+	text, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %v", err)
+	}
+	t, err := template.New(filepath.Base(path)).Parse(string(text))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %v", err)
+	}
+	return t, nil
+}
+
+// Template returns a transform wrapping the Markdown in a document template.
+// The metadata placeholders are filled from the front matter, and the title
+// falls back to the first H1 heading.
+func Template(t *template.Template) Transform {
+	return Func(func(md string, meta Metadata) (string, error) {
+		fm, content := markdown.ParseFrontMatter(md)
+		data := TemplateData{
+			Content:  content,
+			Title:    fm.Title,
+			Subtitle: fm.Subtitle,
+			Author:   fm.Author,
+			Date:     fm.Date,
+			Tags:     fm.Tags,
+		}
+		if meta.InputPath != "" {
+			data.Source = filepath.Base(meta.InputPath)
+		}
+		if data.Title == "" {
+			data.Title = firstTitle(content)
+		}
+
+		var b strings.Builder
+		if err := t.Execute(&b, data); err != nil {
+			return "", fmt.Errorf("failed to render template: %v", err)
+		}
+		return b.String(), nil
+	})
+}
+
+// firstTitle returns the text of the first H1 heading outside code blocks
+func firstTitle(md string) string {
+	var fence markdown.CodeFence
+	for _, line := range strings.Split(md, "\n") {
+		if fence.Line(line) {
+			continue
+		}
+		if m := atxHeading.FindStringSubmatch(line); m != nil && len(m[1]) == 1 {
+			if title := strings.TrimSpace(strings.TrimRight(strings.TrimSpace(m[2]), "#")); title != "" {
+				return title
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,66 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"text/template"
+)
+
+func TestTemplate(t *testing.T) {
+	const frontMatter = "---\ntitle: Report\nauthor: Ana\ndate: 2024-01-15\ntags:\n  - go\n  - docs\n---\n\n"
+	tests := []struct {
+		name     string
+		template string
+		md       string
+		path     string
+		want     string
+	}{
+		{"content", "Header\n\n{{ .Content }}\nFooter\n", "# Title\n\nText\n", "", "Header\n\n# Title\n\nText\n\nFooter\n"},
+		{"front matter fields", "{{ .Title }} by {{ .Author }} on {{ .Date }} {{ range .Tags }}#{{ . }} {{ end }}\n\n{{ .Content }}", frontMatter + "Text\n", "", "Report by Ana on 2024-01-15 #go #docs \n\nText\n"},
+		{"title from first heading", "Title: {{ .Title }}\n", "Intro\n\n# First\n\n# Second\n", "", "Title: First\n"},
+		{"heading in code block", "Title: {{ .Title }}\n", "```\n# comment\n```\n\n## Part\n", "", "Title: \n"},
+		{"closing hashes", "Title: {{ .Title }}\n", "# Summary ##\n", "", "Title: Summary\n"},
+		{"front matter title first", "Title: {{ .Title }}\n", frontMatter + "# Heading\n", "", "Title: Report\n"},
+		{"source", "{{ .Source }}\n", "Text\n", "docs/report.pdf", "report.pdf\n"},
+		{"no source when merged", "[{{ .Source }}]\n", "Text\n", "", "[]\n"},
+	}
+	for _, tt := range tests {
+		tmpl := template.Must(template.New(tt.name).Parse(tt.template))
+		got, err := Template(tmpl).Apply(tt.md, Metadata{InputPath: tt.path})
+		if err != nil || got != tt.want {
+			t.Errorf("%s: got %q, %v, want %q", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestTemplateExecuteError(t *testing.T) {
+	tmpl := template.Must(template.New("bad").Parse("{{ .Missing }}"))
+	if _, err := Template(tmpl).Apply("Text\n", Metadata{}); err == nil {
+		t.Error("Apply() with an unknown field: error = nil, want an error")
+	}
+}
+
+func TestParseTemplate(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		text    string
+		wantErr bool
+	}{
+		{"valid.md", "# {{ .Title }}\n\n{{ .Content }}", false},
+		{"unclosed.md", "{{ .Content ", true},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(dir, tt.name)
+		if err := os.WriteFile(path, []byte(tt.text), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ParseTemplate(path); (err != nil) != tt.wantErr {
+			t.Errorf("ParseTemplate(%s) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+	if _, err := ParseTemplate(filepath.Join(dir, "missing.md")); err == nil {
+		t.Error("ParseTemplate(missing.md) error = nil, want an error")
+	}
+}
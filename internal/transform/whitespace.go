@@ -0,0 +1,115 @@
+package transform
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+)
+
+// listItemLine matches a line opening a list item, which the lines indented
+// below it continue rather than start code
+var listItemLine = regexp.MustCompile(`^\s*([-*+]|\d+[.)])(\s|$)`)
+
+// TrimWhitespace removes trailing whitespace from every line, collapses runs
+// of spaces inside lines and trims table cells. Fenced and indented code
+// blocks, inline code spans and leading indentation are left untouched. Hard
+// line breaks written as trailing spaces are kept as a backslash break.
+func TrimWhitespace(md string) string {
+	lines := strings.Split(md, "\n")
+	var fence markdown.CodeFence
+	// code is set within an indented code block, list once a list item opens
+	// and until a line at the margin follows a blank line
+	code, list, blank := false, false, true
+	for i, line := range lines {
+		if open := fence.Open(); fence.Line(line) {
+			if !open {
+				lines[i] = strings.TrimRight(line, " \t")
+			}
+			code, blank = false, false
+			continue
+		}
+
+		trimmed := strings.TrimLeft(line, " ")
+		if strings.TrimSpace(line) == "" {
+			blank = true
+			if code {
+				continue
+			}
+		} else {
+			indented := strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t")
+			if code = indented && (code || blank && !list); code {
+				blank = false
+				continue
+			}
+			switch {
+			case listItemLine.MatchString(line):
+				list = true
+			case !indented && blank:
+				list = false
+			}
+			blank = false
+		}
+
+		hardBreak := strings.HasSuffix(line, "  ") && strings.TrimSpace(line) != "" &&
+			i+1 < len(lines) && strings.TrimSpace(lines[i+1]) != ""
+
+		indent := line[:len(line)-len(trimmed)]
+		body := collapseSpaces(strings.TrimRight(trimmed, " \t"))
+		if strings.HasPrefix(body, "|") {
+			body = trimCells(body)
+		}
+		if hardBreak {
+			body += "\\"
+		}
+		lines[i] = indent + body
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// collapseSpaces replaces runs of spaces and tabs with a single space outside code spans
+func collapseSpaces(s string) string {
+	var b strings.Builder
+	inCode := false
+	space := false
+	for _, r := range s {
+		if r == '`' {
+			inCode = !inCode
+		}
+		if !inCode && (r == ' ' || r == '\t') {
+			space = true
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// trimCells normalizes the padding of each cell in a pipe table row
+func trimCells(row string) string {
+	inner := strings.TrimPrefix(row, "|")
+	if strings.HasSuffix(inner, "|") && !strings.HasSuffix(inner, "\\|") {
+		inner = inner[:len(inner)-1]
+	}
+
+	// Escaped pipes belong to the cell text
+	var cells []string
+	start := 0
+	for i := 0; i < len(inner); i++ {
+		switch inner[i] {
+		case '\\':
+			i++
+		case '|':
+			cells = append(cells, strings.TrimSpace(inner[start:i]))
+			start = i + 1
+		}
+	}
+	cells = append(cells, strings.TrimSpace(inner[start:]))
+
+	return "| " + strings.Join(cells, " | ") + " |"
+}
@@ -0,0 +1,25 @@
+package transform
+
+import "testing"
+
+func TestTrimWhitespace(t *testing.T) {
+	tests := []struct {
+		name, md, want string
+	}{
+		{"trailing", "Line one   \n\nLine two\t\n", "Line one\n\nLine two\n"},
+		{"doubled spaces", "Some   spaced    words", "Some spaced words"},
+		{"indentation kept", "- item\n  -  nested   item", "- item\n  - nested item"},
+		{"code span", "Run `a  b`  now", "Run `a  b` now"},
+		{"fenced code", "```\nx  =  1   \n```\nafter  text", "```\nx  =  1   \n```\nafter text"},
+		{"indented code", "Text\n\n    x  =  1  \n\nMore  text", "Text\n\n    x  =  1  \n\nMore text"},
+		{"list continuation", "- item\n\n    continued   here", "- item\n\n    continued here"},
+		{"table cells", "|  A |B  |\n| --- |  --- |\n|  1|  2 \\| 3 |", "| A | B |\n| --- | --- |\n| 1 | 2 \\| 3 |"},
+		{"hard break", "first  \nsecond", "first\\\nsecond"},
+		{"no break before blank", "first  \n\nsecond", "first\n\nsecond"},
+	}
+	for _, tt := range tests {
+		if got := TrimWhitespace(tt.md); got != tt.want {
+			t.Errorf("%s: TrimWhitespace(%q) = %q, want %q", tt.name, tt.md, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"sync"
+)
+
+// Asset naming schemes accepted by NewAssetNamer
+const (
+	// AssetNamingPositional names assets after their document and place in it
+	AssetNamingPositional = "positional"
+	// AssetNamingHash names assets after a digest of their content, so the
+	// same image extracted from several documents is stored once
+	AssetNamingHash = "hash"
+	// AssetNamingSequential numbers assets in the order they are extracted
+	AssetNamingSequential = "sequential"
+)
+
+// hashLength is the number of hex digits of the content digest kept in hash names
+const hashLength = 16
+
+// AssetNamer chooses the file names of extracted assets. One namer is shared
+// by every document of a run, so sequential numbers do not repeat. A nil
+// namer keeps positional names.
+type AssetNamer struct {
+	scheme string
+	mu     sync.Mutex
+	count  int
+}
+
+// NewAssetNamer returns a namer for a naming scheme, positional when empty
+func NewAssetNamer(scheme string) (*AssetNamer, error) {
+	switch scheme {
+	case "", AssetNamingPositional, AssetNamingHash, AssetNamingSequential:
+		return &AssetNamer{scheme: scheme}, nil
+	}
+	return nil, fmt.Errorf("unsupported assets naming: %s (expected positional, hash or sequential)", scheme)
+}
+
+// Name returns the file name of an asset. positional is the name a converter
+// derives from the document; other schemes keep only its extension.
+// Sequential numbers follow the order Name is called in, so converters
+// extracting pages concurrently name their assets in page order.
+func (n *AssetNamer) Name(positional string, data []byte) string {
+	if n == nil {
+		return positional
+	}
+	ext := filepath.Ext(positional)
+	switch n.scheme {
+	case AssetNamingHash:
+		sum := sha256.Sum256(data)
+		return hex.EncodeToString(sum[:])[:hashLength] + ext
+	case AssetNamingSequential:
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		n.count++
+		return fmt.Sprintf("asset%d%s", n.count, ext)
+	}
+	return positional
+}
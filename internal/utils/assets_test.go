@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestAssetNamer(t *testing.T) {
+	image := []byte("image data")
+	tests := []struct {
+		scheme string
+		want   []string
+	}{
+		{"", []string{"doc-page1-img1.png", "doc-page2-img1.jpg", "doc-page3-img1.png"}},
+		{AssetNamingPositional, []string{"doc-page1-img1.png", "doc-page2-img1.jpg", "doc-page3-img1.png"}},
+		{AssetNamingHash, []string{"b41b86dcfdc6219b.png", "b41b86dcfdc6219b.jpg", "873517954b8a3d8f.png"}},
+		{AssetNamingSequential, []string{"asset1.png", "asset2.jpg", "asset3.png"}},
+	}
+	for _, tt := range tests {
+		namer, err := NewAssetNamer(tt.scheme)
+		if err != nil {
+			t.Fatalf("NewAssetNamer(%q) error = %v", tt.scheme, err)
+		}
+		got := []string{
+			namer.Name("doc-page1-img1.png", image),
+			namer.Name("doc-page2-img1.jpg", image),
+			namer.Name("doc-page3-img1.png", []byte("other data")),
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("%q: names = %v, want %v", tt.scheme, got, tt.want)
+		}
+	}
+
+	var namer *AssetNamer
+	if got := namer.Name("doc-img1.png", image); got != "doc-img1.png" {
+		t.Errorf("nil namer: name = %q, want doc-img1.png", got)
+	}
+	if _, err := NewAssetNamer("random"); err == nil {
+		t.Error("NewAssetNamer(random): error = nil, want an error")
+	}
+}
+
+func TestAssetNamerConcurrent(t *testing.T) {
+	namer, _ := NewAssetNamer(AssetNamingSequential)
+	const n = 100
+	names := make(chan string, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			names <- namer.Name("img.png", nil)
+		}()
+	}
+	wg.Wait()
+	close(names)
+	seen := make(map[string]bool)
+	for name := range names {
+		if seen[name] {
+			t.Errorf("name %s given twice", name)
+		}
+		seen[name] = true
+	}
+	if len(seen) != n || !seen[fmt.Sprintf("asset%d.png", n)] {
+		t.Errorf("got %d distinct names, want asset1.png to asset%d.png", len(seen), n)
+	}
+}
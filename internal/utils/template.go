@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// templatePlaceholder matches a {placeholder} in an output template
+var templatePlaceholder = regexp.MustCompile(`\{([^{}]*)\}`)
+
+// ValidateOutputTemplate checks that a template only uses the supported placeholders
+func ValidateOutputTemplate(template string) error {
+	for _, m := range templatePlaceholder.FindAllStringSubmatch(template, -1) {
+		switch m[1] {
+		case "name", "ext", "date", "parent":
+		default:
+			return fmt.Errorf("invalid output template placeholder %s (expected {name}, {ext}, {date} or {parent})", m[0])
+		}
+	}
+	return nil
+}
+
+// RenderOutputTemplate builds an output file name for inputPath from a template.
+// {name} is the input name without extension, {ext} its extension without the
+// dot, {date} the current date as YYYY-MM-DD and {parent} the name of the
+// directory containing the input.
+func RenderOutputTemplate(template, inputPath string, now time.Time) (string, error) {
+	if err := ValidateOutputTemplate(template); err != nil {
+		return "", err
+	}
+
+	base := filepath.Base(inputPath)
+	ext := filepath.Ext(base)
+	parent := filepath.Dir(inputPath)
+	if abs, err := filepath.Abs(parent); err == nil {
+		parent = abs
+	}
+
+	replacer := strings.NewReplacer(
+		"{name}", strings.TrimSuffix(base, ext),
+		"{ext}", strings.TrimPrefix(ext, "."),
+		"{date}", now.Format("2006-01-02"),
+		"{parent}", filepath.Base(parent),
+	)
+	return replacer.Replace(template), nil
+}
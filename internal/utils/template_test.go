@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRenderOutputTemplate(t *testing.T) {
+	now := time.Date(2024, 3, 9, 15, 4, 5, 0, time.UTC)
+	tests := []struct {
+		template, input string
+		want            string
+		wantErr         bool
+	}{
+		{"{date}-{name}.md", "docs/report.pdf", "2024-03-09-report.md", false},
+		{"{parent}_{name}.{ext}.md", "docs/report.pdf", "docs_report.pdf.md", false},
+		{"{name}", "archive.tar.gz", "archive.tar", false},
+		{"fixed.md", "docs/report.pdf", "fixed.md", false},
+		{"{title}.md", "docs/report.pdf", "", true},
+		{"{}.md", "docs/report.pdf", "", true},
+	}
+	for _, tt := range tests {
+		got, err := RenderOutputTemplate(tt.template, tt.input, now)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("RenderOutputTemplate(%q, %q) = %q, %v, want %q", tt.template, tt.input, got, err, tt.want)
+		}
+	}
+}
+
+func TestGetOutputPath(t *testing.T) {
+	dir := t.TempDir()
+	today := time.Now().Format("2006-01-02")
+	tests := []struct {
+		input, output, template string
+		want                    string
+	}{
+		{"docs/report.pdf", "", "", "report.md"},
+		{"docs/report.pdf", dir, "", filepath.Join(dir, "report.md")},
+		{"docs/report.pdf", dir, "{date}-{name}.md", filepath.Join(dir, today+"-report.md")},
+		{"docs/report.pdf", "", "{parent}-{name}.md", "docs-report.md"},
+		{"docs/report.pdf", filepath.Join(dir, "out.md"), "{name}.txt", filepath.Join(dir, "out.md")},
+	}
+	for _, tt := range tests {
+		got, err := GetOutputPath(tt.input, tt.output, tt.template)
+		if err != nil || got != tt.want {
+			t.Errorf("GetOutputPath(%q, %q, %q) = %q, %v, want %q", tt.input, tt.output, tt.template, got, err, tt.want)
+		}
+	}
+	if _, err := GetOutputPath("report.pdf", dir, "{bad}"); err == nil {
+		t.Error("GetOutputPath() with an invalid placeholder: error = nil")
+	}
+}
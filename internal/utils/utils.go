@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 // EnsureDir creates a directory if it doesn't exist
@@ -17,23 +18,49 @@ func FileExists(path string) bool {
 	return !os.IsNotExist(err)
 }
 
-// GetOutputPath generates an output path based on input path and options
-func GetOutputPath(inputPath, outputOption string) (string, error) {
-	if outputOption == "" {
+// GetOutputPath generates an output path based on input path and options.
+// When the output is a directory, or not given, the file name is built from
+// template if one is set (see RenderOutputTemplate).
+func GetOutputPath(inputPath, outputOption, template string) (string, error) {
+	name := func() (string, error) {
+		if template != "" {
+			return RenderOutputTemplate(template, inputPath, time.Now())
+		}
 		// Use input filename with .md extension
 		base := filepath.Base(inputPath)
 		ext := filepath.Ext(base)
 		return strings.TrimSuffix(base, ext) + ".md", nil
 	}
 
+	if outputOption == "" {
+		return name()
+	}
+
 	// Check if outputOption is a directory
 	info, err := os.Stat(outputOption)
 	if err == nil && info.IsDir() {
-		base := filepath.Base(inputPath)
-		ext := filepath.Ext(base)
-		return filepath.Join(outputOption, strings.TrimSuffix(base, ext)+".md"), nil
+		file, err := name()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(outputOption, file), nil
 	}
 
 	// Output is a specific file path
 	return outputOption, nil
 }
+
+// linkEscaper percent-encodes the characters that would end a Markdown link
+// destination early, and the percent sign so encoded names read back as is
+var linkEscaper = strings.NewReplacer("%", "%25", " ", "%20", "(", "%28", ")", "%29", "<", "%3C", ">", "%3E")
+
+// AssetLink returns the path used to reference an asset from the Markdown output.
+// linkDir is the assets directory relative to the output file; when empty,
+// assetsDir is used as is. Spaces and parentheses are percent-encoded, as a
+// link destination cannot hold them.
+func AssetLink(linkDir, assetsDir, name string) string {
+	if linkDir == "" {
+		linkDir = assetsDir
+	}
+	return linkEscaper.Replace(filepath.ToSlash(filepath.Join(linkDir, name)))
+}
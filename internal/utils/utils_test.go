@@ -0,0 +1,23 @@
+package utils
+
+import "testing"
+
+func TestAssetLink(t *testing.T) {
+	tests := []struct {
+		linkDir, assetsDir, name string
+		want                     string
+	}{
+		{"", "assets", "doc-page1-img1.png", "assets/doc-page1-img1.png"},
+		{"../assets", "out/assets", "a.png", "../assets/a.png"},
+		{"", "assets", "my report-fig.png", "assets/my%20report-fig.png"},
+		{"", "assets", "chart (final).png", "assets/chart%20%28final%29.png"},
+		{"", "my assets", "a.png", "my%20assets/a.png"},
+		{"", "assets", "100%.png", "assets/100%25.png"},
+		{"", "assets", "<b>.png", "assets/%3Cb%3E.png"},
+	}
+	for _, tt := range tests {
+		if got := AssetLink(tt.linkDir, tt.assetsDir, tt.name); got != tt.want {
+			t.Errorf("AssetLink(%q, %q, %q) = %q, want %q", tt.linkDir, tt.assetsDir, tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/errs"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+)
+
+// ReadMetadata reads the core properties of a workbook and counts the
+// worksheets it would convert
+func ReadMetadata(inputPath string) (markdown.Metadata, error) {
+	z, err := zip.OpenReader(inputPath)
+	if err != nil {
+		return markdown.Metadata{}, fmt.Errorf("%w: failed to open XLSX: %v", errs.ErrCorrupt, err)
+	}
+	defer z.Close()
+
+	wb := &workbook{zip: z}
+	var core struct {
+		Title    string `xml:"title"`
+		Creator  string `xml:"creator"`
+		Subject  string `xml:"subject"`
+		Keywords string `xml:"keywords"`
+		Created  string `xml:"created"`
+		Modified string `xml:"modified"`
+		Language string `xml:"language"`
+	}
+	// The core properties are optional
+	if r, err := wb.open("docProps/core.xml"); err == nil {
+		err := xml.NewDecoder(r).Decode(&core)
+		r.Close()
+		if err != nil {
+			return markdown.Metadata{}, fmt.Errorf("%w: failed to parse docProps/core.xml: %v", errs.ErrCorrupt, err)
+		}
+	}
+	sheets, err := wb.readSheets()
+	if err != nil {
+		return markdown.Metadata{}, err
+	}
+
+	meta := markdown.Metadata{
+		Title:    strings.TrimSpace(core.Title),
+		Author:   strings.TrimSpace(core.Creator),
+		Subject:  strings.TrimSpace(core.Subject),
+		Keywords: markdown.ParseKeywords(core.Keywords),
+		Pages:    len(sheets),
+		Language: strings.TrimSpace(core.Language),
+	}
+	meta.Created, _ = time.Parse(time.RFC3339, strings.TrimSpace(core.Created))
+	meta.Modified, _ = time.Parse(time.RFC3339, strings.TrimSpace(core.Modified))
+	return meta, nil
+}
@@ -0,0 +1,202 @@
+package xlsx
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/errs"
+	"github.com/leandrowiemesfilho/markdown-converter/internal/markdown"
+)
+
+// maxColumns bounds the width of a table. Sheets may declare a dimension
+// reaching the last column of the sheet, or have stray cells far to the right.
+const maxColumns = 1000
+
+// cellEscaper keeps cell text on one table row
+var cellEscaper = strings.NewReplacer("|", `\|`, "\r\n", "<br>", "\n", "<br>")
+
+// tableWriter writes the rows of a sheet as a GFM table as they are read. The
+// first row is the header, and the column count is taken from the sheet's
+// dimension or, without one, from the header. Rows and columns beyond the
+// limits are counted and left out, as are the cells of later rows reaching
+// past a header that set the column count, since the table cannot widen once
+// its header is written.
+type tableWriter struct {
+	out     *bufio.Writer
+	columns int
+	maxRows int
+	maxCols int
+	written int
+	// widest is the cell count of the widest row, written or not
+	widest      int
+	omittedRows int
+}
+
+func (t *tableWriter) writeRow(cells []string) {
+	t.widest = max(t.widest, len(cells))
+	if t.maxRows > 0 && t.written >= t.maxRows {
+		t.omittedRows++
+		return
+	}
+	if t.maxCols > 0 && len(cells) > t.maxCols {
+		cells = cells[:t.maxCols]
+	}
+	if t.written == 0 {
+		t.columns = max(t.columns, len(cells))
+		if t.maxCols > 0 {
+			t.columns = min(t.columns, t.maxCols)
+		}
+		t.out.WriteString("\n")
+	}
+	if len(cells) > t.columns {
+		cells = cells[:t.columns]
+	}
+	for len(cells) < t.columns {
+		cells = append(cells, "")
+	}
+	t.out.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	if t.written == 0 {
+		t.out.WriteString("|" + strings.Repeat(" --- |", t.columns) + "\n")
+	}
+	t.written++
+}
+
+// omitted returns the number of rows and columns left out of the table
+func (t *tableWriter) omitted() (int, int) {
+	columns := 0
+	if t.widest > t.columns {
+		columns = t.widest - t.columns
+	}
+	return t.omittedRows, columns
+}
+
+// writeSheet streams the rows of a worksheet into a table. Rows without any
+// text are left out, and cells are placed in the column of their reference so
+// that skipped cells stay empty.
+func (c *Converter) writeSheet(out *bufio.Writer, wb *workbook, s sheet) error {
+	r, err := wb.open(s.Path)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	table := &tableWriter{out: out, maxRows: c.MaxRows, maxCols: c.MaxCols}
+	var row []string
+	var cellType, cellRef string
+	var value strings.Builder
+	inValue := false
+
+	decoder := xml.NewDecoder(r)
+	for {
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("%w: failed to parse sheet %s: %v", errs.ErrCorrupt, s.Name, err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "dimension":
+				if ref := attr(t, "ref"); ref != "" {
+					last := ref[strings.LastIndex(ref, ":")+1:]
+					table.columns = min(columnIndex(last)+1, maxColumns)
+				}
+			case "row":
+				row = row[:0]
+			case "c":
+				cellType, cellRef = attr(t, "t"), attr(t, "r")
+				value.Reset()
+			case "v", "t":
+				inValue = true
+			case "rPh":
+				if err := decoder.Skip(); err != nil {
+					return fmt.Errorf("%w: failed to parse sheet %s: %v", errs.ErrCorrupt, s.Name, err)
+				}
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "v", "t":
+				inValue = false
+			case "c":
+				column := len(row)
+				if cellRef != "" {
+					column = max(columnIndex(cellRef), len(row))
+				}
+				if column >= maxColumns {
+					continue
+				}
+				for len(row) < column {
+					row = append(row, "")
+				}
+				row = append(row, cellEscaper.Replace(strings.TrimSpace(wb.cellText(cellType, value.String()))))
+			case "row":
+				for len(row) > 0 && row[len(row)-1] == "" {
+					row = row[:len(row)-1]
+				}
+				if len(row) > 0 {
+					table.writeRow(row)
+				}
+			}
+		case xml.CharData:
+			if inValue {
+				value.Write(t)
+			}
+		}
+	}
+
+	rows, columns := table.omitted()
+	if rows > 0 || columns > 0 {
+		if c.Logf != nil {
+			c.Logf("Sheet %s: left out %d rows and %d columns", s.Name, rows, columns)
+		}
+		if c.TruncationMarker {
+			out.WriteString("\n" + markdown.TruncationNote(rows, columns) + "\n")
+		}
+	}
+	return nil
+}
+
+// cellText resolves the value of a cell by its type
+func (wb *workbook) cellText(cellType, value string) string {
+	switch cellType {
+	case "s":
+		if i, err := strconv.Atoi(value); err == nil && i >= 0 && i < len(wb.strings) {
+			return wb.strings[i]
+		}
+		return ""
+	case "b":
+		if value == "1" {
+			return "TRUE"
+		}
+		return "FALSE"
+	}
+	return value
+}
+
+// columnIndex returns the zero-based column of a cell reference such as "C12"
+func columnIndex(ref string) int {
+	column := 0
+	for _, r := range strings.ToUpper(ref) {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		column = column*26 + int(r-'A'+1)
+	}
+	return max(column-1, 0)
+}
+
+func attr(e xml.StartElement, name string) string {
+	for _, a := range e.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
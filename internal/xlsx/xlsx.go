@@ -0,0 +1,191 @@
+// Package xlsx converts Excel workbooks (.xlsx) to Markdown, one table per
+// sheet. Sheets are read as a stream of XML tokens and their rows written as
+// they are read, so large sheets are never held in memory.
+package xlsx
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/leandrowiemesfilho/markdown-converter/internal/errs"
+)
+
+// Converter converts Excel workbooks to Markdown
+type Converter struct {
+	// MaxRows is the number of rows written per sheet, the header included;
+	// zero writes every row
+	MaxRows int
+	// MaxCols is the number of columns written per sheet; zero writes every column
+	MaxCols int
+	// TruncationMarker notes the rows and columns left out below a table
+	TruncationMarker bool
+	// Logf, when set, reports the rows and columns left out of each sheet
+	Logf func(format string, args ...interface{})
+}
+
+// workbook is an open workbook package
+type workbook struct {
+	zip *zip.ReadCloser
+	// strings is the shared string table cells refer to by index
+	strings []string
+}
+
+// sheet is a worksheet of the workbook, in tab order
+type sheet struct {
+	Name string
+	Path string
+}
+
+func (c *Converter) ToMarkdown(inputPath string, w io.Writer) error {
+	z, err := zip.OpenReader(inputPath)
+	if err != nil {
+		return fmt.Errorf("%w: failed to open XLSX: %v", errs.ErrCorrupt, err)
+	}
+	defer z.Close()
+
+	wb := &workbook{zip: z}
+	sheets, err := wb.readSheets()
+	if err != nil {
+		return err
+	}
+	if wb.strings, err = wb.readSharedStrings(); err != nil {
+		return err
+	}
+
+	out := bufio.NewWriter(w)
+	for i, s := range sheets {
+		if i > 0 {
+			out.WriteString("\n")
+		}
+		out.WriteString("## " + s.Name + "\n")
+		if err := c.writeSheet(out, wb, s); err != nil {
+			return err
+		}
+	}
+	if err := out.Flush(); err != nil {
+		return fmt.Errorf("failed to write markdown: %v", err)
+	}
+	return nil
+}
+
+// open returns a reader for a package part
+func (wb *workbook) open(name string) (io.ReadCloser, error) {
+	for _, f := range wb.zip.File {
+		if f.Name == name {
+			r, err := f.Open()
+			if err != nil {
+				return nil, fmt.Errorf("%w: failed to open %s: %v", errs.ErrCorrupt, name, err)
+			}
+			return r, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: missing part %s", errs.ErrCorrupt, name)
+}
+
+// decodePart unmarshals a small XML part
+func (wb *workbook) decodePart(name string, v interface{}) error {
+	r, err := wb.open(name)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	if err := xml.NewDecoder(r).Decode(v); err != nil {
+		return fmt.Errorf("%w: failed to parse %s: %v", errs.ErrCorrupt, name, err)
+	}
+	return nil
+}
+
+// readSheets lists the worksheets of the workbook with the parts holding them
+func (wb *workbook) readSheets() ([]sheet, error) {
+	var book struct {
+		Sheets []struct {
+			Name string `xml:"name,attr"`
+			ID   string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := wb.decodePart("xl/workbook.xml", &book); err != nil {
+		return nil, err
+	}
+	var rels struct {
+		Relationships []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := wb.decodePart("xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return nil, err
+	}
+
+	targets := make(map[string]string)
+	for _, rel := range rels.Relationships {
+		if strings.HasPrefix(rel.Target, "/") {
+			targets[rel.ID] = strings.TrimPrefix(rel.Target, "/")
+		} else {
+			targets[rel.ID] = path.Join("xl", rel.Target)
+		}
+	}
+
+	var sheets []sheet
+	for _, s := range book.Sheets {
+		// Chart sheets and missing targets have no cells to render
+		if target, ok := targets[s.ID]; ok && strings.HasPrefix(target, "xl/worksheets/") {
+			sheets = append(sheets, sheet{Name: s.Name, Path: target})
+		}
+	}
+	return sheets, nil
+}
+
+// readSharedStrings reads the shared string table, joining the runs of rich
+// text strings. Workbooks without text cells have no table.
+func (wb *workbook) readSharedStrings() ([]string, error) {
+	r, err := wb.open("xl/sharedStrings.xml")
+	if err != nil {
+		return nil, nil
+	}
+	defer r.Close()
+
+	var table []string
+	var text strings.Builder
+	inText := false
+	decoder := xml.NewDecoder(r)
+	for {
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			return table, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to parse shared strings: %v", errs.ErrCorrupt, err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "si":
+				text.Reset()
+			case "t":
+				inText = true
+			case "rPh":
+				// Phonetic guides repeat the reading of East Asian text
+				if err := decoder.Skip(); err != nil {
+					return nil, fmt.Errorf("%w: failed to parse shared strings: %v", errs.ErrCorrupt, err)
+				}
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "si":
+				table = append(table, text.String())
+			case "t":
+				inText = false
+			}
+		case xml.CharData:
+			if inText {
+				text.Write(t)
+			}
+		}
+	}
+}
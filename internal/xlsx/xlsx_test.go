@@ -0,0 +1,266 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const sheetNS = `xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"`
+
+// writeXlsx writes a workbook of sheets, each given as its name and the rows
+// inside <sheetData>, with a shared string table when strings is not empty
+func writeXlsx(t testing.TB, sharedStrings []string, sheets ...[2]string) string {
+	t.Helper()
+	var book, rels strings.Builder
+	files := map[string]string{}
+	for i, s := range sheets {
+		fmt.Fprintf(&book, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, s[0], i+1, i+1)
+		fmt.Fprintf(&rels, `<Relationship Id="rId%d" Target="worksheets/sheet%d.xml"/>`, i+1, i+1)
+		files[fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1)] = `<worksheet ` + sheetNS + `><sheetData>` + s[1] + `</sheetData></worksheet>`
+	}
+	files["xl/workbook.xml"] = `<workbook ` + sheetNS + ` xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>` + book.String() + `</sheets></workbook>`
+	files["xl/_rels/workbook.xml.rels"] = `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` + rels.String() + `</Relationships>`
+	if len(sharedStrings) > 0 {
+		files["xl/sharedStrings.xml"] = `<sst ` + sheetNS + `><si><t>` + strings.Join(sharedStrings, `</t></si><si><t>`) + `</t></si></sst>`
+	}
+
+	var buf bytes.Buffer
+	z := zip.NewWriter(&buf)
+	for name, data := range files {
+		f, err := z.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(data))
+	}
+	if err := z.Close(); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "test.xlsx")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// convertXlsx converts a workbook, failing the test on errors
+func convertXlsx(t testing.TB, c *Converter, path string) string {
+	t.Helper()
+	var out bytes.Buffer
+	if err := c.ToMarkdown(path, &out); err != nil {
+		t.Fatalf("ToMarkdown() error = %v", err)
+	}
+	return out.String()
+}
+
+func TestToMarkdown(t *testing.T) {
+	shared := []string{"Name", "Note", "Ana", "a|b"}
+	tests := []struct {
+		name string
+		rows string
+		want string
+	}{
+		{
+			"shared and inline strings",
+			`<row><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c></row>` +
+				`<row><c r="A2" t="s"><v>2</v></c><c r="B2" t="inlineStr"><is><t>line one` + "\n" + `line two</t></is></c></row>`,
+			"| Name | Note |\n| --- | --- |\n| Ana | line one<br>line two |\n",
+		},
+		{
+			"numbers, booleans and escaped pipes",
+			`<row><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c></row>` +
+				`<row><c r="A2"><v>42.5</v></c><c r="B2" t="b"><v>1</v></c></row>` +
+				`<row><c r="A3" t="s"><v>3</v></c><c r="B3" t="b"><v>0</v></c></row>`,
+			"| Name | Note |\n| --- | --- |\n| 42.5 | TRUE |\n| a\\|b | FALSE |\n",
+		},
+		{
+			"skipped cells and empty rows",
+			`<row><c r="A1" t="s"><v>0</v></c><c r="C1" t="s"><v>1</v></c></row>` +
+				`<row><c r="A2"/><c r="B2"><v></v></c></row>` +
+				`<row><c r="B3"><v>7</v></c></row>`,
+			"| Name |  | Note |\n| --- | --- | --- |\n|  | 7 |  |\n",
+		},
+		{
+			"rich text and phonetic guides",
+			`<row><c r="A1" t="inlineStr"><is><r><t>Bold</t></r><r><t xml:space="preserve"> part</t></r><rPh><t>ボールド</t></rPh></is></c></row>`,
+			"| Bold part |\n| --- |\n",
+		},
+		{
+			"row wider than the header",
+			`<row><c r="A1" t="s"><v>0</v></c></row><row><c r="A2"><v>1</v></c><c r="B2"><v>2</v></c></row>`,
+			"| Name |\n| --- |\n| 1 |\n",
+		},
+		{
+			"unknown shared string",
+			`<row><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>99</v></c></row>`,
+			"| Name |\n| --- |\n",
+		},
+	}
+	for _, tt := range tests {
+		got := convertXlsx(t, &Converter{}, writeXlsx(t, shared, [2]string{"Data", tt.rows}))
+		if want := "## Data\n\n" + tt.want; got != want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, want)
+		}
+	}
+}
+
+func TestSheets(t *testing.T) {
+	path := writeXlsx(t, nil,
+		[2]string{"First", `<row><c r="A1" t="inlineStr"><is><t>One</t></is></c></row>`},
+		[2]string{"Second", `<row><c r="A1" t="inlineStr"><is><t>Two</t></is></c></row>`},
+	)
+	want := "## First\n\n| One |\n| --- |\n\n## Second\n\n| Two |\n| --- |\n"
+	if got := convertXlsx(t, &Converter{}, path); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestColumnIndex(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want int
+	}{
+		{"A1", 0},
+		{"c12", 2},
+		{"Z9", 25},
+		{"AA1", 26},
+		{"XFD1048576", 16383},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		if got := columnIndex(tt.ref); got != tt.want {
+			t.Errorf("columnIndex(%q) = %d, want %d", tt.ref, got, tt.want)
+		}
+	}
+}
+
+// numberRows returns rows of two numeric cells, the first a header row
+func numberRows(n int) string {
+	var b strings.Builder
+	for i := 1; i <= n; i++ {
+		fmt.Fprintf(&b, `<row r="%d"><c r="A%d"><v>%d</v></c><c r="B%d"><v>%d</v></c></row>`, i, i, i, i, i*2)
+	}
+	return b.String()
+}
+
+func TestMaxRows(t *testing.T) {
+	path := writeXlsx(t, nil, [2]string{"Data", numberRows(1000)})
+	tests := []struct {
+		c    Converter
+		want string
+	}{
+		{Converter{MaxRows: 3}, "## Data\n\n| 1 | 2 |\n| --- | --- |\n| 2 | 4 |\n| 3 | 6 |\n"},
+		{Converter{MaxRows: 1}, "## Data\n\n| 1 | 2 |\n| --- | --- |\n"},
+	}
+	for _, tt := range tests {
+		if got := convertXlsx(t, &tt.c, path); got != tt.want {
+			t.Errorf("MaxRows %d: got %q, want %q", tt.c.MaxRows, got, tt.want)
+		}
+	}
+	if got := convertXlsx(t, &Converter{}, path); !strings.HasSuffix(got, "| 999 | 1998 |\n| 1000 | 2000 |\n") {
+		t.Errorf("without a limit: output ends %q, want every row", got[len(got)-40:])
+	}
+}
+
+func TestLimits(t *testing.T) {
+	wide := `<row><c r="A1"><v>1</v></c><c r="B1"><v>2</v></c><c r="C1"><v>3</v></c></row>` +
+		`<row><c r="A2"><v>4</v></c><c r="D2"><v>5</v></c></row>`
+	path := writeXlsx(t, nil, [2]string{"Data", wide}, [2]string{"Long", numberRows(5)})
+	tests := []struct {
+		name string
+		c    Converter
+		want string
+		logs []string
+	}{
+		{
+			name: "columns",
+			c:    Converter{MaxCols: 2},
+			want: "## Data\n\n| 1 | 2 |\n| --- | --- |\n| 4 |  |\n\n## Long\n\n| 1 | 2 |\n| --- | --- |\n| 2 | 4 |\n| 3 | 6 |\n| 4 | 8 |\n| 5 | 10 |\n",
+			logs: []string{"Sheet Data: left out 0 rows and 2 columns"},
+		},
+		{
+			name: "rows and columns with marker",
+			c:    Converter{MaxRows: 2, MaxCols: 1, TruncationMarker: true},
+			want: "## Data\n\n| 1 |\n| --- |\n| 4 |\n\n… (3 more columns)\n\n## Long\n\n| 1 |\n| --- |\n| 2 |\n\n… (3 more rows, 1 more column)\n",
+			logs: []string{
+				"Sheet Data: left out 0 rows and 3 columns",
+				"Sheet Long: left out 3 rows and 1 columns",
+			},
+		},
+		{
+			name: "row wider than the header",
+			c:    Converter{MaxRows: 10, MaxCols: 5, TruncationMarker: true},
+			want: "## Data\n\n| 1 | 2 | 3 |\n| --- | --- | --- |\n| 4 |  |  |\n\n… (1 more column)\n\n## Long\n\n| 1 | 2 |\n| --- | --- |\n| 2 | 4 |\n| 3 | 6 |\n| 4 | 8 |\n| 5 | 10 |\n",
+			logs: []string{"Sheet Data: left out 0 rows and 1 columns"},
+		},
+	}
+	for _, tt := range tests {
+		var logs []string
+		tt.c.Logf = func(format string, args ...interface{}) { logs = append(logs, fmt.Sprintf(format, args...)) }
+		if got := convertXlsx(t, &tt.c, path); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+		if fmt.Sprint(logs) != fmt.Sprint(tt.logs) {
+			t.Errorf("%s: logs = %q, want %q", tt.name, logs, tt.logs)
+		}
+	}
+}
+
+func TestConcurrent(t *testing.T) {
+	path := writeXlsx(t, []string{"Header"}, [2]string{"Data", `<row><c r="A1" t="s"><v>0</v></c></row>` + numberRows(200)})
+	want := convertXlsx(t, &Converter{}, path)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var out bytes.Buffer
+			if err := (&Converter{}).ToMarkdown(path, &out); err != nil || out.String() != want {
+				t.Errorf("concurrent conversion = %d bytes, %v, want %d bytes", out.Len(), err, len(want))
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// heapWriter discards what is written and records the largest heap seen
+// while writing
+type heapWriter struct {
+	peak uint64
+}
+
+func (w *heapWriter) Write(p []byte) (int, error) {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	w.peak = max(w.peak, stats.HeapAlloc)
+	return len(p), nil
+}
+
+// BenchmarkLargeSheet converts sheets of growing length. The peak heap stays
+// about the same across sizes, since rows are written as they are read.
+func BenchmarkLargeSheet(b *testing.B) {
+	for _, rows := range []int{10_000, 100_000} {
+		b.Run(fmt.Sprintf("rows=%d", rows), func(b *testing.B) {
+			path := writeXlsx(b, nil, [2]string{"Data", numberRows(rows)})
+			b.ReportAllocs()
+			b.ResetTimer()
+			var peak uint64
+			for i := 0; i < b.N; i++ {
+				runtime.GC()
+				w := &heapWriter{}
+				if err := (&Converter{}).ToMarkdown(path, w); err != nil {
+					b.Fatal(err)
+				}
+				peak = max(peak, w.peak)
+			}
+			b.ReportMetric(float64(peak)/(1<<20), "peak-MB")
+		})
+	}
+}